@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	adoptModule string
+	adoptName   string
+	adoptScan   bool
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [path]",
+	Short: "Move an existing dotfile into pact and link it back",
+	Long: `Bring an existing config file or directory under pact's management:
+move it into .pact/, symlink it back where it was, add it to the module's
+"files" in pact.json, and stage both for the next 'pact push'.
+
+Known configs go where 'pact read' puts them (~/.zshrc in shell, ~/.gitconfig
+in git); anything else gets a module named after it. Editor settings get a
+target for each OS, since they live somewhere different on each.
+
+Use --scan to pick from every config pact knows about that isn't managed yet.
+
+Examples:
+  pact adopt ~/.tmux.conf                  # tmux/tmux.conf in the tmux module
+  pact adopt ~/.config/kitty               # kitty/kitty in the kitty module
+  pact adopt ~/.wezterm.lua --module terminal
+  pact adopt --scan`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if adoptScan == (len(args) == 1) {
+			fmt.Println("Error: give a path to adopt, or --scan")
+			os.Exit(1)
+		}
+		if adoptScan && adoptName != "" {
+			fmt.Println("Error: --name adopts one file; it can't be used with --scan")
+			os.Exit(1)
+		}
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var files []detect.ConfigFile
+		if adoptScan {
+			files = pickUnmanaged(cfg, pactDir)
+			if len(files) == 0 {
+				return
+			}
+		} else {
+			path, err := config.ExpandPath(args[0])
+			if err == nil {
+				path, err = filepath.Abs(path)
+			}
+			if err == nil {
+				var cf detect.ConfigFile
+				if cf, err = adoptable(path, pactDir); err == nil {
+					files = append(files, cf)
+				}
+			}
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		var staged []string
+		for _, cf := range files {
+			if err := adoptFile(cfg, pactDir, cf); err != nil {
+				fmt.Printf("✗ %s: %v\n", homeRelative(cf.SourcePath), err)
+				continue
+			}
+			fmt.Printf("✓ Adopted %s into %s\n", homeRelative(cf.SourcePath), cf.Module)
+			fmt.Printf("  %s -> .pact/%s\n", homeRelative(cf.SourcePath), filepath.ToSlash(cf.DestPath))
+			staged = append(staged, cf.DestPath)
+		}
+		if len(staged) == 0 {
+			os.Exit(1)
+		}
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := git.Stage(pactDir, append(staged, "pact.json")...); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			return
+		}
+		fmt.Println("Staged; run 'pact push' to commit")
+		if len(staged) < len(files) {
+			os.Exit(1)
+		}
+	},
+}
+
+// adoptable checks path can be adopted and describes where it goes
+func adoptable(path, pactDir string) (detect.ConfigFile, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return detect.ConfigFile{}, err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err := filepath.EvalSymlinks(path); err == nil && strings.HasPrefix(link, pactDir+string(filepath.Separator)) {
+			return detect.ConfigFile{}, fmt.Errorf("%s is already managed by pact", path)
+		}
+		return detect.ConfigFile{}, fmt.Errorf("%s is a symlink; adopt the file it points to", path)
+	}
+	if path == pactDir || strings.HasPrefix(path, pactDir+string(filepath.Separator)) {
+		return detect.ConfigFile{}, fmt.Errorf("%s is inside .pact/", path)
+	}
+
+	cf := detect.ConfigFileAt(path, info.IsDir())
+	if adoptModule != "" {
+		cf.Module = adoptModule
+		cf.DestPath = filepath.Join(adoptModule, filepath.Base(cf.DestPath))
+	}
+	if adoptName != "" {
+		cf.Name = adoptName
+	}
+	return cf, nil
+}
+
+// adoptFile moves cf into .pact/, links it back, and adds its file entry
+func adoptFile(cfg *config.PactConfig, pactDir string, cf detect.ConfigFile) error {
+	files, err := adoptFiles(cfg, cf.Module)
+	if err != nil {
+		return err
+	}
+	if _, ok := files[cf.Name]; ok {
+		return fmt.Errorf("%s.files already has %s; pick another with --name", cf.Module, cf.Name)
+	}
+	dest := filepath.Join(pactDir, cf.DestPath)
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf(".pact/%s already exists", filepath.ToSlash(cf.DestPath))
+	}
+
+	if err := moveAndLink(cf, dest); err != nil {
+		return err
+	}
+	var target any = homeRelative(cf.SourcePath)
+	if targets := detect.OSTargets(cf); targets != nil {
+		target = targets
+	}
+	files[cf.Name] = map[string]any{
+		"source": filepath.ToSlash(cf.DestPath),
+		"target": target,
+	}
+	return nil
+}
+
+// adoptFiles returns the module's "files", adding the module and its files
+// to pact.json when missing
+func adoptFiles(cfg *config.PactConfig, module string) (map[string]any, error) {
+	switch module {
+	case "", "name", "version", "secrets", "settings", "hooks":
+		return nil, fmt.Errorf("can't add files to '%s'; pick a module with --module", module)
+	}
+	mod, ok := cfg.Raw[module].(map[string]any)
+	if !ok {
+		if cfg.Raw[module] != nil {
+			return nil, fmt.Errorf("%s in pact.json isn't an object", module)
+		}
+		mod = map[string]any{}
+		cfg.Raw[module] = mod
+	}
+	files, ok := mod["files"].(map[string]any)
+	if !ok {
+		if mod["files"] != nil {
+			return nil, fmt.Errorf("%s.files in pact.json isn't an object", module)
+		}
+		files = map[string]any{}
+		mod["files"] = files
+	}
+	return files, nil
+}
+
+// moveAndLink moves the file into .pact/ and symlinks it back, moving it
+// back if the link can't be made
+func moveAndLink(cf detect.ConfigFile, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(cf.SourcePath, dest); err != nil {
+		// Across filesystems: copy, then remove the original
+		if err := detect.CopyConfigFile(detect.ConfigFile{SourcePath: cf.SourcePath, DestPath: filepath.Base(dest), IsDir: cf.IsDir}, filepath.Dir(dest)); err != nil {
+			os.RemoveAll(dest)
+			return err
+		}
+		if err := os.RemoveAll(cf.SourcePath); err != nil {
+			return err
+		}
+	}
+	if err := os.Symlink(dest, cf.SourcePath); err != nil {
+		if restoreErr := os.Rename(dest, cf.SourcePath); restoreErr != nil {
+			return fmt.Errorf("%v; the file is now at %s", err, dest)
+		}
+		return err
+	}
+	return nil
+}
+
+// homeRelative writes paths in the home directory with ~/ so pact.json
+// works on other machines
+func homeRelative(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") && rel != "." {
+		return "~/" + filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// unmanagedConfigs returns the known config files pact doesn't manage yet:
+// not linked from .pact/ and not the target of a file entry
+func unmanagedConfigs(cfg *config.PactConfig, pactDir string) []detect.ConfigFile {
+	targets := map[string]bool{}
+	if items, err := cfg.GetSyncItems(); err == nil {
+		for _, item := range items {
+			targets[filepath.Clean(item.Target)] = true
+		}
+	}
+
+	var files []detect.ConfigFile
+	for _, cf := range detect.DiscoverConfigFiles() {
+		if targets[filepath.Clean(cf.SourcePath)] {
+			continue
+		}
+		if adopt, err := adoptable(cf.SourcePath, pactDir); err == nil {
+			files = append(files, adopt)
+		}
+	}
+	return files
+}
+
+// pickUnmanaged lets the user choose which unmanaged configs to adopt
+func pickUnmanaged(cfg *config.PactConfig, pactDir string) []detect.ConfigFile {
+	files := unmanagedConfigs(cfg, pactDir)
+	if len(files) == 0 {
+		fmt.Println("✓ Every config pact knows about is already managed")
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		for _, cf := range files {
+			fmt.Printf("  %s %-20s %s\n", localOnlyStyle.Render("○"), cf.Name, dimStyle.Render(homeRelative(cf.SourcePath)))
+		}
+		fmt.Println("\nRun 'pact adopt --scan' in a terminal to pick, or 'pact adopt <path>' for one.")
+		return nil
+	}
+
+	result, err := tea.NewProgram(initialAdoptModel(files), ui.Keys().ProgramOptions()...).Run()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	m, ok := result.(adoptModel)
+	if !ok || m.cancelled {
+		fmt.Println("Cancelled.")
+		return nil
+	}
+	var chosen []detect.ConfigFile
+	for i, cf := range m.files {
+		if m.selected[i] {
+			chosen = append(chosen, cf)
+		}
+	}
+	if len(chosen) == 0 {
+		fmt.Println("Nothing selected.")
+	}
+	return chosen
+}
+
+// adoptModel is the multi-select picker for 'pact adopt --scan'
+type adoptModel struct {
+	files     []detect.ConfigFile
+	cursor    int
+	selected  map[int]bool
+	cancelled bool
+	quitting  bool
+}
+
+func initialAdoptModel(files []detect.ConfigFile) adoptModel {
+	return adoptModel{files: files, selected: map[int]bool{}}
+}
+
+func (m adoptModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m adoptModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keys := ui.Keys()
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		m.cursor = max(0, min(m.cursor+keys.Scroll(mouse), len(m.files)-1))
+		return m, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Quit), key.Matches(keyMsg, keys.Back):
+		m.cancelled = true
+		m.quitting = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if m.cursor < len(m.files)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, keys.Toggle):
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case key.Matches(keyMsg, keys.All):
+		all := true
+		for i := range m.files {
+			all = all && m.selected[i]
+		}
+		for i := range m.files {
+			m.selected[i] = !all
+		}
+	case key.Matches(keyMsg, keys.Enter):
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m adoptModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\nSelect configs to adopt:\n\n")
+	for i, cf := range m.files {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		checkbox := "[ ]"
+		if m.selected[i] {
+			checkbox = "[x]"
+		}
+		b.WriteString(fmt.Sprintf("%s%s %-20s %s\n", cursor, checkbox, cf.Name, dimStyle.Render(homeRelative(cf.SourcePath)+" → "+cf.Module)))
+	}
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(listHelp("adopt", ui.Keys().Quit, "quit")))
+	return b.String()
+}
+
+func init() {
+	adoptCmd.Flags().StringVarP(&adoptModule, "module", "m", "", "Module to add it to (default: picked from the path)")
+	adoptCmd.Flags().StringVar(&adoptName, "name", "", "Name of the file entry (default: the file's name)")
+	adoptCmd.Flags().BoolVar(&adoptScan, "scan", false, "Pick from known configs that aren't managed yet")
+	rootCmd.AddCommand(adoptCmd)
+}
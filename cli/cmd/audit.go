@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/buildinfo"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/spf13/cobra"
+)
+
+var auditJSON bool
+
+// auditReport is the inventory printed by `pact audit-machine`
+type auditReport struct {
+	GeneratedAt     time.Time     `json:"generatedAt"`
+	Hostname        string        `json:"hostname"`
+	User            string        `json:"user"`
+	OS              string        `json:"os"`
+	Arch            string        `json:"arch"`
+	PactVersion     string        `json:"pactVersion"`
+	PackageManagers []string      `json:"packageManagers"`
+	Shell           string        `json:"shell,omitempty"`
+	GitUser         string        `json:"gitUser,omitempty"`
+	GitEmail        string        `json:"gitEmail,omitempty"`
+	Tools           []auditTool   `json:"tools"`
+	Editors         []string      `json:"editors,omitempty"`
+	LLMModels       []string      `json:"llmModels,omitempty"`
+	CodingAgents    []string      `json:"codingAgents,omitempty"`
+	Secrets         []auditSecret `json:"secrets"`
+	ConfigFiles     []string      `json:"configFiles,omitempty"`
+}
+
+type auditTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Path    string `json:"path"`
+}
+
+// auditSecret names a secret and where it is set; values are never read
+type auditSecret struct {
+	Name       string `json:"name"`
+	InEnv      bool   `json:"inEnv"`
+	InKeychain bool   `json:"inKeychain"`
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit-machine",
+	Short: "Print a read-only inventory of this machine",
+	Long: `Scan this machine and print an inventory for IT and security reviews:
+installed tools with versions and paths, package managers, shell, editors,
+local LLM models, and secrets by name only (values are never read).
+
+Nothing is changed and pact doesn't need to be initialized.
+
+Examples:
+  pact audit-machine
+  pact audit-machine --json > inventory.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		report := buildAuditReport(cmd.Context())
+
+		if auditJSON {
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+			return
+		}
+
+		fmt.Printf("%s (%s) %s/%s, scanned %s\n\n", report.Hostname, report.User, report.OS, report.Arch, report.GeneratedAt.Format("2006-01-02 15:04"))
+		fmt.Println("Tools:")
+		for _, t := range report.Tools {
+			version := t.Version
+			if version == "" {
+				version = "?"
+			}
+			fmt.Printf("  %-16s %-14s %s\n", t.Name, version, t.Path)
+		}
+		fmt.Println()
+		fmt.Printf("Package managers: %s\n", joinOrNone(report.PackageManagers))
+		fmt.Printf("Shell:            %s\n", orNone(report.Shell))
+		fmt.Printf("Git identity:     %s\n", orNone(strings.TrimSpace(report.GitUser+" "+report.GitEmail)))
+		fmt.Printf("Editors:          %s\n", joinOrNone(report.Editors))
+		fmt.Printf("LLM models:       %s\n", joinOrNone(report.LLMModels))
+		fmt.Printf("Coding agents:    %s\n", joinOrNone(report.CodingAgents))
+		fmt.Println()
+		fmt.Println("Secrets (names only):")
+		if len(report.Secrets) == 0 {
+			fmt.Println("  none found")
+		}
+		for _, s := range report.Secrets {
+			where := detect.SecretDetected{InEnv: s.InEnv, InKeychain: s.InKeychain}.Location()
+			fmt.Printf("  %-28s %s\n", s.Name, where)
+		}
+	},
+}
+
+func buildAuditReport(ctx context.Context) auditReport {
+	detected := detect.Scan(detect.ScanOptions{IncludeFiles: true, Context: ctx})
+
+	report := auditReport{
+		GeneratedAt:     time.Now().UTC(),
+		OS:              runtime.GOOS,
+		Arch:            runtime.GOARCH,
+		PactVersion:     buildinfo.Get().Version,
+		PackageManagers: apply.PackageManagers(),
+		Shell:           detected.Shell.Type,
+		GitUser:         detected.Git.User,
+		GitEmail:        detected.Git.Email,
+		Secrets:         []auditSecret{},
+	}
+	report.Hostname, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		report.User = u.Username
+	}
+
+	// Everything detection found that runs as a command
+	seen := make(map[string]bool)
+	var names []string
+	add := func(tools ...string) {
+		for _, t := range tools {
+			if t != "" && !seen[t] {
+				seen[t] = true
+				names = append(names, t)
+			}
+		}
+	}
+	add(detected.CLI.Tools...)
+	add(detected.CLI.Custom...)
+	add(detected.Shell.Tools...)
+	if detected.Shell.Prompt != nil {
+		add(detected.Shell.Prompt.Tool)
+	}
+	add(report.PackageManagers...)
+	if detected.LLM.Local != nil {
+		add(detected.LLM.Local.Runtime)
+		report.LLMModels = detected.LLM.Local.Models
+	}
+	sort.Strings(names)
+
+	versions := detect.DetectVersions(ctx, names)
+	for _, name := range names {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		report.Tools = append(report.Tools, auditTool{Name: name, Version: versions[name], Path: path})
+	}
+
+	if detected.Editor.Default != "" {
+		report.Editors = append(report.Editors, detected.Editor.Default)
+	}
+	report.Editors = append(report.Editors, detected.Editor.Others...)
+	if detected.LLM.Coding != nil {
+		report.CodingAgents = detected.LLM.Coding.Agents
+	}
+
+	for _, s := range detected.Secrets {
+		report.Secrets = append(report.Secrets, auditSecret{
+			Name:       s.Name,
+			InEnv:      s.InEnv,
+			InKeychain: keyring.HasSecret(s.Name),
+		})
+	}
+	for _, cf := range detected.ConfigFiles {
+		if !cf.Exists {
+			continue
+		}
+		report.ConfigFiles = append(report.ConfigFiles, cf.SourcePath)
+	}
+	return report
+}
+
+func joinOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func init() {
+	auditCmd.Flags().BoolVar(&auditJSON, "json", false, "Output the report as JSON")
+	rootCmd.AddCommand(auditCmd)
+}
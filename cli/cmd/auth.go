@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+var (
+	authWithToken bool
+	authWorkspace bool
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage the GitHub token pact uses",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Sign in to GitHub and store the token in the keychain",
+	Long: `Sign in to GitHub in the browser, the way 'pact init' does, and store the
+token in the OS keychain.
+
+--with-token stores a token you created yourself instead, read from stdin.
+A fine-grained token limited to your my-pact repo, with Contents set to
+"Read and write", gives pact no more access than it needs.
+
+Examples:
+  pact auth login
+  pact auth login --with-token < token.txt`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		var token string
+		var info *auth.TokenInfo
+		var err error
+		if authWithToken {
+			token = strings.TrimSpace(readPassphrase("Paste the token: "))
+			if token == "" {
+				fmt.Println("Error: no token given")
+				os.Exit(1)
+			}
+			info, err = checkToken(ctx, token)
+		} else {
+			token, info, err = signIn(ctx)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := storeToken(info.Login, token); err != nil {
+			fmt.Printf("Error: couldn't store the token in the keychain: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Signed in as %s (%s), now the default account\n", info.Login, info.Kind)
+		for _, line := range tokenWarnings(info) {
+			fmt.Printf("  %s\n", line)
+		}
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show who pact signs in to GitHub as, and what the token can do",
+	Long: `Show the GitHub account pact uses, where its token comes from, the token's
+scopes, and whether it can push to the pact repo. Scopes pact doesn't use
+are pointed out, since a leaked token can do whatever they allow.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		token, source, err := tokenWithSource()
+		if err != nil {
+			fmt.Println("○ Not signed in to GitHub. Run 'pact auth login' or 'pact init'.")
+			os.Exit(1)
+		}
+		info, err := auth.CheckToken(ctx, token)
+		if err != nil {
+			if strings.Contains(err.Error(), "status 401") {
+				fmt.Printf("✗ The token from %s is invalid or was revoked. Run 'pact auth login'.\n", source)
+			} else {
+				fmt.Printf("Error: %v\n", err)
+			}
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Signed in to GitHub as %s\n", info.Login)
+		fmt.Printf("  Token:   %s, from %s\n", info.Kind, source)
+		if info.HasScopes() {
+			scopes := strings.Join(info.Scopes, ", ")
+			if scopes == "" {
+				scopes = "none"
+			}
+			fmt.Printf("  Scopes:  %s\n", scopes)
+		}
+
+		if accounts := keyring.Accounts(); len(accounts) > 1 {
+			fmt.Printf("  Stored:  %s\n", strings.Join(accounts, ", "))
+		}
+
+		owner, name := pactRepoPath(info.Login)
+		if owner != "" {
+			fmt.Printf("  Repo:    %s/%s: %s\n", owner, name, repoAccess(ctx, token, owner, name))
+		}
+		for _, line := range tokenWarnings(info) {
+			fmt.Printf("  %s\n", line)
+		}
+	},
+}
+
+var authSwitchCmd = &cobra.Command{
+	Use:   "switch [login]",
+	Short: "Change which GitHub account pact uses",
+	Long: `Make another signed-in GitHub account the default, or with --workspace
+bind just this pact workspace to it, so its pushes and pulls use that
+account's token while other workspaces keep the default. --workspace
+without a login unbinds the workspace. Sign in to each account once with
+'pact auth login' first.
+
+Examples:
+  pact auth switch jh
+  pact auth switch jh-work --workspace
+  pact auth switch --workspace`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		login := ""
+		if len(args) == 1 {
+			login = args[0]
+		} else if !authWorkspace {
+			fmt.Println("Error: name the account to switch to, one of:", strings.Join(keyring.Accounts(), ", "))
+			os.Exit(1)
+		}
+
+		if login != "" {
+			if _, err := keyring.GetAccountToken(login); err != nil {
+				fmt.Printf("Error: no token stored for %s; run 'pact auth login' and sign in as %s\n", login, login)
+				os.Exit(1)
+			}
+		}
+
+		if authWorkspace {
+			if !config.Exists() {
+				fmt.Println("Pact is not initialized. Run 'pact init' first.")
+				os.Exit(1)
+			}
+			if err := state.SetAccount(login); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if login == "" {
+				fmt.Println("✓ This workspace uses the default GitHub account again")
+			} else {
+				fmt.Printf("✓ This workspace now uses %s\n", login)
+			}
+			return
+		}
+
+		if err := keyring.SetDefaultAccount(login); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ %s is now the default GitHub account\n", login)
+		if bound := keyring.Account(); bound != "" && !strings.EqualFold(bound, login) {
+			fmt.Printf("  This workspace still uses %s ('pact auth switch --workspace' unbinds it)\n", bound)
+		}
+	},
+}
+
+var authRotateCmd = &cobra.Command{
+	Use:   "rotate",
+	Short: "Replace the GitHub token with a new one and revoke the old one",
+	Long: `Sign in to GitHub again for a fresh token, store it in place of the one
+pact has for the account, and then revoke the old token through GitHub's
+API so a leaked copy stops working. --with-token stores a token you created
+yourself instead, read from stdin. The old token is only revoked once the
+new one is stored.
+
+Examples:
+  pact auth rotate
+  pact auth rotate --with-token < token.txt`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		old, source, err := tokenWithSource()
+		if err != nil {
+			fmt.Println("○ Not signed in to GitHub. Run 'pact auth login' or 'pact init'.")
+			os.Exit(1)
+		}
+		if strings.HasPrefix(source, "$") {
+			fmt.Printf("Error: the token comes from %s, not the keychain; replace it where it's set\n", source)
+			os.Exit(1)
+		}
+		login, err := githubLogin(ctx, old)
+		if err != nil {
+			fmt.Printf("Error: couldn't check the current token: %v\n", err)
+			fmt.Println("  Run 'pact auth login' to sign in again.")
+			os.Exit(1)
+		}
+
+		var token string
+		var info *auth.TokenInfo
+		if authWithToken {
+			token = strings.TrimSpace(readPassphrase("Paste the new token: "))
+			if token == "" {
+				fmt.Println("Error: no token given")
+				os.Exit(1)
+			}
+			info, err = checkToken(ctx, token)
+		} else {
+			token, info, err = signIn(ctx)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !strings.EqualFold(info.Login, login) {
+			fmt.Printf("Error: the new token is for %s, not %s; the old token is unchanged\n", info.Login, login)
+			os.Exit(1)
+		}
+		if token == old {
+			fmt.Println("Error: GitHub handed back the same token; revoke it at https://github.com/settings/applications and sign in again")
+			os.Exit(1)
+		}
+
+		if err := keyring.SetAccountToken(login, token); err != nil {
+			fmt.Printf("Error: couldn't store the token in the keychain: %v\n", err)
+			os.Exit(1)
+		}
+		if keyring.DefaultAccount() == "" {
+			keyring.SetDefaultAccount(login)
+		}
+		fmt.Printf("✓ Stored a new %s for %s\n", info.Kind, login)
+
+		if err := auth.RevokeToken(ctx, old); err != nil {
+			fmt.Printf("Warning: couldn't revoke the old token: %v\n", err)
+			fmt.Println("  Revoke it at https://github.com/settings/applications or https://github.com/settings/tokens")
+			return
+		}
+		fmt.Println("✓ Revoked the old token")
+	},
+}
+
+// storeToken keeps token as login's in the keychain and makes login the
+// default account
+func storeToken(login, token string) error {
+	if err := keyring.SetAccountToken(login, token); err != nil {
+		return err
+	}
+	return keyring.SetDefaultAccount(login)
+}
+
+// migrateTokens moves tokens kept under the keychain item names of older
+// versions of pact to the account-scoped ones, asking GitHub whose the old
+// default token is when no stored account's token matches it
+func migrateTokens() {
+	if !keyring.HasLegacyToken() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	keyring.Migrate(func(token string) (string, error) {
+		return githubLogin(ctx, token)
+	})
+}
+
+// signIn runs GitHub's device flow in the browser and returns the new token,
+// checked to have the scopes pact needs
+func signIn(ctx context.Context) (string, *auth.TokenInfo, error) {
+	fmt.Println("Authenticating with GitHub...")
+	fmt.Println()
+
+	deviceCode, err := auth.RequestDeviceCode(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fmt.Printf("Please visit: %s\n", deviceCode.VerificationURI)
+	fmt.Printf("And enter code: %s\n", deviceCode.UserCode)
+	fmt.Println()
+	fmt.Println("Waiting for authorization...")
+
+	// Try to open browser
+	browser.OpenURL(deviceCode.VerificationURI)
+
+	token, err := auth.PollForToken(ctx, deviceCode.DeviceCode, deviceCode.Interval)
+	if err != nil {
+		return "", nil, err
+	}
+	info, err := checkToken(ctx, token)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, info, nil
+}
+
+// checkToken asks GitHub about token and fails if it lacks a scope pact
+// can't work without
+func checkToken(ctx context.Context, token string) (*auth.TokenInfo, error) {
+	info, err := auth.CheckToken(ctx, token)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 401") {
+			return nil, fmt.Errorf("GitHub doesn't accept the token")
+		}
+		return nil, err
+	}
+	if missing := info.MissingScopes(); len(missing) > 0 {
+		return nil, fmt.Errorf("the token lacks the %s scope pact needs for the pact repo", strings.Join(missing, ", "))
+	}
+	return info, nil
+}
+
+// tokenWarnings points out access the token has that pact doesn't need, and
+// what the ssh module can't do without
+func tokenWarnings(info *auth.TokenInfo) []string {
+	var lines []string
+	if extra := info.ExtraScopes(); len(extra) > 0 {
+		lines = append(lines, fmt.Sprintf("! Scopes pact doesn't use: %s", strings.Join(extra, ", ")))
+	}
+	if info.HasScope("repo") {
+		lines = append(lines, "→ The repo scope reaches every repo you can. A fine-grained token for my-pact")
+		lines = append(lines, "  only is narrower: create one and run 'pact auth login --with-token'.")
+	}
+	if info.HasScopes() && !info.HasScope("write:public_key") {
+		lines = append(lines, "○ Without write:public_key, ssh.github can't upload your SSH key")
+	}
+	return lines
+}
+
+// pactRepoPath is the pact repo's owner and name on GitHub: origin's, once
+// pact is set up, else login's my-pact. owner is "" for a repo elsewhere.
+func pactRepoPath(login string) (owner, name string) {
+	if pactDir, err := config.GetPactDir(); err == nil {
+		if remote, err := git.RemoteURL(pactDir); err == nil {
+			if !git.IsGitHub(remote) {
+				return "", ""
+			}
+			return git.RepoPath(remote)
+		}
+	}
+	return login, "my-pact"
+}
+
+// repoAccess says whether the token can push to the pact repo
+func repoAccess(ctx context.Context, token, owner, name string) string {
+	if name != "my-pact" {
+		return "not checked (pact only checks repos named my-pact)"
+	}
+	repo, err := auth.GetRepo(ctx, token, owner)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("couldn't check (%v)", err)
+	case repo == nil:
+		return "not found, or the token can't see it"
+	case !repo.Permissions.Push:
+		return "read-only; the token needs Contents set to \"Read and write\""
+	}
+	return "can push"
+}
+
+func init() {
+	authLoginCmd.Flags().BoolVar(&authWithToken, "with-token", false, "Store a token read from stdin instead of signing in through the browser")
+	authCmd.AddCommand(authLoginCmd)
+	authSwitchCmd.Flags().BoolVar(&authWorkspace, "workspace", false, "Bind only this pact workspace to the account")
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authSwitchCmd)
+	authRotateCmd.Flags().BoolVar(&authWithToken, "with-token", false, "Store a new token read from stdin instead of signing in through the browser")
+	authCmd.AddCommand(authRotateCmd)
+	rootCmd.AddCommand(authCmd)
+}
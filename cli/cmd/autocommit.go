@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var (
+	autoCommitSchedule   bool
+	autoCommitUnschedule bool
+	autoCommitAt         string
+	autoCommitDir        string
+)
+
+// autoCommitTask names the scheduled job, as a crontab comment or a Windows
+// scheduled task
+const autoCommitTask = "pact-autocommit"
+
+var autoCommitCmd = &cobra.Command{
+	Use:   "autocommit",
+	Short: "Commit local .pact changes so edits aren't lost",
+	Long: `Commit local changes in .pact/ with a generated message, without pushing
+them to your pact repo. Turn it on in pact.json:
+
+  "settings": {"autoCommit": true}
+
+or push each auto-commit to a draft branch, leaving your main branch alone:
+
+  "settings": {"autoCommit": {"branch": "pact-drafts"}}
+
+--schedule runs it daily for this workspace (cron on macOS and Linux, a
+scheduled task on Windows); --unschedule stops that. 'pact push' pushes the
+commits as usual.
+
+Examples:
+  pact autocommit
+  pact autocommit --schedule --at 18:00
+  pact autocommit --unschedule`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if autoCommitDir != "" {
+			// Scheduled runs start outside the workspace
+			if err := os.Chdir(autoCommitDir); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if autoCommitUnschedule {
+			if err := unscheduleAutoCommit(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("✓ Daily auto-commit removed")
+			return
+		}
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		enabled, branch := autoCommitSetting(cfg)
+		if !enabled {
+			fmt.Println("Auto-commit is off. Set settings.autoCommit in pact.json to turn it on.")
+			return
+		}
+
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if autoCommitSchedule {
+			if err := scheduleAutoCommit(autoCommitAt, filepath.Dir(pactDir)); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ pact autocommit will run daily at %s\n", autoCommitAt)
+			return
+		}
+
+		changed, err := git.ChangedFiles(pactDir)
+		if err != nil {
+			fmt.Printf("Error checking for changes: %v\n", err)
+			os.Exit(1)
+		}
+		churn := churnOnly(pactDir, changed)
+		if len(changed) == len(churn) {
+			fmt.Println("No changes to commit.")
+			return
+		}
+
+		message := autoCommitMessage(changed, churn)
+		committed, err := git.Commit(pactDir, message, withoutChurn(pactDir, changed), churn...)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !committed {
+			fmt.Println("No changes to commit.")
+			return
+		}
+		fmt.Printf("✓ %s\n", message)
+
+		if branch == "" {
+			return
+		}
+		token, err := remoteToken(pactDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := checkRepoPolicy(cmd.Context(), loadPolicy(), pactDir, token); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := git.PushBranch(cmd.Context(), token, pactDir, branch); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Pushed to %s\n", branch)
+	},
+}
+
+// autoCommitSetting reads settings.autoCommit: true, or an object with an
+// optional draft branch to push to
+func autoCommitSetting(cfg *config.PactConfig) (enabled bool, branch string) {
+	switch v := cfg.Get("settings.autoCommit").(type) {
+	case bool:
+		return v, ""
+	case map[string]any:
+		if on, ok := v["enabled"].(bool); ok && !on {
+			return false, ""
+		}
+		branch, _ := v["branch"].(string)
+		return true, branch
+	}
+	return false, ""
+}
+
+// autoCommitMessage names the changed files, leaving out the churn
+func autoCommitMessage(changed, churn []string) string {
+	skipped := make(map[string]bool)
+	for _, p := range churn {
+		skipped[p] = true
+	}
+	var files []string
+	for _, p := range changed {
+		if !skipped[p] {
+			files = append(files, p)
+		}
+	}
+
+	summary := strings.Join(files, ", ")
+	if len(files) > 3 {
+		summary = fmt.Sprintf("%s and %d more", strings.Join(files[:3], ", "), len(files)-3)
+	}
+	return fmt.Sprintf("Auto-commit %s: %s", time.Now().Format("2006-01-02"), summary)
+}
+
+// scheduleAutoCommit runs 'pact autocommit' daily at a "15:04" time in the
+// workspace holding .pact
+func scheduleAutoCommit(at, workspace string) error {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("--at should be a time like 18:00")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if runtime.GOOS == "windows" {
+		cmd := exec.Command("schtasks", "/Create", "/SC", "DAILY", "/TN", autoCommitTask,
+			"/TR", fmt.Sprintf(`"%s" autocommit --dir "%s"`, exe, workspace), "/ST", t.Format("15:04"), "/F")
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("schtasks failed: %s", strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+
+	lines, err := crontabWithout(autoCommitTask)
+	if err != nil {
+		return err
+	}
+	lines = append(lines, fmt.Sprintf("%d %d * * * %s autocommit --dir %s # %s", t.Minute(), t.Hour(), shellQuote(exe), shellQuote(workspace), autoCommitTask))
+	return writeCrontab(lines)
+}
+
+// unscheduleAutoCommit removes the daily job, if there is one
+func unscheduleAutoCommit() error {
+	if runtime.GOOS == "windows" {
+		if exec.Command("schtasks", "/Query", "/TN", autoCommitTask).Run() != nil {
+			return nil
+		}
+		if output, err := exec.Command("schtasks", "/Delete", "/TN", autoCommitTask, "/F").CombinedOutput(); err != nil {
+			return fmt.Errorf("schtasks failed: %s", strings.TrimSpace(string(output)))
+		}
+		return nil
+	}
+	lines, err := crontabWithout(autoCommitTask)
+	if err != nil {
+		return err
+	}
+	return writeCrontab(lines)
+}
+
+// crontabWithout returns the user's crontab lines, minus those tagged with
+// the task's comment. Only a missing crontab reads as empty; any other
+// failure is an error, so the crontab is never replaced by a partial one.
+func crontabWithout(task string) ([]string, error) {
+	cmd := exec.Command("crontab", "-l")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		if _, exited := err.(*exec.ExitError); exited && strings.Contains(strings.ToLower(stderr.String()), "no crontab") {
+			return nil, nil
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("can't read your crontab: %s", msg)
+		}
+		return nil, fmt.Errorf("can't read your crontab: %v", err)
+	}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if !strings.HasSuffix(line, "# "+task) {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeCrontab(lines []string) error {
+	if _, err := exec.LookPath("crontab"); err != nil {
+		return fmt.Errorf("crontab isn't available; run 'pact autocommit' daily with your system's scheduler")
+	}
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("crontab failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func init() {
+	autoCommitCmd.Flags().BoolVar(&autoCommitSchedule, "schedule", false, "Run pact autocommit daily")
+	autoCommitCmd.Flags().BoolVar(&autoCommitUnschedule, "unschedule", false, "Stop running pact autocommit daily")
+	autoCommitCmd.Flags().StringVar(&autoCommitAt, "at", "18:00", "Time of day for --schedule")
+	autoCommitCmd.Flags().StringVar(&autoCommitDir, "dir", "", "Workspace to commit in, instead of the current directory")
+	rootCmd.AddCommand(autoCommitCmd)
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var changelogDays int
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Show a timeline of environment changes",
+	Long: `Show a day-by-day timeline of environment changes, built from the
+pact.json history in your pact repo and what was installed on this machine.
+
+Examples:
+  pact changelog           # Last 20 days with changes
+  pact changelog --days 0  # Everything`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		versions, err := git.FileHistory(pactDir, "pact.json")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		days := make(map[string]*changelogDay)
+		addChange := func(when time.Time, change string) {
+			key := when.Format("2006-01-02")
+			day, ok := days[key]
+			if !ok {
+				day = &changelogDay{date: when}
+				days[key] = day
+			}
+			day.changes = append(day.changes, change)
+		}
+
+		var previous *config.PactConfig
+		for _, version := range versions {
+			var current *config.PactConfig
+			if version.Content != nil {
+				current, err = config.Parse(version.Content)
+				if err != nil {
+					// Skip commits with a broken pact.json, diffing past them
+					continue
+				}
+			}
+			if previous == nil && current != nil {
+				addChange(version.When, "created pact.json")
+			} else {
+				for _, change := range config.Changes(previous, current) {
+					addChange(version.When, change)
+				}
+			}
+			previous = current
+		}
+
+		if journal, err := state.LoadJournal(); err == nil {
+			for _, entry := range journal.Entries {
+				addChange(entry.InstalledAt, fmt.Sprintf("installed %s here", entry.Name))
+			}
+		}
+
+		var uncommitted []string
+		if cfg, err := config.Load(); err == nil {
+			uncommitted = config.Changes(previous, cfg)
+		}
+
+		sorted := make([]*changelogDay, 0, len(days))
+		for _, day := range days {
+			sorted = append(sorted, day)
+		}
+		sort.Slice(sorted, func(i, j int) bool {
+			return sorted[i].date.After(sorted[j].date)
+		})
+		if changelogDays > 0 && len(sorted) > changelogDays {
+			sorted = sorted[:changelogDays]
+		}
+
+		if len(sorted) == 0 && len(uncommitted) == 0 {
+			fmt.Println("No changes recorded yet.")
+			return
+		}
+
+		if len(uncommitted) > 0 {
+			fmt.Printf("Uncommitted: %s\n", strings.Join(uncommitted, ", "))
+		}
+		now := time.Now()
+		for _, day := range sorted {
+			label := day.date.Format("Jan 2")
+			if day.date.Year() != now.Year() {
+				label = day.date.Format("Jan 2, 2006")
+			}
+			fmt.Printf("%s: %s\n", label, strings.Join(day.changes, ", "))
+		}
+	},
+}
+
+type changelogDay struct {
+	date    time.Time
+	changes []string
+}
+
+func init() {
+	changelogCmd.Flags().IntVar(&changelogDays, "days", 20, "Number of days with changes to show (0 for all)")
+	rootCmd.AddCommand(changelogCmd)
+}
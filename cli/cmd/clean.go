@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanDryRun   bool
+	cleanKeepDays int
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove orphaned artifacts pact left behind",
+	Long: `Find and remove artifacts pact created but no longer references:
+  - prompt theme files no longer in pact.json
+  - backups older than the retention window
+  - install journal entries whose files are gone
+  - pact.exe.old left behind by 'pact update'
+  - temp downloads
+
+Examples:
+  pact clean --dry-run       # Show what would be removed
+  pact clean --keep-days 7   # Also remove backups older than a week`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized.")
+			return
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		artifacts, err := apply.FindArtifacts(cfg, time.Duration(cleanKeepDays)*24*time.Hour)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(artifacts) == 0 {
+			fmt.Println("✓ Nothing to clean")
+			return
+		}
+
+		var total int64
+		for _, a := range artifacts {
+			size := ""
+			if a.Kind != "journal" {
+				size = formatSize(a.Size)
+			}
+			fmt.Printf("  %-11s %-9s %s (%s)\n", a.Kind, size, a.Path, a.Reason)
+			total += a.Size
+		}
+		fmt.Printf("\n%d artifacts, %s\n", len(artifacts), formatSize(total))
+
+		if cleanDryRun {
+			fmt.Println("Dry run - nothing removed.")
+			return
+		}
+
+		freed, errs := apply.RemoveArtifacts(artifacts)
+		for _, err := range errs {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+		fmt.Printf("✓ Freed %s\n", formatSize(freed))
+	},
+}
+
+// formatSize renders a byte count as B/KB/MB/GB
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
+
+func init() {
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Show what would be removed without removing it")
+	cleanCmd.Flags().IntVar(&cleanKeepDays, "keep-days", 30, "Keep backups newer than this many days")
+	rootCmd.AddCommand(cleanCmd)
+}
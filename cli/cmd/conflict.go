@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	psync "github.com/cloudboy-jh/pact/internal/sync"
+	"github.com/cloudboy-jh/pact/internal/ui"
+)
+
+// onConflict returns what to do with files that differ from pact, from
+// --on-conflict or settings.onConflict; empty means ask
+func onConflict(cfg *config.PactConfig) (string, error) {
+	choice := syncOnConflict
+	if choice == "" {
+		choice = cfg.GetString("settings.onConflict")
+	}
+	if choice != "" && !containsString(apply.ConflictChoices, choice) {
+		return "", fmt.Errorf("unknown conflict choice '%s' (use %s)", choice, strings.Join(apply.ConflictChoices, ", "))
+	}
+	return choice, nil
+}
+
+// promptConflict shows how a target differs from its pact source and asks
+// what to do with it. Quitting keeps the file.
+func promptConflict(c apply.Conflict) string {
+	result, err := tea.NewProgram(newConflictModel(c), ui.Keys().ProgramOptions(tea.WithAltScreen())...).Run()
+	if err != nil {
+		fmt.Printf("Warning: %v; backing up %s\n", err, c.Item.Target)
+		return apply.ConflictBackup
+	}
+	choice := result.(conflictModel).choice
+	fmt.Printf("%s/%s: %s\n", c.Item.Module, c.Item.Name, choice)
+	return choice
+}
+
+// editMerge opens a merge with conflict markers in $EDITOR and returns what
+// the user saved
+func editMerge(item config.SyncItem, merged string) (string, error) {
+	f, err := os.CreateTemp("", "pact-merge-*"+filepath.Ext(item.Target))
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(merged); err != nil {
+		f.Close()
+		return "", err
+	}
+	f.Close()
+
+	fmt.Printf("Resolve the conflicts in %s, then save and close it\n", filepath.Base(item.Target))
+	if err := openInEditor(f.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(f.Name())
+	return string(data), err
+}
+
+var conflictKeys = struct {
+	Next, Prev, Keep, Overwrite, Merge, Backup, Quit key.Binding
+}{
+	Next:      key.NewBinding(key.WithKeys("right", "tab", "l")),
+	Prev:      key.NewBinding(key.WithKeys("left", "shift+tab", "h")),
+	Keep:      key.NewBinding(key.WithKeys("k")),
+	Overwrite: key.NewBinding(key.WithKeys("o")),
+	Merge:     key.NewBinding(key.WithKeys("m")),
+	Backup:    key.NewBinding(key.WithKeys("b")),
+	Quit:      key.NewBinding(key.WithKeys("q", "esc", "ctrl+c")),
+}
+
+// conflictModel is the three-way diff viewer: what changed here and in pact
+// since the last sync, and how they'd merge
+type conflictModel struct {
+	conflict apply.Conflict
+	tabs     []string
+	bodies   []string
+	tab      int
+	view     viewport.Model
+	ready    bool
+	choice   string
+}
+
+func newConflictModel(c apply.Conflict) conflictModel {
+	m := conflictModel{conflict: c}
+	switch {
+	case !c.Text():
+		m.tabs = []string{"Conflict"}
+		m.bodies = []string{dimStyle.Render("  A directory or binary file; there's no diff to show.")}
+	case c.Base == nil:
+		m.tabs = []string{"Here → pact", "Merge"}
+		m.bodies = []string{diffOrNone(string(c.Local), string(c.Pact), "the same as pact")}
+	default:
+		m.tabs = []string{"Changed here", "Changed in pact", "Merge"}
+		m.bodies = []string{
+			diffOrNone(string(c.Base), string(c.Local), "no changes here since the last sync"),
+			diffOrNone(string(c.Base), string(c.Pact), "no changes in pact since the last sync"),
+		}
+	}
+	if c.Text() {
+		merged, conflicts := c.Merge()
+		var b strings.Builder
+		if conflicts > 0 {
+			b.WriteString(removedLineStyle.Render(fmt.Sprintf("  %d conflicting change(s); merging opens them in %s", conflicts, getEditor())) + "\n\n")
+		} else {
+			b.WriteString(addedLineStyle.Render("  Merges cleanly") + "\n\n")
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(merged, "\n"), "\n") {
+			text := "  " + line
+			switch strings.TrimRight(line, "\r") {
+			case psync.MarkerLocal, psync.MarkerBase, psync.MarkerSep, psync.MarkerPact:
+				text = hunkStyle.Render(text)
+			}
+			b.WriteString(text + "\n")
+		}
+		m.bodies = append(m.bodies, b.String())
+	}
+	return m
+}
+
+func diffOrNone(old, new, none string) string {
+	if old == new {
+		return dimStyle.Render("  "+none) + "\n"
+	}
+	return lineDiff(old, new)
+}
+
+func (m conflictModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m conflictModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Header and footer take four lines
+		if !m.ready {
+			m.view = viewport.New(msg.Width, max(msg.Height-4, 1))
+			m.view.KeyMap = viewport.KeyMap{
+				Up:           ui.Keys().Up,
+				Down:         ui.Keys().Down,
+				PageUp:       key.NewBinding(key.WithKeys("pgup")),
+				PageDown:     key.NewBinding(key.WithKeys("pgdown", " ")),
+				HalfPageUp:   key.NewBinding(key.WithKeys("ctrl+u")),
+				HalfPageDown: key.NewBinding(key.WithKeys("ctrl+d")),
+			}
+			m.view.SetContent(m.bodies[m.tab])
+			m.ready = true
+		} else {
+			m.view.Width = msg.Width
+			m.view.Height = max(msg.Height-4, 1)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, conflictKeys.Keep), key.Matches(msg, conflictKeys.Quit):
+			m.choice = apply.ConflictKeep
+			return m, tea.Quit
+		case key.Matches(msg, conflictKeys.Overwrite):
+			m.choice = apply.ConflictOverwrite
+			return m, tea.Quit
+		case key.Matches(msg, conflictKeys.Backup):
+			m.choice = apply.ConflictBackup
+			return m, tea.Quit
+		case key.Matches(msg, conflictKeys.Merge):
+			if m.conflict.Text() {
+				m.choice = apply.ConflictMerge
+				return m, tea.Quit
+			}
+			return m, nil
+		case key.Matches(msg, conflictKeys.Next):
+			m.tab = (m.tab + 1) % len(m.tabs)
+			m.view.SetContent(m.bodies[m.tab])
+			m.view.GotoTop()
+			return m, nil
+		case key.Matches(msg, conflictKeys.Prev):
+			m.tab = (m.tab + len(m.tabs) - 1) % len(m.tabs)
+			m.view.SetContent(m.bodies[m.tab])
+			m.view.GotoTop()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.view, cmd = m.view.Update(msg)
+	return m, cmd
+}
+
+func (m conflictModel) View() string {
+	if !m.ready {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(moduleStyle.Render(fmt.Sprintf("%s differs from pact (%s/%s)", homeRelative(m.conflict.Item.Target), m.conflict.Item.Module, m.conflict.Item.Name)))
+	b.WriteString("\n")
+	for i, tab := range m.tabs {
+		if i == m.tab {
+			b.WriteString(syncedStyle.Render("[" + tab + "]"))
+		} else {
+			b.WriteString(dimStyle.Render(" " + tab + " "))
+		}
+		b.WriteString(" ")
+	}
+	b.WriteString("\n")
+	b.WriteString(m.view.View())
+	b.WriteString("\n")
+	help := "  ←/→: view  " + navLabel() + ": scroll  k: keep  o: overwrite  b: back up and replace"
+	if m.conflict.Text() {
+		help += "  m: merge"
+	}
+	b.WriteString(dimStyle.Render(help))
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// cancelOnInterrupt returns a context cancelled by the first Ctrl+C, so the
+// running step is stopped and later steps are skipped while pact still saves
+// what it did. A second Ctrl+C quits immediately.
+func cancelOnInterrupt(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			fmt.Fprintln(os.Stderr, "\nStopping... (press Ctrl+C again to quit now)")
+			signal.Stop(signals)
+			cancel()
+		case <-ctx.Done():
+			signal.Stop(signals)
+		}
+	}()
+	return ctx, cancel
+}
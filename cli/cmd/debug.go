@@ -22,7 +22,7 @@ var debugCmd = &cobra.Command{
 		fmt.Printf("terminal.font = %q\n", font)
 
 		// Test applying terminal
-		results, _ := apply.ApplyModule(cfg, "terminal")
+		results, _ := apply.ApplyModule(cfg, "terminal", apply.Options{})
 		fmt.Printf("Results: %d\n", len(results))
 		for _, r := range results {
 			fmt.Printf("  %s/%s: success=%v skipped=%v msg=%s err=%v\n",
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffJSON    bool
+	diffModules []string
+	diffInstall bool
+)
+
+// diffReport is the output of `pact diff --json`
+type diffReport struct {
+	Drift   bool                `json:"drift"`
+	Modules []detect.DiffResult `json:"modules"`
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show where this machine drifts from pact.json",
+	Long: `Scan this machine and compare it against pact.json: tools, shell, git
+settings, editor, keybindings, snippets, LLM setup, App Store apps, and
+secrets that are installed here but not in pact.json, or in pact.json but
+missing here.
+
+Nothing is changed unless --install is given, which offers to install
+what's in pact.json but missing here, picking items one by one. Exits 0
+when the machine matched, 1 when it drifted, and 2 on errors, so it can
+run from cron or CI.
+
+Examples:
+  pact diff
+  pact diff --module cli --module git
+  pact diff --json
+  pact diff --install`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(2)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(2)
+		}
+
+		detected := detect.Scan(detect.ScanOptions{Modules: diffModules, Context: cmd.Context(), Secrets: cfg.GetSecrets()})
+		if cmd.Context().Err() != nil {
+			os.Exit(2)
+		}
+		for i := range detected.Secrets {
+			detected.Secrets[i].InKeychain = keyring.HasSecret(detected.Secrets[i].Name)
+		}
+
+		// Compare reports every module in pact.json; skip ones not scanned
+		report := diffReport{Modules: []detect.DiffResult{}}
+		for _, d := range detect.Drift(withoutIgnored(cfg, detect.Compare(detected, cfg))) {
+			if len(diffModules) == 0 || containsString(diffModules, d.Module) {
+				report.Modules = append(report.Modules, d)
+			}
+		}
+		report.Drift = len(report.Modules) > 0
+
+		if diffJSON {
+			output, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(2)
+			}
+			fmt.Println(string(output))
+		} else {
+			missing := missingItems(cfg, report.Modules)
+			renderDrift(report.Modules, installPlans(missing))
+			if diffInstall {
+				offerInstalls(cmd.Context(), cfg, missing, false)
+			}
+		}
+
+		if report.Drift {
+			os.Exit(1)
+		}
+	},
+}
+
+func renderDrift(drift []detect.DiffResult, plans map[string]string) {
+	if len(drift) == 0 {
+		fmt.Println("✓ This machine matches pact.json")
+		return
+	}
+
+	items := 0
+	for _, d := range drift {
+		fmt.Println(moduleStyle.Render(d.Module))
+		for _, item := range d.LocalOnly {
+			fmt.Printf("  %s %-24s %s\n", localOnlyStyle.Render("○"), item.Name, dimStyle.Render(strings.TrimSpace("here, not in pact.json "+formatValue(item.Value))))
+		}
+		for _, item := range d.PactOnly {
+			fmt.Printf("  %s %-24s %s\n", pactOnlyStyle.Render("✗"), item.Name, dimStyle.Render(strings.TrimSpace("in pact.json, missing here "+formatValue(item.Value))))
+			if plan, ok := plans[d.Module+"/"+item.Type+"/"+item.Name]; ok {
+				fmt.Printf("    %s\n", dimStyle.Render("→ "+plan))
+			}
+		}
+		items += len(d.LocalOnly) + len(d.PactOnly)
+	}
+	fmt.Println()
+	fmt.Printf("%d difference(s) in %d module(s). Run 'pact sync' or 'pact diff --install' to install what's missing, or 'pact read' to import what's new.\n", items, len(drift))
+}
+
+func init() {
+	diffCmd.Flags().BoolVar(&diffJSON, "json", false, "Output the drift as JSON")
+	diffCmd.Flags().BoolVar(&diffInstall, "install", false, "Offer to install what's in pact.json but missing here")
+	diffCmd.Flags().StringSliceVarP(&diffModules, "module", "m", nil, "Only compare these modules (repeat or comma-separate)")
+	rootCmd.AddCommand(diffCmd)
+}
@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/spf13/cobra"
+)
+
+// doctorCheck is one diagnostic and, when it fails, how to fix it
+type doctorCheck struct {
+	Name   string
+	Status string // "ok", "warn", "fail"
+	Detail string
+	Fix    string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check pact's setup and suggest fixes",
+	Long: `Run diagnostics on pact's setup: pact.json structure, keychain access,
+the GitHub token, package managers, file sources in the pact repo, and
+symlinks left broken by earlier syncs. Each problem comes with a fix.
+
+Exits non-zero when a check fails.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := runDoctor(cmd.Context())
+
+		failed := 0
+		for _, c := range checks {
+			icon := "✓"
+			switch c.Status {
+			case "warn":
+				icon = "○"
+			case "fail":
+				icon = "✗"
+				failed++
+			}
+			fmt.Printf("  %s %-18s %s\n", icon, c.Name, c.Detail)
+			if c.Fix != "" {
+				fmt.Printf("    → %s\n", c.Fix)
+			}
+		}
+		fmt.Println()
+
+		if failed > 0 {
+			fmt.Printf("%d problem(s) found.\n", failed)
+			os.Exit(1)
+		}
+		fmt.Println("No problems found.")
+	},
+}
+
+func runDoctor(ctx context.Context) []doctorCheck {
+	var checks []doctorCheck
+
+	cfg, cfgChecks := doctorConfig()
+	checks = append(checks, cfgChecks...)
+	checks = append(checks, doctorKeyring(), doctorToken(ctx), doctorPackageManager())
+	if cfg != nil {
+		checks = append(checks, doctorFiles(cfg)...)
+	}
+	checks = append(checks, doctorInstalledPaths())
+	return checks
+}
+
+func doctorConfig() (*config.PactConfig, []doctorCheck) {
+	if !config.Exists() {
+		return nil, []doctorCheck{{
+			Name:   "pact.json",
+			Status: "fail",
+			Detail: "pact is not initialized",
+			Fix:    "run 'pact init'",
+		}}
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, []doctorCheck{{
+			Name:   "pact.json",
+			Status: "fail",
+			Detail: err.Error(),
+			Fix:    "fix the JSON with 'pact edit'",
+		}}
+	}
+
+	problems := cfg.Validate()
+	if len(problems) == 0 {
+		return cfg, []doctorCheck{{Name: "pact.json", Status: "ok", Detail: fmt.Sprintf("valid, %d module(s)", len(cfg.GetModules()))}}
+	}
+
+	var checks []doctorCheck
+	for _, p := range problems {
+		checks = append(checks, doctorCheck{
+			Name:   "pact.json",
+			Status: "fail",
+			Detail: p,
+			Fix:    "fix the entry with 'pact edit'; it is skipped on sync until then",
+		})
+	}
+	return cfg, checks
+}
+
+// doctorKeyring writes and removes a probe secret to prove the keychain works
+func doctorKeyring() doctorCheck {
+	check := doctorCheck{Name: "Keychain"}
+	const probe = "pact-doctor-probe"
+	if err := keyring.SetSecret(probe, "ok"); err != nil {
+		check.Status = "fail"
+		check.Detail = fmt.Sprintf("%s: %v", keyring.Backend(), err)
+		check.Fix = "unlock the keychain, or on Linux start a Secret Service provider (gnome-keyring, KeePassXC)"
+		return check
+	}
+	keyring.DeleteSecret(probe)
+	check.Status = "ok"
+	check.Detail = keyring.Backend()
+	return check
+}
+
+func doctorToken(ctx context.Context) doctorCheck {
+	check := doctorCheck{Name: "GitHub token"}
+	if pactDir, err := config.GetPactDir(); err == nil {
+		if remote, err := git.RemoteURL(pactDir); err == nil && (git.IsSSH(remote) || !git.IsGitHub(remote)) {
+			check.Status = "ok"
+			check.Detail = "not needed for " + remote
+			return check
+		}
+	}
+	token, err := getToken()
+	if err != nil {
+		check.Status = "fail"
+		check.Detail = "no token in keychain or environment"
+		check.Fix = "run 'pact init' to sign in"
+		return check
+	}
+
+	info, err := auth.CheckToken(ctx, token)
+	if err != nil {
+		if strings.Contains(err.Error(), "status 401") {
+			check.Status = "fail"
+			check.Detail = "token is invalid or was revoked"
+			check.Fix = "run 'pact auth login' to sign in again"
+		} else {
+			check.Status = "warn"
+			check.Detail = fmt.Sprintf("couldn't reach GitHub: %v", err)
+		}
+		return check
+	}
+	if missing := info.MissingScopes(); len(missing) > 0 {
+		check.Status = "fail"
+		check.Detail = fmt.Sprintf("signed in as %s, but the token lacks the %s scope", info.Login, strings.Join(missing, ", "))
+		check.Fix = "run 'pact auth login' to sign in again"
+		return check
+	}
+	if extra := info.ExtraScopes(); len(extra) > 0 {
+		check.Status = "warn"
+		check.Detail = fmt.Sprintf("signed in as %s; the token also has %s, which pact doesn't use", info.Login, strings.Join(extra, ", "))
+		check.Fix = "see 'pact auth status' for a narrower token"
+		return check
+	}
+	check.Status = "ok"
+	check.Detail = "signed in as " + info.Login
+	return check
+}
+
+func doctorPackageManager() doctorCheck {
+	check := doctorCheck{Name: "Package manager"}
+	managers := apply.PackageManagers()
+	if len(managers) == 0 {
+		check.Status = "fail"
+		check.Detail = "none found; CLI tools and apps can't be installed"
+		if b := apply.PackageManagerBootstrap(); b != nil {
+			check.Fix = "install " + b.Name + ": " + b.CommandLine()
+		}
+		return check
+	}
+	if b := apply.PackageManagerBootstrap(); b != nil {
+		check.Status = "warn"
+		check.Detail = fmt.Sprintf("%s (%s)", strings.Join(managers, ", "), b.Reason)
+		check.Fix = b.CommandLine()
+		return check
+	}
+	check.Status = "ok"
+	check.Detail = strings.Join(managers, ", ")
+	return check
+}
+
+// doctorFiles checks that file sources exist in the pact repo and that
+// synced symlinks still point somewhere
+func doctorFiles(cfg *config.PactConfig) []doctorCheck {
+	items, err := cfg.GetSyncItems()
+	if err != nil {
+		return []doctorCheck{{Name: "Files", Status: "fail", Detail: err.Error()}}
+	}
+
+	var checks []doctorCheck
+	for _, item := range items {
+		name := item.Module + "/" + item.Name
+		if _, err := os.Stat(item.Source); err != nil {
+			checks = append(checks, doctorCheck{
+				Name:   "Files",
+				Status: "fail",
+				Detail: fmt.Sprintf("%s: source %s is missing", name, item.Source),
+				Fix:    "add the file to the pact repo or remove the entry from pact.json",
+			})
+			continue
+		}
+		if brokenSymlink(item.Target) {
+			checks = append(checks, doctorCheck{
+				Name:   "Files",
+				Status: "fail",
+				Detail: fmt.Sprintf("%s: %s is a broken symlink", name, item.Target),
+				Fix:    "run 'pact sync " + item.Module + "' to relink it",
+			})
+		}
+	}
+
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{Name: "Files", Status: "ok", Detail: fmt.Sprintf("%d synced files found", len(items))})
+	}
+	return checks
+}
+
+// doctorInstalledPaths checks files pact placed directly (binaries, fonts)
+func doctorInstalledPaths() doctorCheck {
+	check := doctorCheck{Name: "Installed files"}
+	journal, err := state.LoadJournal()
+	if err != nil {
+		check.Status = "warn"
+		check.Detail = err.Error()
+		return check
+	}
+
+	var missing []string
+	for _, e := range journal.Entries {
+		for _, p := range e.Paths {
+			if _, err := os.Stat(p); err != nil {
+				missing = append(missing, filepath.Base(p))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		check.Status = "warn"
+		check.Detail = "missing: " + strings.Join(missing, ", ")
+		check.Fix = "run 'pact sync' to reinstall them"
+		return check
+	}
+	check.Status = "ok"
+	check.Detail = fmt.Sprintf("%d installs recorded", len(journal.Entries))
+	return check
+}
+
+func brokenSymlink(p string) bool {
+	info, err := os.Lstat(p)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return false
+	}
+	_, err = os.Stat(p)
+	return err != nil
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
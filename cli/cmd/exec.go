@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/spf13/cobra"
+)
+
+var execProject string
+
+var execCmd = &cobra.Command{
+	Use:   "exec [--project <name>] -- <command> [args...]",
+	Short: "Run a command with secrets from the keychain in its environment",
+	Long: `Run a command with secrets injected as environment variables.
+
+Without --project, the global secrets from pact.json are injected. With
+--project, only that project's secrets are injected, so a project never
+sees another project's keys.
+
+Examples:
+  pact exec -- npm run dev
+  pact exec --project acme -- ./deploy.sh`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		env := os.Environ()
+		if execProject != "" {
+			names := cfg.GetProjectSecrets(execProject)
+			if names == nil {
+				fmt.Printf("Error: no secrets configured for project '%s'\n", execProject)
+				os.Exit(1)
+			}
+			for _, name := range names {
+				value, err := keyring.GetProjectSecret(execProject, name)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %s is not set (pact secret set %s --project %s)\n", name, name, execProject)
+					continue
+				}
+				env = append(env, name+"="+value)
+			}
+		} else {
+			for _, name := range cfg.GetSecrets() {
+				value, err := keyring.GetSecret(name)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %s is not set (pact secret set %s)\n", name, name)
+					continue
+				}
+				env = append(env, name+"="+value)
+			}
+		}
+
+		c := exec.Command(args[0], args[1:]...)
+		c.Env = env
+		c.Stdin = os.Stdin
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+
+		if err := c.Run(); err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			}
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	execCmd.Flags().StringVar(&execProject, "project", "", "Inject only this project's secrets")
+	rootCmd.AddCommand(execCmd)
+}
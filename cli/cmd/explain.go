@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [module[:item]]",
+	Short: "Show descriptions from pact.json",
+	Long: `Show the "description" fields in pact.json, so a shared pact documents
+itself.
+
+Modules and object items (files, custom sources) take a "description" key.
+Items in plain lists are described in the module's "descriptions" map.
+
+Examples:
+  pact explain              # Describe every module
+  pact explain cli          # Describe cli and its items
+  pact explain cli:jq       # Describe one item`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(args) == 0 {
+			for _, module := range cfg.GetModules() {
+				desc := cfg.ModuleDescription(module)
+				if desc == "" {
+					desc = "(no description)"
+				}
+				fmt.Printf("  %-12s %s\n", module, desc)
+			}
+			return
+		}
+
+		module, item, hasItem := strings.Cut(args[0], ":")
+		if !cfg.HasKey(module) {
+			fmt.Printf("Error: module '%s' not found in pact.json\n", module)
+			os.Exit(1)
+		}
+
+		if !hasItem {
+			fmt.Println(ui.RenderModuleDetail(cfg, module))
+			return
+		}
+
+		desc, ok := cfg.ItemDescriptions(module)[item]
+		if !ok {
+			fmt.Printf("%s has no description. Add one under %s.descriptions in pact.json.\n", args[0], module)
+			return
+		}
+		fmt.Printf("%s: %s\n", args[0], desc)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Modules that make sense inside a container - no GUI apps, fonts, or LLMs
+var devcontainerModules = []string{"cli", "shell", "git"}
+
+var (
+	exportModules []string
+	exportUser    string
+	exportImage   string
+	exportWrite   bool
+	exportCommand bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export pact setup for other environments",
+	Long:  `Generate configuration that reproduces your pact setup in other environments.`,
+}
+
+var exportDevcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Generate a devcontainer.json that installs pact and syncs",
+	Long: `Generate a devcontainer.json whose postCreateCommand installs pact, clones
+your my-pact repo, and runs a headless sync of container-safe modules
+(cli, shell, git) - so Codespaces match your local shell setup.
+
+The generated command authenticates with the GITHUB_TOKEN that Codespaces
+provides. For other devcontainer hosts, export PACT_GITHUB_TOKEN instead.
+
+Examples:
+  pact export devcontainer                   # Print devcontainer.json
+  pact export devcontainer --write           # Write .devcontainer/devcontainer.json
+  pact export devcontainer --command         # Print only the postCreateCommand
+  pact export devcontainer --modules cli,git # Limit synced modules`,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, m := range exportModules {
+			if !containsString(devcontainerModules, m) {
+				fmt.Printf("Error: module '%s' is not supported in devcontainers (supported: %s)\n",
+					m, strings.Join(devcontainerModules, ", "))
+				os.Exit(1)
+			}
+		}
+
+		user := exportUser
+		if user == "" && config.Exists() {
+			if cfg, err := config.Load(); err == nil {
+				user = cfg.GetString("name")
+			}
+		}
+		if user == "" {
+			fmt.Println("Error: could not determine your GitHub user. Pass --user <login>.")
+			os.Exit(1)
+		}
+
+		postCreate := buildDevcontainerCommand(user, exportModules)
+		if exportCommand {
+			fmt.Println(postCreate)
+			return
+		}
+
+		devcontainer := map[string]any{
+			"name":              "pact",
+			"image":             exportImage,
+			"postCreateCommand": postCreate,
+			"remoteEnv": map[string]string{
+				"PACT_GITHUB_TOKEN": "${localEnv:PACT_GITHUB_TOKEN}",
+			},
+		}
+
+		output, err := json.MarshalIndent(devcontainer, "", "  ")
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !exportWrite {
+			fmt.Println(string(output))
+			return
+		}
+
+		target := filepath.Join(".devcontainer", "devcontainer.json")
+		if _, err := os.Stat(target); err == nil {
+			fmt.Printf("%s already exists. Add this postCreateCommand to it instead:\n\n", target)
+			fmt.Println(postCreate)
+			os.Exit(1)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(target, append(output, '\n'), 0644); err != nil {
+			fmt.Printf("Error writing %s: %v\n", target, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Wrote %s\n", target)
+	},
+}
+
+// buildDevcontainerCommand builds the shell command that bootstraps pact in a
+// fresh container: install the binary, clone my-pact to ~/.pact, and sync.
+// The token goes to git in an auth header set through the environment for
+// the clone alone, so it's never saved in the remote URL or shown in logs.
+func buildDevcontainerCommand(user string, modules []string) string {
+	auth := `GIT_CONFIG_COUNT=1 GIT_CONFIG_KEY_0=http.https://github.com/.extraheader ` +
+		`GIT_CONFIG_VALUE_0="AUTHORIZATION: basic $(printf 'x-access-token:%s' "${PACT_GITHUB_TOKEN:-$GITHUB_TOKEN}" | base64 | tr -d '\n')"`
+	steps := []string{
+		"curl -fsSL https://pact-dev.com/install.sh | sh",
+		fmt.Sprintf(`%s git clone "https://github.com/%s/my-pact.git" "$HOME/.pact"`, auth, user),
+		"cd \"$HOME\" && pact sync " + strings.Join(modules, " "),
+	}
+	return strings.Join(steps, " && ")
+}
+
+func containsString(items []string, s string) bool {
+	for _, item := range items {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	exportDevcontainerCmd.Flags().StringSliceVar(&exportModules, "modules", devcontainerModules, "Modules to sync in the container")
+	exportDevcontainerCmd.Flags().StringVar(&exportUser, "user", "", "GitHub user owning my-pact (default: name from pact.json)")
+	exportDevcontainerCmd.Flags().StringVar(&exportImage, "image", "mcr.microsoft.com/devcontainers/base:ubuntu", "Base image")
+	exportDevcontainerCmd.Flags().BoolVar(&exportWrite, "write", false, "Write .devcontainer/devcontainer.json")
+	exportDevcontainerCmd.Flags().BoolVar(&exportCommand, "command", false, "Print only the postCreateCommand")
+
+	exportCmd.AddCommand(exportDevcontainerCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// githubCache is loaded from the state dir the first time it's needed
+var githubCache *state.GitHubCache
+
+func cachedGitHub() *state.GitHubCache {
+	if githubCache == nil {
+		githubCache = state.LoadGitHubCache()
+	}
+	return githubCache
+}
+
+// saveGitHubCache writes the cache, now that there may be a pact repo to
+// keep it in; it's only a cache, so failures are ignored
+func saveGitHubCache() {
+	cachedGitHub().Save()
+}
+
+// unreachable reports whether err means GitHub couldn't be reached, as
+// opposed to GitHub answering with an error
+func unreachable(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// githubLogin returns who token signs in as, asking GitHub at most once
+// every state.GitHubTTL
+func githubLogin(ctx context.Context, token string) (string, error) {
+	c := cachedGitHub()
+	key := state.TokenKey(token)
+	cached, ok := c.Users[key]
+	if ok && cached.Age() < state.GitHubTTL {
+		return cached.Login, nil
+	}
+
+	user, err := auth.GetUser(ctx, token)
+	if err != nil {
+		if ok && unreachable(err) && cached.Age() < state.GitHubOfflineTTL {
+			return cached.Login, nil
+		}
+		if ok {
+			delete(c.Users, key)
+			saveGitHubCache()
+		}
+		return "", err
+	}
+	c.Users[key] = state.CachedUser{Login: user.Login, CheckedAt: time.Now()}
+	saveGitHubCache()
+	return user.Login, nil
+}
+
+// myPactRepo returns owner's my-pact repo, or nil if it doesn't exist,
+// asking GitHub at most once every state.GitHubTTL. Missing repos aren't
+// remembered, so one created since is found straight away.
+func myPactRepo(ctx context.Context, token, owner string) (*state.CachedRepo, error) {
+	c := cachedGitHub()
+	key := state.RepoKey(owner, "my-pact")
+	cached, ok := c.Repos[key]
+	if ok && cached.Age() < state.GitHubTTL {
+		return &cached, nil
+	}
+
+	repo, err := auth.GetRepo(ctx, token, owner)
+	if err != nil {
+		if ok && unreachable(err) && cached.Age() < state.GitHubOfflineTTL {
+			return &cached, nil
+		}
+		return nil, err
+	}
+	if repo == nil {
+		if ok {
+			delete(c.Repos, key)
+			saveGitHubCache()
+		}
+		return nil, nil
+	}
+	rememberRepo(owner, "my-pact", repo.Private)
+	cached = c.Repos[key]
+	return &cached, nil
+}
+
+// rememberRepo records owner/name's visibility after pact created it,
+// changed it, or asked GitHub
+func rememberRepo(owner, name string, private bool) {
+	cachedGitHub().Repos[state.RepoKey(owner, name)] = state.CachedRepo{Private: private, CheckedAt: time.Now()}
+	saveGitHubCache()
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var graphFormat string
+
+// graphNode is a module, hook, or file in the graph
+type graphNode struct {
+	ID    string
+	Label string
+	Kind  string // module, hook, or file
+}
+
+// graphEdge points from what runs first to what runs after it
+type graphEdge struct {
+	From, To string
+	Label    string
+}
+
+type pactGraph struct {
+	Nodes []graphNode
+	Edges []graphEdge
+}
+
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Draw how modules, hooks, and files relate",
+	Long: `Print the pact as a graph for documenting and reviewing it:
+  - modules, with an edge to each module they list in needs
+  - pre hooks pointing at the modules they run before, and post hooks
+    after them; top-level hooks connect to every module
+  - the files each module places, labelled source → target
+
+A module's needs ("editor": {"needs": ["cli"]}) also makes 'pact sync'
+apply it after the modules it needs.
+
+--format dot (the default) is for Graphviz; mermaid pastes into Markdown.
+
+Examples:
+  pact graph | dot -Tsvg > pact.svg
+  pact graph --format mermaid`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		g := buildGraph(cfg)
+		switch graphFormat {
+		case "dot":
+			fmt.Print(g.dot())
+		case "mermaid":
+			fmt.Print(g.mermaid())
+		default:
+			fmt.Printf("Error: unknown format '%s' (use dot or mermaid)\n", graphFormat)
+			os.Exit(1)
+		}
+	},
+}
+
+// buildGraph collects the modules in pact.json and what hangs off them
+func buildGraph(cfg *config.PactConfig) *pactGraph {
+	g := &pactGraph{}
+	modules := cfg.GetModules()
+	for _, m := range modules {
+		g.Nodes = append(g.Nodes, graphNode{ID: graphID("m", m), Label: m, Kind: "module"})
+	}
+
+	for _, m := range modules {
+		for _, need := range cfg.ModuleNeeds(m) {
+			g.Edges = append(g.Edges, graphEdge{From: graphID("m", need), To: graphID("m", m), Label: "needs"})
+		}
+	}
+
+	for _, stage := range []string{"pre", "post"} {
+		for i, hook := range cfg.GetStringSlice("hooks." + stage) {
+			id := graphID("h", fmt.Sprintf("%s_%d", stage, i))
+			g.Nodes = append(g.Nodes, graphNode{ID: id, Label: stage + ": " + hook, Kind: "hook"})
+			for _, m := range modules {
+				g.hookEdge(id, graphID("m", m), stage)
+			}
+		}
+		for _, m := range modules {
+			for i, hook := range cfg.GetStringSlice(m + ".hooks." + stage) {
+				id := graphID("h", fmt.Sprintf("%s_%s_%d", m, stage, i))
+				g.Nodes = append(g.Nodes, graphNode{ID: id, Label: stage + ": " + hook, Kind: "hook"})
+				g.hookEdge(id, graphID("m", m), stage)
+			}
+		}
+	}
+
+	items, _ := cfg.GetSyncItems()
+	pactDir, _ := config.GetPactDir()
+	home, _ := os.UserHomeDir()
+	for _, item := range items {
+		id := graphID("f", item.Module+"_"+item.Name)
+		label := fmt.Sprintf("%s → %s", graphPath(item.Source, pactDir, ""), graphPath(item.Target, home, "~"))
+		g.Nodes = append(g.Nodes, graphNode{ID: id, Label: label, Kind: "file"})
+		g.Edges = append(g.Edges, graphEdge{From: graphID("m", item.Module), To: id})
+	}
+	return g
+}
+
+// hookEdge links a pre hook to the module it runs before, or a module to
+// the post hook that runs after it
+func (g *pactGraph) hookEdge(hook, module, stage string) {
+	if stage == "pre" {
+		g.Edges = append(g.Edges, graphEdge{From: hook, To: module})
+	} else {
+		g.Edges = append(g.Edges, graphEdge{From: module, To: hook})
+	}
+}
+
+var graphIDUnsafe = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// graphID makes a node ID both dot and mermaid accept
+func graphID(prefix, name string) string {
+	return prefix + "_" + graphIDUnsafe.ReplaceAllString(name, "_")
+}
+
+// graphPath shortens path relative to base, written as prefix
+func graphPath(path, base, prefix string) string {
+	if base == "" {
+		return path
+	}
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	if prefix == "" {
+		return filepath.ToSlash(rel)
+	}
+	return prefix + "/" + filepath.ToSlash(rel)
+}
+
+func (g *pactGraph) dot() string {
+	shapes := map[string]string{"module": "box", "hook": "ellipse", "file": "note"}
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	var b strings.Builder
+	b.WriteString("digraph pact {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s [label=\"%s\", shape=%s];\n", n.ID, quote.Replace(n.Label), shapes[n.Kind])
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "  %s -> %s [label=\"%s\"];\n", e.From, e.To, quote.Replace(e.Label))
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", e.From, e.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func (g *pactGraph) mermaid() string {
+	shapes := map[string][2]string{"module": {"[", "]"}, "hook": {"([", "])"}, "file": {"[/", "/]"}}
+	quote := strings.NewReplacer(`"`, "#quot;")
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		s := shapes[n.Kind]
+		fmt.Fprintf(&b, "  %s%s\"%s\"%s\n", n.ID, s[0], quote.Replace(n.Label), s[1])
+	}
+	for _, e := range g.Edges {
+		if e.Label != "" {
+			fmt.Fprintf(&b, "  %s -->|%s| %s\n", e.From, quote.Replace(e.Label), e.To)
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", e.From, e.To)
+		}
+	}
+	return b.String()
+}
+
+func init() {
+	graphCmd.Flags().StringVar(&graphFormat, "format", "dot", "Output format: dot or mermaid")
+	rootCmd.AddCommand(graphCmd)
+}
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ignoreShared bool
+	ignoreRemove bool
+	ignoreList   bool
+)
+
+var ignoreCmd = &cobra.Command{
+	Use:   "ignore <module> <name>",
+	Short: "Stop read, diff, status, and reconcile from bringing up an item",
+	Long: `Ignore a difference between this machine and pact.json for good, such as
+a python3 that ships with the OS or an agent your employer installs.
+read, diff, status, and reconcile leave ignored items out.
+
+Items are ignored on this machine only, in .pact/state/ignored.json.
+With --pact the name goes in pact.json's ignore instead, for every
+machine; names there can be glob patterns such as 'com.corp.*'.
+
+Examples:
+  pact ignore cli python3
+  pact ignore apps 'com.corp.*' --pact
+  pact ignore cli python3 --remove
+  pact ignore --list`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if ignoreList {
+			renderIgnored(cfg)
+			return
+		}
+		if len(args) != 2 {
+			fmt.Println("Error: name a module and an item, e.g. 'pact ignore cli python3'")
+			os.Exit(1)
+		}
+		module, name := args[0], args[1]
+
+		if ignoreShared {
+			if ignoreRemove {
+				if !cfg.RemoveValue("ignore."+module, name) {
+					fmt.Printf("%s isn't ignored in pact.json's %s\n", name, module)
+					return
+				}
+				pruneIgnoredDrift(cfg, module)
+			} else if !addIgnoredDrift(cfg, module, name) {
+				fmt.Printf("%s is already ignored in pact.json's %s\n", name, module)
+				return
+			}
+			if err := cfg.Save(); err != nil {
+				fmt.Printf("Error saving pact.json: %v\n", err)
+				os.Exit(1)
+			}
+			if ignoreRemove {
+				fmt.Printf("✓ No longer ignoring %s in %s on any machine\n", name, module)
+			} else {
+				fmt.Printf("✓ Ignoring %s in %s on every machine\n", name, module)
+			}
+			fmt.Println("\nRun 'pact push' to sync the change.")
+			return
+		}
+
+		item := state.IgnoredItem{Module: module, Name: name}
+		if ignoreRemove {
+			removed, err := state.Unignore(item)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if removed == 0 {
+				fmt.Printf("%s isn't ignored in %s on this machine\n", name, module)
+				return
+			}
+			fmt.Printf("✓ No longer ignoring %s in %s on this machine\n", name, module)
+			return
+		}
+		if err := state.Ignore(item); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Ignoring %s in %s on this machine\n", name, module)
+	},
+}
+
+// addIgnoredDrift adds name to pact.json's ignore for module, reporting
+// whether it wasn't there yet
+func addIgnoredDrift(cfg *config.PactConfig, module, name string) bool {
+	if containsString(cfg.GetStringSlice("ignore."+module), name) {
+		return false
+	}
+	ignore, ok := cfg.Raw["ignore"].(map[string]any)
+	if !ok {
+		ignore = make(map[string]any)
+		cfg.Raw["ignore"] = ignore
+	}
+	list, _ := ignore[module].([]any)
+	ignore[module] = append(list, name)
+	return true
+}
+
+// pruneIgnoredDrift drops module's list from pact.json's ignore once it's
+// empty, and ignore itself once nothing is left in it
+func pruneIgnoredDrift(cfg *config.PactConfig, module string) {
+	ignore, _ := cfg.Raw["ignore"].(map[string]any)
+	if list, ok := ignore[module].([]any); ok && len(list) == 0 {
+		delete(ignore, module)
+	}
+	if len(ignore) == 0 {
+		delete(cfg.Raw, "ignore")
+	}
+}
+
+// renderIgnored lists what's ignored on this machine and in pact.json
+func renderIgnored(cfg *config.PactConfig) {
+	local, err := state.LoadIgnored()
+	if err != nil {
+		fmt.Printf("Warning: could not read ignored items: %v\n", err)
+	}
+	shared := cfg.GetIgnoredDrift()
+	for module, names := range shared {
+		if len(names) == 0 {
+			delete(shared, module)
+		}
+	}
+	if len(local) == 0 && len(shared) == 0 {
+		fmt.Println("Nothing is ignored.")
+		return
+	}
+
+	if len(local) > 0 {
+		fmt.Println(moduleStyle.Render("This machine"))
+		for _, item := range local {
+			fmt.Printf("  %-10s %s %s\n", item.Module, item.Name, dimStyle.Render(item.Type))
+		}
+	}
+	if len(shared) > 0 {
+		fmt.Println(moduleStyle.Render("pact.json (every machine)"))
+		modules := make([]string, 0, len(shared))
+		for module := range shared {
+			modules = append(modules, module)
+		}
+		sort.Strings(modules)
+		for _, module := range modules {
+			for _, name := range shared[module] {
+				fmt.Printf("  %-10s %s\n", module, name)
+			}
+		}
+	}
+}
+
+// withoutIgnored drops the drift pact is told to ignore from diffs. A
+// warning goes to stderr so JSON output stays clean.
+func withoutIgnored(cfg *config.PactConfig, diffs []detect.DiffResult) []detect.DiffResult {
+	list, err := state.LoadIgnoreList(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read ignored items: %v\n", err)
+	}
+	return detect.WithoutIgnored(diffs, func(module string, item detect.DiffItem) bool {
+		return list.Has(module, item.Type, item.Name)
+	})
+}
+
+func init() {
+	ignoreCmd.Flags().BoolVar(&ignoreShared, "pact", false, "Ignore on every machine, in pact.json")
+	ignoreCmd.Flags().BoolVar(&ignoreRemove, "remove", false, "Stop ignoring the item")
+	ignoreCmd.Flags().BoolVar(&ignoreList, "list", false, "List ignored items")
+	rootCmd.AddCommand(ignoreCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/buildinfo"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/policy"
+	"github.com/spf13/cobra"
+)
+
+var infoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Show version and environment details for bug reports",
+	Long: `Show pact's version and build, the platform, and how pact sees this
+machine. Paste the output when filing an issue.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		build := buildinfo.Get()
+		commit, built := build.ShortCommit(), build.Date
+		if commit == "" {
+			commit = "unknown"
+		}
+		if built == "" {
+			built = "unknown"
+		}
+
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			pactDir = fmt.Sprintf("unknown (%v)", err)
+		} else if !config.Exists() {
+			pactDir += " (not initialized)"
+		}
+
+		schema := "n/a"
+		if cfg, err := config.Load(); err == nil {
+			if v := cfg.GetString("version"); v != "" {
+				schema = v
+			}
+		}
+
+		token := "no token stored"
+		if keyring.HasToken() {
+			token = "token stored"
+		}
+
+		managers := strings.Join(apply.PackageManagers(), ", ")
+		if managers == "" {
+			managers = "none found"
+		}
+
+		policyPath := "none"
+		if p, err := policy.Load(); err != nil {
+			policyPath = err.Error()
+		} else if p.Active() {
+			policyPath = p.Path
+		}
+
+		rows := [][2]string{
+			{"Version", build.Version},
+			{"Channel", build.Channel},
+			{"Commit", commit},
+			{"Built", built},
+			{"Go", runtime.Version()},
+			{"OS/Arch", runtime.GOOS + "/" + runtime.GOARCH},
+			{"Pact dir", pactDir},
+			{"Keyring", fmt.Sprintf("%s (%s)", keyring.Backend(), token)},
+			{"Package managers", managers},
+			{"Config schema", schema},
+			{"Policy", policyPath},
+		}
+		for _, row := range rows {
+			fmt.Printf("%-18s %s\n", row[0]+":", row[1])
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(infoCmd)
+}
@@ -1,26 +1,66 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/cloudboy-jh/pact/internal/auth"
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/crypto"
 	"github.com/cloudboy-jh/pact/internal/git"
 	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/policy"
 	"github.com/cloudboy-jh/pact/internal/ui"
-	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
-var fromUser string
+var (
+	fromUser    string
+	initRemote  string
+	initSSHKey  string
+	initPrivate bool
+
+	// initPrivateSet is whether --private was given, rather than defaulted
+	initPrivateSet bool
+)
 
 var initCmd = &cobra.Command{
 	Use:   "init",
 	Short: "Initialize pact in current directory",
-	Long:  `Authenticate with GitHub and clone your pact repo to ./.pact/ in the current directory.`,
+	Long: `Authenticate with GitHub and clone your pact repo to ./.pact/ in the current directory.
+
+With --from, start from someone else's pact instead: their my-pact's files
+are copied without their git name, email, signing key, secrets, or history
+and pushed as the first commit of a new my-pact under your account.
+
+A new my-pact is private unless you say otherwise when asked, or pass
+--private=false. Change it later with 'pact repo visibility'.
+
+With --remote, clone a pact repo from anywhere else instead, such as GitLab,
+Gitea, or your own server. SSH remotes sign in with the SSH agent, or with
+--ssh-key, and need no GitHub sign-in; HTTPS remotes off GitHub use the
+token in $PACT_REMOTE_TOKEN. The repo must already exist.
+
+Examples:
+  pact init
+  pact init --from octocat
+  pact init --private=false
+  pact init --remote git@gitlab.com:me/pact.git
+  pact init --remote ssh://git@git.example.com/me/pact.git --ssh-key ~/.ssh/id_ed25519`,
 	Run: func(cmd *cobra.Command, args []string) {
+		// Ctrl+C stops the clone and removes the half-cloned repo
+		ctx, stop := cancelOnInterrupt(cmd.Context())
+		defer stop()
+
+		initPrivateSet = cmd.Flags().Changed("private")
+
 		// Show logo with welcome message
 		fmt.Println(ui.RenderLogo())
 
@@ -31,14 +71,31 @@ var initCmd = &cobra.Command{
 			return
 		}
 
+		if initRemote != "" && fromUser != "" {
+			fmt.Println("Error: --remote and --from can't be used together")
+			os.Exit(1)
+		}
+		if initSSHKey != "" {
+			git.SetAuth(git.SSHAuth{KeyFile: initSSHKey})
+		}
+		// Remotes off GitHub, or reached over SSH, don't need a GitHub token
+		if initRemote != "" && (git.IsSSH(initRemote) || !git.IsGitHub(initRemote)) {
+			token, _ := tokenFor(initRemote)
+			if err := cloneRemote(ctx, token, initRemote); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		// Check if we already have a token
 		if keyring.HasToken() {
 			fmt.Println("Found existing GitHub token. Verifying...")
 			token, _ := keyring.GetToken()
-			user, err := auth.GetUser(token)
+			login, err := githubLogin(ctx, token)
 			if err == nil {
-				fmt.Printf("Authenticated as %s\n", user.Login)
-				if err := setupRepo(token, user.Login); err != nil {
+				fmt.Printf("Authenticated as %s\n", login)
+				if err := setupRepo(ctx, token, login); err != nil {
 					fmt.Printf("Error: %v\n", err)
 					os.Exit(1)
 				}
@@ -48,48 +105,23 @@ var initCmd = &cobra.Command{
 			keyring.DeleteToken()
 		}
 
-		// Start device flow
-		fmt.Println("Authenticating with GitHub...")
-		fmt.Println()
-
-		deviceCode, err := auth.RequestDeviceCode()
+		token, info, err := signIn(ctx)
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
+		login := info.Login
 
-		fmt.Printf("Please visit: %s\n", deviceCode.VerificationURI)
-		fmt.Printf("And enter code: %s\n", deviceCode.UserCode)
-		fmt.Println()
-		fmt.Println("Waiting for authorization...")
-
-		// Try to open browser
-		browser.OpenURL(deviceCode.VerificationURI)
-
-		// Poll for token
-		token, err := auth.PollForToken(deviceCode.DeviceCode, deviceCode.Interval)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
-		}
-
-		// Get user info
-		user, err := auth.GetUser(token)
-		if err != nil {
-			fmt.Printf("Error getting user info: %v\n", err)
-			os.Exit(1)
-		}
-
-		fmt.Printf("\n✓ Authenticated as %s\n", user.Login)
+		fmt.Printf("\n✓ Authenticated as %s\n", login)
 
 		// Store token
-		if err := keyring.SetToken(token); err != nil {
+		if err := storeToken(login, token); err != nil {
 			fmt.Printf("Warning: Could not store token in keychain: %v\n", err)
 			fmt.Println("You may need to re-authenticate on next run.")
 		}
 
 		// Setup repo
-		if err := setupRepo(token, user.Login); err != nil {
+		if err := setupRepo(ctx, token, login); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -97,34 +129,46 @@ var initCmd = &cobra.Command{
 }
 
 func init() {
-	initCmd.Flags().StringVar(&fromUser, "from", "", "Fork pact from another user")
+	initCmd.Flags().StringVar(&fromUser, "from", "", "Start from another user's pact: copy their my-pact without their identity or secrets")
+	initCmd.Flags().StringVar(&initRemote, "remote", "", "Clone the pact repo from this URL instead of GitHub's my-pact")
+	initCmd.Flags().StringVar(&initSSHKey, "ssh-key", "", "SSH key for the remote, instead of the SSH agent")
+	initCmd.Flags().BoolVar(&initPrivate, "private", true, "Create my-pact as a private repo (--private=false for public); asked when not given")
 }
 
-func setupRepo(token, username string) error {
-	targetUser := username
+func setupRepo(ctx context.Context, token, username string) error {
 	if fromUser != "" {
-		targetUser = fromUser
-		// TODO: Implement fork functionality
-		fmt.Printf("Forking from %s is not yet implemented\n", fromUser)
-		return nil
+		return forkRepo(ctx, token, username, fromUser)
+	}
+	if initRemote != "" {
+		return cloneRemote(ctx, token, initRemote)
+	}
+	targetUser := username
+
+	pol := loadPolicy()
+	if err := pol.CheckRemote(git.DefaultRemote(targetUser)); err != nil {
+		return err
 	}
 
 	// Check if repo exists
 	fmt.Printf("Checking for %s/my-pact repo...\n", targetUser)
-	exists, err := auth.RepoExists(token, targetUser)
+	repo, err := myPactRepo(ctx, token, targetUser)
 	if err != nil {
 		return fmt.Errorf("failed to check repo: %w", err)
 	}
 
-	if !exists {
+	if repo == nil {
 		fmt.Println("Repo not found. Creating...")
-		if err := auth.CreateRepo(token); err != nil {
+		private := newRepoPrivate(pol)
+		if err := auth.CreateRepo(ctx, token, private); err != nil {
 			return fmt.Errorf("failed to create repo: %w", err)
 		}
-		fmt.Println("✓ Created my-pact repo")
+		rememberRepo(targetUser, "my-pact", private)
+		fmt.Printf("✓ Created my-pact repo (%s)\n", visibility(private))
 
 		// Wait a moment for GitHub to initialize the repo
 		time.Sleep(2 * time.Second)
+	} else if pol.RequirePrivate && !repo.Private {
+		return fmt.Errorf("%s/my-pact is public, but %s requires a private repo", targetUser, pol.Path)
 	}
 
 	// Get local pact directory (current working directory)
@@ -135,9 +179,10 @@ func setupRepo(token, username string) error {
 
 	// Clone repo to ./.pact/
 	fmt.Println("Cloning to ./.pact/...")
-	if err := git.Clone(token, targetUser, pactDir); err != nil {
+	if err := git.Clone(ctx, token, git.DefaultRemote(targetUser), pactDir); err != nil {
 		return fmt.Errorf("failed to clone: %w", err)
 	}
+	saveGitHubCache()
 
 	fmt.Println("✓ Cloned repo to ./.pact/")
 
@@ -156,6 +201,152 @@ func setupRepo(token, username string) error {
 	return nil
 }
 
+// cloneRemote clones an existing pact repo from remote, and records the SSH
+// key it was cloned with in git.auth so later pulls and pushes use it too
+func cloneRemote(ctx context.Context, token, remote string) error {
+	if err := loadPolicy().CheckRemote(remote); err != nil {
+		return err
+	}
+	pactDir, err := config.GetLocalPactDir()
+	if err != nil {
+		return fmt.Errorf("failed to get pact directory: %w", err)
+	}
+
+	fmt.Printf("Cloning %s to ./.pact/...\n", remote)
+	if err := git.Clone(ctx, token, remote, pactDir); err != nil {
+		return fmt.Errorf("failed to clone: %w", err)
+	}
+	fmt.Println("✓ Cloned repo to ./.pact/")
+
+	if !config.Exists() {
+		fmt.Println("Creating default pact.json...")
+		if err := createDefaultConfig(repoOwner(remote)); err != nil {
+			return fmt.Errorf("failed to create default config: %w", err)
+		}
+		fmt.Println("✓ Created pact.json")
+	}
+	if initSSHKey != "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		gitCfg, ok := cfg.Raw["git"].(map[string]any)
+		if cfg.Raw["git"] == nil {
+			gitCfg, ok = map[string]any{}, true
+			cfg.Raw["git"] = gitCfg
+		}
+		if ok && gitCfg["auth"] == nil {
+			gitCfg["auth"] = map[string]any{"type": "ssh", "key": initSSHKey}
+			if err := cfg.Save(); err != nil {
+				return err
+			}
+			fmt.Println("✓ Saved the SSH key to git.auth in pact.json")
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Pact initialized! Run 'pact' to see status or 'pact sync' to apply configs.")
+	return nil
+}
+
+// forkRepo starts username's pact from another user's my-pact: it clones
+// theirs, strips their identity and secrets, creates username's repo, and
+// pushes the result there
+func forkRepo(ctx context.Context, token, username, from string) error {
+	if strings.EqualFold(from, username) {
+		return fmt.Errorf("that's your own pact; run 'pact init' without --from")
+	}
+	pol := loadPolicy()
+	for _, user := range []string{from, username} {
+		if err := pol.CheckRemote(git.DefaultRemote(user)); err != nil {
+			return err
+		}
+	}
+
+	repo, err := myPactRepo(ctx, token, username)
+	if err != nil {
+		return fmt.Errorf("failed to check repo: %w", err)
+	}
+	if repo != nil {
+		return fmt.Errorf("%s/my-pact already exists; run 'pact init' without --from to use it", username)
+	}
+
+	pactDir, err := config.GetLocalPactDir()
+	if err != nil {
+		return fmt.Errorf("failed to get pact directory: %w", err)
+	}
+	fmt.Printf("Cloning %s/my-pact to ./.pact/...\n", from)
+	if err := git.Clone(ctx, token, git.DefaultRemote(from), pactDir); err != nil {
+		return fmt.Errorf("failed to clone: %w", err)
+	}
+	if err := startFrom(ctx, token, username, from, pactDir, newRepoPrivate(pol)); err != nil {
+		// Leave nothing behind so 'pact init --from' can be run again
+		os.RemoveAll(pactDir)
+		return err
+	}
+
+	fmt.Println()
+	fmt.Printf("Pact initialized from %s's! Review pact.json, then run 'pact sync' to apply it.\n", from)
+	return nil
+}
+
+// startFrom makes a freshly cloned pact username's own
+func startFrom(ctx context.Context, token, username, from, pactDir string, private bool) error {
+	if config.Exists() {
+		cfg, err := config.Load()
+		if err != nil {
+			return err
+		}
+		if removed := cfg.StripIdentity(username); len(removed) > 0 {
+			fmt.Printf("✓ Removed %s's %s\n", from, strings.Join(removed, ", "))
+		}
+		if err := cfg.Save(); err != nil {
+			return err
+		}
+	}
+	filepath.WalkDir(pactDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if err == nil && d.Name() == crypto.SecretsFile && os.Remove(path) == nil {
+			rel, _ := filepath.Rel(pactDir, path)
+			fmt.Printf("✓ Removed %s (encrypted for %s)\n", filepath.ToSlash(rel), from)
+		}
+		return nil
+	})
+
+	fmt.Println("Creating your my-pact repo...")
+	if err := auth.CreateEmptyRepo(ctx, token, private); err != nil {
+		return fmt.Errorf("failed to create repo: %w", err)
+	}
+	rememberRepo(username, "my-pact", private)
+	// A fresh history, so none of theirs is pushed to your repo
+	if err := git.StartOver(ctx, token, pactDir, username, fmt.Sprintf("Start from %s's pact", from)); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Pushed to %s/my-pact\n", username)
+	return nil
+}
+
+// newRepoPrivate decides whether to create my-pact as a private repo: always
+// under a policy requiring it, else as --private says, else as the user
+// answers. Private is the default.
+func newRepoPrivate(pol *policy.Policy) bool {
+	if pol.RequirePrivate {
+		return true
+	}
+	if initPrivateSet {
+		return initPrivate
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true
+	}
+	fmt.Print("Make my-pact private, so only you can see it? [Y/n] ")
+	response, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response != "n" && response != "no"
+}
+
 func createDefaultConfig(username string) error {
 	pactDir, err := config.GetPactDir()
 	if err != nil {
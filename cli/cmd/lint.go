@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFix     bool
+	lintOffline bool
+)
+
+// lintIssue is one mistake in pact.json; fix is set when --fix can correct it
+type lintIssue struct {
+	Rule   string
+	Detail string
+	Hint   string
+	fix    func(cfg *config.PactConfig)
+}
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check pact.json for common mistakes",
+	Long: `Check pact.json for mistakes that are valid JSON but probably not what you
+meant:
+  - tools listed twice, or in both cli.tools and shell.tools
+  - file entries whose source isn't in the pact repo
+  - file targets inside .pact itself
+  - secrets that are neither set in the keychain nor used anywhere in the repo
+  - custom tool sources that can't be reached (skipped with --offline)
+
+--fix corrects the mechanical ones (duplicate tools) and saves pact.json.
+Exits non-zero when problems remain.
+
+Examples:
+  pact lint
+  pact lint --fix
+  pact lint --offline`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		issues := lintConfig(cmd.Context(), cfg)
+		fixed := 0
+		if lintFix {
+			var remaining []lintIssue
+			for _, issue := range issues {
+				if issue.fix == nil {
+					remaining = append(remaining, issue)
+					continue
+				}
+				issue.fix(cfg)
+				fmt.Printf("  ✓ %-18s fixed: %s\n", issue.Rule, issue.Detail)
+				fixed++
+			}
+			if fixed > 0 {
+				if err := cfg.Save(); err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			issues = remaining
+		}
+
+		for _, issue := range issues {
+			fmt.Printf("  ✗ %-18s %s\n", issue.Rule, issue.Detail)
+			if issue.Hint != "" {
+				fmt.Printf("    → %s\n", issue.Hint)
+			}
+		}
+		if len(issues) == 0 {
+			if fixed == 0 {
+				fmt.Println("✓ No problems found")
+			}
+			return
+		}
+		fmt.Println()
+		fixable := 0
+		for _, issue := range issues {
+			if issue.fix != nil {
+				fixable++
+			}
+		}
+		if fixable > 0 {
+			fmt.Printf("%d problem(s) found, %d fixable with 'pact lint --fix'.\n", len(issues), fixable)
+		} else {
+			fmt.Printf("%d problem(s) found.\n", len(issues))
+		}
+		os.Exit(1)
+	},
+}
+
+// lintConfig runs every rule against pact.json
+func lintConfig(ctx context.Context, cfg *config.PactConfig) []lintIssue {
+	var issues []lintIssue
+	issues = append(issues, lintDuplicateTools(cfg)...)
+	issues = append(issues, lintFiles(cfg)...)
+	issues = append(issues, lintSecrets(cfg)...)
+	if !lintOffline {
+		issues = append(issues, lintCustomSources(ctx, cfg)...)
+	}
+	return issues
+}
+
+// lintDuplicateTools finds tools listed twice in a list, or in both
+// cli.tools and shell.tools; shell.tools installs them too
+func lintDuplicateTools(cfg *config.PactConfig) []lintIssue {
+	var issues []lintIssue
+	for _, path := range []string{"cli.tools", "shell.tools"} {
+		seen := make(map[string]bool)
+		for _, tool := range cfg.GetStringSlice(path) {
+			if seen[tool] {
+				path, tool := path, tool
+				issues = append(issues, lintIssue{
+					Rule:   "duplicate-tool",
+					Detail: fmt.Sprintf("%s is listed twice in %s", tool, path),
+					fix:    func(cfg *config.PactConfig) { dedupeList(cfg, path) },
+				})
+			}
+			seen[tool] = true
+		}
+	}
+
+	shellTools := make(map[string]bool)
+	for _, tool := range cfg.GetStringSlice("shell.tools") {
+		shellTools[tool] = true
+	}
+	for _, tool := range cfg.GetStringSlice("cli.tools") {
+		if shellTools[tool] {
+			tool := tool
+			issues = append(issues, lintIssue{
+				Rule:   "duplicate-tool",
+				Detail: fmt.Sprintf("%s is in both cli.tools and shell.tools", tool),
+				Hint:   "shell.tools installs it too; keep it there",
+				fix:    func(cfg *config.PactConfig) { cfg.RemoveValue("cli.tools", tool) },
+			})
+		}
+	}
+	return issues
+}
+
+// dedupeList keeps the first of each repeated string in the list at path
+func dedupeList(cfg *config.PactConfig, path string) {
+	parts := strings.Split(path, ".")
+	parent := cfg.GetMap(strings.Join(parts[:len(parts)-1], "."))
+	list, ok := parent[parts[len(parts)-1]].([]any)
+	if !ok {
+		return
+	}
+	seen := make(map[string]bool)
+	kept := make([]any, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			if seen[s] {
+				continue
+			}
+			seen[s] = true
+		}
+		kept = append(kept, v)
+	}
+	parent[parts[len(parts)-1]] = kept
+}
+
+// lintFiles finds file entries whose source is missing or whose target
+// points back into the pact repo
+func lintFiles(cfg *config.PactConfig) []lintIssue {
+	items, err := cfg.GetSyncItems()
+	if err != nil {
+		return []lintIssue{{Rule: "files", Detail: err.Error()}}
+	}
+	pactDir, _ := config.GetPactDir()
+
+	var issues []lintIssue
+	for _, item := range items {
+		name := item.Module + "/" + item.Name
+		if _, err := os.Stat(item.Source); err != nil {
+			issues = append(issues, lintIssue{
+				Rule:   "missing-source",
+				Detail: fmt.Sprintf("%s: %s isn't in the pact repo", name, item.Source),
+				Hint:   "add the file, or run 'pact adopt' on the target",
+			})
+		}
+		if rel, err := filepath.Rel(pactDir, item.Target); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			issues = append(issues, lintIssue{
+				Rule:   "target-in-pact",
+				Detail: fmt.Sprintf("%s: target %s is inside .pact", name, item.Target),
+				Hint:   "point the target at where the program reads its config",
+			})
+		}
+	}
+	return issues
+}
+
+// lintSecrets finds secrets that aren't set here and aren't mentioned in any
+// file in the pact repo or anywhere else in pact.json
+func lintSecrets(cfg *config.PactConfig) []lintIssue {
+	secrets := cfg.GetSecrets()
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	var corpus strings.Builder
+	raw := make(map[string]any, len(cfg.Raw))
+	for k, v := range cfg.Raw {
+		if k != "secrets" {
+			raw[k] = v
+		}
+	}
+	if data, err := json.Marshal(raw); err == nil {
+		corpus.Write(data)
+	}
+	if pactDir, err := config.GetPactDir(); err == nil {
+		filepath.WalkDir(pactDir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() && (d.Name() == ".git" || d.Name() == "state") {
+				return filepath.SkipDir
+			}
+			if d.IsDir() || d.Name() == "pact.json" || d.Name() == "secrets.enc" {
+				return nil
+			}
+			if info, err := d.Info(); err == nil && info.Size() < 1<<20 {
+				data, _ := os.ReadFile(p)
+				corpus.Write(data)
+			}
+			return nil
+		})
+	}
+
+	var issues []lintIssue
+	text := corpus.String()
+	for _, name := range secrets {
+		if keyring.HasSecret(name) || strings.Contains(text, name) {
+			continue
+		}
+		issues = append(issues, lintIssue{
+			Rule:   "unused-secret",
+			Detail: fmt.Sprintf("%s isn't set in the keychain or used in the pact repo", name),
+			Hint:   fmt.Sprintf("set it with 'pact secret set %s', or remove it from secrets", name),
+		})
+	}
+	return issues
+}
+
+// lintCustomSources finds cli.custom tools whose source can't be reached
+func lintCustomSources(ctx context.Context, cfg *config.PactConfig) []lintIssue {
+	var issues []lintIssue
+	for _, tool := range cfg.GetStringSlice("cli.custom") {
+		checkCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := apply.CheckCustomSource(checkCtx, cfg, tool)
+		cancel()
+		if err != nil {
+			issues = append(issues, lintIssue{
+				Rule:   "unreachable-source",
+				Detail: fmt.Sprintf("%s: %v", tool, err),
+				Hint:   "check cli.customSources." + tool,
+			})
+		}
+	}
+	return issues
+}
+
+func init() {
+	lintCmd.Flags().BoolVar(&lintFix, "fix", false, "Fix what can be fixed mechanically and save pact.json")
+	lintCmd.Flags().BoolVar(&lintOffline, "offline", false, "Skip checks that need the network")
+	rootCmd.AddCommand(lintCmd)
+}
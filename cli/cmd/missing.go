@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/undo"
+	"golang.org/x/term"
+)
+
+// missingItem is a pact-only item that can be installed by itself, with
+// what installing it would do
+type missingItem struct {
+	module string
+	item   detect.DiffItem
+	plan   apply.Result
+}
+
+func (m missingItem) key() string {
+	return m.module + "/" + m.item.Type + "/" + m.item.Name
+}
+
+// installable reports whether installing the item would do anything
+func (m missingItem) installable() bool {
+	return m.plan.Error == nil && m.plan.Planned
+}
+
+// describe says what installing the item would do, or why it can't be
+func (m missingItem) describe() string {
+	if m.plan.Error != nil {
+		return "can't install: " + m.plan.Error.Error()
+	}
+	return m.plan.Message
+}
+
+// missingItems dry-runs the install of each pact-only item in diffs that
+// apply can install by itself. Items in modules the policy disallows are
+// left out.
+func missingItems(cfg *config.PactConfig, diffs []detect.DiffResult) []missingItem {
+	pol := loadPolicy()
+	opts := apply.Options{DryRun: true, ForbidScripts: pol.ForbidScripts}
+	opts.Timeouts, _ = apply.ParseTimeouts(cfg)
+
+	var modules []string
+	for _, d := range diffs {
+		modules = append(modules, d.Module)
+	}
+	allowed, _ := pol.Filter(modules)
+
+	var missing []missingItem
+	for _, d := range diffs {
+		if !containsString(allowed, d.Module) {
+			continue
+		}
+		for _, item := range d.PactOnly {
+			if plan, ok := apply.InstallItem(cfg, d.Module, item.Type, item.Name, item.Value, opts); ok {
+				missing = append(missing, missingItem{module: d.Module, item: item, plan: plan})
+			}
+		}
+	}
+	return missing
+}
+
+// installPlans maps each missing item's module, type, and name to what
+// installing it would do
+func installPlans(missing []missingItem) map[string]string {
+	plans := make(map[string]string, len(missing))
+	for _, m := range missing {
+		plans[m.key()] = m.describe()
+	}
+	return plans
+}
+
+// offerInstalls lets the user pick missing items to install now, one at a
+// time, without syncing their whole modules, asking first whether to when
+// ask is set. It does nothing without a terminal.
+func offerInstalls(ctx context.Context, cfg *config.PactConfig, missing []missingItem, ask bool) {
+	var keys, labels []string
+	byKey := make(map[string]missingItem)
+	for _, m := range missing {
+		if !m.installable() {
+			continue
+		}
+		keys = append(keys, m.key())
+		labels = append(labels, fmt.Sprintf("%s %s (%s)", m.module, m.item.Name, m.plan.Message))
+		byKey[m.key()] = m
+	}
+	if len(keys) == 0 || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return
+	}
+
+	fmt.Println()
+	if ask && !confirm(fmt.Sprintf("%d item(s) in pact.json can be installed now. Pick which?", len(keys))) {
+		return
+	}
+	selected, ok := pickItems("Install on this machine:", keys, labels)
+	if !ok || len(selected) == 0 {
+		fmt.Println("Nothing installed.")
+		return
+	}
+
+	var items []missingItem
+	for _, key := range selected {
+		items = append(items, byKey[key])
+	}
+	installMissing(ctx, cfg, items)
+}
+
+// installMissing installs items one at a time, as one undoable change
+func installMissing(ctx context.Context, cfg *config.PactConfig, items []missingItem) {
+	opts := apply.Options{
+		Context:       ctx,
+		Jobs:          1,
+		ForbidScripts: loadPolicy().ForbidScripts,
+		AcceptLicense: acceptLicense,
+		Confirm:       confirm,
+		Sudo:          apply.NewSudo(true),
+	}
+	defer opts.Sudo.Close()
+	opts.Timeouts, _ = apply.ParseTimeouts(cfg)
+
+	var modules []string
+	for _, m := range items {
+		if !containsString(modules, m.module) {
+			modules = append(modules, m.module)
+		}
+	}
+	if journal, err := undo.Begin(modules); err == nil {
+		opts.Undo = journal
+	} else {
+		fmt.Printf("Warning: these installs can't be undone: %v\n", err)
+	}
+
+	fmt.Println()
+	var results []apply.Result
+	for _, m := range items {
+		if opts.Cancelled() {
+			break
+		}
+		result, _ := apply.InstallItem(cfg, m.module, m.item.Type, m.item.Name, m.item.Value, opts)
+		results = append(results, result)
+	}
+	renderApplyResults(results)
+
+	if opts.Undo != nil {
+		if err := opts.Undo.Save(); err != nil {
+			fmt.Printf("Warning: could not save undo journal: %v\n", err)
+		} else if len(opts.Undo.Changes) > 0 {
+			fmt.Println("Run 'pact undo' to revert these installs")
+		}
+	}
+}
@@ -74,6 +74,11 @@ var nukeCmd = &cobra.Command{
 		} else {
 			fmt.Println("  ✓ Removed token from keychain")
 		}
+		for _, login := range keyring.Accounts() {
+			if err := keyring.DeleteAccountToken(login); err == nil {
+				fmt.Printf("  ✓ Removed %s's token from keychain\n", login)
+			}
+		}
 
 		fmt.Println()
 		fmt.Println("Pact has been completely removed.")
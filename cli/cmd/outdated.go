@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	outdatedJSON bool
+	outdatedYes  bool
+)
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List pact's packages that have updates, and upgrade the ones you pick",
+	Long: `Ask each package manager on this machine that can list updates (brew,
+apt, winget) which packages have a newer version, and show the ones pact
+installed or pact.json lists. In a terminal, pick which to upgrade.
+
+Packages installed some other way are left out.
+
+Examples:
+  pact outdated
+  pact outdated --yes    # Upgrade everything listed without asking
+  pact outdated --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := cancelOnInterrupt(cmd.Context())
+		defer stop()
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		opts := apply.Options{Context: ctx, ForbidScripts: loadPolicy().ForbidScripts}
+		opts.Timeouts, _ = apply.ParseTimeouts(cfg)
+		if !outdatedJSON {
+			fmt.Println("Checking for updates...")
+		}
+		outdated, errs := apply.Outdated(cfg, opts)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "Warning: could not check for updates: %v\n", err)
+		}
+
+		if outdatedJSON {
+			if outdated == nil {
+				outdated = []apply.OutdatedPackage{}
+			}
+			output, err := json.MarshalIndent(outdated, "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+			return
+		}
+
+		if len(outdated) == 0 {
+			fmt.Println("✓ Everything pact manages is up to date")
+			return
+		}
+		fmt.Println()
+		renderOutdated(outdated)
+
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+		selected := outdated
+		if !outdatedYes {
+			if !interactive {
+				fmt.Println("\nRun 'pact outdated' in a terminal to pick upgrades, or pass --yes to upgrade them all")
+				return
+			}
+			var keys, labels []string
+			byKey := make(map[string]apply.OutdatedPackage)
+			for _, p := range outdated {
+				key := p.Backend + "/" + p.Package
+				keys = append(keys, key)
+				labels = append(labels, fmt.Sprintf("%s %s → %s (%s)", p.Name, p.Installed, p.Available, p.Backend))
+				byKey[key] = p
+			}
+			picked, ok := pickItems("Upgrade:", keys, labels)
+			if !ok || len(picked) == 0 {
+				fmt.Println("Nothing upgraded.")
+				return
+			}
+			selected = nil
+			for _, key := range picked {
+				selected = append(selected, byKey[key])
+			}
+		}
+
+		if interactive {
+			opts.AcceptLicense = acceptLicense
+		}
+		opts.Sudo = apply.NewSudo(interactive)
+		defer opts.Sudo.Close()
+
+		fmt.Println()
+		var results []apply.Result
+		for _, p := range selected {
+			if opts.Cancelled() {
+				break
+			}
+			results = append(results, apply.Upgrade(p, opts))
+		}
+		renderApplyResults(results)
+	},
+}
+
+// renderOutdated prints the outdated packages as a table
+func renderOutdated(outdated []apply.OutdatedPackage) {
+	fmt.Printf("  %-22s %-16s %-16s %s\n", "Package", "Installed", "Available", "Via")
+	for _, p := range outdated {
+		fmt.Printf("  %-22s %-16s %-16s %s\n", p.Name, p.Installed, p.Available, dimStyle.Render(p.Backend))
+	}
+}
+
+func init() {
+	outdatedCmd.Flags().BoolVar(&outdatedJSON, "json", false, "Output the outdated packages as JSON")
+	outdatedCmd.Flags().BoolVarP(&outdatedYes, "yes", "y", false, "Upgrade every outdated package without asking")
+	rootCmd.AddCommand(outdatedCmd)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/policy"
+)
+
+// loadPolicy reads the admin policy. pact won't run under a policy file it
+// can't read.
+func loadPolicy() *policy.Policy {
+	p, err := policy.Load()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	return p
+}
+
+// allowedModules drops the modules the policy disallows, saying which
+func allowedModules(p *policy.Policy, modules []string) []string {
+	allowed, denied := p.Filter(modules)
+	for _, m := range denied {
+		fmt.Printf("○ Skipping %s: disallowed by %s\n", m, p.Path)
+	}
+	return allowed
+}
+
+// checkRepoPolicy verifies the pact repo's remote host and, when the policy
+// requires it, that the repo is private. A repo that must be private doesn't
+// pass unless GitHub says it is, so one hosted elsewhere never does.
+func checkRepoPolicy(ctx context.Context, p *policy.Policy, pactDir, token string) error {
+	if p.RemoteHost == "" && !p.RequirePrivate {
+		return nil
+	}
+
+	remote, err := git.RemoteURL(pactDir)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckRemote(remote); err != nil {
+		return err
+	}
+
+	if p.RequirePrivate && token == "" && git.IsGitHub(remote) {
+		return fmt.Errorf("can't confirm the pact repo is private without a GitHub token, which %s requires", p.Path)
+	}
+	// Ask GitHub now rather than trust the cache: the repo may have been
+	// made public since
+	return p.CheckPrivate(remote, func(owner, name string) (bool, bool, error) {
+		repo, err := auth.GetNamedRepo(ctx, token, owner, name)
+		if err != nil || repo == nil {
+			return false, false, err
+		}
+		rememberRepo(owner, name, repo.Private)
+		return repo.Private, true, nil
+	})
+}
+
+// repoOwner returns the owner in a remote like https://github.com/<owner>/my-pact.git
+func repoOwner(remote string) string {
+	owner, _ := git.RepoPath(remote)
+	return owner
+}
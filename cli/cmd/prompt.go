@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/cloudboy-jh/pact/internal/ui"
+	"golang.org/x/term"
+)
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes"
+}
+
+// readLine asks for a line of text with editing, paste, and ↑/↓ through
+// earlier answers to the same prompt, kept in the state dir under history.
+// ok is false when cancelled with Esc or Ctrl+C. Without a terminal it reads
+// a plain line.
+func readLine(prompt, history string) (string, bool) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Print(prompt)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", false
+		}
+		return strings.TrimSpace(line), true
+	}
+
+	input := textinput.New()
+	input.Prompt = prompt
+	input.Focus()
+	past := state.PromptHistory(history)
+	result, err := tea.NewProgram(lineModel{input: input, history: past, pos: len(past)}).Run()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	m, _ := result.(lineModel)
+	if m.cancelled {
+		return "", false
+	}
+	answer := strings.TrimSpace(m.input.Value())
+	if answer != "" {
+		state.AddPromptHistory(history, answer)
+	}
+	return answer, true
+}
+
+// lineModel is a one-line text input; pos is the history entry shown, with
+// len(history) being the line being typed
+type lineModel struct {
+	input     textinput.Model
+	history   []string
+	pos       int
+	draft     string
+	done      bool
+	cancelled bool
+}
+
+func (m lineModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m lineModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.Type {
+		case tea.KeyEnter:
+			m.done = true
+			return m, tea.Quit
+		case tea.KeyEsc, tea.KeyCtrlC:
+			m.done = true
+			m.cancelled = true
+			return m, tea.Quit
+		case tea.KeyUp:
+			if m.pos > 0 {
+				if m.pos == len(m.history) {
+					m.draft = m.input.Value()
+				}
+				m.pos--
+				m.input.SetValue(m.history[m.pos])
+				m.input.CursorEnd()
+			}
+			return m, nil
+		case tea.KeyDown:
+			if m.pos < len(m.history) {
+				m.pos++
+				if m.pos == len(m.history) {
+					m.input.SetValue(m.draft)
+				} else {
+					m.input.SetValue(m.history[m.pos])
+				}
+				m.input.CursorEnd()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m lineModel) View() string {
+	// Leave the answer on screen once it's given
+	if m.done {
+		if m.cancelled {
+			return m.input.Prompt + "\n"
+		}
+		return m.input.Prompt + m.input.Value() + "\n"
+	}
+	return m.input.View()
+}
+
+// pickItems lets the user toggle items in a list, starting with all of them
+// selected, and returns the selected ones; ok is false when cancelled
+func pickItems(title string, items, labels []string) (selected []string, ok bool) {
+	result, err := tea.NewProgram(checklistModel{title: title, items: items, labels: labels, selected: allSelected(len(items))}, ui.Keys().ProgramOptions()...).Run()
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	m, _ := result.(checklistModel)
+	if m.cancelled {
+		return nil, false
+	}
+	for i, item := range m.items {
+		if m.selected[i] {
+			selected = append(selected, item)
+		}
+	}
+	return selected, true
+}
+
+func allSelected(n int) map[int]bool {
+	selected := make(map[int]bool, n)
+	for i := 0; i < n; i++ {
+		selected[i] = true
+	}
+	return selected
+}
+
+// checklistModel is a multi-select list; labels, when set, are shown
+// dimmed beside each item
+type checklistModel struct {
+	title     string
+	items     []string
+	labels    []string
+	cursor    int
+	selected  map[int]bool
+	cancelled bool
+	quitting  bool
+}
+
+func (m checklistModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m checklistModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keys := ui.Keys()
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		m.cursor = max(0, min(m.cursor+keys.Scroll(mouse), len(m.items)-1))
+		return m, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Quit), key.Matches(keyMsg, keys.Back):
+		m.cancelled = true
+		m.quitting = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if m.cursor < len(m.items)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, keys.Toggle):
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case key.Matches(keyMsg, keys.All):
+		all := true
+		for i := range m.items {
+			all = all && m.selected[i]
+		}
+		for i := range m.items {
+			m.selected[i] = !all
+		}
+	case key.Matches(keyMsg, keys.Enter):
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m checklistModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n" + m.title + "\n\n")
+	for i, item := range m.items {
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		checkbox := "[ ]"
+		if m.selected[i] {
+			checkbox = "[x]"
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s", cursor, checkbox, item))
+		if i < len(m.labels) && m.labels[i] != "" {
+			b.WriteString(" " + dimStyle.Render(m.labels[i]))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(listHelp("confirm", ui.Keys().Quit, "quit")))
+	return b.String()
+}
+
+// listHelp is the footer of the list pickers: moving, toggling, enter doing
+// what enter says, selecting all, and leaving with exit
+func listHelp(enter string, exit key.Binding, exitDesc string) string {
+	keys := ui.Keys()
+	return fmt.Sprintf("  %s: navigate  %s: toggle  %s: %s  %s: all  %s: %s",
+		navLabel(), ui.Label(keys.Toggle), ui.Label(keys.Enter), enter, ui.Label(keys.All), ui.Label(exit), exitDesc)
+}
+
+// navLabel names the up and down keys, as arrows when they're bound
+func navLabel() string {
+	keys := ui.Keys()
+	if ui.Matches(keys.Up, "up") && ui.Matches(keys.Down, "down") {
+		return "↑/↓"
+	}
+	return ui.Label(keys.Up) + "/" + ui.Label(keys.Down)
+}
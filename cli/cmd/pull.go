@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	psync "github.com/cloudboy-jh/pact/internal/sync"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	pullPrefer string
+	pullDryRun bool
+)
+
+var pullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull changes from GitHub, merging pact.json",
+	Long: `Fetch the pact repo's changes from GitHub and merge them with this
+machine's, without applying anything (run 'pact sync' for that).
+
+pact.json is merged key by key: lists such as cli.tools keep what either
+side added and drop what either removed, and you're asked which value to
+keep only where both sides changed the same setting. Other files merge line
+by line; where both sides changed the same lines you pick one version.
+--prefer local or remote answers every question the same way, which is
+needed when pull isn't run in a terminal.
+
+Changes made here that GitHub doesn't have yet are left uncommitted for
+'pact push'. Commits made here first are saved to a pact-before-pull-<sha>
+branch.
+
+Examples:
+  pact pull
+  pact pull --dry-run
+  pact pull --prefer remote`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		switch pullPrefer {
+		case "", "local", "remote":
+		default:
+			fmt.Printf("Error: unknown --prefer '%s' (use local or remote)\n", pullPrefer)
+			os.Exit(1)
+		}
+
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		token, err := remoteToken(pactDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("Fetching changes...")
+		if err := git.Fetch(ctx, token, pactDir); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		in, err := git.GetIncoming(pactDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if in.Behind == 0 {
+			fmt.Println("✓ Already up to date")
+			return
+		}
+		fmt.Printf("%d new commit(s) on GitHub\n\n", in.Behind)
+
+		interactive := term.IsTerminal(int(os.Stdin.Fd())) && !pullDryRun
+		merged := make(map[string][]byte, len(in.Files))
+		var unresolved []string
+		for _, f := range in.Files {
+			data, note, err := mergeIncoming(f, interactive, &unresolved)
+			if err != nil {
+				fmt.Printf("  ✗ %s: %v\n", f.Path, err)
+				os.Exit(1)
+			}
+			merged[f.Path] = data
+			if note != "" {
+				fmt.Printf("  ✓ %-24s %s\n", f.Path, dimStyle.Render(note))
+			}
+		}
+
+		if len(unresolved) > 0 {
+			fmt.Println()
+			if pullDryRun {
+				fmt.Printf("Both sides changed %s; pull would ask which to keep.\n", strings.Join(unresolved, ", "))
+				return
+			}
+			fmt.Printf("Error: both sides changed %s; run 'pact pull' in a terminal, or pass --prefer local or remote\n", strings.Join(unresolved, ", "))
+			os.Exit(1)
+		}
+		if pullDryRun {
+			fmt.Println("\nDry run: nothing changed")
+			return
+		}
+
+		saved, err := git.FinishPull(pactDir, in, merged)
+		if saved != "" {
+			fmt.Printf("\nSaved the commits made here to branch %s\n", saved)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println()
+		fmt.Println("✓ Pulled latest changes")
+		if changed, err := git.HasChanges(pactDir); err == nil && changed {
+			fmt.Println("  Changes made here are left uncommitted; 'pact push' shares them")
+		}
+	},
+}
+
+// mergeIncoming decides what a file the remote changed should become: the
+// remote's version if it wasn't changed here, else both sides merged. It
+// returns nil for a deleted file and what was done, if worth saying. Value
+// conflicts it can't settle are added to unresolved.
+func mergeIncoming(f git.IncomingFile, interactive bool, unresolved *[]string) ([]byte, string, error) {
+	switch {
+	case bytes.Equal(f.Local, f.Remote) && (f.Local == nil) == (f.Remote == nil):
+		return f.Remote, "", nil
+	case bytes.Equal(f.Local, f.Base) && (f.Local == nil) == (f.Base == nil):
+		switch {
+		case f.Remote == nil:
+			return nil, "removed", nil
+		case f.Base == nil:
+			return f.Remote, "added", nil
+		}
+		return f.Remote, "updated", nil
+	}
+
+	if f.Path == "pact.json" && f.Local != nil && f.Remote != nil {
+		conflicts := 0
+		data, err := config.MergeJSON(f.Base, f.Local, f.Remote, func(path string, local, remote any) bool {
+			if pullPrefer != "" || interactive {
+				conflicts++
+			}
+			return pickSide(path, formatJSON(local), formatJSON(remote), interactive, unresolved)
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if conflicts > 0 {
+			return data, fmt.Sprintf("merged, %d setting(s) chosen", conflicts), nil
+		}
+		return data, "merged", nil
+	}
+
+	if isText(f.Local) && isText(f.Remote) {
+		var text string
+		var conflicts int
+		if f.Base != nil && isText(f.Base) {
+			text, conflicts = psync.Merge3(string(f.Base), string(f.Local), string(f.Remote))
+		} else {
+			text, conflicts = psync.Merge2(string(f.Local), string(f.Remote))
+		}
+		if conflicts == 0 {
+			return []byte(text), "merged", nil
+		}
+		if interactive && pullPrefer == "" {
+			fmt.Print(lineDiff(string(f.Local), string(f.Remote)))
+		}
+	}
+
+	local, remote := "changed here", "changed on GitHub"
+	if f.Local == nil {
+		local = "removed here"
+	}
+	if f.Remote == nil {
+		remote = "removed on GitHub"
+	}
+	if pickSide(f.Path, local, remote, interactive, unresolved) {
+		return f.Remote, "took GitHub's version", nil
+	}
+	return f.Local, "kept this machine's version", nil
+}
+
+// pickSide settles a conflict with --prefer, else by asking; it returns true
+// to take the remote's side. Without either, the conflict is left for the
+// caller to report and this machine's side stands in.
+func pickSide(what, local, remote string, interactive bool, unresolved *[]string) bool {
+	if pullPrefer != "" {
+		return pullPrefer == "remote"
+	}
+	if !interactive {
+		*unresolved = append(*unresolved, what)
+		return false
+	}
+
+	fmt.Printf("  ≠ %s\n", moduleStyle.Render(what))
+	fmt.Printf("    here:   %s\n", local)
+	fmt.Printf("    GitHub: %s\n", remote)
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Print("    Keep [h]ere or take [g]itHub's? ")
+		answer, err := reader.ReadString('\n')
+		switch strings.TrimSpace(strings.ToLower(answer)) {
+		case "h", "here":
+			return false
+		case "g", "github":
+			return true
+		}
+		if err != nil {
+			fmt.Println()
+			return false
+		}
+	}
+}
+
+// formatJSON shows a pact.json value on one line
+func formatJSON(v any) string {
+	if v == nil {
+		return "(removed)"
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func isText(data []byte) bool {
+	return data != nil && bytes.IndexByte(data, 0) < 0 && utf8.Valid(data)
+}
+
+func init() {
+	pullCmd.Flags().StringVar(&pullPrefer, "prefer", "", "Settle conflicts without asking: local or remote")
+	pullCmd.Flags().BoolVar(&pullDryRun, "dry-run", false, "Show what pulling would change without changing anything")
+	rootCmd.AddCommand(pullCmd)
+}
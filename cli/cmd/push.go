@@ -1,27 +1,52 @@
 package cmd
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/cloudboy-jh/pact/internal/config"
 	"github.com/cloudboy-jh/pact/internal/git"
-	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/sync"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var (
 	pushMessage string
 	pushForce   bool
+	pushDryRun  bool
+	pushReview  bool
+	pushPick    bool
+	pushExclude []string
 )
 
 var pushCmd = &cobra.Command{
 	Use:   "push",
 	Short: "Push local changes to GitHub",
-	Long:  `Commit and push all local changes in .pact/ to GitHub.`,
+	Long: `Commit and push all local changes in .pact/ to GitHub.
+
+Push first lists the changed files, with pact.json changes key by key.
+Leave files out with --exclude, or choose them with --pick; files whose
+only changes are covered by their "ignore" rules are always left out.
+
+With --dry-run, pact also shows the remote and branch, the commit message,
+and the diff of every file it would commit, then stops without committing
+or pushing. --review shows the same and pushes once you confirm; without a
+terminal to confirm in, nothing is pushed.
+
+Examples:
+  pact push -m "Add zed keybindings"
+  pact push --dry-run
+  pact push --review
+  pact push --pick
+  pact push --exclude shell/fish_variables`,
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+
 		if !config.Exists() {
 			fmt.Println("Pact is not initialized. Run 'pact init' first.")
 			os.Exit(1)
@@ -35,40 +60,121 @@ var pushCmd = &cobra.Command{
 		}
 
 		// Get token
-		token, err := keyring.GetToken()
+		token, err := remoteToken(pactDir)
 		if err != nil {
-			fmt.Println("Not authenticated. Run 'pact init' to authenticate.")
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := checkRepoPolicy(ctx, loadPolicy(), pactDir, token); err != nil {
+			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
 
 		// Check for changes
-		hasChanges, err := git.HasChanges(pactDir)
+		changes, err := git.Changes(pactDir)
 		if err != nil {
 			fmt.Printf("Error checking for changes: %v\n", err)
 			os.Exit(1)
 		}
+		if len(changes) == 0 {
+			fmt.Println("No changes to push.")
+			return
+		}
+		changed := make([]string, len(changes))
+		for i, c := range changes {
+			changed[i] = c.Path
+		}
+
+		// Files left out of the commit, with why
+		skip := make(map[string]string)
+		for _, p := range churnOnly(pactDir, changed) {
+			skip[p] = "only ignored changes"
+		}
+		for _, pattern := range pushExclude {
+			matched := false
+			for _, p := range changed {
+				if excludes(pactDir, pattern, p) {
+					skip[p] = "excluded"
+					matched = true
+				}
+			}
+			if !matched {
+				fmt.Printf("Warning: --exclude %s matches no changed file\n", pattern)
+			}
+		}
+		summarizePush(pactDir, changes, skip)
 
-		if !hasChanges {
+		if pushPick {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				fmt.Println("Error: --pick needs a terminal; use --exclude instead")
+				os.Exit(1)
+			}
+			var candidates, labels []string
+			for _, c := range changes {
+				if _, ok := skip[c.Path]; !ok {
+					candidates = append(candidates, c.Path)
+					labels = append(labels, c.Kind)
+				}
+			}
+			picked, ok := pickItems("Select files to commit:", candidates, labels)
+			if !ok {
+				fmt.Println("Cancelled.")
+				return
+			}
+			for _, p := range candidates {
+				if !containsString(picked, p) {
+					skip[p] = "deselected"
+				}
+			}
+		}
+
+		if len(skip) == len(changed) {
 			fmt.Println("No changes to push.")
 			return
 		}
+		skipped := make([]string, 0, len(skip))
+		for p := range skip {
+			skipped = append(skipped, p)
+		}
+		sort.Strings(skipped)
+
+		stripped := withoutChurn(pactDir, changed)
+		if pushDryRun {
+			previewPush(pactDir, changed, skipped, stripped, pushMessage)
+			fmt.Printf("Dry run: %d file(s) would be committed and pushed; nothing was changed\n", len(changed)-len(skip))
+			return
+		}
 
 		// Get commit message
 		message := pushMessage
 		if message == "" {
-			fmt.Print("Commit message: ")
-			reader := bufio.NewReader(os.Stdin)
-			message, _ = reader.ReadString('\n')
-			message = strings.TrimSpace(message)
+			var ok bool
+			if message, ok = readLine("Commit message: ", "commit-message"); !ok {
+				fmt.Println("Cancelled.")
+				return
+			}
 		}
 
 		if message == "" {
 			message = "Update pact configuration"
 		}
 
+		if pushReview {
+			previewPush(pactDir, changed, skipped, stripped, message)
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				fmt.Println("Nothing pushed: confirm in a terminal, or push without --review")
+				return
+			}
+			if !confirm("Push these changes?") {
+				fmt.Println("Nothing pushed.")
+				return
+			}
+		}
+
 		// Push
 		fmt.Println("Pushing changes...")
-		if err := git.Push(token, pactDir, message); err != nil {
+		if err := git.Push(ctx, token, pactDir, message, stripped, skipped...); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -77,7 +183,87 @@ var pushCmd = &cobra.Command{
 	},
 }
 
+// excludes reports whether an --exclude pattern, a path in .pact or a
+// directory holding it, covers a changed path
+func excludes(pactDir, pattern, changed string) bool {
+	if filepath.IsAbs(pattern) {
+		rel, err := filepath.Rel(pactDir, pattern)
+		if err != nil {
+			return false
+		}
+		pattern = rel
+	}
+	pattern = strings.TrimSuffix(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	return changed == pattern || strings.HasPrefix(changed, pattern+"/")
+}
+
+// churnFilters returns the "ignore" rules of the changed files whose file
+// entries have any, by path in .pact
+func churnFilters(pactDir string, changed []string) map[string]*sync.Filter {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	items, _ := cfg.GetSyncItems()
+	isChanged := make(map[string]bool)
+	for _, p := range changed {
+		isChanged[p] = true
+	}
+
+	filters := make(map[string]*sync.Filter)
+	for _, item := range items {
+		rel, err := filepath.Rel(pactDir, item.Source)
+		if err != nil || item.IsDir || !isChanged[filepath.ToSlash(rel)] {
+			continue
+		}
+		if filter, err := sync.NewFilter(item); filter != nil && err == nil {
+			filters[filepath.ToSlash(rel)] = filter
+		}
+	}
+	return filters
+}
+
+// churnOnly returns the changed files whose changes are all ignored by
+// their file entry's "ignore" rules
+func churnOnly(pactDir string, changed []string) []string {
+	var churn []string
+	for rel, filter := range churnFilters(pactDir, changed) {
+		committed, err := git.HeadFile(pactDir, rel)
+		if err != nil {
+			continue
+		}
+		current, err := os.ReadFile(filepath.Join(pactDir, rel))
+		if err == nil && filter.Same(committed, current) {
+			churn = append(churn, rel)
+		}
+	}
+	sort.Strings(churn)
+	return churn
+}
+
+// withoutChurn returns the changed files that have churn in them, stripped
+// of it, so a commit takes their real changes and leaves the churn behind
+func withoutChurn(pactDir string, changed []string) map[string][]byte {
+	stripped := make(map[string][]byte)
+	for rel, filter := range churnFilters(pactDir, changed) {
+		current, err := os.ReadFile(filepath.Join(pactDir, rel))
+		if err != nil {
+			continue
+		}
+		if clean := filter.Strip(current); !bytes.Equal(clean, current) {
+			stripped[rel] = clean
+		}
+	}
+	return stripped
+}
+
 func init() {
 	pushCmd.Flags().StringVarP(&pushMessage, "message", "m", "", "Commit message")
 	pushCmd.Flags().BoolVar(&pushForce, "force", false, "Force push (overwrite remote)")
+	pushCmd.Flags().BoolVar(&pushDryRun, "dry-run", false, "Preview the diff, commit message, and remote without committing or pushing")
+	pushCmd.Flags().BoolVar(&pushReview, "review", false, "Preview the diff, commit message, and remote, then ask before pushing")
+	pushCmd.Flags().BoolVar(&pushDryRun, "dry", false, "Same as --dry-run")
+	pushCmd.Flags().MarkHidden("dry")
+	pushCmd.Flags().BoolVar(&pushPick, "pick", false, "Choose which changed files to commit")
+	pushCmd.Flags().StringArrayVar(&pushExclude, "exclude", nil, "Leave a file, or a directory's files, out of the commit (repeatable)")
 }
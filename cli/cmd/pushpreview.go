@@ -0,0 +1,196 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+var (
+	addedLineStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#34d399"))
+	removedLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#f87171"))
+	hunkStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("#22d3ee"))
+)
+
+// diffContext is how many unchanged lines show around each change
+const diffContext = 3
+
+// summarizePush lists the changed files push would commit, with what
+// changed in pact.json key by key, and the files it leaves out
+func summarizePush(pactDir string, changes []git.FileChange, skip map[string]string) {
+	fmt.Println("Changes in .pact:")
+	for _, c := range changes {
+		if reason, ok := skip[c.Path]; ok {
+			fmt.Printf("  %s %s %s\n", localOnlyStyle.Render("○"), c.Path, dimStyle.Render("not committed: "+reason))
+			continue
+		}
+		switch c.Kind {
+		case git.ChangeAdded:
+			fmt.Printf("  %s %s\n", addedLineStyle.Render("+"), c.Path)
+		case git.ChangeDeleted:
+			fmt.Printf("  %s %s\n", removedLineStyle.Render("-"), c.Path)
+		default:
+			fmt.Printf("  %s %s\n", hunkStyle.Render("~"), c.Path)
+		}
+		if c.Path == "pact.json" && c.Kind != git.ChangeDeleted {
+			for _, change := range pactJSONChanges(pactDir) {
+				fmt.Printf("      %s\n", dimStyle.Render(change))
+			}
+		}
+	}
+	fmt.Println()
+}
+
+// pactJSONChanges describes the uncommitted changes to pact.json
+func pactJSONChanges(pactDir string) []string {
+	current, err := config.Load()
+	if err != nil {
+		return []string{"doesn't parse: " + err.Error()}
+	}
+	var previous *config.PactConfig
+	if data, err := git.HeadFile(pactDir, "pact.json"); err == nil {
+		previous, _ = config.Parse(data)
+	}
+	changes := config.Changes(previous, current)
+	if len(changes) == 0 {
+		return []string{"no setting changes (formatting or descriptions only)"}
+	}
+	return changes
+}
+
+// previewPush shows where push would go, its commit message, and the diff of
+// every file it would commit
+func previewPush(pactDir string, changed, skip []string, stripped map[string][]byte, message string) {
+	remote, err := git.RemoteURL(pactDir)
+	if err != nil {
+		remote = "(no remote)"
+	}
+	branch, err := git.Branch(pactDir)
+	if err != nil {
+		branch = "(no branch)"
+	}
+	fmt.Printf("Remote:  %s\n", remote)
+	fmt.Printf("Branch:  %s\n", branch)
+	if message == "" {
+		message = dimStyle.Render("(asked when pushing)")
+	}
+	fmt.Printf("Message: %s\n", message)
+
+	skipped := make(map[string]bool)
+	for _, p := range skip {
+		skipped[p] = true
+	}
+	for _, p := range changed {
+		if skipped[p] {
+			continue
+		}
+		old, _ := git.HeadFile(pactDir, p)
+		current, err := os.ReadFile(filepath.Join(pactDir, filepath.FromSlash(p)))
+		if clean, ok := stripped[p]; ok {
+			current = clean
+		}
+		fmt.Println()
+		switch {
+		case err != nil:
+			fmt.Println(moduleStyle.Render("deleted  " + p))
+		case old == nil:
+			fmt.Println(moduleStyle.Render("added    " + p))
+		default:
+			fmt.Println(moduleStyle.Render("modified " + p))
+		}
+		if bytes.IndexByte(old, 0) >= 0 || bytes.IndexByte(current, 0) >= 0 {
+			fmt.Println(dimStyle.Render("  binary file"))
+			continue
+		}
+		fmt.Print(lineDiff(string(old), string(current)))
+	}
+	fmt.Println()
+}
+
+// diffLine is one line of a line diff: '+', '-', or ' ' for context
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// hunkRange formats the lines from start up to end the way git does, where
+// an empty range names the line before it
+func hunkRange(start, end int) string {
+	if end == start {
+		return fmt.Sprintf("%d,0", start-1)
+	}
+	return fmt.Sprintf("%d,%d", start, end-start)
+}
+
+// lineDiff renders the changes from old to new as colorized hunks
+func lineDiff(old, new string) string {
+	var lines []diffLine
+	for _, d := range diff.Do(old, new) {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		}
+		for _, text := range strings.SplitAfter(d.Text, "\n") {
+			if text != "" {
+				lines = append(lines, diffLine{op, strings.TrimSuffix(text, "\n")})
+			}
+		}
+	}
+
+	// Line numbers in old and new where each diff line starts
+	oldNum, newNum := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	oldNum[0], newNum[0] = 1, 1
+	for i, l := range lines {
+		oldNum[i+1], newNum[i+1] = oldNum[i], newNum[i]
+		if l.op != '+' {
+			oldNum[i+1]++
+		}
+		if l.op != '-' {
+			newNum[i+1]++
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		// Grow the hunk until diffContext*2 unchanged lines separate changes
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(lines); j++ {
+			if lines[j].op != ' ' {
+				end = j + 1
+			} else if j-end >= diffContext*2 {
+				break
+			}
+		}
+		end = min(end+diffContext, len(lines))
+
+		b.WriteString(hunkStyle.Render(fmt.Sprintf("  @@ -%s +%s @@", hunkRange(oldNum[start], oldNum[end]), hunkRange(newNum[start], newNum[end]))) + "\n")
+		for _, l := range lines[start:end] {
+			text := fmt.Sprintf("  %c%s", l.op, l.text)
+			switch l.op {
+			case '+':
+				text = addedLineStyle.Render(text)
+			case '-':
+				text = removedLineStyle.Render(text)
+			}
+			b.WriteString(text + "\n")
+		}
+		i = end
+	}
+	return b.String()
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -17,15 +18,15 @@ import (
 	"github.com/cloudboy-jh/pact/internal/git"
 	"github.com/cloudboy-jh/pact/internal/keyring"
 	"github.com/cloudboy-jh/pact/internal/ui"
-	"github.com/pkg/browser"
 	"github.com/spf13/cobra"
 )
 
 var (
-	flagDiff   bool
-	flagJSON   bool
-	flagYes    bool
-	flagDryRun bool
+	flagDiff    bool
+	flagJSON    bool
+	flagYes     bool
+	flagDryRun  bool
+	flagTimings bool
 )
 
 var readCmd = &cobra.Command{
@@ -43,7 +44,8 @@ Examples:
   pact read --diff           # Show what differs from pact.json
   pact read --json           # Output as JSON (no prompts)
   pact read -y               # Import everything without prompts
-  pact read --dry-run        # Preview without modifying anything`,
+  pact read --dry-run        # Preview without modifying anything
+  pact read git --timings    # Show how long each probe took`,
 	Run: runRead,
 }
 
@@ -52,6 +54,7 @@ func init() {
 	readCmd.Flags().BoolVar(&flagJSON, "json", false, "Output detected config as JSON")
 	readCmd.Flags().BoolVarP(&flagYes, "yes", "y", false, "Import all detected items without prompting")
 	readCmd.Flags().BoolVar(&flagDryRun, "dry-run", false, "Preview changes without modifying anything")
+	readCmd.Flags().BoolVar(&flagTimings, "timings", false, "Show how long each probe took (on stderr)")
 
 	rootCmd.AddCommand(readCmd)
 }
@@ -59,7 +62,11 @@ func init() {
 func runRead(cmd *cobra.Command, args []string) {
 	// Check if pact is initialized
 	if !config.Exists() {
-		if !promptGitHubConnect() {
+		// Ctrl+C stops the clone and removes the half-cloned repo
+		ctx, stop := cancelOnInterrupt(cmd.Context())
+		connected := promptGitHubConnect(ctx)
+		stop()
+		if !connected {
 			return
 		}
 	}
@@ -68,12 +75,29 @@ func runRead(cmd *cobra.Command, args []string) {
 	fmt.Println("Scanning your development environment...")
 	fmt.Println()
 
+	// Load existing config for comparison
+	var existingCfg *config.PactConfig
+	if config.Exists() {
+		var err error
+		existingCfg, err = config.Load()
+		if err != nil {
+			fmt.Printf("Warning: Could not load existing pact.json: %v\n", err)
+		}
+	}
+
 	// Scan environment
 	opts := detect.ScanOptions{
 		Modules:      args,
 		IncludeFiles: true,
+		Context:      cmd.Context(),
+	}
+	if existingCfg != nil {
+		opts.Secrets = existingCfg.GetSecrets()
 	}
 	detected := detect.Scan(opts)
+	if flagTimings {
+		renderTimings(detected.Timings)
+	}
 
 	// If --json flag, output JSON and exit
 	if flagJSON {
@@ -86,25 +110,6 @@ func runRead(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	// Load existing config for comparison
-	var existingCfg *config.PactConfig
-	if config.Exists() {
-		var err error
-		existingCfg, err = config.Load()
-		if err != nil {
-			fmt.Printf("Warning: Could not load existing pact.json: %v\n", err)
-		}
-	}
-
-	// Get secrets from existing config for comparison
-	var existingSecrets []string
-	if existingCfg != nil {
-		existingSecrets = existingCfg.GetSecrets()
-	}
-
-	// Re-scan secrets with existing secrets for comparison
-	detected.Secrets = detect.DetectSecrets(existingSecrets)
-
 	// Update keychain status for secrets
 	for i := range detected.Secrets {
 		detected.Secrets[i].InKeychain = keyring.HasSecret(detected.Secrets[i].Name)
@@ -114,19 +119,38 @@ func runRead(cmd *cobra.Command, args []string) {
 	var diffs []detect.DiffResult
 	if existingCfg != nil {
 		diffs = detect.Compare(detected, existingCfg)
+		if len(args) > 0 {
+			// Modules that weren't scanned would all look pact-only
+			var scanned []detect.DiffResult
+			for _, d := range diffs {
+				if d.Module == "files" || containsString(args, d.Module) {
+					scanned = append(scanned, d)
+				}
+			}
+			diffs = scanned
+		}
+		diffs = withoutIgnored(existingCfg, diffs)
 	} else {
 		// No existing config - everything is "local only"
 		diffs = createAllLocalDiffs(detected)
 	}
 
-	// Render the diff
-	renderDiffs(diffs, existingCfg != nil)
+	// Render the diff, with how each missing item would be installed
+	var missing []missingItem
+	if existingCfg != nil {
+		missing = missingItems(existingCfg, diffs)
+	}
+	renderDiffs(diffs, existingCfg != nil, installPlans(missing))
 
 	// If --diff flag, just show diffs and exit
 	if flagDiff {
 		return
 	}
 
+	if !flagDryRun && !flagYes {
+		offerInstalls(cmd.Context(), existingCfg, missing, true)
+	}
+
 	// Count new items
 	newCount := detect.CountNewItems(diffs)
 	if newCount == 0 {
@@ -149,7 +173,7 @@ func runRead(cmd *cobra.Command, args []string) {
 	}
 
 	// Run interactive TUI picker
-	p := tea.NewProgram(initialReadModel(detected, diffs))
+	p := tea.NewProgram(initialReadModel(detected, diffs), ui.Keys().ProgramOptions()...)
 	result, err := p.Run()
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
@@ -162,8 +186,21 @@ func runRead(cmd *cobra.Command, args []string) {
 	}
 }
 
+// renderTimings lists each probe's time on stderr, so --json output stays
+// clean
+func renderTimings(timings []detect.ProbeTiming) {
+	for _, t := range timings {
+		if t.Skipped != "" {
+			fmt.Fprintf(os.Stderr, "  %-14s %s\n", t.Probe, dimStyle.Render("skipped ("+t.Skipped+")"))
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", t.Probe, t.Duration.Round(time.Microsecond))
+	}
+	fmt.Fprintln(os.Stderr)
+}
+
 // promptGitHubConnect prompts user to connect GitHub and initialize pact
-func promptGitHubConnect() bool {
+func promptGitHubConnect(ctx context.Context) bool {
 	fmt.Println(ui.RenderLogo())
 	fmt.Println()
 	fmt.Println("Pact is not initialized.")
@@ -179,84 +216,72 @@ func promptGitHubConnect() bool {
 	}
 
 	// Run the init flow
-	return runInitFlow()
+	return runInitFlow(ctx)
 }
 
 // runInitFlow runs the GitHub auth and repo setup (extracted from init.go)
-func runInitFlow() bool {
+func runInitFlow(ctx context.Context) bool {
 	// Check if we already have a token
 	if keyring.HasToken() {
 		fmt.Println("Found existing GitHub token. Verifying...")
 		token, _ := keyring.GetToken()
-		user, err := auth.GetUser(token)
+		login, err := githubLogin(ctx, token)
 		if err == nil {
-			fmt.Printf("Authenticated as %s\n", user.Login)
-			return setupPactRepo(token, user.Login)
+			fmt.Printf("Authenticated as %s\n", login)
+			return setupPactRepo(ctx, token, login)
 		}
 		fmt.Println("Token expired or invalid. Re-authenticating...")
 		keyring.DeleteToken()
 	}
 
-	// Start device flow
-	fmt.Println("Authenticating with GitHub...")
-	fmt.Println()
-
-	deviceCode, err := auth.RequestDeviceCode()
+	token, info, err := signIn(ctx)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return false
 	}
+	login := info.Login
 
-	fmt.Printf("Please visit: %s\n", deviceCode.VerificationURI)
-	fmt.Printf("And enter code: %s\n", deviceCode.UserCode)
-	fmt.Println()
-	fmt.Println("Waiting for authorization...")
-
-	// Try to open browser
-	browser.OpenURL(deviceCode.VerificationURI)
-
-	// Poll for token
-	token, err := auth.PollForToken(deviceCode.DeviceCode, deviceCode.Interval)
-	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return false
-	}
-
-	// Get user info
-	user, err := auth.GetUser(token)
-	if err != nil {
-		fmt.Printf("Error getting user info: %v\n", err)
-		return false
-	}
-
-	fmt.Printf("\n✓ Authenticated as %s\n", user.Login)
+	fmt.Printf("\n✓ Authenticated as %s\n", login)
 
 	// Store token
-	if err := keyring.SetToken(token); err != nil {
+	if err := storeToken(login, token); err != nil {
 		fmt.Printf("Warning: Could not store token in keychain: %v\n", err)
 	}
 
-	return setupPactRepo(token, user.Login)
+	return setupPactRepo(ctx, token, login)
 }
 
 // setupPactRepo creates the repo and clones it
-func setupPactRepo(token, username string) bool {
+func setupPactRepo(ctx context.Context, token, username string) bool {
+	pol := loadPolicy()
+	if err := pol.CheckRemote(git.DefaultRemote(username)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return false
+	}
+
 	// Check if repo exists
 	fmt.Printf("Checking for %s/my-pact repo...\n", username)
-	exists, err := auth.RepoExists(token, username)
+	repo, err := myPactRepo(ctx, token, username)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return false
 	}
 
-	if !exists {
+	if repo == nil {
 		fmt.Println("Repo not found. Creating...")
-		if err := auth.CreateRepo(token); err != nil {
+		private := newRepoPrivate(pol)
+		if err := auth.CreateRepo(ctx, token, private); err != nil {
 			fmt.Printf("Error: %v\n", err)
 			return false
 		}
-		fmt.Println("✓ Created my-pact repo")
+		rememberRepo(username, "my-pact", private)
+		fmt.Printf("✓ Created my-pact repo (%s)\n", visibility(private))
 		time.Sleep(2 * time.Second)
+	} else if pol.RequirePrivate {
+		if !repo.Private {
+			fmt.Printf("Error: %s/my-pact must be private under %s\n", username, pol.Path)
+			return false
+		}
 	}
 
 	// Get local pact directory
@@ -268,10 +293,11 @@ func setupPactRepo(token, username string) bool {
 
 	// Clone repo
 	fmt.Println("Cloning to ./.pact/...")
-	if err := git.Clone(token, username, pactDir); err != nil {
+	if err := git.Clone(ctx, token, git.DefaultRemote(username), pactDir); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return false
 	}
+	saveGitHubCache()
 	fmt.Println("✓ Cloned repo to ./.pact/")
 
 	return true
@@ -354,11 +380,26 @@ func createAllLocalDiffs(detected *detect.DetectedConfig) []detect.DiffResult {
 		diffs = append(diffs, diff)
 	}
 
+	// App Store, Flatpak, and Snap apps
+	if len(detected.Apps.Mas) > 0 || len(detected.Apps.Flatpak) > 0 || len(detected.Apps.Snap) > 0 {
+		diff := detect.DiffResult{Module: "apps"}
+		for _, app := range detected.Apps.Mas {
+			diff.LocalOnly = append(diff.LocalOnly, detect.DiffItem{Name: app.Name, Type: "mas", Value: app.ID})
+		}
+		for _, app := range detected.Apps.Flatpak {
+			diff.LocalOnly = append(diff.LocalOnly, detect.DiffItem{Name: app, Type: "flatpak"})
+		}
+		for _, app := range detected.Apps.Snap {
+			diff.LocalOnly = append(diff.LocalOnly, detect.DiffItem{Name: app, Type: "snap"})
+		}
+		diffs = append(diffs, diff)
+	}
+
 	// Secrets
 	if len(detected.Secrets) > 0 {
 		diff := detect.DiffResult{Module: "secrets"}
 		for _, s := range detected.Secrets {
-			diff.LocalOnly = append(diff.LocalOnly, detect.DiffItem{Name: s.Name, Type: "secret"})
+			diff.LocalOnly = append(diff.LocalOnly, detect.DiffItem{Name: s.Name, Type: "secret", Value: s.Location()})
 		}
 		diffs = append(diffs, diff)
 	}
@@ -395,7 +436,7 @@ var (
 )
 
 // renderDiffs displays the diff results
-func renderDiffs(diffs []detect.DiffResult, hasExisting bool) {
+func renderDiffs(diffs []detect.DiffResult, hasExisting bool, plans map[string]string) {
 	fmt.Println("Detected Configuration:")
 	fmt.Println(strings.Repeat("─", 60))
 
@@ -432,6 +473,9 @@ func renderDiffs(diffs []detect.DiffResult, hasExisting bool) {
 				pactOnlyStyle.Render("✗"),
 				item.Name,
 				pactOnlyStyle.Render("← PACT ONLY (not installed) "+value))
+			if plan, ok := plans[diff.Module+"/"+item.Type+"/"+item.Name]; ok {
+				fmt.Printf("      %s\n", dimStyle.Render("→ "+plan))
+			}
 		}
 	}
 
@@ -442,6 +486,28 @@ func renderDiffs(diffs []detect.DiffResult, hasExisting bool) {
 		syncedStyle.Render("●"),
 		localOnlyStyle.Render("○"),
 		pactOnlyStyle.Render("✗"))
+
+	if secretsNeedSync(diffs) {
+		fmt.Println(dimStyle.Render("Secrets aren't in both env and keychain. Run 'pact secret sync' to reconcile."))
+	}
+}
+
+// secretsNeedSync reports whether any secret lives only in env or only in
+// the keychain
+func secretsNeedSync(diffs []detect.DiffResult) bool {
+	for _, diff := range diffs {
+		if diff.Module != "secrets" {
+			continue
+		}
+		for _, items := range [][]detect.DiffItem{diff.Synced, diff.LocalOnly} {
+			for _, item := range items {
+				if item.Value == detect.SecretEnvOnly || item.Value == detect.SecretKeychainOnly {
+					return true
+				}
+			}
+		}
+	}
+	return false
 }
 
 func formatValue(v any) string {
@@ -497,8 +563,8 @@ func applySelection(selected map[string][]detect.DiffItem, detected *detect.Dete
 		if username == "" {
 			if keyring.HasToken() {
 				token, _ := keyring.GetToken()
-				if user, err := auth.GetUser(token); err == nil {
-					username = user.Login
+				if login, err := githubLogin(context.Background(), token); err == nil {
+					username = login
 				}
 			}
 		}
@@ -560,47 +626,6 @@ type readModel struct {
 	quitting  bool
 }
 
-type readKeyMap struct {
-	Up     key.Binding
-	Down   key.Binding
-	Toggle key.Binding
-	Enter  key.Binding
-	Back   key.Binding
-	All    key.Binding
-	Quit   key.Binding
-}
-
-var readKeys = readKeyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "down"),
-	),
-	Toggle: key.NewBinding(
-		key.WithKeys(" "),
-		key.WithHelp("space", "toggle"),
-	),
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "confirm"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("b", "esc"),
-		key.WithHelp("b/esc", "back"),
-	),
-	All: key.NewBinding(
-		key.WithKeys("a"),
-		key.WithHelp("a", "all"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
-}
-
 func initialReadModel(detected *detect.DetectedConfig, diffs []detect.DiffResult) readModel {
 	// Filter to only modules with local-only items
 	var filteredDiffs []detect.DiffResult
@@ -630,32 +655,35 @@ func (m readModel) Init() tea.Cmd {
 }
 
 func (m readModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keys := ui.Keys()
 	switch msg := msg.(type) {
+	case tea.MouseMsg:
+		m.cursor = max(0, min(m.cursor+keys.Scroll(msg), m.getMaxIndex()))
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, readKeys.Quit):
+		case key.Matches(msg, keys.Quit):
 			m.cancelled = true
 			m.quitting = true
 			return m, tea.Quit
 
-		case key.Matches(msg, readKeys.Up):
+		case key.Matches(msg, keys.Up):
 			if m.cursor > 0 {
 				m.cursor--
 			}
 
-		case key.Matches(msg, readKeys.Down):
+		case key.Matches(msg, keys.Down):
 			maxIdx := m.getMaxIndex()
 			if m.cursor < maxIdx {
 				m.cursor++
 			}
 
-		case key.Matches(msg, readKeys.Toggle):
+		case key.Matches(msg, keys.Toggle):
 			m.toggleCurrent()
 
-		case key.Matches(msg, readKeys.All):
+		case key.Matches(msg, keys.All):
 			m.toggleAll()
 
-		case key.Matches(msg, readKeys.Enter):
+		case key.Matches(msg, keys.Enter):
 			if m.stage == 0 {
 				// Move to item selection for first selected module
 				for i, d := range m.diffs {
@@ -683,7 +711,7 @@ func (m readModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, tea.Quit
 			}
 
-		case key.Matches(msg, readKeys.Back):
+		case key.Matches(msg, keys.Back):
 			if m.stage == 1 {
 				m.stage = 0
 				m.cursor = 0
@@ -801,7 +829,7 @@ func (m readModel) View() string {
 		}
 
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  ↑/↓: navigate  space: toggle  enter: continue  a: all  q: quit"))
+		b.WriteString(dimStyle.Render(listHelp("continue", ui.Keys().Quit, "quit")))
 	} else {
 		module := m.diffs[m.moduleIdx].Module
 		b.WriteString(fmt.Sprintf("\nImporting from: %s\n\n", moduleStyle.Render(module)))
@@ -829,7 +857,7 @@ func (m readModel) View() string {
 		}
 
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("  ↑/↓: navigate  space: toggle  enter: confirm  b: back  a: all"))
+		b.WriteString(dimStyle.Render(listHelp("confirm", ui.Keys().Back, "back")))
 	}
 
 	return b.String()
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/cloudboy-jh/pact/internal/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var reconcileModules []string
+
+// Actions a difference can be resolved with
+const (
+	actionSkip    = "skip"
+	actionInstall = "install"
+	actionImport  = "import"
+	actionIgnore  = "ignore"
+	actionDelete  = "delete"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Resolve drift both ways: install, import, ignore, or delete each difference",
+	Long: `Scan this machine, compare it against pact.json, and pick what to do
+about each difference, in either direction:
+
+  install  install an item in pact.json that's missing here
+  import   add an item found here to pact.json
+  ignore   never bring the difference up again on this machine
+  delete   remove an item that's missing here from pact.json
+
+Nothing runs until you've chosen for every difference; then the chosen
+actions run as one batch. Differences left at skip stay as they are.
+
+Examples:
+  pact reconcile
+  pact reconcile --module cli --module apps`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			fmt.Println("Error: pact reconcile needs a terminal; run 'pact diff' to see the drift")
+			os.Exit(1)
+		}
+
+		fmt.Println("Scanning your development environment...")
+		detected := detect.Scan(detect.ScanOptions{Modules: reconcileModules, Context: cmd.Context(), Secrets: cfg.GetSecrets()})
+		if cmd.Context().Err() != nil {
+			os.Exit(1)
+		}
+		for i := range detected.Secrets {
+			detected.Secrets[i].InKeychain = keyring.HasSecret(detected.Secrets[i].Name)
+		}
+
+		var drift []detect.DiffResult
+		for _, d := range detect.Drift(withoutIgnored(cfg, detect.Compare(detected, cfg))) {
+			if len(reconcileModules) == 0 || containsString(reconcileModules, d.Module) {
+				drift = append(drift, d)
+			}
+		}
+
+		rows := reconcileRows(cfg, drift)
+		if len(rows) == 0 {
+			fmt.Println("✓ This machine matches pact.json")
+			return
+		}
+
+		result, err := tea.NewProgram(reconcileModel{rows: rows, choices: make([]int, len(rows))}, ui.Keys().ProgramOptions()...).Run()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		m, _ := result.(reconcileModel)
+		if m.cancelled {
+			fmt.Println("Cancelled.")
+			return
+		}
+		runReconcile(cmd.Context(), cfg, detected, m.chosen())
+	},
+}
+
+// reconcileRow is one difference and the actions that can resolve it
+type reconcileRow struct {
+	module  string
+	item    detect.DiffItem
+	local   bool // Here but not in pact.json
+	actions []string
+	missing missingItem // How to install it, for pact-only items
+}
+
+func (r reconcileRow) describe() string {
+	if r.local {
+		return strings.TrimSpace("here, not in pact.json " + formatValue(r.item.Value))
+	}
+	return strings.TrimSpace("in pact.json, missing here " + formatValue(r.item.Value))
+}
+
+// reconcileRows lists the differences in drift with the actions each can
+// take
+func reconcileRows(cfg *config.PactConfig, drift []detect.DiffResult) []reconcileRow {
+	missing := make(map[string]missingItem)
+	for _, m := range missingItems(cfg, drift) {
+		missing[m.key()] = m
+	}
+
+	var rows []reconcileRow
+	for _, d := range drift {
+		for _, item := range d.LocalOnly {
+			rows = append(rows, reconcileRow{module: d.Module, item: item, local: true, actions: []string{actionSkip, actionImport, actionIgnore}})
+		}
+		for _, item := range d.PactOnly {
+			row := reconcileRow{module: d.Module, item: item, actions: []string{actionSkip}}
+			if m, ok := missing[d.Module+"/"+item.Type+"/"+item.Name]; ok && m.installable() {
+				row.missing = m
+				row.actions = append(row.actions, actionInstall)
+			}
+			if pactPaths(d.Module, item) != nil {
+				row.actions = append(row.actions, actionDelete)
+			}
+			row.actions = append(row.actions, actionIgnore)
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// pactPaths returns the pact.json lists a pact-only item can be deleted
+// from, or nil for items that have to be edited by hand
+func pactPaths(module string, item detect.DiffItem) []string {
+	value, _ := item.Value.(string)
+	switch module + "/" + item.Type {
+	case "cli/tool":
+		return []string{"cli.tools"}
+	case "cli/custom":
+		return []string{"cli.custom"}
+	case "cli/package":
+		return []string{"cli." + value}
+	case "shell/tool":
+		return []string{"shell.tools"}
+	case "editor/extension":
+		return []string{"editor." + value + ".extensions", "editor.extensions"}
+	case "apps/app":
+		return []string{fmt.Sprintf("apps.%s.install", runtime.GOOS)}
+	case "apps/startup":
+		return []string{fmt.Sprintf("apps.%s.startup", runtime.GOOS)}
+	case "llm/model":
+		return []string{"llm.local.models"}
+	case "llm/agent":
+		return []string{"llm.coding.agents"}
+	case "llm/provider":
+		return []string{"llm.providers"}
+	}
+	return nil
+}
+
+// reconcileChoice is a difference and the action picked for it
+type reconcileChoice struct {
+	row    reconcileRow
+	action string
+}
+
+// runReconcile carries out the chosen actions: ignores and deletions first,
+// then imports into pact.json, then installs
+func runReconcile(ctx context.Context, cfg *config.PactConfig, detected *detect.DetectedConfig, choices []reconcileChoice) {
+	var ignores []state.IgnoredItem
+	var deletes []reconcileRow
+	var installs []missingItem
+	imports := make(map[string][]detect.DiffItem)
+	for _, c := range choices {
+		switch c.action {
+		case actionIgnore:
+			ignores = append(ignores, state.IgnoredItem{Module: c.row.module, Type: c.row.item.Type, Name: c.row.item.Name})
+		case actionDelete:
+			deletes = append(deletes, c.row)
+		case actionImport:
+			imports[c.row.module] = append(imports[c.row.module], c.row.item)
+		case actionInstall:
+			installs = append(installs, c.row.missing)
+		}
+	}
+	if len(ignores)+len(deletes)+len(imports)+len(installs) == 0 {
+		fmt.Println("Nothing to do.")
+		return
+	}
+
+	fmt.Println()
+	if len(ignores) > 0 {
+		if err := state.Ignore(ignores...); err != nil {
+			fmt.Printf("Error: could not save ignored items: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Ignoring %d item(s) on this machine\n", len(ignores))
+	}
+
+	if len(deletes) > 0 {
+		removed := 0
+		for _, row := range deletes {
+			found := false
+			for _, p := range pactPaths(row.module, row.item) {
+				if cfg.RemoveValue(p, row.item.Name) {
+					found = true
+				}
+			}
+			if found {
+				removed++
+			} else {
+				fmt.Printf("  ✗ %s isn't in a list pact can edit; remove it with 'pact edit'\n", row.item.Name)
+			}
+		}
+		if err := cfg.Save(); err != nil {
+			fmt.Printf("Error saving pact.json: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Removed %d item(s) from pact.json\n", removed)
+		if len(imports) == 0 {
+			fmt.Println("\nRun 'pact push' to sync the change.")
+		}
+	}
+
+	if len(imports) > 0 {
+		applySelection(imports, detected)
+	}
+
+	if len(installs) > 0 {
+		installMissing(ctx, cfg, installs)
+	}
+}
+
+// ============================================================================
+// TUI Model for choosing an action per difference
+// ============================================================================
+
+type reconcileModel struct {
+	rows      []reconcileRow
+	choices   []int // Index into each row's actions
+	cursor    int
+	cancelled bool
+	quitting  bool
+}
+
+func (m reconcileModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m reconcileModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keys := ui.Keys()
+	if mouse, ok := msg.(tea.MouseMsg); ok {
+		m.cursor = max(0, min(m.cursor+keys.Scroll(mouse), len(m.rows)-1))
+		return m, nil
+	}
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Quit), key.Matches(keyMsg, keys.Back):
+		m.cancelled = true
+		m.quitting = true
+		return m, tea.Quit
+	case key.Matches(keyMsg, keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, keys.Down):
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, keys.Toggle):
+		m.choices[m.cursor] = (m.choices[m.cursor] + 1) % len(m.rows[m.cursor].actions)
+	case key.Matches(keyMsg, keys.All):
+		// Give every difference the action under the cursor, where it
+		// can take it, and skip the rest
+		action := m.rows[m.cursor].actions[m.choices[m.cursor]]
+		for i, row := range m.rows {
+			m.choices[i] = 0
+			for j, a := range row.actions {
+				if a == action {
+					m.choices[i] = j
+				}
+			}
+		}
+	case key.Matches(keyMsg, keys.Enter):
+		m.quitting = true
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m reconcileModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	keys := ui.Keys()
+	var b strings.Builder
+	b.WriteString("\nResolve drift: pick an action for each difference\n")
+	module := ""
+	for i, row := range m.rows {
+		if row.module != module {
+			module = row.module
+			b.WriteString("\n" + moduleStyle.Render(module) + "\n")
+		}
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		glyph := pactOnlyStyle.Render("✗")
+		if row.local {
+			glyph = localOnlyStyle.Render("○")
+		}
+		action := row.actions[m.choices[i]]
+		label := fmt.Sprintf("[%-7s]", action)
+		if action == actionSkip {
+			label = dimStyle.Render(label)
+		}
+		b.WriteString(fmt.Sprintf("%s%s %s %-24s %s\n", cursor, label, glyph, row.item.Name, dimStyle.Render(row.describe())))
+	}
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  %s: navigate  %s: change action  %s: same action for all  %s: run  %s: quit",
+		navLabel(), ui.Label(keys.Toggle), ui.Label(keys.All), ui.Label(keys.Enter), ui.Label(keys.Quit))))
+	return b.String()
+}
+
+// chosen returns the differences with an action other than skip
+func (m reconcileModel) chosen() []reconcileChoice {
+	var choices []reconcileChoice
+	for i, row := range m.rows {
+		if action := row.actions[m.choices[i]]; action != actionSkip {
+			choices = append(choices, reconcileChoice{row: row, action: action})
+		}
+	}
+	return choices
+}
+
+func init() {
+	reconcileCmd.Flags().StringSliceVarP(&reconcileModules, "module", "m", nil, "Only reconcile these modules (repeat or comma-separate)")
+	rootCmd.AddCommand(reconcileCmd)
+}
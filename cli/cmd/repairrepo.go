@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var repairRepoCmd = &cobra.Command{
+	Use:   "repair-repo",
+	Short: "Get the pact repo back on its branch so pull and push work",
+	Long: `Fix a .pact repo that 'pact sync' and 'pact push' can't pull or push from:
+  - an unfinished rebase is aborted, putting the branch back where it was
+  - an unfinished merge is aborted, dropping its changes
+  - a detached HEAD (after checking out an old commit) returns to the
+    default branch; commits made while detached are kept on a
+    pact-detached-<sha> branch
+
+Examples:
+  pact repair-repo`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		state, err := git.State(pactDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if state == "" {
+			fmt.Println("✓ The pact repo is on a branch with no rebase or merge in progress")
+			return
+		}
+
+		done, err := git.Repair(pactDir)
+		for _, step := range done {
+			fmt.Printf("✓ %s\n", step)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repairRepoCmd)
+}
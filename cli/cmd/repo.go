@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/spf13/cobra"
+)
+
+var repoYes bool
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Manage the pact repo on GitHub",
+}
+
+var repoVisibilityCmd = &cobra.Command{
+	Use:   "visibility [private|public]",
+	Short: "Show or change who can see your my-pact repo",
+	Long: `Show whether your my-pact repo on GitHub is private, or make it private or
+public. Making it public asks first, since pact.json often has your email,
+aliases, and tool list; --yes skips the question.
+
+Examples:
+  pact repo visibility
+  pact repo visibility private`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"private", "public"},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := cmd.Context()
+		if len(args) == 1 && args[0] != "private" && args[0] != "public" {
+			fmt.Printf("Error: unknown visibility '%s' (use private or public)\n", args[0])
+			os.Exit(1)
+		}
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		remote, err := git.RemoteURL(pactDir)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		owner, name := git.RepoPath(remote)
+		if !git.IsGitHub(remote) || name != "my-pact" {
+			fmt.Printf("Error: %s isn't a my-pact repo on GitHub; change its visibility on its host\n", remote)
+			os.Exit(1)
+		}
+		token, err := getToken()
+		if err != nil {
+			fmt.Println("Not authenticated. Run 'pact init' to authenticate.")
+			os.Exit(1)
+		}
+
+		private, err := auth.RepoIsPrivate(ctx, token, owner)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(args) == 0 {
+			fmt.Printf("%s/my-pact is %s\n", owner, visibility(private))
+			return
+		}
+
+		want := args[0] == "private"
+		if want == private {
+			fmt.Printf("✓ %s/my-pact is already %s\n", owner, visibility(private))
+			return
+		}
+		if !want {
+			if pol := loadPolicy(); pol.RequirePrivate {
+				fmt.Printf("Error: %s requires a private repo\n", pol.Path)
+				os.Exit(1)
+			}
+			if !repoYes && !confirm(fmt.Sprintf("Anyone will be able to read %s/my-pact, including pact.json and every file in it. Make it public?", owner)) {
+				fmt.Println("Cancelled.")
+				return
+			}
+		}
+
+		if err := auth.SetRepoPrivate(ctx, token, owner, want); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		rememberRepo(owner, "my-pact", want)
+		fmt.Printf("✓ %s/my-pact is now %s\n", owner, visibility(want))
+	},
+}
+
+func visibility(private bool) string {
+	if private {
+		return "private"
+	}
+	return "public"
+}
+
+func init() {
+	repoVisibilityCmd.Flags().BoolVarP(&repoYes, "yes", "y", false, "Make the repo public without asking")
+	repoCmd.AddCommand(repoVisibilityCmd)
+	rootCmd.AddCommand(repoCmd)
+}
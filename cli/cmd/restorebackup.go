@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/backup"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var restoreBackupCmd = &cobra.Command{
+	Use:   "restore-backup [backup|latest] [path...]",
+	Short: "Put back files a sync replaced",
+	Long: `When a sync replaces a file or directory that pact didn't place, it moves
+the original to .pact/state/backups/<timestamp>/ first. With no arguments,
+list the backups; with a backup (or "latest"), put its files back, or only
+the given paths. Anything pact placed there since is removed if it's a
+symlink and backed up itself otherwise.
+
+'pact clean' removes backups older than its retention window.
+
+Examples:
+  pact restore-backup
+  pact restore-backup latest
+  pact restore-backup 20250114-093012 ~/.zshrc`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+
+		if len(args) == 0 {
+			sets, err := backup.List()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(sets) == 0 {
+				fmt.Println("No backups.")
+				return
+			}
+			for _, set := range sets {
+				fmt.Println(moduleStyle.Render(set.ID))
+				for _, f := range set.Files {
+					fmt.Printf("  %s\n", f.Path)
+				}
+			}
+			return
+		}
+
+		set, err := backup.Load(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		restored, err := set.Restore(args[1:]...)
+		for _, p := range restored {
+			fmt.Printf("✓ Restored %s\n", p)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreBackupCmd)
+}
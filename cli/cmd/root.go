@@ -8,6 +8,9 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
 	"github.com/cloudboy-jh/pact/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -49,7 +52,77 @@ func Execute() {
 	}
 }
 
+// useGitBackend applies git.backend from pact.json before any command runs
+func useGitBackend() {
+	if !config.Exists() {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	if err := git.SetBackend(cfg.GetString("git.backend")); err != nil {
+		fmt.Printf("Warning: git.backend: %v\n", err)
+	}
+	a, err := gitAuth(cfg.Get("git.auth"))
+	if err != nil {
+		fmt.Printf("Warning: git.auth: %v\n", err)
+	}
+	git.SetAuth(a)
+}
+
+// gitAuth reads git.auth: "token" or "ssh", or an object such as
+// {"type": "ssh", "key": "~/.ssh/id_ed25519"} or {"type": "token",
+// "username": "oauth2"}
+func gitAuth(v any) (git.Auth, error) {
+	var kind, keyFile, username string
+	switch val := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		kind = val
+	case map[string]any:
+		kind, _ = val["type"].(string)
+		keyFile, _ = val["key"].(string)
+		username, _ = val["username"].(string)
+	default:
+		return nil, fmt.Errorf("should be \"token\", \"ssh\", or an object")
+	}
+	switch kind {
+	case "", "token":
+		return git.TokenAuth{Username: username}, nil
+	case "ssh":
+		return git.SSHAuth{KeyFile: keyFile}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q (use token or ssh)", kind)
+}
+
+// useAccount picks the GitHub account this workspace is bound to, if any
+func useAccount() {
+	if !config.Exists() {
+		return
+	}
+	if login := state.Account(); login != "" {
+		keyring.UseAccount(login)
+	}
+}
+
+// useKeymap applies ui.keymap and ui.mouse from pact.json to the TUIs
+func useKeymap() {
+	if !config.Exists() {
+		return
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return
+	}
+	if err := ui.LoadKeymap(cfg); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
 func init() {
+	cobra.OnInitialize(useGitBackend, migrateTokens, useAccount, useKeymap)
 	rootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "Print version information")
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(syncCmd)
@@ -67,27 +140,7 @@ type model struct {
 	cfg      *config.PactConfig
 	quitting bool
 	err      error
-}
-
-type keyMap struct {
-	Sync key.Binding
-	Edit key.Binding
-	Quit key.Binding
-}
-
-var keys = keyMap{
-	Sync: key.NewBinding(
-		key.WithKeys("s"),
-		key.WithHelp("s", "sync"),
-	),
-	Edit: key.NewBinding(
-		key.WithKeys("e"),
-		key.WithHelp("e", "edit"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("q", "ctrl+c"),
-		key.WithHelp("q", "quit"),
-	),
+	width    int
 }
 
 func initialModel() model {
@@ -115,18 +168,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case editDoneMsg:
 		return m, nil
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
 	case tea.KeyMsg:
 		switch {
-		case key.Matches(msg, keys.Quit):
+		case key.Matches(msg, ui.Keys().Quit):
 			m.quitting = true
 			return m, tea.Quit
-		case key.Matches(msg, keys.Sync):
+		case key.Matches(msg, ui.Keys().Sync):
 			// Run sync command
 			c := exec.Command(os.Args[0], "sync")
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
 				return syncDoneMsg{err}
 			})
-		case key.Matches(msg, keys.Edit):
+		case key.Matches(msg, ui.Keys().Edit):
 			// Run edit command
 			c := exec.Command(os.Args[0], "edit")
 			return m, tea.ExecProcess(c, func(err error) tea.Msg {
@@ -143,12 +199,12 @@ func (m model) View() string {
 	}
 
 	if m.err != nil {
-		return fmt.Sprintf("Error loading config: %v\n\nPress q to quit.", m.err)
+		return fmt.Sprintf("Error loading config: %v\n\nPress %s to quit.", m.err, ui.Label(ui.Keys().Quit))
 	}
 
 	if m.cfg == nil {
 		return "Loading...\n"
 	}
 
-	return ui.RenderStatus(m.cfg, 0, 0)
+	return ui.RenderStatus(m.cfg, 0, 0, m.width)
 }
@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"syscall"
 
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/crypto"
+	"github.com/cloudboy-jh/pact/internal/detect"
 	"github.com/cloudboy-jh/pact/internal/keyring"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
@@ -19,11 +25,16 @@ var secretCmd = &cobra.Command{
 	Long:  `Manage secrets stored in your OS keychain.`,
 }
 
+var secretProject string
+
 var secretSetCmd = &cobra.Command{
 	Use:   "set <name>",
 	Short: "Set a secret",
-	Long:  `Store a secret in the OS keychain.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Store a secret in the OS keychain.
+
+With --project, the secret is stored under that project's scope so the same
+name can hold different values per project.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 
@@ -46,12 +57,17 @@ var secretSetCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
-		if err := keyring.SetSecret(name, value); err != nil {
+		if secretProject != "" {
+			err = keyring.SetProjectSecret(secretProject, name, value)
+		} else {
+			err = keyring.SetSecret(name, value)
+		}
+		if err != nil {
 			fmt.Printf("Error storing secret: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Secret '%s' stored in keychain\n", name)
+		fmt.Printf("✓ Secret '%s' stored in keychain%s\n", name, projectSuffix(secretProject))
 	},
 }
 
@@ -67,17 +83,31 @@ var secretListCmd = &cobra.Command{
 		}
 
 		secrets := cfg.GetSecrets()
-		if len(secrets) == 0 {
+		projects := cfg.GetSecretProjects()
+		if len(secrets) == 0 && len(projects) == 0 {
 			fmt.Println("No secrets configured in pact.json")
 			return
 		}
 
-		fmt.Println("Secrets:")
-		for _, name := range secrets {
-			if keyring.HasSecret(name) {
-				fmt.Printf("  ● %s (set)\n", name)
-			} else {
-				fmt.Printf("  ○ %s (not set)\n", name)
+		if len(secrets) > 0 {
+			fmt.Println("Secrets:")
+			for _, name := range secrets {
+				if keyring.HasSecret(name) {
+					fmt.Printf("  ● %s (set)\n", name)
+				} else {
+					fmt.Printf("  ○ %s (not set)\n", name)
+				}
+			}
+		}
+
+		for _, project := range projects {
+			fmt.Printf("\nProject %s:\n", project)
+			for _, name := range cfg.GetProjectSecrets(project) {
+				if keyring.HasProjectSecret(project, name) {
+					fmt.Printf("  ● %s (set)\n", name)
+				} else {
+					fmt.Printf("  ○ %s (not set)\n", name)
+				}
 			}
 		}
 	},
@@ -91,22 +121,420 @@ var secretRemoveCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		name := args[0]
 
-		if !keyring.HasSecret(name) {
-			fmt.Printf("Secret '%s' is not set\n", name)
+		var err error
+		if secretProject != "" {
+			if !keyring.HasProjectSecret(secretProject, name) {
+				fmt.Printf("Secret '%s' is not set%s\n", name, projectSuffix(secretProject))
+				return
+			}
+			err = keyring.DeleteProjectSecret(secretProject, name)
+		} else {
+			if !keyring.HasSecret(name) {
+				fmt.Printf("Secret '%s' is not set\n", name)
+				return
+			}
+			err = keyring.DeleteSecret(name)
+		}
+		if err != nil {
+			fmt.Printf("Error removing secret: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Secret '%s' removed from keychain%s\n", name, projectSuffix(secretProject))
+	},
+}
+
+var (
+	secretSyncToKeychain bool
+	secretSyncExport     bool
+)
+
+var secretSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Reconcile secrets between env and keychain",
+	Long: `Show whether each secret in pact.json lives in the environment, the
+keychain, or both, and reconcile them:
+
+  --to-keychain   copy env-only values into the keychain
+  --export        print export lines for keychain values missing from env
+
+Examples:
+  pact secret sync                    # Show where each secret lives
+  pact secret sync --to-keychain      # Store env-only secrets in keychain
+  eval "$(pact secret sync --export)" # Load keychain-only secrets into env`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		secrets := cfg.GetSecrets()
+		if len(secrets) == 0 {
+			if !secretSyncExport {
+				fmt.Println("No secrets configured in pact.json")
+			}
 			return
 		}
 
-		if err := keyring.DeleteSecret(name); err != nil {
-			fmt.Printf("Error removing secret: %v\n", err)
+		var envOnly, keychainOnly []string
+		for _, name := range secrets {
+			_, inEnv := os.LookupEnv(name)
+			s := detect.SecretDetected{Name: name, InEnv: inEnv, InKeychain: keyring.HasSecret(name)}
+			switch s.Location() {
+			case detect.SecretEnvOnly:
+				envOnly = append(envOnly, name)
+			case detect.SecretKeychainOnly:
+				keychainOnly = append(keychainOnly, name)
+			}
+
+			if !secretSyncExport && !secretSyncToKeychain {
+				icon := "●"
+				if s.Location() != detect.SecretInBoth {
+					icon = "○"
+				}
+				fmt.Printf("  %s %-28s %s\n", icon, name, s.Location())
+			}
+		}
+
+		// Export lines go to stdout alone so they can be eval'd
+		if secretSyncExport {
+			for _, name := range keychainOnly {
+				value, err := keyring.GetSecret(name)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "✗ %s: %v\n", name, err)
+					continue
+				}
+				fmt.Println(exportLine(name, value))
+			}
+			return
+		}
+
+		if secretSyncToKeychain {
+			for _, name := range envOnly {
+				if err := keyring.SetSecret(name, os.Getenv(name)); err != nil {
+					fmt.Printf("  ✗ %s: %v\n", name, err)
+					continue
+				}
+				fmt.Printf("  ✓ %s copied to keychain\n", name)
+			}
+			if len(envOnly) == 0 {
+				fmt.Println("No env-only secrets to copy.")
+			}
+			return
+		}
+
+		if len(envOnly) > 0 {
+			fmt.Printf("\n%d env-only: run 'pact secret sync --to-keychain' to store them\n", len(envOnly))
+		}
+		if len(keychainOnly) > 0 {
+			fmt.Printf("%d keychain-only: run 'eval \"$(pact secret sync --export)\"' to load them\n", len(keychainOnly))
+		}
+	},
+}
+
+// exportLine renders a shell statement that sets name=value in the current
+// shell: PowerShell on Windows, POSIX sh elsewhere
+func exportLine(name, value string) string {
+	if runtime.GOOS == "windows" {
+		return fmt.Sprintf("$env:%s = '%s'", name, strings.ReplaceAll(value, "'", "''"))
+	}
+	return fmt.Sprintf("export %s='%s'", name, strings.ReplaceAll(value, "'", `'\''`))
+}
+
+var secretExportFile string
+
+var secretExportCmd = &cobra.Command{
+	Use:   "export --encrypted <file>",
+	Short: "Export keychain secrets to a passphrase-encrypted file",
+	Long: `Export every secret in pact.json that is set in the keychain to a file
+encrypted with a passphrase (age format), for moving secrets to a new
+machine. Import it there with 'pact secret import <file>'.
+
+The file can also be decrypted with the age CLI: age -d <file>
+
+Example:
+  pact secret export --encrypted secrets.age`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if secretExportFile == "" {
+			fmt.Println("Error: --encrypted <file> is required (secrets are never exported in plaintext)")
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		export := collectSecrets(cfg)
+		if export.Count() == 0 {
+			fmt.Println("No secrets from pact.json are set in the keychain.")
+			return
+		}
+
+		passphrase := readPassphrase("Passphrase: ")
+		if passphrase == "" {
+			fmt.Println("Error: passphrase cannot be empty")
+			os.Exit(1)
+		}
+		if readPassphrase("Confirm passphrase: ") != passphrase {
+			fmt.Println("Error: passphrases don't match")
+			os.Exit(1)
+		}
+
+		plaintext, err := json.Marshal(export)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		encrypted, err := crypto.Encrypt(plaintext, passphrase, crypto.DefaultWorkFactor)
+		if err != nil {
+			fmt.Printf("Error encrypting secrets: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(secretExportFile, encrypted, 0600); err != nil {
+			fmt.Printf("Error writing %s: %v\n", secretExportFile, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Exported %d secret(s) to %s\n", export.Count(), secretExportFile)
+	},
+}
+
+var secretImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import secrets from a passphrase-encrypted file",
+	Long:  `Decrypt a file created by 'pact secret export' and store its secrets in the keychain.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		plaintext, err := crypto.Decrypt(data, readPassphrase("Passphrase: "))
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var bundle crypto.Bundle
+		if err := json.Unmarshal(plaintext, &bundle); err != nil {
+			fmt.Printf("Error: %s is not a pact secrets export: %v\n", args[0], err)
+			os.Exit(1)
+		}
+
+		var known []string
+		if cfg, err := config.Load(); err == nil {
+			known = cfg.GetSecrets()
+		}
+
+		names := make([]string, 0, len(bundle.Secrets))
+		for name := range bundle.Secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := keyring.SetSecret(name, bundle.Secrets[name]); err != nil {
+				fmt.Printf("  ✗ %s: %v\n", name, err)
+				continue
+			}
+			note := ""
+			if known != nil && !containsString(known, name) {
+				note = " (not in pact.json)"
+			}
+			fmt.Printf("  ✓ %s%s\n", name, note)
+		}
+
+		projects := make([]string, 0, len(bundle.Projects))
+		for project := range bundle.Projects {
+			projects = append(projects, project)
+		}
+		sort.Strings(projects)
+
+		for _, project := range projects {
+			for name, value := range bundle.Projects[project] {
+				if err := keyring.SetProjectSecret(project, name, value); err != nil {
+					fmt.Printf("  ✗ %s%s: %v\n", name, projectSuffix(project), err)
+					continue
+				}
+				fmt.Printf("  ✓ %s%s\n", name, projectSuffix(project))
+			}
+		}
+	},
+}
+
+var secretKeygenImport bool
+
+var secretKeygenCmd = &cobra.Command{
+	Use:   "keygen",
+	Short: "Create the age key that decrypts secrets.enc",
+	Long: `Create an age key pair for this machine and store the secret key in the
+keychain. Add the printed public key (age1...) to "secrets.recipients" in
+pact.json, then run 'pact secret seal'.
+
+On a new machine, bring an existing key over with --import instead, so
+'pact sync secrets' can decrypt secrets.enc there.
+
+Examples:
+  pact secret keygen
+  pact secret keygen --import`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if secretKeygenImport {
+			identity := strings.TrimSpace(readPassphrase("Age secret key (AGE-SECRET-KEY-1...): "))
+			recipient, err := crypto.Recipient(identity)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if err := keyring.SetAgeIdentity(identity); err != nil {
+				fmt.Printf("Error storing key: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("✓ Age key stored in keychain (public key %s)\n", recipient)
+			return
+		}
+
+		if _, err := keyring.GetAgeIdentity(); err == nil {
+			fmt.Println("Error: this machine already has an age key; use --import to replace it")
+			os.Exit(1)
+		}
+		identity, recipient, err := crypto.GenerateIdentity()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := keyring.SetAgeIdentity(identity); err != nil {
+			fmt.Printf("Error storing key: %v\n", err)
 			os.Exit(1)
 		}
 
-		fmt.Printf("✓ Secret '%s' removed from keychain\n", name)
+		fmt.Println("✓ Age key stored in keychain")
+		fmt.Printf("\nPublic key: %s\n", recipient)
+		fmt.Println("Add it to \"secrets.recipients\" in pact.json, then run 'pact secret seal'.")
+		fmt.Println("\nSave the secret key somewhere safe; new machines need it to decrypt secrets.enc:")
+		fmt.Printf("  %s\n", identity)
 	},
 }
 
+var secretSealCmd = &cobra.Command{
+	Use:   "seal",
+	Short: "Encrypt keychain secrets into secrets.enc in the pact repo",
+	Long: `Encrypt every secret in pact.json that is set in the keychain to the keys
+in "secrets.recipients" and write it to secrets.enc in the pact repo. Commit
+it with 'pact push'; 'pact sync secrets' decrypts it into the keychain on
+another machine.
+
+Recipients are age public keys (age1..., from 'pact secret keygen') or GPG
+key IDs, fingerprints, or emails (encrypted with the gpg CLI), not a mix:
+
+  "secrets": {
+    "global": ["OPENAI_API_KEY"],
+    "recipients": ["age1..."]
+  }
+
+Run it again after adding a recipient or changing a secret.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		recipients := cfg.GetSecretRecipients()
+		if len(recipients) == 0 {
+			fmt.Println("Error: no recipients; add age keys (from 'pact secret keygen') or GPG key IDs to \"secrets.recipients\" in pact.json")
+			os.Exit(1)
+		}
+
+		bundle := collectSecrets(cfg)
+		if bundle.Count() == 0 {
+			fmt.Println("No secrets from pact.json are set in the keychain.")
+			return
+		}
+
+		sealed, err := crypto.Seal(cmd.Context(), bundle, recipients)
+		if err != nil {
+			fmt.Printf("Error encrypting secrets: %v\n", err)
+			os.Exit(1)
+		}
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if err := os.WriteFile(filepath.Join(pactDir, crypto.SecretsFile), sealed, 0644); err != nil {
+			fmt.Printf("Error writing %s: %v\n", crypto.SecretsFile, err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✓ Encrypted %d secret(s) to %s for %d recipient(s)\n", bundle.Count(), crypto.SecretsFile, len(recipients))
+		fmt.Println("Run 'pact push' to commit it.")
+	},
+}
+
+func projectSuffix(project string) string {
+	if project == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (project %s)", project)
+}
+
+// collectSecrets gathers the keychain values of every secret in pact.json
+func collectSecrets(cfg *config.PactConfig) crypto.Bundle {
+	bundle := crypto.Bundle{Version: 1, Secrets: make(map[string]string)}
+	for _, name := range cfg.GetSecrets() {
+		if value, err := keyring.GetSecret(name); err == nil {
+			bundle.Secrets[name] = value
+		}
+	}
+	for _, project := range cfg.GetSecretProjects() {
+		for _, name := range cfg.GetProjectSecrets(project) {
+			if value, err := keyring.GetProjectSecret(project, name); err == nil {
+				if bundle.Projects == nil {
+					bundle.Projects = make(map[string]map[string]string)
+				}
+				if bundle.Projects[project] == nil {
+					bundle.Projects[project] = make(map[string]string)
+				}
+				bundle.Projects[project][name] = value
+			}
+		}
+	}
+	return bundle
+}
+
+// readPassphrase reads a passphrase without echo
+func readPassphrase(prompt string) string {
+	fmt.Print(prompt)
+	password, err := term.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		reader := bufio.NewReader(os.Stdin)
+		input, _ := reader.ReadString('\n')
+		return strings.TrimRight(input, "\r\n")
+	}
+	return string(password)
+}
+
 func init() {
+	secretSetCmd.Flags().StringVar(&secretProject, "project", "", "Scope the secret to a project")
+	secretRemoveCmd.Flags().StringVar(&secretProject, "project", "", "Remove the secret from a project's scope")
+	secretExportCmd.Flags().StringVar(&secretExportFile, "encrypted", "", "Write secrets to this passphrase-encrypted file")
+
+	secretKeygenCmd.Flags().BoolVar(&secretKeygenImport, "import", false, "Store an existing age secret key instead of creating one")
+
+	secretSyncCmd.Flags().BoolVar(&secretSyncToKeychain, "to-keychain", false, "Copy env-only secret values into the keychain")
+	secretSyncCmd.Flags().BoolVar(&secretSyncExport, "export", false, "Print export lines for keychain-only secrets")
+
 	secretCmd.AddCommand(secretSetCmd)
 	secretCmd.AddCommand(secretListCmd)
 	secretCmd.AddCommand(secretRemoveCmd)
+	secretCmd.AddCommand(secretSyncCmd)
+	secretCmd.AddCommand(secretExportCmd)
+	secretCmd.AddCommand(secretImportCmd)
+	secretCmd.AddCommand(secretKeygenCmd)
+	secretCmd.AddCommand(secretSealCmd)
 }
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Manage named environment snapshots",
+	Long: `Save and restore named snapshots of your environment definition:
+pact.json, pact.lock (if present), and this machine's install journal.
+
+Snapshots live in .pact/state and are never pushed.
+
+Examples:
+  pact snapshot create before-zsh-rework
+  pact snapshot list
+  pact snapshot restore before-zsh-rework`,
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Capture the current environment definition",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		snap, err := state.CreateSnapshot(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Snapshot '%s' created (%s)\n", snap.Name, strings.Join(snap.Files, ", "))
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List snapshots",
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		snapshots, err := state.ListSnapshots()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(snapshots) == 0 {
+			fmt.Println("No snapshots. Create one with 'pact snapshot create <name>'.")
+			return
+		}
+		for _, snap := range snapshots {
+			fmt.Printf("  %-24s %s\n", snap.Name, snap.Created.Format("2006-01-02 15:04"))
+		}
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <name>",
+	Short: "Restore a snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		if !confirm(fmt.Sprintf("Replace pact.json and the install journal with snapshot '%s'?", args[0])) {
+			fmt.Println("Cancelled.")
+			return
+		}
+
+		snap, err := state.RestoreSnapshot(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Restored %s from '%s'\n", strings.Join(snap.Files, ", "), snap.Name)
+		fmt.Println("\nRun 'pact sync' to apply it, then 'pact push' to share it.")
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a snapshot",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		if err := state.DeleteSnapshot(args[0]); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Snapshot '%s' deleted\n", args[0])
+	},
+}
+
+// requireInitialized exits when there is no pact.json
+func requireInitialized() {
+	if !config.Exists() {
+		fmt.Println("Pact is not initialized. Run 'pact init' first.")
+		os.Exit(1)
+	}
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -1,20 +1,62 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/keyring"
 	"github.com/cloudboy-jh/pact/internal/ui"
 	"github.com/spf13/cobra"
 	"golang.org/x/term"
 )
 
+var statusJSON bool
+
+// statusReport is the output of `pact status --json`
+type statusReport struct {
+	Name     string           `json:"name"`
+	Host     string           `json:"host"`
+	LastSync *time.Time       `json:"lastSync,omitempty"`
+	Modules  []moduleReport   `json:"modules"`
+	Secrets  []secretReport   `json:"secrets"`
+	Git      *gitStatusReport `json:"git,omitempty"`
+}
+
+type moduleReport struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"` // As the status screen shows it, e.g. "synced" or "drifted"
+	Files       int        `json:"files"`
+	LastApplied *time.Time `json:"lastApplied,omitempty"`
+	Changed     []string   `json:"changed,omitempty"`
+	Failed      []string   `json:"failed,omitempty"` // Items that failed in the last sync
+}
+
+type secretReport struct {
+	Name string `json:"name"`
+	Set  bool   `json:"set"`
+}
+
+type gitStatusReport struct {
+	Branch  string `json:"branch"`
+	Changes int    `json:"changes"`          // Uncommitted files
+	Ahead   *int   `json:"ahead,omitempty"`  // Unset without an origin branch
+	Behind  *int   `json:"behind,omitempty"` // As of the last pull
+}
+
 var statusCmd = &cobra.Command{
-	Use:   "status",
+	Use:   "status [module]",
 	Short: "Show pact status",
-	Long:  `Display the current status of all modules and secrets.`,
+	Long: `Display the current status of all modules and secrets.
+
+With a module name, show that module's description, last sync, and items.
+--json prints module statuses, secrets, the last sync, and the repo's
+branch and ahead/behind counts for scripts and dashboards.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if !config.Exists() {
 			fmt.Println("Pact is not initialized. Run 'pact init' first.")
@@ -27,15 +69,77 @@ var statusCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if statusJSON {
+			output, err := json.MarshalIndent(buildStatusReport(cfg), "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println(string(output))
+			return
+		}
+
+		if len(args) == 1 {
+			if !cfg.HasKey(args[0]) {
+				fmt.Printf("Error: module '%s' not found in pact.json\n", args[0])
+				os.Exit(1)
+			}
+			fmt.Println(ui.RenderModuleDetail(cfg, args[0]))
+			return
+		}
+
 		runInteractiveStatus(cfg)
 	},
 }
 
+func buildStatusReport(cfg *config.PactConfig) statusReport {
+	report := statusReport{
+		Name:    cfg.GetString("name"),
+		Modules: []moduleReport{},
+		Secrets: []secretReport{},
+	}
+	report.Host, _ = os.Hostname()
+
+	for _, status := range ui.GetModuleStatuses(cfg) {
+		module := moduleReport{
+			Name:    status.Name,
+			Status:  status.State(),
+			Files:   status.FileCount,
+			Changed: status.Changed,
+			Failed:  status.Failed,
+		}
+		if !status.LastApplied.IsZero() {
+			applied := status.LastApplied
+			module.LastApplied = &applied
+			if report.LastSync == nil || applied.After(*report.LastSync) {
+				report.LastSync = &applied
+			}
+		}
+		report.Modules = append(report.Modules, module)
+	}
+
+	for _, name := range cfg.GetSecrets() {
+		report.Secrets = append(report.Secrets, secretReport{Name: name, Set: keyring.HasSecret(name)})
+	}
+
+	if pactDir, err := config.GetPactDir(); err == nil {
+		if branch, err := git.Branch(pactDir); err == nil {
+			report.Git = &gitStatusReport{Branch: branch}
+			changed, _ := git.ChangedFiles(pactDir)
+			report.Git.Changes = len(changed)
+			if ahead, behind, err := git.AheadBehind(pactDir); err == nil {
+				report.Git.Ahead, report.Git.Behind = &ahead, &behind
+			}
+		}
+	}
+	return report
+}
+
 func runInteractiveStatus(cfg *config.PactConfig) {
 	// Check if we're in a terminal (some terminal emulators report stdin as non-tty)
 	if !term.IsTerminal(int(os.Stdin.Fd())) && !term.IsTerminal(int(os.Stdout.Fd())) {
 		// Non-interactive mode
-		fmt.Println(ui.RenderStatus(cfg, 0, 0))
+		fmt.Println(ui.RenderStatus(cfg, 0, 0, termWidth()))
 		return
 	}
 
@@ -54,7 +158,7 @@ func runInteractiveStatus(cfg *config.PactConfig) {
 	oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
 	if err != nil {
 		// Fallback to non-interactive mode
-		fmt.Println(ui.RenderStatus(cfg, 0, 0))
+		fmt.Println(ui.RenderStatus(cfg, 0, 0, termWidth()))
 		return
 	}
 	defer term.Restore(int(os.Stdin.Fd()), oldState)
@@ -64,82 +168,130 @@ func runInteractiveStatus(cfg *config.PactConfig) {
 	// Render status (convert \n to \r\n for raw mode)
 	renderStatus(cfg, scrollOffset, height)
 
-	// Read single keys - one byte at a time
-	buf := make([]byte, 1)
+	km := ui.Keys()
+	if km.Mouse {
+		// Report wheel events as SGR mouse sequences
+		fmt.Print("\033[?1000h\033[?1006h")
+		defer fmt.Print("\033[?1000l\033[?1006l")
+	}
+
+	// Read a key, or an escape sequence, at a time
+	buf := make([]byte, 32)
 	for {
 		n, err := os.Stdin.Read(buf)
 		if err != nil {
 			break
 		}
+		if n == 0 {
+			continue
+		}
+
+		name := rawKeyName(buf[:n])
+		switch {
+		case ui.Matches(km.Quit, name):
+			// Clear and exit
+			fmt.Print("\033[H\033[2J")
+			return
+		case ui.Matches(km.Sync, name):
+			// Restore terminal, run sync, then return
+			term.Restore(int(os.Stdin.Fd()), oldState)
+			fmt.Print("\033[H\033[2J")
+			runSync()
+			return
+		case ui.Matches(km.Retry, name):
+			term.Restore(int(os.Stdin.Fd()), oldState)
+			fmt.Print("\033[H\033[2J")
+			syncRetryFailed = true
+			runSync()
+			return
+		case ui.Matches(km.Edit, name):
+			// Drain any pending input first
+			drainInput()
+			// Show edit menu inline (below status)
+			showEditMenuInline()
+			// Read choice while still in raw mode
+			choiceBuf := make([]byte, 1)
+			_, err := os.Stdin.Read(choiceBuf)
+			if err != nil {
+				// Error reading - just re-render
+				renderStatus(cfg, scrollOffset, height)
+				continue
+			}
 
-		if n > 0 {
-			key := buf[0]
-			switch key {
-			case 'q', 'Q', 3: // q, Q, or Ctrl+C
-				// Clear and exit
-				fmt.Print("\033[H\033[2J")
+			switch choiceBuf[0] {
+			case 'l', 'L':
+				term.Restore(int(os.Stdin.Fd()), oldState)
+				fmt.Print("\r\n")
+				editCmd.Run(editCmd, []string{})
 				return
-			case 's', 'S':
-				// Restore terminal, run sync, then return
+			case 'w', 'W':
 				term.Restore(int(os.Stdin.Fd()), oldState)
-				fmt.Print("\033[H\033[2J")
-				runSync()
+				fmt.Print("\r\n")
+				editCmd.Run(editCmd, []string{"web"})
 				return
-			case 'e', 'E':
-				// Drain any pending input first
-				drainInput()
-				// Show edit menu inline (below status)
-				showEditMenuInline()
-				// Read choice while still in raw mode
-				choiceBuf := make([]byte, 1)
-				_, err := os.Stdin.Read(choiceBuf)
-				if err != nil {
-					// Error reading - just re-render
-					renderStatus(cfg, scrollOffset, height)
-					continue
-				}
-
-				switch choiceBuf[0] {
-				case 'l', 'L':
-					term.Restore(int(os.Stdin.Fd()), oldState)
-					fmt.Print("\r\n")
-					editCmd.Run(editCmd, []string{})
-					return
-				case 'w', 'W':
-					term.Restore(int(os.Stdin.Fd()), oldState)
-					fmt.Print("\r\n")
-					editCmd.Run(editCmd, []string{"web"})
-					return
-				case 'q', 'Q', 3: // q, Q, or Ctrl+C
-					// Cancel - re-render status
-					renderStatus(cfg, scrollOffset, height)
-				default:
-					// Any other key - cancel and re-render status
-					renderStatus(cfg, scrollOffset, height)
-				}
-			case 'r', 'R':
-				// Refresh
-				cfg, _ = config.Load()
-				scrollOffset = 0
+			default:
+				// Cancel - re-render status
+				renderStatus(cfg, scrollOffset, height)
+			}
+		case ui.Matches(km.Refresh, name):
+			cfg, _ = config.Load()
+			scrollOffset = 0
+			renderStatus(cfg, scrollOffset, height)
+		case ui.Matches(km.Down, name), name == "wheeldown":
+			maxScroll := ui.GetMaxScroll(cfg, height, termWidth())
+			if scrollOffset < maxScroll {
+				scrollOffset++
+				renderStatus(cfg, scrollOffset, height)
+			}
+		case ui.Matches(km.Up, name), name == "wheelup":
+			if scrollOffset > 0 {
+				scrollOffset--
 				renderStatus(cfg, scrollOffset, height)
-			case 'j', 'J': // Vim-style down
-				maxScroll := ui.GetMaxScroll(cfg, height)
-				if scrollOffset < maxScroll {
-					scrollOffset++
-					renderStatus(cfg, scrollOffset, height)
-				}
-			case 'k', 'K': // Vim-style up
-				if scrollOffset > 0 {
-					scrollOffset--
-					renderStatus(cfg, scrollOffset, height)
-				}
-			case 27: // Lone ESC key - ignore
-				continue
 			}
 		}
 	}
 }
 
+// rawKeyName names a key read in raw mode the way bubbletea does ("up",
+// "ctrl+c", "q"); mouse wheel events are "wheelup" and "wheeldown"
+func rawKeyName(b []byte) string {
+	seq := string(b)
+	switch seq {
+	case "\x1b[A", "\x1bOA":
+		return "up"
+	case "\x1b[B", "\x1bOB":
+		return "down"
+	case "\x1b":
+		return "esc"
+	case "\r", "\n":
+		return "enter"
+	case "\t":
+		return "tab"
+	case " ":
+		return " "
+	}
+	if strings.HasPrefix(seq, "\x1b[<64;") {
+		return "wheelup"
+	}
+	if strings.HasPrefix(seq, "\x1b[<65;") {
+		return "wheeldown"
+	}
+	if len(b) == 1 && b[0] > 0 && b[0] <= 26 {
+		return "ctrl+" + string(rune('a'+b[0]-1))
+	}
+	return seq
+}
+
+// termWidth is the terminal's current width, read on every render so a
+// resized pane is picked up; 0 when stdout isn't a terminal
+func termWidth() int {
+	width, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
 func renderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) {
 	// Clear screen
 	fmt.Print("\033[H\033[2J")
@@ -147,7 +299,7 @@ func renderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) {
 	fmt.Print("\033[1;1H")
 
 	// Get status and convert newlines for raw mode
-	status := ui.RenderStatus(cfg, scrollOffset, termHeight)
+	status := ui.RenderStatus(cfg, scrollOffset, termHeight, termWidth())
 	lines := strings.Split(status, "\n")
 	for i, line := range lines {
 		fmt.Print(line)
@@ -184,3 +336,7 @@ func showEditMenuInline() {
 	fmt.Print("\r\n")
 	fmt.Print("Choose: ")
 }
+
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output status as JSON")
+}
@@ -1,26 +1,42 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/cloudboy-jh/pact/internal/apply"
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/crypto"
 	"github.com/cloudboy-jh/pact/internal/git"
-	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/cloudboy-jh/pact/internal/undo"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	syncCI            bool
+	syncDryRun        bool
+	syncScope         string
+	syncJobs          int
+	syncLowBandwidth  bool
+	syncNoSudo        bool
+	syncOnConflict    string
+	syncRetryFailed   bool
+	syncSkipDiskCheck bool
+	syncYes           bool
 )
 
 var syncCmd = &cobra.Command{
-	Use:   "sync [module]",
+	Use:   "sync [modules...]",
 	Short: "Sync and apply configs",
 	Long: `Pull latest changes from GitHub and apply module configs.
 
 Without arguments, shows an interactive picker to select modules.
-With a module name, syncs that specific module directly.
+With module names, syncs those modules directly.
 
 Examples:
   pact sync              # Interactive module picker
@@ -28,9 +44,63 @@ Examples:
   pact sync cli          # Install CLI tools (bun, node, lazygit, etc.)
   pact sync git          # Configure git (user, email, default branch)
   pact sync editor       # Setup editor preferences
-  pact sync all          # Apply everything`,
-	Args: cobra.MaximumNArgs(1),
+  pact sync cli shell    # Apply several modules
+  pact sync all          # Apply everything
+  pact sync cli --jobs 4 # Install four CLI tools at a time
+  pact sync --retry-failed # Re-apply modules where items failed last time
+
+In headless environments without a keychain (Codespaces, devcontainers),
+the token is read from PACT_GITHUB_TOKEN, GITHUB_TOKEN, or GH_TOKEN.
+
+Use --low-bandwidth on metered connections to defer fonts, GUI apps, and
+LLM models (or set "settings": {"lowBandwidth": true} in pact.json). The
+next normal sync completes deferred items, even for modules you didn't pick.
+
+On a machine with no package manager (a fresh Mac without Homebrew, Windows
+without winget or scoop, or winget with no sources), sync offers to set one
+up before installing anything.
+
+Use --scope user on shared or locked-down machines: installs that need admin
+rights (sudo, choco, a system Homebrew) and writes outside your home directory
+are refused instead of attempted. Set "settings": {"scope": "user"} to make it
+the default.
+
+Use --retry-failed after a sync where some items failed: it re-applies just
+the modules with failures, where everything that did install is skipped as
+already there. 'pact status' marks those modules partially applied.
+
+Steps that need admin rights run through sudo. When sudo needs a password,
+pact asks for it once, before the first such step, and keeps it fresh for
+the rest of the sync; without a terminal those steps fail instead of
+waiting. Pass --no-sudo to skip them. Run as root, pact drops sudo.
+
+Use --dry-run to print the exact commands and file changes each module would
+make without pulling, installing, or writing anything.
+
+When a file pact would place already exists here with different content,
+sync shows what changed here and in pact since the last sync and asks
+whether to keep it, overwrite it, merge both sides' changes into the pact
+repo, or back it up and replace it. Pass --on-conflict (or set
+"settings": {"onConflict": "keep"}) to decide up front; without a terminal
+the file is backed up.
+
+Modules that overwrite files or change OS settings show what they would
+change and ask before applying. Pass --yes to apply without asking (needed
+when there's no terminal, and with --ci), or set "confirm": false on a
+module to never ask for it.
+
+Use --ci in pipelines: no prompts, no keychain access, apps/llm/terminal
+(fonts), secrets, ssh, and OS settings (sound, machine, power, printers, mounts)
+are skipped, and results are printed as JSON. Exits non-zero when any item fails.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx, stop := cancelOnInterrupt(cmd.Context())
+		defer stop()
+
+		if syncCI {
+			runCISync(ctx, args)
+			return
+		}
+
 		if !config.Exists() {
 			fmt.Println("Pact is not initialized. Run 'pact init' first.")
 			os.Exit(1)
@@ -43,19 +113,31 @@ Examples:
 			os.Exit(1)
 		}
 
-		// Get token for pull
-		token, err := keyring.GetToken()
-		if err != nil {
-			fmt.Println("Not authenticated. Run 'pact init' to authenticate.")
+		// Get token for pull, and for the policy to check the repo is private
+		pol := loadPolicy()
+		var token string
+		if pol.RequirePrivate || !syncDryRun {
+			if token, err = remoteToken(pactDir); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := checkRepoPolicy(ctx, pol, pactDir, token); err != nil {
+			fmt.Printf("Error: %v\n", err)
 			os.Exit(1)
 		}
 
-		// Pull latest changes
-		fmt.Println("Pulling latest changes...")
-		if err := git.Pull(token, pactDir); err != nil {
-			fmt.Printf("Warning: Could not pull: %v\n", err)
+		if syncDryRun {
+			fmt.Println("Dry run: nothing will be pulled, installed, or written")
 		} else {
-			fmt.Println("✓ Pulled latest changes")
+			// Pull latest changes
+			fmt.Println("Pulling latest changes...")
+			if err := git.Pull(ctx, token, pactDir); err != nil {
+				fmt.Printf("Warning: Could not pull: %v\n", err)
+				fmt.Println("  Run 'pact pull' to merge GitHub's changes with this machine's")
+			} else {
+				fmt.Println("✓ Pulled latest changes")
+			}
 		}
 		fmt.Println()
 
@@ -66,8 +148,12 @@ Examples:
 			os.Exit(1)
 		}
 
-		// Get available modules from config
+		// Get available modules from config; secrets isn't a module in
+		// pact.json but syncs once secrets.enc is in the repo
 		modules := cfg.GetModules()
+		if _, err := os.Stat(filepath.Join(pactDir, crypto.SecretsFile)); err == nil {
+			modules = append(modules, "secrets")
+		}
 		if len(modules) == 0 {
 			fmt.Println("No modules found in pact.json")
 			return
@@ -75,12 +161,23 @@ Examples:
 
 		var modulesToSync []string
 
-		if len(args) > 0 {
+		if syncRetryFailed {
+			for _, module := range state.FailedModules() {
+				if containsString(modules, module) {
+					modulesToSync = append(modulesToSync, module)
+				}
+			}
+			if len(modulesToSync) == 0 {
+				fmt.Println("✓ Nothing failed in the last sync")
+				return
+			}
+			fmt.Printf("Retrying failed items in %s\n", strings.Join(modulesToSync, ", "))
+		} else if len(args) > 0 {
 			arg := strings.ToLower(args[0])
 			if arg == "all" {
 				modulesToSync = modules
 			} else {
-				modulesToSync = []string{args[0]}
+				modulesToSync = args
 			}
 		} else {
 			// Interactive mode - show picker
@@ -91,13 +188,112 @@ Examples:
 			}
 		}
 
+		opts := apply.Options{
+			LowBandwidth:  syncLowBandwidth || cfg.Get("settings.lowBandwidth") == true,
+			DryRun:        syncDryRun,
+			Scope:         syncScope,
+			Jobs:          syncJobs,
+			NoSudo:        syncNoSudo,
+			ForbidScripts: pol.ForbidScripts,
+			Context:       ctx,
+		}
+		if opts.Timeouts, err = apply.ParseTimeouts(cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.OnConflict, err = onConflict(cfg); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if opts.Scope == "" {
+			opts.Scope = cfg.GetString("settings.scope")
+		}
+		if opts.Scope != "" && opts.Scope != apply.ScopeUser && opts.Scope != apply.ScopeSystem {
+			fmt.Printf("Error: unknown scope '%s' (use user or system)\n", opts.Scope)
+			os.Exit(1)
+		}
+		if opts.UserScope() {
+			fmt.Println("\nUser scope: only your home directory will be changed; nothing needing admin rights runs")
+		}
+		interactive := term.IsTerminal(int(os.Stdin.Fd()))
+		if interactive {
+			opts.AcceptLicense = acceptLicense
+			opts.Confirm = confirm
+			opts.EditMerge = editMerge
+			if !syncYes {
+				opts.ResolveConflict = promptConflict
+			}
+		}
+		if syncYes {
+			opts.Confirm = func(string) bool { return true }
+		}
+		if !opts.DryRun && !opts.NoSudo {
+			opts.Sudo = apply.NewSudo(interactive)
+			defer opts.Sudo.Close()
+		}
+		if opts.LowBandwidth {
+			fmt.Println("\nLow-bandwidth mode: fonts, apps, and models will be deferred")
+		} else {
+			for _, module := range state.DeferredModules() {
+				if !containsString(modulesToSync, module) {
+					fmt.Printf("\nCompleting deferred items in %s\n", module)
+					modulesToSync = append(modulesToSync, module)
+				}
+			}
+		}
+
+		if pol.Active() {
+			modulesToSync = allowedModules(pol, modulesToSync)
+			if len(modulesToSync) == 0 {
+				fmt.Println("No modules left to sync.")
+				return
+			}
+		}
+
+		// Modules run after the modules they list in needs
+		modulesToSync = cfg.OrderByNeeds(modulesToSync)
+		if seed, ok := config.ShuffleSeed(); ok {
+			fmt.Printf("\nShuffled module and file order (%s=%d): %s\n", config.ShuffleSeedEnv, seed, strings.Join(modulesToSync, ", "))
+		}
+
+		if bootstrap := apply.PackageManagerBootstrap(); bootstrap != nil && needsPackageManager(modulesToSync) {
+			runBootstrap(bootstrap, opts, interactive)
+		}
+
+		if !syncSkipDiskCheck {
+			report := apply.Preflight(cfg, modulesToSync, opts)
+			if !report.OK() && opts.DryRun {
+				renderPreflight(report)
+			} else if !report.OK() {
+				renderPreflight(report)
+				fmt.Println("\nFree up space, sync fewer modules, or use --low-bandwidth to defer large downloads.")
+				fmt.Println("Use --skip-disk-check to sync anyway.")
+				os.Exit(1)
+			}
+		}
+
+		if !opts.DryRun {
+			if journal, err := undo.Begin(modulesToSync); err == nil {
+				opts.Undo = journal
+			} else {
+				fmt.Printf("Warning: this sync can't be undone: %v\n", err)
+			}
+		}
+
 		// Apply selected modules
 		fmt.Println()
 		var allResults []apply.Result
 
-		for _, moduleName := range modulesToSync {
+		for i, moduleName := range modulesToSync {
+			if opts.Cancelled() {
+				fmt.Printf("Cancelled; skipped %s\n", strings.Join(modulesToSync[i:], ", "))
+				break
+			}
 			fmt.Printf("Applying %s...\n", moduleName)
-			results, err := apply.ApplyModule(cfg, moduleName)
+			if !opts.DryRun && !syncYes && apply.NeedsConfirm(cfg, moduleName) && !confirmModule(cfg, moduleName, opts, interactive) {
+				continue
+			}
+			results, err := apply.ApplyModule(cfg, moduleName, opts)
 			if err != nil {
 				fmt.Printf("  Error applying %s: %v\n", moduleName, err)
 				continue
@@ -108,9 +304,146 @@ Examples:
 		// Render results
 		fmt.Println()
 		renderApplyResults(allResults)
+		if !opts.DryRun {
+			renderPartialModules(allResults)
+		}
+
+		if opts.Undo != nil {
+			if err := opts.Undo.Save(); err != nil {
+				fmt.Printf("Warning: could not save undo journal: %v\n", err)
+			} else if len(opts.Undo.Changes) > 0 {
+				fmt.Println("Run 'pact undo' to revert this sync")
+			}
+		}
 	},
 }
 
+func init() {
+	syncCmd.Flags().BoolVar(&syncLowBandwidth, "low-bandwidth", false, "Defer large downloads (fonts, apps, LLM models) until the next normal sync")
+	syncCmd.Flags().BoolVar(&syncDryRun, "dry-run", false, "Show the commands and file changes sync would make without making them")
+	syncCmd.Flags().BoolVar(&syncNoSudo, "no-sudo", false, "Skip steps that need sudo instead of asking for a password")
+	syncCmd.Flags().StringVar(&syncScope, "scope", "", "Install scope: 'user' never touches system locations or needs admin rights")
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", 1, "Install up to this many CLI tools at once (brew, scoop, and custom tools)")
+	syncCmd.Flags().StringVar(&syncOnConflict, "on-conflict", "", "When a file here differs from pact: keep, overwrite, merge, or backup (default: ask, or backup without a terminal)")
+	syncCmd.Flags().BoolVar(&syncRetryFailed, "retry-failed", false, "Re-apply the modules with items that failed in their last sync")
+	syncCmd.Flags().BoolVar(&syncSkipDiskCheck, "skip-disk-check", false, "Don't check free disk space before installing")
+	syncCmd.Flags().BoolVarP(&syncYes, "yes", "y", false, "Apply modules that overwrite files or change OS settings without asking")
+	syncCmd.Flags().BoolVar(&syncCI, "ci", false, "Non-interactive CI mode with JSON output (env token, no keychain, skips apps/llm/terminal and OS settings)")
+}
+
+// confirmModule shows what a module would change and asks before applying
+// it; modules with nothing to change go ahead without asking
+func confirmModule(cfg *config.PactConfig, module string, opts apply.Options, interactive bool) bool {
+	changes := plannedChanges(cfg, module, opts)
+	if len(changes) == 0 {
+		return true
+	}
+
+	if !interactive {
+		fmt.Printf("  Skipped: %d change(s) need confirmation; run in a terminal or pass --yes\n", len(changes))
+		return false
+	}
+	for i, r := range changes {
+		if i == 5 {
+			fmt.Printf("  → ... and %d more\n", len(changes)-i)
+			break
+		}
+		fmt.Printf("  → %-24s %s\n", r.Name, dimStyle.Render(strings.TrimPrefix(r.Message, "would ")))
+	}
+	if !confirm(fmt.Sprintf("  Apply %d change(s) to %s?", len(changes), module)) {
+		fmt.Println("  Skipped")
+		return false
+	}
+	return true
+}
+
+// plannedChanges previews a module, returning the changes applying it would
+// make. A module that fails to preview returns none: applying reports the
+// error.
+func plannedChanges(cfg *config.PactConfig, module string, opts apply.Options) []apply.Result {
+	preview := opts
+	preview.DryRun = true
+	preview.Undo = nil
+	results, err := apply.ApplyModule(cfg, module, preview)
+	if err != nil {
+		return nil
+	}
+	var changes []apply.Result
+	for _, r := range results {
+		if r.Planned {
+			changes = append(changes, r)
+		}
+	}
+	return changes
+}
+
+// packageModules are the modules that install through a package manager
+var packageModules = map[string]bool{
+	"cli":      true,
+	"shell":    true,
+	"git":      true,
+	"editor":   true,
+	"terminal": true,
+	"apps":     true,
+	"llm":      true,
+}
+
+func needsPackageManager(modules []string) bool {
+	for _, module := range modules {
+		if packageModules[module] {
+			return true
+		}
+	}
+	return false
+}
+
+// runBootstrap offers to set up a package manager before installing
+func runBootstrap(bootstrap *apply.Bootstrap, opts apply.Options, interactive bool) {
+	fmt.Printf("\n%s: pact can set up %s with\n  %s\n", bootstrap.Reason, bootstrap.Name, bootstrap.CommandLine())
+	switch {
+	case bootstrap.System && opts.UserScope():
+		fmt.Printf("%s needs admin rights; not allowed with --scope user\n", bootstrap.Name)
+	case bootstrap.Script && opts.ForbidScripts:
+		fmt.Println("Install scripts are forbidden by policy; ask an admin to set it up")
+	case opts.DryRun:
+		fmt.Println("Dry run: not setting it up")
+	case !interactive:
+		fmt.Println("Not a terminal; installs will fail until it's set up")
+	case confirm(fmt.Sprintf("Set up %s now?", bootstrap.Name)):
+		if err := bootstrap.Run(); err != nil {
+			fmt.Printf("✗ %v\n", err)
+		} else {
+			fmt.Printf("✓ %s is ready\n", bootstrap.Name)
+		}
+	default:
+		fmt.Println("○ Skipped; installs will fail until it's set up")
+	}
+}
+
+// acceptLicense asks whether to re-run an install blocked on a license
+// prompt in the terminal
+func acceptLicense(command, notice string) bool {
+	fmt.Printf("\n  %s is waiting on a license agreement (%s).\n", command, notice)
+	return confirm("  Run it in this terminal to review and accept?")
+}
+
+// renderPreflight prints the sizes behind a failed disk space check
+func renderPreflight(report *apply.PreflightReport) {
+	fmt.Printf("\n✗ Not enough disk space in %s\n\n", report.Path)
+	for _, item := range report.Items {
+		size := formatSize(item.Bytes)
+		switch {
+		case item.Manual:
+			size += " (pulled manually, not counted)"
+		case item.Estimated:
+			size = "~" + size
+		}
+		fmt.Printf("  %-10s %-24s %s\n", item.Module, item.Name, size)
+	}
+	fmt.Printf("\n  Needed: %s (plus 1 GB headroom)\n", formatSize(report.Required))
+	fmt.Printf("  Free:   %s\n", formatSize(report.Free))
+}
+
 func promptModuleSelection(cfg *config.PactConfig, modules []string) []string {
 	fmt.Printf("Found %d modules in pact.json:\n\n", len(modules))
 
@@ -118,6 +451,9 @@ func promptModuleSelection(cfg *config.PactConfig, modules []string) []string {
 	for i, mod := range modules {
 		details := getModulePreview(cfg, mod)
 		fmt.Printf("  [%d] %-12s %s\n", i+1, mod, details)
+		if desc := cfg.ModuleDescription(mod); desc != "" {
+			fmt.Printf("      %s\n", desc)
+		}
 	}
 
 	fmt.Println()
@@ -126,11 +462,9 @@ func promptModuleSelection(cfg *config.PactConfig, modules []string) []string {
 	fmt.Println("  'a' or 'all' to sync all modules")
 	fmt.Println("  'q' or 'quit' to cancel")
 	fmt.Println()
-	fmt.Print("Select modules: ")
 
-	reader := bufio.NewReader(os.Stdin)
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(strings.ToLower(input))
+	input, _ := readLine("Select modules: ", "sync-modules")
+	input = strings.ToLower(input)
 
 	if input == "" || input == "q" || input == "quit" {
 		return nil
@@ -187,6 +521,18 @@ func getModulePreview(cfg *config.PactConfig, module string) string {
 		if def := cfg.GetString("editor.default"); def != "" {
 			parts = append(parts, def)
 		}
+	case "secrets":
+		parts = append(parts, crypto.SecretsFile+" into the keychain")
+	case "keybindings", "snippets":
+		var editors []string
+		for _, editor := range config.KeybindingEditors {
+			if cfg.HasKey(module + "." + editor) {
+				editors = append(editors, editor)
+			}
+		}
+		if len(editors) > 0 {
+			parts = append(parts, strings.Join(editors, ", "))
+		}
 	case "terminal":
 		if font := cfg.GetString("terminal.font"); font != "" {
 			parts = append(parts, font)
@@ -220,6 +566,7 @@ func renderApplyResults(results []apply.Result) {
 	fonts := []apply.Result{}
 	extensions := []apply.Result{}
 	apps := []apply.Result{}
+	hooks := []apply.Result{}
 
 	for _, r := range results {
 		switch r.Category {
@@ -235,6 +582,8 @@ func renderApplyResults(results []apply.Result) {
 			extensions = append(extensions, r)
 		case "app":
 			apps = append(apps, r)
+		case "hook":
+			hooks = append(hooks, r)
 		}
 	}
 
@@ -354,8 +703,76 @@ func renderApplyResults(results []apply.Result) {
 		fmt.Println()
 	}
 
+	// Render hooks
+	if len(hooks) > 0 {
+		fmt.Println("Hooks:")
+		for _, r := range hooks {
+			icon, status := getResultDisplay(r)
+			fmt.Printf("  %s %-20s %s\n", icon, r.Module+" "+r.Name, status)
+			if r.Success {
+				successCount++
+			} else {
+				failCount++
+			}
+		}
+		fmt.Println()
+	}
+
 	// Summary
+	planned := 0
+	for _, r := range results {
+		if r.Planned {
+			planned++
+		}
+	}
+	if syncDryRun || planned > 0 {
+		fmt.Printf("Dry run: %d changes planned, %d skipped, %d failed; nothing was changed\n", planned, skipCount, failCount)
+		return
+	}
 	fmt.Printf("Done: %d applied, %d skipped, %d failed\n", successCount, skipCount, failCount)
+
+	deferred := 0
+	for _, r := range results {
+		if r.Deferred {
+			deferred++
+		}
+	}
+	if deferred > 0 {
+		fmt.Printf("%d deferred; run 'pact sync' on an unmetered connection to finish them\n", deferred)
+	}
+}
+
+// renderPartialModules names the modules where some items failed, so a few
+// failed installs among many don't go unnoticed
+func renderPartialModules(results []apply.Result) {
+	var modules []string
+	total := make(map[string]int)
+	failed := make(map[string]int)
+	for _, r := range results {
+		if _, ok := total[r.Module]; !ok {
+			modules = append(modules, r.Module)
+		}
+		total[r.Module]++
+		if r.Error != nil || (!r.Success && !r.Skipped) {
+			failed[r.Module]++
+		}
+	}
+
+	retry := false
+	for _, m := range modules {
+		switch {
+		case failed[m] == 0:
+		case failed[m] < total[m]:
+			fmt.Printf("! %s partially applied: %d of %d failed\n", m, failed[m], total[m])
+			retry = true
+		default:
+			fmt.Printf("✗ %s failed: all %d items failed\n", m, total[m])
+			retry = true
+		}
+	}
+	if retry {
+		fmt.Println("Run 'pact sync --retry-failed' to retry them")
+	}
 }
 
 func getResultDisplay(r apply.Result) (string, string) {
@@ -365,6 +782,9 @@ func getResultDisplay(r apply.Result) (string, string) {
 	if r.Skipped {
 		return "○", r.Message
 	}
+	if r.Planned {
+		return "→", r.Message
+	}
 	if r.Success {
 		return "✓", r.Message
 	}
@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/policy"
+)
+
+// ciSkippedModules are never applied in CI: GUI apps, LLM runtimes/models,
+// and terminal (fonts) are heavy and pointless on a build agent; sound,
+// machine, power, printers, and mounts change OS settings that need admin
+// rights (and machine asks first); and secrets and ssh need the keychain
+var ciSkippedModules = map[string]bool{
+	"apps":     true,
+	"llm":      true,
+	"terminal": true,
+	"sound":    true,
+	"machine":  true,
+	"power":    true,
+	"printers": true,
+	"mounts":   true,
+	"secrets":  true,
+	"ssh":      true,
+}
+
+// ciReport is the machine-readable output of `pact sync --ci`
+type ciReport struct {
+	DryRun  bool            `json:"dryRun,omitempty"`
+	Pulled  bool            `json:"pulled"`
+	Warning string          `json:"warning,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Modules []string        `json:"modules"`
+	Skipped []string        `json:"skippedModules,omitempty"`
+	Results []ciResult      `json:"results"`
+	Secrets map[string]bool `json:"secrets,omitempty"`
+	Summary map[string]int  `json:"summary"`
+}
+
+// ciResult is a JSON-friendly apply.Result
+type ciResult struct {
+	Category string `json:"category"`
+	Module   string `json:"module"`
+	Name     string `json:"name"`
+	Status   string `json:"status"` // "applied", "planned", "skipped", "failed"
+	Message  string `json:"message,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// runCISync is the non-interactive sync used by pipelines. It never touches
+// the keychain: the token comes from the environment and secrets are only
+// reported as present/absent in the environment.
+func runCISync(ctx context.Context, args []string) {
+	report := ciReport{Summary: map[string]int{"applied": 0, "skipped": 0, "failed": 0}}
+	// sudo fails rather than waiting for a password no one can type
+	opts := apply.Options{DryRun: syncDryRun, Scope: syncScope, NoSudo: syncNoSudo, Sudo: apply.NewSudo(false), Context: ctx}
+	defer opts.Sudo.Close()
+	if opts.DryRun {
+		report.DryRun = true
+		report.Summary = map[string]int{"planned": 0, "skipped": 0, "failed": 0}
+	}
+
+	pactDir, err := config.GetPactDir()
+	if err != nil || !config.Exists() {
+		exitCI(report, "pact is not initialized (no pact.json found)")
+	}
+
+	// Pull with an env token when one is provided; CI checkouts may
+	// already be up to date, so a missing token is only a warning
+	pol, err := policy.Load()
+	if err != nil {
+		exitCI(report, err.Error())
+	}
+	opts.ForbidScripts = pol.ForbidScripts
+	token, hasToken := envTokenFor(pactDir)
+	if err := checkRepoPolicy(ctx, pol, pactDir, token); err != nil {
+		exitCI(report, err.Error())
+	}
+
+	git.Progress = nil
+	apply.HookOutput = nil
+	apply.AttachedOutput = os.Stderr
+	if opts.DryRun {
+		report.Warning = "dry run, skipped pull"
+	} else if hasToken {
+		if err := git.Pull(ctx, token, pactDir); err != nil {
+			report.Warning = fmt.Sprintf("could not pull: %v", err)
+		} else {
+			report.Pulled = true
+		}
+	} else {
+		report.Warning = "no token in environment, skipped pull"
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		exitCI(report, err.Error())
+	}
+	if opts.Scope == "" {
+		opts.Scope = cfg.GetString("settings.scope")
+	}
+	if opts.Timeouts, err = apply.ParseTimeouts(cfg); err != nil {
+		exitCI(report, err.Error())
+	}
+	if opts.OnConflict, err = onConflict(cfg); err != nil {
+		exitCI(report, err.Error())
+	}
+
+	requested := cfg.GetModules()
+	if len(args) > 0 && strings.ToLower(args[0]) != "all" {
+		requested = args
+	}
+
+	for _, module := range requested {
+		if ciSkippedModules[module] || !pol.AllowsModule(module) {
+			report.Skipped = append(report.Skipped, module)
+			continue
+		}
+		report.Modules = append(report.Modules, module)
+	}
+
+	for i, module := range report.Modules {
+		if opts.Cancelled() {
+			report.Warning = "cancelled; skipped " + strings.Join(report.Modules[i:], ", ")
+			break
+		}
+		// Nothing can be confirmed in a pipeline, so overwrites need --yes
+		if !opts.DryRun && !syncYes && apply.NeedsConfirm(cfg, module) {
+			if changes := plannedChanges(cfg, module, opts); len(changes) > 0 {
+				report.Results = append(report.Results, ciResult{
+					Module:  module,
+					Name:    module,
+					Status:  "skipped",
+					Message: fmt.Sprintf("%d change(s) need confirmation; pass --yes to apply them", len(changes)),
+				})
+				report.Summary["skipped"]++
+				continue
+			}
+		}
+		results, err := apply.ApplyModule(cfg, module, opts)
+		if err != nil {
+			report.Results = append(report.Results, ciResult{
+				Module: module,
+				Name:   module,
+				Status: "failed",
+				Error:  err.Error(),
+			})
+			report.Summary["failed"]++
+			continue
+		}
+		for _, r := range results {
+			cr := toCIResult(r)
+			report.Summary[cr.Status]++
+			report.Results = append(report.Results, cr)
+		}
+	}
+
+	// Env-only secrets: report which configured secrets the pipeline provides
+	if secrets := cfg.GetSecrets(); len(secrets) > 0 {
+		report.Secrets = make(map[string]bool)
+		for _, name := range secrets {
+			_, ok := os.LookupEnv(name)
+			report.Secrets[name] = ok
+		}
+	}
+
+	printCIReport(report)
+	if report.Summary["failed"] > 0 {
+		os.Exit(1)
+	}
+}
+
+func toCIResult(r apply.Result) ciResult {
+	cr := ciResult{
+		Category: r.Category,
+		Module:   r.Module,
+		Name:     r.Name,
+		Message:  r.Message,
+	}
+	switch {
+	case r.Error != nil:
+		cr.Status = "failed"
+		cr.Error = r.Error.Error()
+	case r.Skipped:
+		cr.Status = "skipped"
+	case r.Planned:
+		cr.Status = "planned"
+	case r.Success:
+		cr.Status = "applied"
+	default:
+		cr.Status = "failed"
+	}
+	return cr
+}
+
+func printCIReport(report ciReport) {
+	output, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(string(output))
+}
+
+// exitCI prints the report with a fatal error and exits non-zero
+func exitCI(report ciReport, message string) {
+	report.Error = message
+	printCIReport(report)
+	os.Exit(1)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/git"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+)
+
+// tokenEnvVars are checked in order when no token is stored in the keychain,
+// so headless environments (Codespaces, devcontainers, CI) can authenticate
+var tokenEnvVars = []string{"PACT_GITHUB_TOKEN", "GITHUB_TOKEN", "GH_TOKEN"}
+
+// getToken returns the GitHub token from the OS keychain, falling back to
+// environment variables
+func getToken() (string, error) {
+	token, _, err := tokenWithSource()
+	return token, err
+}
+
+// tokenWithSource is getToken, also saying where the token came from
+func tokenWithSource() (token, source string, err error) {
+	if token, err := keyring.GetToken(); err == nil && token != "" {
+		if login := keyring.Account(); login != "" {
+			return token, fmt.Sprintf("the keychain (%s, bound to this workspace)", login), nil
+		}
+		return token, "the keychain", nil
+	}
+
+	for _, name := range tokenEnvVars {
+		if token := os.Getenv(name); token != "" {
+			return token, "$" + name, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("no GitHub token in keychain or environment")
+}
+
+// tokenFromEnv returns the first GitHub token found in the environment
+func tokenFromEnv() string {
+	for _, name := range tokenEnvVars {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// remoteTokenEnv holds the access token for a pact repo hosted somewhere
+// other than GitHub, such as GitLab or Gitea
+const remoteTokenEnv = "PACT_REMOTE_TOKEN"
+
+// tokenFor returns the token for pulling from and pushing to remote: none
+// over SSH, PACT_REMOTE_TOKEN (possibly empty) for other hosts, and the
+// GitHub token for GitHub
+func tokenFor(remote string) (string, error) {
+	switch {
+	case git.IsSSH(remote):
+		return "", nil
+	case !git.IsGitHub(remote):
+		return os.Getenv(remoteTokenEnv), nil
+	}
+	token, err := getToken()
+	if err != nil {
+		return "", fmt.Errorf("not authenticated; run 'pact init' to sign in to GitHub")
+	}
+	return token, nil
+}
+
+// remoteToken is tokenFor the pact repo's origin
+func remoteToken(pactDir string) (string, error) {
+	remote, err := git.RemoteURL(pactDir)
+	if err != nil {
+		return "", err
+	}
+	return tokenFor(remote)
+}
+
+// envTokenFor is remoteToken without the keychain, for headless runs; ok is
+// false when the remote is on GitHub and no token is set
+func envTokenFor(pactDir string) (token string, ok bool) {
+	remote, err := git.RemoteURL(pactDir)
+	if err != nil {
+		return "", false
+	}
+	if !git.IsGitHub(remote) {
+		token, _ = tokenFor(remote)
+		return token, true
+	}
+	token = tokenFromEnv()
+	return token, token != ""
+}
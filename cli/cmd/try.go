@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/undo"
+	"github.com/spf13/cobra"
+)
+
+var tryTimeout time.Duration
+
+var tryCmd = &cobra.Command{
+	Use:   "try <module>",
+	Short: "Apply a module temporarily and revert unless kept",
+	Long: `Apply a module, then revert it automatically unless you confirm keeping
+the changes. The changes are recorded like a sync's: files are restored
+from backups, settings put back, and anything installed is uninstalled on
+revert. Kept changes can still be reverted later with 'pact undo'.
+
+Useful for trying a new prompt theme or editor setup: open a new terminal,
+look around, then come back and answer.
+
+Examples:
+  pact try shell               # Revert after 5 minutes unless kept
+  pact try shell --timeout 30s
+  pact try editor --timeout 0  # Wait for an answer indefinitely`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		module := args[0]
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !cfg.HasKey(module) {
+			fmt.Printf("Error: module '%s' not found in pact.json\n", module)
+			os.Exit(1)
+		}
+
+		pol := loadPolicy()
+		if !pol.AllowsModule(module) {
+			fmt.Printf("Error: module '%s' is disallowed by %s\n", module, pol.Path)
+			os.Exit(1)
+		}
+
+		journal, err := undo.Begin([]string{module})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Trying %s...\n", module)
+		results, err := apply.ApplyModule(cfg, module, apply.Options{ForbidScripts: pol.ForbidScripts, Undo: journal})
+		if err != nil {
+			fmt.Printf("Error applying %s: %v\n", module, err)
+		}
+		fmt.Println()
+		renderApplyResults(results)
+		fmt.Println()
+
+		if waitForKeep(tryTimeout) {
+			if err := journal.Save(); err != nil {
+				fmt.Printf("Warning: could not save undo journal: %v\n", err)
+			}
+			fmt.Println("✓ Kept changes")
+			return
+		}
+
+		fmt.Printf("Reverting %s...\n", module)
+		errs := journal.Revert(apply.Uninstall)
+		for _, err := range errs {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+		if len(journal.Changes) > 0 {
+			// Keep what failed so 'pact undo' can retry it
+			if err := journal.Save(); err != nil {
+				fmt.Printf("Warning: could not save undo journal: %v\n", err)
+			} else {
+				fmt.Printf("\n%d change(s) weren't reverted; run 'pact undo' to retry them\n", len(journal.Changes))
+			}
+		} else if err := journal.Discard(); err != nil {
+			fmt.Printf("Warning: could not remove undo journal: %v\n", err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("✓ Reverted")
+	},
+}
+
+// waitForKeep asks whether to keep the changes, answering no when the
+// timeout expires (0 waits forever)
+func waitForKeep(timeout time.Duration) bool {
+	if timeout > 0 {
+		fmt.Printf("Keep these changes? [y/N] (reverting in %s) ", timeout)
+	} else {
+		fmt.Print("Keep these changes? [y/N] ")
+	}
+
+	answer := make(chan string, 1)
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		answer <- strings.TrimSpace(strings.ToLower(response))
+	}()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		expired = time.After(timeout)
+	}
+
+	select {
+	case response := <-answer:
+		return response == "y" || response == "yes"
+	case <-expired:
+		fmt.Println("\nTimed out.")
+		return false
+	}
+}
+
+func init() {
+	tryCmd.Flags().DurationVar(&tryTimeout, "timeout", 5*time.Minute, "Revert automatically after this long (0 waits for an answer)")
+	rootCmd.AddCommand(tryCmd)
+}
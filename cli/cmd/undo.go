@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/undo"
+	"github.com/spf13/cobra"
+)
+
+var (
+	undoList bool
+	undoYes  bool
+)
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last sync",
+	Long: `Revert the changes the last 'pact sync' made: shell config edits and
+synced files are restored from backups, global git config values are reset,
+and anything it installed is uninstalled.
+
+The last 10 syncs are kept in .pact/state/undo; each undo reverts the most
+recent one that hasn't been undone.
+
+Examples:
+  pact undo          # Show the last sync's changes and revert them
+  pact undo --list   # List syncs that can be undone`,
+	Run: func(cmd *cobra.Command, args []string) {
+		requireInitialized()
+
+		if undoList {
+			journals, err := undo.List()
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if len(journals) == 0 {
+				fmt.Println("Nothing to undo.")
+				return
+			}
+			for _, j := range journals {
+				fmt.Printf("  %s  %-24s %d changes\n", j.Created.Format("2006-01-02 15:04"), strings.Join(j.Modules, ", "), len(j.Changes))
+			}
+			return
+		}
+
+		journal, err := undo.Last()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if journal == nil {
+			fmt.Println("Nothing to undo.")
+			return
+		}
+
+		fmt.Printf("Last sync (%s, %s):\n\n", journal.Created.Format("2006-01-02 15:04"), strings.Join(journal.Modules, ", "))
+		for i := len(journal.Changes) - 1; i >= 0; i-- {
+			fmt.Printf("  %s\n", journal.Changes[i].Describe())
+		}
+		fmt.Println()
+
+		if !undoYes && !confirm("Revert these changes?") {
+			fmt.Println("Cancelled.")
+			return
+		}
+
+		errs := journal.Revert(apply.Uninstall)
+		for _, err := range errs {
+			fmt.Printf("  ✗ %v\n", err)
+		}
+		if len(journal.Changes) > 0 {
+			// Keep what failed so the next undo can retry it
+			if err := journal.Save(); err != nil {
+				fmt.Printf("Warning: could not save undo journal: %v\n", err)
+			} else {
+				fmt.Printf("\n%d change(s) weren't reverted; run 'pact undo' again to retry them\n", len(journal.Changes))
+			}
+		} else if err := journal.Discard(); err != nil {
+			fmt.Printf("Warning: could not remove undo journal: %v\n", err)
+		}
+		if len(errs) > 0 {
+			os.Exit(1)
+		}
+		fmt.Println("✓ Reverted last sync")
+	},
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoList, "list", false, "List syncs that can be undone")
+	undoCmd.Flags().BoolVarP(&undoYes, "yes", "y", false, "Don't ask for confirmation")
+	rootCmd.AddCommand(undoCmd)
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/spf13/cobra"
+)
+
+var uninstallYes bool
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall <module>:<name>",
+	Short: "Uninstall an item and remove it from pact.json",
+	Long: `Uninstall a tool, app, extension, or font that pact installed, using the
+same backend it was installed with, then remove it from pact.json so the
+machine and config stay in lockstep.
+
+Items pact didn't install are only removed from pact.json.
+
+Examples:
+  pact uninstall cli:ripgrep
+  pact uninstall apps:spotify
+  pact uninstall editor:golang.go
+  pact uninstall terminal:"JetBrainsMono Nerd Font"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		module, name, ok := strings.Cut(args[0], ":")
+		if !ok || module == "" || name == "" {
+			fmt.Println("Error: expected <module>:<name>, e.g. cli:ripgrep")
+			os.Exit(1)
+		}
+
+		if !config.Exists() {
+			fmt.Println("Pact is not initialized. Run 'pact init' first.")
+			os.Exit(1)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			fmt.Printf("Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		journal, err := state.LoadJournal()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		entry, installed := journal.Find(module, name)
+		paths := uninstallConfigPaths(module)
+		inConfig := false
+		for _, p := range paths {
+			if containsString(cfg.GetStringSlice(p), name) || cfg.GetString(p) == name {
+				inConfig = true
+			}
+		}
+
+		if !installed && !inConfig {
+			fmt.Printf("Error: %s is neither installed by pact nor in pact.json\n", args[0])
+			os.Exit(1)
+		}
+
+		fmt.Println("This will:")
+		if installed {
+			fmt.Printf("  - Uninstall %s (via %s)\n", name, entry.Backend)
+		} else {
+			fmt.Printf("  - Leave %s installed (not installed by pact)\n", name)
+		}
+		if inConfig {
+			fmt.Printf("  - Remove %s from pact.json\n", args[0])
+		}
+		fmt.Println()
+
+		if !uninstallYes && !confirm("Continue?") {
+			fmt.Println("Cancelled.")
+			return
+		}
+
+		if installed {
+			if err := apply.Uninstall(entry); err != nil {
+				fmt.Printf("  ✗ %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			journal.Remove(module, name)
+			if err := journal.Save(); err != nil {
+				fmt.Printf("Error saving install journal: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("  ✓ Uninstalled %s\n", name)
+		}
+
+		if inConfig {
+			for _, p := range paths {
+				cfg.RemoveValue(p, name)
+			}
+			if module == "cli" {
+				delete(cfg.GetMap("cli.customSources"), name)
+			}
+			if err := cfg.Save(); err != nil {
+				fmt.Printf("Error saving pact.json: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("  ✓ Removed %s from pact.json\n", args[0])
+			fmt.Println("\nRun 'pact push' to sync the change.")
+		}
+	},
+}
+
+// uninstallConfigPaths returns the pact.json keys that can list an item of
+// the given module
+func uninstallConfigPaths(module string) []string {
+	switch module {
+	case "cli":
+		return []string{"cli.tools", "cli.custom", "cli.casks", "cli.taps", "cli.npm", "cli.pip", "cli.cargo", "cli.go"}
+	case "shell":
+		return []string{"shell.tools", "shell.prompt.tool"}
+	case "editor":
+		return []string{"editor.extensions", "editor.vscode.extensions", "editor.cursor.extensions"}
+	case "terminal":
+		return []string{"terminal.font"}
+	case "apps":
+		return []string{fmt.Sprintf("apps.%s.install", runtime.GOOS)}
+	case "llm":
+		return []string{"llm.local.runtime", "llm.local.models"}
+	}
+	return nil
+}
+
+func init() {
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Skip confirmation")
+	rootCmd.AddCommand(uninstallCmd)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -8,6 +9,8 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/cloudboy-jh/pact/internal/archive"
+	"github.com/cloudboy-jh/pact/internal/buildinfo"
 	"github.com/cloudboy-jh/pact/internal/ui"
 	"github.com/spf13/cobra"
 )
@@ -148,10 +151,10 @@ func updateViaDirectDownload() {
 		os.Exit(1)
 	}
 
-	currentVersion := strings.TrimPrefix(ui.Version, "v")
+	currentVersion := buildinfo.Get().Version
 	latestVersion = strings.TrimPrefix(latestVersion, "v")
 
-	if currentVersion == latestVersion {
+	if buildinfo.Compare(currentVersion, latestVersion) >= 0 {
 		fmt.Printf("\n✓ You already have the latest version: %s\n", currentVersion)
 		return
 	}
@@ -197,18 +200,18 @@ func updateViaDirectDownload() {
 		os.Exit(1)
 	}
 
-	// Extract
-	fmt.Println("Extracting...")
-	if err := extractArchive(tmpFile, tmpDir); err != nil {
-		fmt.Printf("Error: Failed to extract update: %v\n", err)
-		os.Exit(1)
-	}
-
-	// Find the binary
 	binaryName := "pact"
 	if runtime.GOOS == "windows" {
 		binaryName = "pact.exe"
 	}
+
+	// Extract just the binary, which sits at the top of the archive
+	fmt.Println("Extracting...")
+	match := func(name string) bool { return name == binaryName }
+	if err := archive.Extract(context.Background(), tmpFile, tmpDir, match); err != nil {
+		fmt.Printf("Error: Failed to extract update: %v\n", err)
+		os.Exit(1)
+	}
 	newBinary := filepath.Join(tmpDir, binaryName)
 
 	// Find current binary location
@@ -269,15 +272,6 @@ func downloadFile(url, filepath string) error {
 	return cmd.Run()
 }
 
-func extractArchive(archivePath, destDir string) error {
-	if strings.HasSuffix(archivePath, ".zip") {
-		cmd := exec.Command("unzip", "-q", "-o", archivePath, "-d", destDir)
-		return cmd.Run()
-	}
-	cmd := exec.Command("tar", "-xzf", archivePath, "-C", destDir)
-	return cmd.Run()
-}
-
 func replaceBinary(src, dst string) error {
 	// On Windows, we can't overwrite a running executable
 	// So we rename the old one, move the new one, and schedule deletion of old
@@ -301,7 +295,7 @@ func replaceBinary(src, dst string) error {
 
 		// Schedule deletion of old binary on next reboot (Windows specific)
 		// For now, we'll just leave it and it can be cleaned up manually
-		fmt.Println("Note: Old binary saved as pact.exe.old - you can delete it manually.")
+		fmt.Println("Note: Old binary saved as pact.exe.old - run 'pact clean' to remove it.")
 
 		return nil
 	}
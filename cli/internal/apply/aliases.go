@@ -0,0 +1,168 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Markers around the pact-managed alias block, so re-applying replaces it
+const (
+	aliasBlockStart = "# Pact: aliases"
+	aliasBlockEnd   = "# Pact: end aliases"
+)
+
+// nuUnsupported is shell syntax with no direct nushell alias equivalent
+var nuUnsupported = []string{"&&", "||", ";", "$", "`", ">", "<", "*"}
+
+// injectAliases writes shell.aliases into the shell config, translated for
+// the current shell
+func injectAliases(cfg *config.PactConfig, opts Options) Result {
+	result := Result{
+		Category: "configure",
+		Module:   "shell",
+		Name:     "aliases",
+	}
+
+	aliases := cfg.GetMap("shell.aliases")
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var shell string
+	var shellConfigs []string
+	if runtime.GOOS == "windows" {
+		shell = "powershell"
+		shellConfigs = config.GetPowerShellProfiles(cfg.PowerShellProfileScope())
+	} else {
+		shell = currentShell()
+		rcFile := shellRCFile(shell)
+		if rcFile == "" {
+			return unsupportedShell(result, shell)
+		}
+		shellConfigs = []string{rcFile}
+	}
+
+	var lines, untranslated []string
+	for _, name := range names {
+		command, ok := aliases[name].(string)
+		if !ok {
+			continue
+		}
+		line, ok := aliasLine(shell, name, command)
+		if !ok {
+			untranslated = append(untranslated, name)
+			line = fmt.Sprintf("# %s: not translated for %s: %s", name, shell, command)
+		}
+		lines = append(lines, line)
+	}
+
+	var updated []string
+	for _, shellConfig := range shellConfigs {
+		changed, err := aliasBlock.write(shellConfig, lines, opts)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if changed {
+			updated = append(updated, filepath.Base(shellConfig))
+		}
+	}
+
+	result.Success = true
+	if len(updated) == 0 {
+		result.Skipped = true
+		result.Message = "already configured"
+	} else if opts.DryRun {
+		result = planned(result, "write %d aliases to %s", len(names), strings.Join(updated, ", "))
+	} else {
+		result.Message = fmt.Sprintf("%d aliases written to %s", len(names), strings.Join(updated, ", "))
+	}
+	if len(untranslated) > 0 {
+		result.Message += fmt.Sprintf(" (not translated for %s: %s)", shell, strings.Join(untranslated, ", "))
+	}
+	return result
+}
+
+// aliasLine renders an alias for a shell; ok is false when the command can't
+// be expressed as an alias there
+func aliasLine(shell, name, command string) (string, bool) {
+	switch shell {
+	case "zsh", "bash":
+		return fmt.Sprintf("alias %s='%s'", name, strings.ReplaceAll(command, "'", `'\''`)), true
+	case "fish":
+		return fmt.Sprintf("alias %s '%s'", name, strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(command)), true
+	case "nu":
+		for _, syntax := range nuUnsupported {
+			if strings.Contains(command, syntax) {
+				return "", false
+			}
+		}
+		return fmt.Sprintf("alias %s = %s", name, command), true
+	case "xonsh":
+		return fmt.Sprintf("aliases['%s'] = '%s'", name, strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(command)), true
+	case "powershell":
+		return fmt.Sprintf("function %s { %s @args }", name, command), true
+	}
+	return "", false
+}
+
+// managedBlock is a marked section of a file that pact owns and replaces on
+// every apply, leaving the rest of the file alone
+type managedBlock struct {
+	start, end string
+	top        bool        // A new block goes at the top of the file, not the end
+	perm       os.FileMode // Mode of a new file
+}
+
+var aliasBlock = managedBlock{start: aliasBlockStart, end: aliasBlockEnd, perm: 0644}
+
+// write replaces the block in a file with lines, or adds it. Reports
+// whether the file changed (or would, in a dry run).
+func (b managedBlock) write(path string, lines []string, opts Options) (bool, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+	content := string(existing)
+	block := b.start + "\n" + strings.Join(lines, "\n") + "\n" + b.end + "\n"
+
+	var updated string
+	start := strings.Index(content, b.start+"\n")
+	end := strings.Index(content, b.end+"\n")
+	switch {
+	case start >= 0 && end > start:
+		updated = content[:start] + block + content[end+len(b.end)+1:]
+	case b.top && content != "":
+		updated = block + "\n" + content
+	default:
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		updated = content + "\n" + block
+	}
+
+	if updated == string(existing) {
+		return false, nil
+	}
+	if err := allowPath(path, opts); err != nil {
+		return false, err
+	}
+	if opts.DryRun {
+		return true, nil
+	}
+	if err := opts.Undo.File(path); err != nil {
+		return false, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(updated), b.perm)
+}
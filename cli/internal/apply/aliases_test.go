@@ -0,0 +1,27 @@
+package apply
+
+import "testing"
+
+func TestAliasLine(t *testing.T) {
+	tests := []struct {
+		shell, command string
+		want           string
+		ok             bool
+	}{
+		{"zsh", "git status", "alias gs='git status'", true},
+		{"bash", "echo 'hi'", `alias gs='echo '\''hi'\'''`, true},
+		{"fish", "echo 'hi'", `alias gs 'echo \'hi\''`, true},
+		{"nu", "git status", "alias gs = git status", true},
+		{"nu", "git add . && git commit", "", false},
+		{"xonsh", "git status", "aliases['gs'] = 'git status'", true},
+		{"powershell", "git status", "function gs { git status @args }", true},
+		{"tcsh", "git status", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := aliasLine(tt.shell, "gs", tt.command)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("aliasLine(%q, %q) = %q, %v; want %q, %v", tt.shell, tt.command, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -1,7 +1,8 @@
 package apply
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,7 +12,12 @@ import (
 	"runtime"
 	"strings"
 
+	"github.com/cloudboy-jh/pact/internal/archive"
+	"github.com/cloudboy-jh/pact/internal/backup"
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
+	psync "github.com/cloudboy-jh/pact/internal/sync"
+	"github.com/cloudboy-jh/pact/internal/undo"
 )
 
 // Result represents the result of applying a config item
@@ -23,75 +29,221 @@ type Result struct {
 	Skipped  bool
 	Message  string
 	Error    error
+
+	// Set when something was actually installed, for the install journal
+	Backend string   // Backend that installed it ("brew", "apt", "code", "binary", ...)
+	Package string   // Backend package id when it differs from Name
+	Paths   []string // Files placed directly by pact
+
+	Deferred bool // Postponed by a low-bandwidth sync
+	Planned  bool // Would be done, reported by a dry run
+	Kept     bool // A file left as it was after a conflict with pact
+}
+
+// Options control how a config is applied
+type Options struct {
+	// LowBandwidth defers large downloads (fonts, GUI apps, LLM models) to
+	// the next normal sync while still applying everything else
+	LowBandwidth bool
+
+	// DryRun reports the commands and file changes each step would make
+	// without running or writing anything
+	DryRun bool
+
+	// AcceptLicense is asked whether to re-run an install that stopped on a
+	// license prompt interactively; when nil the step just fails
+	AcceptLicense func(command, notice string) bool
+
+	// Confirm is asked before changing a setting that affects the whole
+	// machine, like its hostname, or uninstalling an app in apps.<os>.remove;
+	// when nil those are skipped
+	Confirm func(question string) bool
+
+	// Undo records every change so the sync can be reverted with 'pact undo'
+	Undo *undo.Journal
+
+	// Scope is ScopeUser to keep every change inside the user's home and
+	// reject anything that needs admin rights
+	Scope string
+	// Jobs is how many CLI tools install at once (brew, scoop, and custom
+	// tools); 0 or 1 installs them one at a time
+	Jobs int
+
+	// NoSudo skips every step that needs sudo instead of running it
+	NoSudo bool
+	// Sudo asks for sudo's password once for the whole run; when nil sudo
+	// asks on its own, as each command needs it
+	Sudo *Sudo
+
+	// ForbidScripts refuses custom tools installed by a downloaded script
+	ForbidScripts bool
+
+	// Context cancels the run, e.g. on Ctrl+C: running commands are
+	// interrupted and remaining steps fail fast
+	Context context.Context
+	// Timeouts limit how long each install, download, and hook may take
+	Timeouts Timeouts
+
+	// OnConflict is what to do with a target that differs from its pact
+	// source: ConflictKeep, ConflictOverwrite, ConflictMerge, or
+	// ConflictBackup. When empty, ResolveConflict is asked, and without it
+	// the target is backed up.
+	OnConflict      string
+	ResolveConflict func(c Conflict) string
+	// EditMerge lets the user resolve the conflict markers left by a merge;
+	// when nil a merge with conflicts fails and changes nothing
+	EditMerge func(item config.SyncItem, merged string) (string, error)
+}
+
+// deferResult marks a result as postponed until a normal sync
+func deferResult(result Result) Result {
+	result.Success = true
+	result.Skipped = true
+	result.Deferred = true
+	result.Message = "deferred (low bandwidth)"
+	return result
+}
+
+// planned marks a result as a change a dry run would make
+func planned(result Result, format string, args ...any) Result {
+	result.Success = true
+	result.Planned = true
+	result.Message = "would " + fmt.Sprintf(format, args...)
+	return result
+}
+
+// commandLine renders a command the way it would be typed in a shell
+func commandLine(cmd *exec.Cmd) string {
+	args := make([]string, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		if arg == "" || strings.ContainsAny(arg, " \t\"'$&|;<>*?()`") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		args[i] = arg
+	}
+	return strings.Join(args, " ")
 }
 
 // Apply applies the entire pact configuration
-func Apply(cfg *config.PactConfig) ([]Result, error) {
+func Apply(cfg *config.PactConfig, opts Options) ([]Result, error) {
 	var results []Result
 
 	// 1. Install CLI tools
-	toolResults := applyCliTools(cfg)
+	toolResults := applyCliTools(cfg, opts)
 	results = append(results, toolResults...)
 
 	// 2. Setup shell (prompt, tools, config injection)
-	shellResults := applyShell(cfg)
+	shellResults := applyShell(cfg, opts)
 	results = append(results, shellResults...)
 
 	// 3. Setup git config
-	gitResults := applyGit(cfg)
+	gitResults := applyGit(cfg, opts)
 	results = append(results, gitResults...)
 
 	// 4. Setup editor + extensions
-	editorResults := applyEditor(cfg)
+	editorResults := applyEditor(cfg, opts)
 	results = append(results, editorResults...)
 
 	// 5. Setup terminal + fonts
-	terminalResults := applyTerminal(cfg)
+	terminalResults := applyTerminal(cfg, opts)
 	results = append(results, terminalResults...)
 
 	// 6. Install apps
-	appResults := applyApps(cfg)
+	appResults := applyApps(cfg, opts)
 	results = append(results, appResults...)
 
 	// 7. Apply any file syncs
-	fileResults := applyFiles(cfg)
+	fileResults := applyFiles(cfg, opts)
 	results = append(results, fileResults...)
 
+	if !opts.DryRun {
+		recordInstalls(results)
+		recordUndo(opts.Undo, results)
+		recordHistory(results)
+		recordApplied(cfg, results)
+		recordDeferred(results)
+	}
 	return results, nil
 }
 
 // ApplyModule applies a specific module
-func ApplyModule(cfg *config.PactConfig, module string) ([]Result, error) {
+func ApplyModule(cfg *config.PactConfig, module string, opts Options) ([]Result, error) {
+	if err := opts.ctx().Err(); err != nil {
+		return nil, err
+	}
+
+	// A failed pre hook skips the module
+	pre := runHooks(cfg, module, "pre", nil, opts)
+	for _, r := range pre {
+		if r.Error != nil {
+			return pre, nil
+		}
+	}
+
+	var results []Result
 	switch module {
 	case "cli":
-		return applyCliTools(cfg), nil
+		results = applyCliTools(cfg, opts)
 	case "shell":
-		return applyShell(cfg), nil
+		results = applyShell(cfg, opts)
 	case "git":
-		return applyGit(cfg), nil
+		results = applyGit(cfg, opts)
 	case "editor":
-		return applyEditor(cfg), nil
+		results = applyEditor(cfg, opts)
 	case "terminal":
-		return applyTerminal(cfg), nil
+		results = applyTerminal(cfg, opts)
 	case "llm":
-		return applyLLM(cfg), nil
+		results = applyLLM(cfg, opts)
 	case "apps":
-		return applyApps(cfg), nil
+		results = applyApps(cfg, opts)
+	case "keybindings":
+		results = applyKeybindings(cfg, opts)
+	case "snippets":
+		results = applySnippets(cfg, opts)
+	case "sound":
+		results = applySound(cfg, opts)
+	case "secrets":
+		results = applySecrets(cfg, opts)
+	case "machine":
+		results = applyMachine(cfg, opts)
+	case "power":
+		results = applyPower(cfg, opts)
+	case "printers":
+		results = applyPrinters(cfg, opts)
+	case "mounts":
+		results = applyMounts(cfg, opts)
+	case "ssh":
+		results = applySSH(cfg, opts)
 	default:
 		// Try to apply files for this module
-		return applyModuleFiles(cfg, module), nil
+		results = applyModuleFiles(cfg, module, opts)
 	}
+
+	post := runHooks(cfg, module, "post", results, opts)
+	results = append(append(pre, results...), post...)
+
+	if !opts.DryRun {
+		recordInstalls(results)
+		recordUndo(opts.Undo, results)
+		recordHistory(results, module)
+		recordApplied(cfg, results, module)
+		recordDeferred(results, module)
+	}
+	return results, nil
 }
 
 // =============================================================================
 // CLI Tools
 // =============================================================================
 
-func applyCliTools(cfg *config.PactConfig) []Result {
+func applyCliTools(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
+	// Homebrew taps the tools and casks below may come from
+	results = append(results, applyTaps(cfg, opts)...)
+
 	// Standard tools from package manager
-	tools := cfg.GetStringSlice("cli.tools")
+	tools := cfg.GetPackages("cli.tools")
 	if len(tools) > 0 {
 		pm := detectPackageManager()
 		if pm == "" {
@@ -102,155 +254,42 @@ func applyCliTools(cfg *config.PactConfig) []Result {
 				Error:    fmt.Errorf("no supported package manager found (brew, apt, winget)"),
 			})
 		} else {
-			for _, tool := range tools {
-				result := installTool(pm, tool)
-				results = append(results, result)
+			jobs := opts.Jobs
+			if !parallelManagers[pm] {
+				jobs = 1
 			}
+			byName := make(map[string]config.Package, len(tools))
+			names := make([]string, len(tools))
+			for i, tool := range tools {
+				byName[tool.Name] = tool
+				names[i] = tool.Name
+			}
+			results = append(results, installAll(names, jobs, func(name string) Result {
+				return installSystemPackage(pm, byName[name], opts)
+			})...)
 		}
 	}
 
 	// Custom tools from GitHub releases
 	customTools := cfg.GetStringSlice("cli.custom")
-	for _, tool := range customTools {
-		result := installCustomTool(cfg, tool)
-		results = append(results, result)
-	}
-
-	return results
-}
-
-// installCustomTool installs a tool from GitHub releases
-func installCustomTool(cfg *config.PactConfig, tool string) Result {
-	result := Result{
-		Category: "install",
-		Module:   "cli",
-		Name:     tool,
-	}
-
-	// Check if already installed
-	if isToolInstalled(tool) {
-		result.Success = true
-		result.Skipped = true
-		result.Message = "already installed"
-		return result
-	}
-
-	// Map tool names to GitHub repos
-	repoMap := map[string]string{
-		"pact":   "cloudboy-jh/pact",
-		"churn":  "cloudboy-jh/churn",
-		"annotr": "cloudboy-jh/annotr",
-	}
-
-	repo, ok := repoMap[tool]
-	if !ok {
-		// Try to install via package manager as fallback
-		pm := detectPackageManager()
-		if pm != "" {
-			return installTool(pm, tool)
-		}
-		result.Error = fmt.Errorf("unknown custom tool and no package manager available")
-		return result
-	}
-
-	// Get latest release from GitHub
-	releaseURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", repo)
-	resp, err := http.Get(releaseURL)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to fetch release info: %w", err)
-		return result
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		result.Error = fmt.Errorf("no releases found for %s", repo)
-		return result
-	}
-
-	var release struct {
-		Assets []struct {
-			Name               string `json:"name"`
-			BrowserDownloadURL string `json:"browser_download_url"`
-		} `json:"assets"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
-		result.Error = fmt.Errorf("failed to parse release info: %w", err)
-		return result
-	}
-
-	// Find the right asset for this OS/arch
-	osName := runtime.GOOS
-	arch := runtime.GOARCH
-	if arch == "amd64" {
-		arch = "x86_64"
-	}
-
-	var downloadURL string
-	for _, asset := range release.Assets {
-		name := strings.ToLower(asset.Name)
-		if strings.Contains(name, osName) && (strings.Contains(name, arch) || strings.Contains(name, "amd64") || strings.Contains(name, "x64")) {
-			downloadURL = asset.BrowserDownloadURL
-			break
-		}
-	}
+	results = append(results, installAll(customTools, opts.Jobs, func(tool string) Result {
+		return installCustomTool(cfg, tool, opts)
+	})...)
 
-	if downloadURL == "" {
-		result.Error = fmt.Errorf("no compatible release found for %s/%s", osName, arch)
-		return result
-	}
+	// GUI tools from Homebrew casks
+	results = append(results, applyCasks(cfg, opts)...)
 
-	// Download and install
-	tmpFile := filepath.Join(os.TempDir(), tool+"-download")
-	if err := downloadFile(downloadURL, tmpFile); err != nil {
-		result.Error = err
-		return result
-	}
-	defer os.Remove(tmpFile)
+	// Global npm, pip, cargo, and go packages
+	results = append(results, applyPackages(cfg, opts)...)
 
-	// Determine install location
-	installDir := "/usr/local/bin"
-	if runtime.GOOS == "windows" {
-		home, _ := os.UserHomeDir()
-		installDir = filepath.Join(home, "bin")
-		os.MkdirAll(installDir, 0755)
-	}
-
-	installPath := filepath.Join(installDir, tool)
-	if runtime.GOOS == "windows" {
-		installPath += ".exe"
-	}
-
-	// Handle tar.gz or zip
-	if strings.HasSuffix(downloadURL, ".tar.gz") || strings.HasSuffix(downloadURL, ".tgz") {
-		if err := extractTarGz(tmpFile, installDir, tool); err != nil {
-			result.Error = err
-			return result
-		}
-	} else if strings.HasSuffix(downloadURL, ".zip") {
-		if err := extractZip(tmpFile, installDir, tool); err != nil {
-			result.Error = err
-			return result
-		}
-	} else {
-		// Direct binary
-		if err := copyFile(tmpFile, installPath); err != nil {
-			result.Error = err
-			return result
-		}
-		os.Chmod(installPath, 0755)
-	}
-
-	result.Success = true
-	result.Message = fmt.Sprintf("installed from %s", repo)
-	return result
+	return results
 }
 
 // =============================================================================
 // Shell
 // =============================================================================
 
-func applyShell(cfg *config.PactConfig) []Result {
+func applyShell(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
 	// Install prompt tool
@@ -258,7 +297,8 @@ func applyShell(cfg *config.PactConfig) []Result {
 	if promptTool != "" {
 		pm := detectPackageManager()
 		if pm != "" {
-			result := installTool(pm, promptTool)
+			result := installTool(pm, promptTool, opts)
+			result.Module = "shell"
 			results = append(results, result)
 		}
 
@@ -266,12 +306,12 @@ func applyShell(cfg *config.PactConfig) []Result {
 		themeSource := cfg.GetString("shell.prompt.source")
 		themeName := cfg.GetString("shell.prompt.theme")
 		if themeSource != "" && themeName != "" {
-			result := downloadPromptTheme(promptTool, themeName, themeSource)
+			result := downloadPromptTheme(promptTool, themeName, themeSource, opts)
 			results = append(results, result)
 		}
 
 		// Inject shell config
-		result := injectShellConfig(cfg, promptTool, themeName)
+		result := injectShellConfig(cfg, promptTool, themeName, opts)
 		results = append(results, result)
 	}
 
@@ -281,11 +321,12 @@ func applyShell(cfg *config.PactConfig) []Result {
 		pm := detectPackageManager()
 		if pm != "" {
 			for _, tool := range shellTools {
-				result := installTool(pm, tool)
+				result := installTool(pm, tool, opts)
+				result.Module = "shell"
 				results = append(results, result)
 
 				// Inject tool init into shell config
-				initResult := injectToolInit(tool)
+				initResult := injectToolInit(cfg, tool, opts)
 				if initResult.Message != "" {
 					results = append(results, initResult)
 				}
@@ -293,11 +334,17 @@ func applyShell(cfg *config.PactConfig) []Result {
 		}
 	}
 
+	if len(cfg.GetMap("shell.aliases")) > 0 {
+		results = append(results, injectAliases(cfg, opts))
+	}
+
+	results = append(results, applyDirenv(cfg, opts)...)
+
 	return results
 }
 
 // injectShellConfig adds prompt initialization to shell config
-func injectShellConfig(cfg *config.PactConfig, promptTool, themeName string) Result {
+func injectShellConfig(cfg *config.PactConfig, promptTool, themeName string, opts Options) Result {
 	result := Result{
 		Category: "configure",
 		Module:   "shell",
@@ -305,32 +352,21 @@ func injectShellConfig(cfg *config.PactConfig, promptTool, themeName string) Res
 	}
 
 	home, _ := os.UserHomeDir()
-	var shellConfig string
+	var shellConfigs []string
 	var initLine string
 
 	switch runtime.GOOS {
 	case "darwin", "linux":
-		// Detect shell
-		shell := os.Getenv("SHELL")
-		if strings.Contains(shell, "zsh") {
-			shellConfig = filepath.Join(home, ".zshrc")
-		} else if strings.Contains(shell, "bash") {
-			shellConfig = filepath.Join(home, ".bashrc")
-		} else {
-			shellConfig = filepath.Join(home, ".zshrc") // default
-		}
-
-		switch promptTool {
-		case "oh-my-posh":
-			themePath := filepath.Join(home, ".config/oh-my-posh/themes", themeName+".omp.json")
-			initLine = fmt.Sprintf(`eval "$(oh-my-posh init %s --config '%s')"`, filepath.Base(shell), themePath)
-		case "starship":
-			initLine = `eval "$(starship init zsh)"`
+		shell := currentShell()
+		rcFile := shellRCFile(shell)
+		if rcFile == "" {
+			return unsupportedShell(result, shell)
 		}
+		shellConfigs = []string{rcFile}
+		initLine = promptInitLine(shell, promptTool, promptThemePath(promptTool, themeName))
 
 	case "windows":
-		shellConfig = filepath.Join(home, "Documents/PowerShell/Microsoft.PowerShell_profile.ps1")
-		os.MkdirAll(filepath.Dir(shellConfig), 0755)
+		shellConfigs = config.GetPowerShellProfiles(cfg.PowerShellProfileScope())
 
 		switch promptTool {
 		case "oh-my-posh":
@@ -348,73 +384,41 @@ func injectShellConfig(cfg *config.PactConfig, promptTool, themeName string) Res
 		return result
 	}
 
-	// Check if already in config
-	existing, _ := os.ReadFile(shellConfig)
-	if strings.Contains(string(existing), promptTool) {
-		result.Success = true
-		result.Skipped = true
-		result.Message = "already configured"
-		return result
-	}
-
-	// Append to shell config
-	f, err := os.OpenFile(shellConfig, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		result.Error = err
-		return result
-	}
-	defer f.Close()
-
-	_, err = f.WriteString(fmt.Sprintf("\n# Pact: %s\n%s\n", promptTool, initLine))
-	if err != nil {
-		result.Error = err
-		return result
-	}
-
-	result.Success = true
-	result.Message = fmt.Sprintf("added to %s", filepath.Base(shellConfig))
-	return result
+	return appendShellInit(result, shellConfigs, promptTool, initLine, opts)
 }
 
 // injectToolInit adds tool initialization to shell config
-func injectToolInit(tool string) Result {
+func injectToolInit(cfg *config.PactConfig, tool string, opts Options) Result {
 	result := Result{
 		Category: "configure",
 		Module:   "shell",
 		Name:     tool + "-init",
 	}
 
-	home, _ := os.UserHomeDir()
-	var shellConfig string
+	var shellConfigs []string
 	var initLine string
 
-	shell := os.Getenv("SHELL")
-	shellName := "zsh"
-	if strings.Contains(shell, "bash") {
-		shellName = "bash"
-	}
-
 	switch runtime.GOOS {
 	case "darwin", "linux":
-		if shellName == "zsh" {
-			shellConfig = filepath.Join(home, ".zshrc")
-		} else {
-			shellConfig = filepath.Join(home, ".bashrc")
-		}
-
-		switch tool {
-		case "zoxide":
-			initLine = fmt.Sprintf(`eval "$(zoxide init %s)"`, shellName)
-		case "fzf":
-			initLine = `[ -f ~/.fzf.zsh ] && source ~/.fzf.zsh`
-		case "direnv":
-			initLine = fmt.Sprintf(`eval "$(direnv hook %s)"`, shellName)
-		default:
+		if !shellInitTools[tool] {
 			return result // No init needed
 		}
+		shell := currentShell()
+		rcFile := shellRCFile(shell)
+		if rcFile == "" {
+			return unsupportedShell(result, shell)
+		}
+		shellConfigs = []string{rcFile}
+		initLine = toolInitLine(shell, tool)
+		if initLine == "" {
+			result.Success = true
+			result.Skipped = true
+			result.Message = fmt.Sprintf("no %s integration for %s", tool, shell)
+			return result
+		}
 
 	case "windows":
-		shellConfig = filepath.Join(home, "Documents/PowerShell/Microsoft.PowerShell_profile.ps1")
+		shellConfigs = config.GetPowerShellProfiles(cfg.PowerShellProfileScope())
 
 		switch tool {
 		case "zoxide":
@@ -428,30 +432,70 @@ func injectToolInit(tool string) Result {
 		return result
 	}
 
-	// Check if already in config
-	existing, _ := os.ReadFile(shellConfig)
-	if strings.Contains(string(existing), tool) {
+	return appendShellInit(result, shellConfigs, tool, initLine, opts)
+}
+
+// appendShellInit appends a pact-marked init line to every shell config that
+// doesn't already reference the tool (PowerShell 5 and 7 keep separate profiles)
+func appendShellInit(result Result, shellConfigs []string, tool, initLine string, opts Options) Result {
+	var updated []string
+	for _, shellConfig := range shellConfigs {
+		// Check if already in config
+		existing, _ := os.ReadFile(shellConfig)
+		if strings.Contains(string(existing), tool) {
+			continue
+		}
+
+		name := filepath.Base(shellConfig)
+		if len(shellConfigs) > 1 {
+			name = filepath.Base(filepath.Dir(shellConfig)) + "/" + name
+		}
+		if err := allowPath(shellConfig, opts); err != nil {
+			result.Error = err
+			return result
+		}
+		if opts.DryRun {
+			updated = append(updated, name)
+			continue
+		}
+
+		if err := opts.Undo.File(shellConfig); err != nil {
+			result.Error = err
+			return result
+		}
+		if err := os.MkdirAll(filepath.Dir(shellConfig), 0755); err != nil {
+			result.Error = err
+			return result
+		}
+
+		f, err := os.OpenFile(shellConfig, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+
+		_, err = f.WriteString(fmt.Sprintf("\n# Pact: %s\n%s\n", tool, initLine))
+		f.Close()
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		updated = append(updated, name)
+	}
+
+	if len(updated) == 0 {
 		result.Success = true
 		result.Skipped = true
 		result.Message = "already configured"
 		return result
 	}
 
-	f, err := os.OpenFile(shellConfig, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
-	if err != nil {
-		result.Error = err
-		return result
-	}
-	defer f.Close()
-
-	_, err = f.WriteString(fmt.Sprintf("\n# Pact: %s\n%s\n", tool, initLine))
-	if err != nil {
-		result.Error = err
-		return result
+	if opts.DryRun {
+		return planned(result, "append to %s: %s", strings.Join(updated, ", "), strings.ReplaceAll(initLine, "\n", "; "))
 	}
 
 	result.Success = true
-	result.Message = fmt.Sprintf("added to %s", filepath.Base(shellConfig))
+	result.Message = fmt.Sprintf("added to %s", strings.Join(updated, ", "))
 	return result
 }
 
@@ -459,77 +503,37 @@ func injectToolInit(tool string) Result {
 // Git
 // =============================================================================
 
-func applyGit(cfg *config.PactConfig) []Result {
+func applyGit(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
-	user := cfg.GetString("git.user")
-	email := cfg.GetString("git.email")
-	defaultBranch := cfg.GetString("git.defaultBranch")
-
-	if user != "" {
-		if err := runGitConfig("user.name", user); err != nil {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "user.name",
-				Error:    err,
-			})
-		} else {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "user.name",
-				Success:  true,
-				Message:  user,
-			})
-		}
+	settings := [][2]string{
+		{"user.name", cfg.GetString("git.user")},
+		{"user.email", cfg.GetString("git.email")},
+		{"init.defaultBranch", cfg.GetString("git.defaultBranch")},
 	}
-
-	if email != "" {
-		if err := runGitConfig("user.email", email); err != nil {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "user.email",
-				Error:    err,
-			})
-		} else {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "user.email",
-				Success:  true,
-				Message:  email,
-			})
+	for _, setting := range settings {
+		if setting[1] != "" {
+			results = append(results, setGitConfig(setting[0], setting[1], opts))
 		}
 	}
 
-	if defaultBranch != "" {
-		if err := runGitConfig("init.defaultBranch", defaultBranch); err != nil {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "init.defaultBranch",
-				Error:    err,
-			})
-		} else {
-			results = append(results, Result{
-				Category: "configure",
-				Module:   "git",
-				Name:     "init.defaultBranch",
-				Success:  true,
-				Message:  defaultBranch,
-			})
-		}
-	}
+	results = append(results, applyGitConfig(cfg, opts)...)
+	results = append(results, applyCredentialHelper(cfg, opts)...)
+	results = append(results, applyGitPager(cfg, opts)...)
+	results = append(results, applyHookManager(cfg, opts)...)
+	results = append(results, applySigning(cfg, opts)...)
 
 	// Git LFS
 	if cfg.Get("git.lfs") == true {
-		if err := exec.Command("git", "lfs", "install").Run(); err != nil {
+		if opts.DryRun {
+			results = append(results, planned(Result{Category: "configure", Module: "git", Name: "lfs"}, "run git lfs install"))
+			return results
+		}
+		if err := exec.CommandContext(opts.ctx(), "git", "lfs", "install").Run(); err != nil {
 			pm := detectPackageManager()
 			if pm != "" {
-				installTool(pm, "git-lfs")
-				exec.Command("git", "lfs", "install").Run()
+				installTool(pm, "git-lfs", opts)
+				exec.CommandContext(opts.ctx(), "git", "lfs", "install").Run()
 			}
 		}
 		results = append(results, Result{
@@ -548,14 +552,14 @@ func applyGit(cfg *config.PactConfig) []Result {
 // Editor
 // =============================================================================
 
-func applyEditor(cfg *config.PactConfig) []Result {
+func applyEditor(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
 	defaultEditor := cfg.GetString("editor.default")
 
 	// Install editor if possible
 	if defaultEditor != "" {
-		result := installEditor(defaultEditor)
+		result := installEditor(defaultEditor, opts)
 		results = append(results, result)
 	}
 
@@ -563,7 +567,7 @@ func applyEditor(cfg *config.PactConfig) []Result {
 	extensions := cfg.GetStringSlice("editor.extensions")
 	if len(extensions) > 0 {
 		for _, ext := range extensions {
-			result := installExtension(defaultEditor, ext)
+			result := installExtension(defaultEditor, ext, opts)
 			results = append(results, result)
 		}
 	}
@@ -571,20 +575,20 @@ func applyEditor(cfg *config.PactConfig) []Result {
 	// Also check for vscode/cursor specific extensions
 	vscodeExts := cfg.GetStringSlice("editor.vscode.extensions")
 	for _, ext := range vscodeExts {
-		result := installExtension("vscode", ext)
+		result := installExtension("vscode", ext, opts)
 		results = append(results, result)
 	}
 
 	cursorExts := cfg.GetStringSlice("editor.cursor.extensions")
 	for _, ext := range cursorExts {
-		result := installExtension("cursor", ext)
+		result := installExtension("cursor", ext, opts)
 		results = append(results, result)
 	}
 
-	return results
+	return append(results, applyEditorSettings(cfg, opts)...)
 }
 
-func installEditor(editor string) Result {
+func installEditor(editor string, opts Options) Result {
 	result := Result{
 		Category: "install",
 		Module:   "editor",
@@ -638,27 +642,33 @@ func installEditor(editor string) Result {
 		pkgName = "neovim"
 	}
 
-	installResult := installTool(pm, pkgName)
+	installResult := installTool(pm, pkgName, opts)
 	result.Success = installResult.Success
 	result.Skipped = installResult.Skipped
+	result.Planned = installResult.Planned
 	result.Message = installResult.Message
 	result.Error = installResult.Error
+	result.Backend = installResult.Backend
+	result.Package = installResult.Package
 	return result
 }
 
-func installExtension(editor, extension string) Result {
+func installExtension(editor, extension string, opts Options) Result {
 	result := Result{
 		Category: "extension",
 		Module:   "editor",
 		Name:     extension,
 	}
 
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
 	var cmd *exec.Cmd
 	switch editor {
 	case "code", "vscode":
-		cmd = exec.Command("code", "--install-extension", extension, "--force")
+		cmd = command(ctx, "code", "--install-extension", extension, "--force")
 	case "cursor":
-		cmd = exec.Command("cursor", "--install-extension", extension, "--force")
+		cmd = command(ctx, "cursor", "--install-extension", extension, "--force")
 	default:
 		result.Success = true
 		result.Skipped = true
@@ -666,6 +676,10 @@ func installExtension(editor, extension string) Result {
 		return result
 	}
 
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+
 	output, err := cmd.CombinedOutput()
 	if err != nil {
 		// Check if already installed
@@ -675,12 +689,13 @@ func installExtension(editor, extension string) Result {
 			result.Message = "already installed"
 			return result
 		}
-		result.Error = fmt.Errorf("%v: %s", err, string(output))
+		result.Error = stepError(ctx, fmt.Errorf("%v: %s", err, string(output)))
 		return result
 	}
 
 	result.Success = true
 	result.Message = "installed"
+	result.Backend = editor
 	return result
 }
 
@@ -688,30 +703,31 @@ func installExtension(editor, extension string) Result {
 // Terminal & Fonts
 // =============================================================================
 
-func applyTerminal(cfg *config.PactConfig) []Result {
+func applyTerminal(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
 	font := cfg.GetString("terminal.font")
 	if font != "" {
-		result := installNerdFont(font)
-		results = append(results, result)
+		if opts.LowBandwidth && !isFontInstalled(font) {
+			results = append(results, deferResult(Result{Category: "font", Module: "terminal", Name: font}))
+		} else {
+			results = append(results, installNerdFont(font, opts))
+		}
 	}
+	results = append(results, applyTerminalEmulators(cfg, opts)...)
+	results = append(results, applyEditorTerminalFonts(cfg, opts)...)
 
 	return results
 }
 
-func installNerdFont(fontName string) Result {
+func installNerdFont(fontName string, opts Options) Result {
 	result := Result{
 		Category: "font",
 		Module:   "terminal",
 		Name:     fontName,
 	}
 
-	// Normalize font name for nerd-fonts
-	nerdFontName := strings.ReplaceAll(fontName, " ", "")
-	nerdFontName = strings.ReplaceAll(nerdFontName, "Nerd Font", "")
-	nerdFontName = strings.ReplaceAll(nerdFontName, "NerdFont", "")
-	nerdFontName = strings.TrimSpace(nerdFontName)
+	nerdFontName := nerdFontBaseName(fontName)
 
 	// Check if font is already installed
 	if isFontInstalled(fontName) {
@@ -721,6 +737,18 @@ func installNerdFont(fontName string) Result {
 		return result
 	}
 
+	if runtime.GOOS == "darwin" && detectPackageManager() == "brew" {
+		if err := allowCommand(exec.Command("brew", "install", "--cask"), opts); err != nil {
+			return refused(result, err)
+		}
+	}
+	if opts.DryRun {
+		return planNerdFont(result, fontName)
+	}
+
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
 	switch runtime.GOOS {
 	case "darwin":
 		// Use Homebrew cask
@@ -728,20 +756,30 @@ func installNerdFont(fontName string) Result {
 		if pm == "brew" {
 			// Try the font cask name
 			caskName := "font-" + strings.ToLower(nerdFontName) + "-nerd-font"
-			cmd := exec.Command("brew", "install", "--cask", caskName)
-			output, err := cmd.CombinedOutput()
+			cmd := command(ctx, "brew", "install", "--cask", caskName)
+			output, err := runInstall(cmd, opts)
+			if _, blocked := err.(*LicenseError); blocked {
+				result.Error = err
+				return result
+			}
+			if err != nil && ctx.Err() != nil {
+				result.Error = stepError(ctx, err)
+				return result
+			}
 			if err != nil {
 				// Try alternative naming
 				caskName = "font-" + strings.ToLower(strings.ReplaceAll(nerdFontName, "Mono", "-mono")) + "-nerd-font"
-				cmd = exec.Command("brew", "install", "--cask", caskName)
-				output, err = cmd.CombinedOutput()
+				cmd = command(ctx, "brew", "install", "--cask", caskName)
+				output, err = runInstall(cmd, opts)
 				if err != nil {
-					result.Error = fmt.Errorf("failed to install font: %s", string(output))
+					result.Error = stepError(ctx, fmt.Errorf("failed to install font: %w", installError(err, output)))
 					return result
 				}
 			}
 			result.Success = true
 			result.Message = "installed via Homebrew"
+			result.Backend = "brew-cask"
+			result.Package = caskName
 			return result
 		}
 
@@ -751,16 +789,17 @@ func installNerdFont(fontName string) Result {
 		fontDir := filepath.Join(home, ".local/share/fonts")
 		os.MkdirAll(fontDir, 0755)
 
-		downloadURL := fmt.Sprintf("https://github.com/ryanoasis/nerd-fonts/releases/latest/download/%s.zip", nerdFontName)
-		tmpFile := filepath.Join(os.TempDir(), nerdFontName+".zip")
+		downloadURL := nerdFontURL(fontName)
+		tmpFile := filepath.Join(os.TempDir(), "pact-font-"+nerdFontName+".zip")
 
-		if err := downloadFile(downloadURL, tmpFile); err != nil {
-			result.Error = err
+		if err := downloadFile(ctx, downloadURL, tmpFile); err != nil {
+			result.Error = stepError(ctx, err)
 			return result
 		}
 		defer os.Remove(tmpFile)
 
-		if err := extractZip(tmpFile, fontDir, ""); err != nil {
+		before := listDir(fontDir)
+		if err := archive.ExtractZip(ctx, tmpFile, fontDir, nil); err != nil {
 			result.Error = err
 			return result
 		}
@@ -770,15 +809,17 @@ func installNerdFont(fontName string) Result {
 
 		result.Success = true
 		result.Message = "installed to ~/.local/share/fonts"
+		result.Backend = "files"
+		result.Paths = newFiles(fontDir, before)
 		return result
 
 	case "windows":
 		// Download and install to Windows fonts folder
-		downloadURL := fmt.Sprintf("https://github.com/ryanoasis/nerd-fonts/releases/latest/download/%s.zip", nerdFontName)
-		tmpFile := filepath.Join(os.TempDir(), nerdFontName+".zip")
+		downloadURL := nerdFontURL(fontName)
+		tmpFile := filepath.Join(os.TempDir(), "pact-font-"+nerdFontName+".zip")
 
-		if err := downloadFile(downloadURL, tmpFile); err != nil {
-			result.Error = err
+		if err := downloadFile(ctx, downloadURL, tmpFile); err != nil {
+			result.Error = stepError(ctx, err)
 			return result
 		}
 		defer os.Remove(tmpFile)
@@ -787,13 +828,16 @@ func installNerdFont(fontName string) Result {
 		fontDir := filepath.Join(home, "AppData/Local/Microsoft/Windows/Fonts")
 		os.MkdirAll(fontDir, 0755)
 
-		if err := extractZip(tmpFile, fontDir, ""); err != nil {
+		before := listDir(fontDir)
+		if err := archive.ExtractZip(ctx, tmpFile, fontDir, nil); err != nil {
 			result.Error = err
 			return result
 		}
 
 		result.Success = true
 		result.Message = "installed to Windows Fonts"
+		result.Backend = "files"
+		result.Paths = newFiles(fontDir, before)
 		return result
 	}
 
@@ -801,6 +845,38 @@ func installNerdFont(fontName string) Result {
 	return result
 }
 
+// planNerdFont reports how installNerdFont would install a font
+func planNerdFont(result Result, fontName string) Result {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "darwin":
+		if detectPackageManager() == "brew" {
+			cask := "font-" + strings.ToLower(nerdFontBaseName(fontName)) + "-nerd-font"
+			return planned(result, "run brew install --cask %s", cask)
+		}
+	case "linux":
+		return planned(result, "download %s, unzip into %s, and run fc-cache -fv", nerdFontURL(fontName), filepath.Join(home, ".local/share/fonts"))
+	case "windows":
+		return planned(result, "download %s and unzip into %s", nerdFontURL(fontName), filepath.Join(home, "AppData/Local/Microsoft/Windows/Fonts"))
+	}
+	result.Error = fmt.Errorf("font installation not supported on this OS")
+	return result
+}
+
+// nerdFontBaseName normalizes a font name to its nerd-fonts release name,
+// e.g. "JetBrainsMono Nerd Font" -> "JetBrainsMono"
+func nerdFontBaseName(fontName string) string {
+	name := strings.ReplaceAll(fontName, " ", "")
+	name = strings.ReplaceAll(name, "Nerd Font", "")
+	name = strings.ReplaceAll(name, "NerdFont", "")
+	return strings.TrimSpace(name)
+}
+
+// nerdFontURL returns the nerd-fonts release zip for a font
+func nerdFontURL(fontName string) string {
+	return fmt.Sprintf("https://github.com/ryanoasis/nerd-fonts/releases/latest/download/%s.zip", nerdFontBaseName(fontName))
+}
+
 func isFontInstalled(fontName string) bool {
 	switch runtime.GOOS {
 	case "darwin":
@@ -844,158 +920,183 @@ func isFontInstalled(fontName string) bool {
 // Apps
 // =============================================================================
 
-func applyApps(cfg *config.PactConfig) []Result {
+func applyApps(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
+	apps := cfg.Apps(runtime.GOOS)
 
-	currentOS := runtime.GOOS
-	appsKey := fmt.Sprintf("apps.%s", currentOS)
+	// Preinstalled apps to get rid of go before anything is installed
+	results = append(results, applyRemove(apps.Remove, opts)...)
 
-	// Get apps for current OS
-	appsMap := cfg.GetMap(appsKey)
-	if appsMap == nil {
-		return results
-	}
+	// Flatpak remotes come before the apps that may install from them
+	results = append(results, applyFlatpak(apps.Flatpak, opts)...)
+	results = append(results, ensureFlathub(apps.Install, opts)...)
 
-	// Check for install list
-	if installList, ok := appsMap["install"].([]any); ok {
-		for _, app := range installList {
-			if appName, ok := app.(string); ok {
-				result := installApp(appName)
-				results = append(results, result)
-			}
+	for _, app := range apps.Install {
+		if opts.LowBandwidth && !isAppInstalled(app) {
+			results = append(results, deferResult(Result{Category: "app", Module: "apps", Name: app.Name}))
+			continue
 		}
+		results = append(results, installApp(app, opts))
 	}
 
-	// Check for shortcuts (just note them, don't install)
-	if shortcuts, ok := appsMap["shortcuts"].(map[string]any); ok {
-		for name := range shortcuts {
-			results = append(results, Result{
-				Category: "app",
-				Module:   "apps",
-				Name:     name,
-				Success:  true,
-				Skipped:  true,
-				Message:  "shortcut configured",
-			})
-		}
+	// App Store apps
+	results = append(results, applyMas(apps.Mas, opts)...)
+
+	results = append(results, applyStartup(apps.Startup, opts)...)
+
+	// Shortcuts are only noted, not set
+	for _, shortcut := range apps.Shortcuts {
+		results = append(results, Result{
+			Category: "app",
+			Module:   "apps",
+			Name:     shortcut.App,
+			Success:  true,
+			Skipped:  true,
+			Message:  "shortcut configured (" + shortcut.Keys + ")",
+		})
 	}
 
 	return results
 }
 
-func installApp(appName string) Result {
+// installApp installs a GUI app with the manager appManager picks, under the
+// name the entry gives for it, else the name pact knows it by there
+func installApp(app config.Package, opts Options) Result {
 	result := Result{
 		Category: "app",
 		Module:   "apps",
-		Name:     appName,
+		Name:     app.Name,
 	}
 
-	pm := detectPackageManager()
-	if pm == "" {
+	m, pkgName := appManager(app, runtime.GOOS)
+	if m == nil {
 		result.Error = fmt.Errorf("no package manager available")
 		return result
 	}
 
-	// Map common app names to package names
-	pkgMap := map[string]map[string]string{
-		"brave": {
-			"brew":   "brave-browser",
-			"winget": "Brave.Brave",
-			"choco":  "brave",
-		},
-		"discord": {
-			"brew":   "discord",
-			"winget": "Discord.Discord",
-			"choco":  "discord",
-		},
-		"spotify": {
-			"brew":   "spotify",
-			"winget": "Spotify.Spotify",
-			"choco":  "spotify",
-		},
-		"steam": {
-			"brew":   "steam",
-			"winget": "Valve.Steam",
-			"choco":  "steam",
-		},
-		"cursor": {
-			"brew":   "cursor",
-			"winget": "Cursor.Cursor",
-		},
-		"vscode": {
-			"brew":   "visual-studio-code",
-			"winget": "Microsoft.VisualStudioCode",
-			"choco":  "vscode",
-		},
-		"slack": {
-			"brew":   "slack",
-			"winget": "SlackTechnologies.Slack",
-			"choco":  "slack",
-		},
-		"notion": {
-			"brew":   "notion",
-			"winget": "Notion.Notion",
-			"choco":  "notion",
-		},
-		"figma": {
-			"brew":   "figma",
-			"winget": "Figma.Figma",
-			"choco":  "figma",
-		},
-		"docker": {
-			"brew":   "docker",
-			"winget": "Docker.DockerDesktop",
-			"choco":  "docker-desktop",
-		},
-	}
-
-	// Get the package name for this package manager
-	pkgName := appName
-	if pkgs, ok := pkgMap[strings.ToLower(appName)]; ok {
-		if pkg, ok := pkgs[pm]; ok {
-			pkgName = pkg
+	// Flatpaks and snaps aren't commands on PATH, so ask their manager
+	switch m.(type) {
+	case pkg.Flatpak, pkg.Snap:
+		if !isToolInstalled(m.Name()) {
+			result.Error = fmt.Errorf("%s isn't installed; install it with your package manager", m.Name())
+			return result
+		}
+		query := m.Query(pkgName)
+		if exec.Command(query[0], query[1:]...).Run() == nil {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already installed"
+			return result
+		}
+	default:
+		if isAppInstalled(app) {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already installed"
+			return result
 		}
 	}
 
-	// Check if already installed (simplified check)
-	if isToolInstalled(strings.ToLower(appName)) {
-		result.Success = true
-		result.Skipped = true
-		result.Message = "already installed"
-		return result
-	}
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
 
-	var cmd *exec.Cmd
-	switch pm {
-	case "brew":
-		cmd = exec.Command("brew", "install", "--cask", pkgName)
-	case "winget":
-		cmd = exec.Command("winget", "install", "--id", pkgName, "-e", "--silent", "--accept-package-agreements", "--accept-source-agreements")
-	case "choco":
-		cmd = exec.Command("choco", "install", pkgName, "-y")
-	case "scoop":
-		cmd = exec.Command("scoop", "install", pkgName)
-	default:
-		result.Error = fmt.Errorf("app installation not supported for %s", pm)
+	// Linux system package managers install apps like any other package
+	args := m.Install(pkgName)
+	if am, ok := m.(pkg.AppManager); ok {
+		args = am.InstallApp(pkgName)
+	} else if runtime.GOOS != "linux" {
+		result.Error = fmt.Errorf("app installation not supported for %s", m.Name())
 		return result
 	}
+	cmd := command(ctx, args[0], args[1:]...)
+	userScopeArgs(cmd, opts)
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
 
-	output, err := cmd.CombinedOutput()
+	output, err := runInstall(cmd, opts)
 	if err != nil {
-		result.Error = fmt.Errorf("%v: %s", err, string(output))
+		result.Error = stepError(ctx, installError(err, output))
 		return result
 	}
 
 	result.Success = true
 	result.Message = "installed"
+	result.Backend = m.Name()
+	if m.Name() == "brew" {
+		result.Backend = "brew-cask"
+	}
+	result.Package = pkgName
 	return result
 }
 
+// isAppInstalled reports whether an app is installed: by asking the manager
+// it installs with, else by finding it in /Applications on macOS or on PATH,
+// since most GUI apps are never on PATH
+func isAppInstalled(app config.Package) bool {
+	if m, name := appManager(app, runtime.GOOS); m != nil {
+		query := m.Query(name)
+		if am, ok := m.(pkg.AppManager); ok {
+			query = am.QueryApp(name)
+		}
+		if len(query) > 0 && isToolInstalled(query[0]) && exec.Command(query[0], query[1:]...).Run() == nil {
+			return true
+		}
+	}
+	if runtime.GOOS == "darwin" {
+		if _, err := os.Stat(filepath.Join("/Applications", app.Name+".app")); err == nil {
+			return true
+		}
+	}
+	return isToolInstalled(strings.ToLower(app.Name))
+}
+
+// appManager picks the manager an app installs with, and its name there. On
+// Linux an entry naming a Flatpak app ID, or a snap, installs with Flatpak or
+// Snap; one naming a package for the system manager installs with that.
+// Otherwise an app pact knows the Flatpak ID of installs with Flatpak where
+// it's set up, then anything else with Snap where that's set up, before
+// falling back to the system manager.
+func appManager(app config.Package, goos string) (pkg.Manager, string) {
+	m := pkg.Detect(goos)
+	if goos == "linux" {
+		if id, ok := app.For("flatpak"); ok || pkg.IsFlatpakID(id) {
+			return pkg.Flatpak{}, id
+		}
+		if name, ok := app.For("snap"); ok {
+			return pkg.Snap{}, name
+		}
+		explicit := false
+		if m != nil {
+			_, explicit = app.Names[m.Name()]
+		}
+		if !explicit {
+			if id := pkg.AppName(app.Name, "flatpak"); id != app.Name && isToolInstalled("flatpak") {
+				return pkg.Flatpak{}, id
+			}
+			if isToolInstalled("snap") {
+				return pkg.Snap{}, pkg.AppName(strings.ToLower(app.Name), "snap")
+			}
+		}
+	}
+	if m == nil {
+		return nil, ""
+	}
+	name, ok := app.For(m.Name())
+	if !ok {
+		name = pkg.AppName(app.Name, m.Name())
+	}
+	return m, name
+}
+
 // =============================================================================
 // LLM
 // =============================================================================
 
-func applyLLM(cfg *config.PactConfig) []Result {
+func applyLLM(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
 	// Install local runtime
@@ -1003,7 +1104,8 @@ func applyLLM(cfg *config.PactConfig) []Result {
 	if localRuntime != "" {
 		pm := detectPackageManager()
 		if pm != "" {
-			result := installTool(pm, localRuntime)
+			result := installTool(pm, localRuntime, opts)
+			result.Module = "llm"
 			results = append(results, result)
 		}
 
@@ -1011,6 +1113,11 @@ func applyLLM(cfg *config.PactConfig) []Result {
 		models := cfg.GetStringSlice("llm.local.models")
 		for _, model := range models {
 			result := pullOllamaModel(localRuntime, model)
+			// Models are large; a model that still needs pulling waits for
+			// an unmetered sync
+			if opts.LowBandwidth && result.Success && result.Message != "already pulled" {
+				result = deferResult(result)
+			}
 			results = append(results, result)
 		}
 	}
@@ -1060,7 +1167,7 @@ func pullOllamaModel(runtime, model string) Result {
 // Files
 // =============================================================================
 
-func applyFiles(cfg *config.PactConfig) []Result {
+func applyFiles(cfg *config.PactConfig, opts Options) []Result {
 	var results []Result
 
 	items, err := cfg.GetSyncItems()
@@ -1069,14 +1176,14 @@ func applyFiles(cfg *config.PactConfig) []Result {
 	}
 
 	for _, item := range items {
-		result := syncFile(item)
+		result := syncFile(item, opts)
 		results = append(results, result)
 	}
 
 	return results
 }
 
-func applyModuleFiles(cfg *config.PactConfig, module string) []Result {
+func applyModuleFiles(cfg *config.PactConfig, module string, opts Options) []Result {
 	var results []Result
 
 	items, err := cfg.GetSyncItemsForModule(module)
@@ -1085,14 +1192,14 @@ func applyModuleFiles(cfg *config.PactConfig, module string) []Result {
 	}
 
 	for _, item := range items {
-		result := syncFile(item)
+		result := syncFile(item, opts)
 		results = append(results, result)
 	}
 
 	return results
 }
 
-func syncFile(item config.SyncItem) Result {
+func syncFile(item config.SyncItem, opts Options) Result {
 	result := Result{
 		Category: "file",
 		Module:   item.Module,
@@ -1109,156 +1216,278 @@ func syncFile(item config.SyncItem) Result {
 		strategy = "symlink"
 	}
 
+	if err := allowPath(item.Target, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	if opts.DryRun {
+		return planSyncFile(result, item, strategy, opts)
+	}
+	if err := opts.Undo.File(item.Target); err != nil {
+		result.Error = err
+		return result
+	}
+
+	if strategy == psync.StrategyMergeTOML {
+		changed, err := psync.MergeTOMLFile(item.Source, item.Target)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		result.Success = true
+		if !changed {
+			result.Skipped = true
+			result.Message = "already merged"
+		} else {
+			result.Message = fmt.Sprintf("merged pact-managed tables from %s", item.Source)
+		}
+		return result
+	}
+
 	targetDir := filepath.Dir(item.Target)
 	os.MkdirAll(targetDir, 0755)
 
+	note := ""
+	if needsBackup(item) {
+		c := newConflict(item)
+		choice := resolveConflict(c, opts)
+		if choice == ConflictMerge && !c.Text() {
+			choice = ConflictBackup
+			note = "; can't merge a directory or binary file"
+		}
+		switch choice {
+		case ConflictKeep:
+			result.Success = true
+			result.Skipped = true
+			result.Kept = true
+			result.Message = "kept the file here; it differs from pact"
+			return result
+		case ConflictMerge:
+			return mergeFile(result, c, strategy, opts)
+		case ConflictOverwrite:
+			note += "; replaced the file here"
+		default:
+			path, err := backup.Save(item.Target)
+			if err != nil {
+				result.Error = err
+				return result
+			}
+			note += fmt.Sprintf("; backed up the old one as %s (pact restore-backup)", filepath.Base(filepath.Dir(path)))
+		}
+	}
+
+	msg, err := placeFile(item, strategy)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = msg + note
+	return result
+}
+
+// placeFile replaces the target with a symlink to or copy of the source
+func placeFile(item config.SyncItem, strategy string) (string, error) {
 	os.RemoveAll(item.Target)
 
+	var msg string
 	switch strategy {
 	case "symlink":
 		if err := os.Symlink(item.Source, item.Target); err != nil {
-			result.Error = err
-			return result
+			return "", err
 		}
-		result.Message = fmt.Sprintf("symlinked -> %s", item.Source)
+		msg = fmt.Sprintf("symlinked -> %s", item.Source)
 	case "copy":
 		cmd := exec.Command("cp", "-r", item.Source, item.Target)
 		if err := cmd.Run(); err != nil {
-			result.Error = err
+			return "", err
+		}
+		msg = fmt.Sprintf("copied from %s", item.Source)
+	default:
+		return "", fmt.Errorf("unknown strategy: %s", strategy)
+	}
+	recordBase(item)
+	return msg, nil
+}
+
+// needsBackup reports whether replacing a target would lose something: it
+// exists, isn't a symlink, and isn't already an exact copy of the source
+func needsBackup(item config.SyncItem) bool {
+	info, err := os.Lstat(item.Target)
+	if err != nil || info.Mode()&os.ModeSymlink != 0 {
+		return false
+	}
+	if !info.IsDir() {
+		target, err1 := os.ReadFile(item.Target)
+		source, err2 := os.ReadFile(item.Source)
+		if err1 == nil && err2 == nil && bytes.Equal(target, source) {
+			return false
+		}
+	}
+	return true
+}
+
+// planSyncFile reports the change syncFile would make to a target
+func planSyncFile(result Result, item config.SyncItem, strategy string, opts Options) Result {
+	var placed string
+	switch strategy {
+	case "symlink":
+		if link, err := os.Readlink(item.Target); err == nil && link == item.Source {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already symlinked"
 			return result
 		}
-		result.Message = fmt.Sprintf("copied from %s", item.Source)
+		placed = "a symlink -> " + item.Source
+	case "copy":
+		placed = "a copy of " + item.Source
+	case psync.StrategyMergeTOML:
+		return planned(result, "merge pact-managed tables from %s into %s", item.Source, item.Target)
 	default:
 		result.Error = fmt.Errorf("unknown strategy: %s", strategy)
 		return result
 	}
 
-	result.Success = true
-	return result
+	if needsBackup(item) {
+		switch opts.OnConflict {
+		case ConflictKeep:
+			result.Success = true
+			result.Skipped = true
+			result.Kept = true
+			result.Message = "would keep the file here; it differs from pact"
+			return result
+		case ConflictMerge:
+			return planned(result, "merge the changes in %s into %s and replace it with %s", item.Target, item.Source, placed)
+		case ConflictOverwrite:
+		case "":
+			if opts.ResolveConflict != nil {
+				return planned(result, "ask whether to keep %s, which differs from pact, or replace it with %s", item.Target, placed)
+			}
+			fallthrough
+		default:
+			return planned(result, "back up %s and replace it with %s", item.Target, placed)
+		}
+	}
+	return planned(result, "replace %s with %s", item.Target, placed)
 }
 
 // =============================================================================
 // Helpers
 // =============================================================================
 
+// detectPackageManager returns the name of the package manager pact
+// installs with on this OS, or ""
 func detectPackageManager() string {
-	switch runtime.GOOS {
-	case "darwin":
-		if _, err := exec.LookPath("brew"); err == nil {
-			return "brew"
-		}
-	case "linux":
-		if _, err := exec.LookPath("apt"); err == nil {
-			return "apt"
-		}
-		if _, err := exec.LookPath("dnf"); err == nil {
-			return "dnf"
-		}
-		if _, err := exec.LookPath("pacman"); err == nil {
-			return "pacman"
-		}
-		if _, err := exec.LookPath("brew"); err == nil {
-			return "brew"
-		}
-	case "windows":
-		if _, err := exec.LookPath("winget"); err == nil {
-			return "winget"
-		}
-		if _, err := exec.LookPath("scoop"); err == nil {
-			return "scoop"
-		}
-		if _, err := exec.LookPath("choco"); err == nil {
-			return "choco"
-		}
+	if m := pkg.Detect(runtime.GOOS); m != nil {
+		return m.Name()
 	}
 	return ""
 }
 
+// PackageManagers returns every supported package manager on PATH, in the
+// order pact prefers them
+func PackageManagers() []string {
+	var found []string
+	for _, m := range pkg.Available() {
+		found = append(found, m.Name())
+	}
+	return found
+}
+
 func isToolInstalled(tool string) bool {
 	_, err := exec.LookPath(tool)
 	return err == nil
 }
 
-func installTool(pm, tool string) Result {
+func installTool(pm, tool string, opts Options) Result {
+	return installSystemPackage(pm, config.Package{Name: tool}, opts)
+}
+
+// installSystemPackage installs a cli.tools entry with pm, under the name
+// the entry gives for pm
+func installSystemPackage(pm string, p config.Package, opts Options) Result {
 	result := Result{
 		Category: "install",
 		Module:   "cli",
-		Name:     tool,
+		Name:     p.Name,
 	}
 
-	if isToolInstalled(tool) {
+	if isToolInstalled(p.Name) {
 		result.Success = true
 		result.Skipped = true
 		result.Message = "already installed"
 		return result
 	}
 
-	var cmd *exec.Cmd
-	switch pm {
-	case "brew":
-		cmd = exec.Command("brew", "install", tool)
-	case "apt":
-		cmd = exec.Command("sudo", "apt", "install", "-y", tool)
-	case "dnf":
-		cmd = exec.Command("sudo", "dnf", "install", "-y", tool)
-	case "pacman":
-		cmd = exec.Command("sudo", "pacman", "-S", "--noconfirm", tool)
-	case "winget":
-		cmd = exec.Command("winget", "install", "--id", tool, "-e", "--silent")
-	case "scoop":
-		cmd = exec.Command("scoop", "install", tool)
-	case "choco":
-		cmd = exec.Command("choco", "install", tool, "-y")
-	default:
+	m := pkg.Get(pm)
+	if m == nil {
 		result.Error = fmt.Errorf("unsupported package manager: %s", pm)
 		return result
 	}
+	pkgName, _ := p.For(pm)
 
-	output, err := cmd.CombinedOutput()
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
+	args := m.Install(pkgName)
+	cmd := command(ctx, args[0], args[1:]...)
+	userScopeArgs(cmd, opts)
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+
+	output, err := runInstall(cmd, opts)
 	if err != nil {
-		result.Error = fmt.Errorf("%v: %s", err, string(output))
+		result.Error = stepError(ctx, installError(err, output))
 		return result
 	}
 
 	result.Success = true
 	result.Message = "installed"
+	result.Backend = pm
+	result.Package = pkgName
 	return result
 }
 
-func runGitConfig(key, value string) error {
-	return exec.Command("git", "config", "--global", key, value).Run()
+// setGitConfig sets a global git config value
+func setGitConfig(key, value string, opts Options) Result {
+	result := Result{
+		Category: "configure",
+		Module:   "git",
+		Name:     key,
+	}
+
+	cmd := exec.CommandContext(opts.ctx(), "git", "config", "--global", key, value)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	opts.Undo.GitConfig(key)
+	if err := cmd.Run(); err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = value
+	return result
 }
 
-func downloadPromptTheme(promptTool, themeName, source string) Result {
+func downloadPromptTheme(promptTool, themeName, source string, opts Options) Result {
 	result := Result{
 		Category: "configure",
 		Module:   "shell",
 		Name:     fmt.Sprintf("%s-theme", promptTool),
 	}
 
-	var themeDir string
-	home, _ := os.UserHomeDir()
-
-	switch promptTool {
-	case "oh-my-posh":
-		switch runtime.GOOS {
-		case "darwin", "linux":
-			themeDir = filepath.Join(home, ".config/oh-my-posh/themes")
-		case "windows":
-			themeDir = filepath.Join(home, "AppData/Local/Programs/oh-my-posh/themes")
-		}
-	case "starship":
-		themeDir = filepath.Join(home, ".config")
-	default:
+	themePath := promptThemePath(promptTool, themeName)
+	if themePath == "" {
 		result.Skipped = true
 		result.Message = "unknown prompt tool"
 		return result
 	}
 
-	os.MkdirAll(themeDir, 0755)
-
-	themePath := filepath.Join(themeDir, themeName+".omp.json")
-
 	if _, err := os.Stat(themePath); err == nil {
 		result.Success = true
 		result.Skipped = true
@@ -1266,19 +1495,62 @@ func downloadPromptTheme(promptTool, themeName, source string) Result {
 		return result
 	}
 
-	cmd := exec.Command("curl", "-sSL", "-o", themePath, source)
+	if err := allowPath(themePath, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Download)
+	defer cancel()
+	cmd := command(ctx, "curl", "-sSL", "-o", themePath, source)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	os.MkdirAll(filepath.Dir(themePath), 0755)
+
 	if output, err := cmd.CombinedOutput(); err != nil {
-		result.Error = fmt.Errorf("failed to download theme: %v: %s", err, string(output))
+		os.Remove(themePath)
+		result.Error = stepError(ctx, fmt.Errorf("failed to download theme: %v: %s", err, string(output)))
 		return result
 	}
 
 	result.Success = true
 	result.Message = fmt.Sprintf("downloaded to %s", themePath)
+	result.Backend = "files"
+	result.Paths = []string{themePath}
 	return result
 }
 
-func downloadFile(url, dest string) error {
-	resp, err := http.Get(url)
+// promptThemePath returns where a prompt theme is downloaded to, or "" for
+// unknown prompt tools
+func promptThemePath(promptTool, themeName string) string {
+	var themeDir string
+	home, _ := os.UserHomeDir()
+
+	switch promptTool {
+	case "oh-my-posh":
+		switch runtime.GOOS {
+		case "darwin", "linux":
+			themeDir = filepath.Join(home, ".config/oh-my-posh/themes")
+		case "windows":
+			themeDir = filepath.Join(home, "AppData/Local/Programs/oh-my-posh/themes")
+		}
+	case "starship":
+		themeDir = filepath.Join(home, ".config")
+	default:
+		return ""
+	}
+
+	return filepath.Join(themeDir, themeName+".omp.json")
+}
+
+// downloadFile downloads url to dest, removing a partial file when the
+// download fails or ctx ends
+func downloadFile(ctx context.Context, url, dest string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -1292,22 +1564,16 @@ func downloadFile(url, dest string) error {
 	if err != nil {
 		return err
 	}
-	defer out.Close()
-
 	_, err = io.Copy(out, resp.Body)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dest)
+	}
 	return err
 }
 
-func extractTarGz(src, destDir, binaryName string) error {
-	cmd := exec.Command("tar", "-xzf", src, "-C", destDir)
-	return cmd.Run()
-}
-
-func extractZip(src, destDir, binaryName string) error {
-	cmd := exec.Command("unzip", "-o", src, "-d", destDir)
-	return cmd.Run()
-}
-
 func copyFile(src, dst string) error {
 	input, err := os.ReadFile(src)
 	if err != nil {
@@ -1315,3 +1581,25 @@ func copyFile(src, dst string) error {
 	}
 	return os.WriteFile(dst, input, 0755)
 }
+
+// listDir returns the names of the entries in dir
+func listDir(dir string) map[string]bool {
+	names := make(map[string]bool)
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		names[e.Name()] = true
+	}
+	return names
+}
+
+// newFiles returns full paths of entries in dir that weren't in before
+func newFiles(dir string, before map[string]bool) []string {
+	var paths []string
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if !before[e.Name()] {
+			paths = append(paths, filepath.Join(dir, e.Name()))
+		}
+	}
+	return paths
+}
@@ -0,0 +1,94 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Bootstrap is a step that sets up a package manager on a machine that has
+// none, so the installs after it don't all fail
+type Bootstrap struct {
+	Name    string   // Package manager being set up
+	Reason  string   // Why it's needed
+	Command []string // Command that sets it up, run on the user's terminal
+	System  bool     // Needs admin rights or writes outside the home dir
+	Script  bool     // Runs a downloaded install script
+
+	paths []string // Where it installs, added to PATH for the rest of the run
+}
+
+// PackageManagerBootstrap returns the setup step this machine needs, or nil
+// when a usable package manager is already there
+func PackageManagerBootstrap() *Bootstrap {
+	home, _ := os.UserHomeDir()
+
+	switch runtime.GOOS {
+	case "darwin":
+		if detectPackageManager() != "" {
+			return nil
+		}
+		return &Bootstrap{
+			Name:    "Homebrew",
+			Reason:  "no package manager found",
+			Command: []string{"/bin/bash", "-c", `$(curl -fsSL https://raw.githubusercontent.com/Homebrew/install/HEAD/install.sh)`},
+			System:  true,
+			Script:  true,
+			paths:   []string{"/opt/homebrew/bin", "/usr/local/bin"},
+		}
+	case "windows":
+		switch detectPackageManager() {
+		case "":
+			return &Bootstrap{
+				Name:    "scoop",
+				Reason:  "no package manager found",
+				Command: []string{"powershell", "-NoProfile", "-ExecutionPolicy", "RemoteSigned", "-Command", "irm get.scoop.sh | iex"},
+				Script:  true,
+				paths:   []string{filepath.Join(home, "scoop", "shims")},
+			}
+		case "winget":
+			if !wingetHasSources() {
+				return &Bootstrap{
+					Name:    "winget sources",
+					Reason:  "winget has no package sources",
+					Command: []string{"winget", "source", "reset", "--force"},
+					System:  true,
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// wingetHasSources reports whether winget's default source is configured
+func wingetHasSources() bool {
+	output, err := exec.Command("winget", "source", "list").CombinedOutput()
+	return err == nil && strings.Contains(strings.ToLower(string(output)), "winget")
+}
+
+// Run performs the setup on the user's terminal and makes the package
+// manager available to the rest of this run
+func (b *Bootstrap) Run() error {
+	if err := runAttached(exec.Command(b.Command[0], b.Command[1:]...)); err != nil {
+		return fmt.Errorf("setting up %s failed: %w", b.Name, err)
+	}
+
+	for _, p := range b.paths {
+		if _, err := os.Stat(p); err == nil {
+			os.Setenv("PATH", p+string(os.PathListSeparator)+os.Getenv("PATH"))
+		}
+	}
+
+	if detectPackageManager() == "" {
+		return fmt.Errorf("%s was set up but isn't on PATH; open a new terminal and sync again", b.Name)
+	}
+	return nil
+}
+
+// CommandLine renders the setup command for display
+func (b *Bootstrap) CommandLine() string {
+	return commandLine(exec.Command(b.Command[0], b.Command[1:]...))
+}
@@ -0,0 +1,122 @@
+package apply
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
+)
+
+// applyTaps adds the Homebrew taps in cli.taps, such as "oven-sh/bun", so
+// cli.tools and cli.casks can install from them
+func applyTaps(cfg *config.PactConfig, opts Options) []Result {
+	taps := cfg.GetStringSlice("cli.taps")
+	if len(taps) == 0 {
+		return nil
+	}
+	if !isToolInstalled("brew") {
+		return []Result{{Category: "install", Module: "cli", Name: "taps", Error: fmt.Errorf("cli.taps needs Homebrew")}}
+	}
+
+	tapped := brewList("tap")
+	var results []Result
+	for _, tap := range taps {
+		result := Result{Category: "install", Module: "cli", Name: tap, Package: tap}
+		if tapped[strings.ToLower(tap)] {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already tapped"
+			results = append(results, result)
+			continue
+		}
+
+		cmd := command(opts.ctx(), "brew", "tap", tap)
+		if err := allowCommand(cmd, opts); err != nil {
+			results = append(results, refused(result, err))
+			continue
+		}
+		if opts.DryRun {
+			results = append(results, planned(result, "run %s", commandLine(cmd)))
+			continue
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("brew tap failed: %s", strings.TrimSpace(string(output))))
+			results = append(results, result)
+			continue
+		}
+		result.Success = true
+		result.Message = "tapped"
+		result.Backend = "brew-tap"
+		results = append(results, result)
+	}
+	return results
+}
+
+// applyCasks installs the Homebrew casks in cli.casks, GUI tools that
+// 'brew install' would look for among formulae
+func applyCasks(cfg *config.PactConfig, opts Options) []Result {
+	casks := cfg.GetStringSlice("cli.casks")
+	if len(casks) == 0 {
+		return nil
+	}
+	if runtime.GOOS != "darwin" {
+		var results []Result
+		for _, cask := range casks {
+			results = append(results, Result{Category: "install", Module: "cli", Name: cask, Success: true, Skipped: true, Message: "casks are macOS only"})
+		}
+		return results
+	}
+	if !isToolInstalled("brew") {
+		return []Result{{Category: "install", Module: "cli", Name: "casks", Error: fmt.Errorf("cli.casks needs Homebrew")}}
+	}
+
+	installed := brewList("list", "--cask")
+	return installAll(casks, opts.Jobs, func(cask string) Result {
+		result := Result{Category: "install", Module: "cli", Name: cask, Package: cask}
+		if installed[strings.ToLower(cask)] {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already installed"
+			return result
+		}
+
+		ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+		defer cancel()
+
+		args := pkg.Brew{}.InstallApp(cask)
+		cmd := command(ctx, args[0], args[1:]...)
+		userScopeArgs(cmd, opts)
+		if err := allowCommand(cmd, opts); err != nil {
+			return refused(result, err)
+		}
+		if opts.DryRun {
+			return planned(result, "run %s", commandLine(cmd))
+		}
+		output, err := runInstall(cmd, opts)
+		if err != nil {
+			result.Error = stepError(ctx, installError(err, output))
+			return result
+		}
+		result.Success = true
+		result.Message = "installed"
+		result.Backend = "brew-cask"
+		return result
+	})
+}
+
+// brewList returns the lowercased lines of a brew listing such as
+// 'brew tap' or 'brew list --cask'
+func brewList(args ...string) map[string]bool {
+	items := make(map[string]bool)
+	output, err := exec.Command("brew", args...).Output()
+	if err != nil {
+		return items
+	}
+	for _, line := range strings.Fields(string(output)) {
+		items[strings.ToLower(line)] = true
+	}
+	return items
+}
@@ -0,0 +1,203 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// Artifact is something pact left on disk that it no longer references
+type Artifact struct {
+	Kind   string // "theme", "backup", "journal", "old-binary", "download"
+	Path   string
+	Size   int64
+	Reason string
+
+	module, name string // Journal entry the artifact belongs to, if any
+}
+
+// tempDownloads are the names of the temp files and directories pact's
+// installs download and extract into
+var tempDownloads = []string{
+	"pact-*-download", "pact-extract-*", "pact-install-script*", "pact-update*", "pact-flatpak-*", "pact-font-*.zip",
+}
+
+// tempDownloadAge is how old a temp download must be to be left over
+const tempDownloadAge = time.Hour
+
+// FindArtifacts finds stale theme files, backups older than retention,
+// journal entries whose files are gone, leftover pact.exe.old binaries, and
+// temp downloads
+func FindArtifacts(cfg *config.PactConfig, retention time.Duration) ([]Artifact, error) {
+	var artifacts []Artifact
+
+	journal, err := state.LoadJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	currentTheme := promptThemePath(cfg.GetString("shell.prompt.tool"), cfg.GetString("shell.prompt.theme"))
+	for _, e := range journal.Entries {
+		if len(e.Paths) > 0 && !anyExists(e.Paths) {
+			artifacts = append(artifacts, Artifact{
+				Kind:   "journal",
+				Path:   e.Key(),
+				Reason: "installed files no longer exist",
+				module: e.Module,
+				name:   e.Name,
+			})
+			continue
+		}
+
+		if e.Module == "shell" && strings.HasSuffix(e.Name, "-theme") {
+			for _, p := range e.Paths {
+				if p == currentTheme {
+					continue
+				}
+				if info, err := os.Stat(p); err == nil {
+					artifacts = append(artifacts, Artifact{
+						Kind:   "theme",
+						Path:   p,
+						Size:   info.Size(),
+						Reason: "theme no longer in pact.json",
+						module: e.Module,
+						name:   e.Name,
+					})
+				}
+			}
+		}
+	}
+
+	if backupsDir, err := state.BackupsDir(); err == nil {
+		entries, _ := os.ReadDir(backupsDir)
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || time.Since(info.ModTime()) < retention {
+				continue
+			}
+			p := filepath.Join(backupsDir, entry.Name())
+			artifacts = append(artifacts, Artifact{
+				Kind:   "backup",
+				Path:   p,
+				Size:   pathSize(p),
+				Reason: fmt.Sprintf("older than %s", formatRetention(retention)),
+			})
+		}
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		old := exe + ".old"
+		if info, err := os.Stat(old); err == nil {
+			artifacts = append(artifacts, Artifact{
+				Kind:   "old-binary",
+				Path:   old,
+				Size:   info.Size(),
+				Reason: "left behind by pact update",
+			})
+		}
+	}
+
+	var matches []string
+	for _, pattern := range tempDownloads {
+		found, _ := filepath.Glob(filepath.Join(os.TempDir(), pattern))
+		matches = append(matches, found...)
+	}
+	for _, p := range matches {
+		// A sync running now may still be using it
+		if info, err := os.Lstat(p); err != nil || time.Since(info.ModTime()) < tempDownloadAge {
+			continue
+		}
+		artifacts = append(artifacts, Artifact{
+			Kind:   "download",
+			Path:   p,
+			Size:   pathSize(p),
+			Reason: "leftover temp download",
+		})
+	}
+
+	return artifacts, nil
+}
+
+// RemoveArtifacts deletes the given artifacts, updating the install journal
+// for themes and orphaned entries. Returns the number of bytes freed.
+func RemoveArtifacts(artifacts []Artifact) (int64, []error) {
+	var freed int64
+	var errs []error
+
+	journal, err := state.LoadJournal()
+	if err != nil {
+		return 0, []error{err}
+	}
+
+	for _, a := range artifacts {
+		switch a.Kind {
+		case "journal":
+			journal.Remove(a.module, a.name)
+		case "theme":
+			if err := os.Remove(a.Path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			for i, e := range journal.Entries {
+				if e.Module == a.module && e.Name == a.name {
+					journal.Entries[i].Paths = removePath(e.Paths, a.Path)
+				}
+			}
+			freed += a.Size
+		default:
+			if err := os.RemoveAll(a.Path); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			freed += a.Size
+		}
+	}
+
+	if err := journal.Save(); err != nil {
+		errs = append(errs, err)
+	}
+	return freed, errs
+}
+
+func anyExists(paths []string) bool {
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func removePath(paths []string, p string) []string {
+	var kept []string
+	for _, existing := range paths {
+		if existing != p {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
+
+// pathSize returns the size of a file, or the total size of a directory
+func pathSize(p string) int64 {
+	var size int64
+	filepath.Walk(p, func(_ string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+func formatRetention(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", int(d/(24*time.Hour)))
+	}
+	return d.String()
+}
@@ -0,0 +1,47 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestFindArtifactsTempDownloads(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("HOME", t.TempDir())
+	// The pact dir is found from the working directory; keep it out of
+	// the checkout
+	wd, _ := os.Getwd()
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"pact-font-Hack.zip", "pact-merge-123.json", "pact-extract-rg-1", "pact-rg-download"} {
+		p := filepath.Join(tmp, name)
+		os.WriteFile(p, nil, 0644)
+		if name != "pact-rg-download" {
+			os.Chtimes(p, old, old)
+		}
+	}
+
+	cfg, _ := config.Parse([]byte(`{}`))
+	artifacts, err := FindArtifacts(cfg, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found []string
+	for _, a := range artifacts {
+		if a.Kind == "download" {
+			found = append(found, filepath.Base(a.Path))
+		}
+	}
+	if len(found) != 2 || found[0] != "pact-extract-rg-1" || found[1] != "pact-font-Hack.zip" {
+		t.Fatalf("temp downloads = %v, want the old font zip and extract dir", found)
+	}
+}
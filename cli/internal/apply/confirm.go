@@ -0,0 +1,38 @@
+package apply
+
+import "github.com/cloudboy-jh/pact/internal/config"
+
+// settingsModules change OS settings rather than installing things
+var settingsModules = map[string]bool{
+	"sound":    true,
+	"machine":  true,
+	"power":    true,
+	"printers": true,
+	"mounts":   true,
+}
+
+// NeedsConfirm reports whether sync should show what a module would change
+// and ask before applying it. "confirm" on the module decides; by default
+// modules that overwrite files or change OS settings ask:
+//
+//	"shell": {"confirm": false, "files": {...}}
+func NeedsConfirm(cfg *config.PactConfig, module string) bool {
+	if confirm, ok := cfg.Get(module + ".confirm").(bool); ok {
+		return confirm
+	}
+	return settingsModules[module] || module == "keybindings" || module == "snippets" || cfg.CountModuleFiles(module) > 0 ||
+		(module == "editor" && writesEditorFiles(cfg))
+}
+
+// writesEditorFiles reports whether the editor module writes settings,
+// keybindings, or snippets, not just installing things
+func writesEditorFiles(cfg *config.PactConfig) bool {
+	for _, editor := range settingsEditors {
+		for _, key := range []string{"settings", "keybindings", "snippets"} {
+			if cfg.HasKey("editor." + editor + "." + key) {
+				return true
+			}
+		}
+	}
+	return false
+}
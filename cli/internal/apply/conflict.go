@@ -0,0 +1,149 @@
+package apply
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+
+	"github.com/cloudboy-jh/pact/internal/backup"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
+	psync "github.com/cloudboy-jh/pact/internal/sync"
+)
+
+// What to do with a target that differs from its pact source, set with
+// --on-conflict or settings.onConflict
+const (
+	ConflictKeep      = "keep"      // Leave the file here as it is
+	ConflictOverwrite = "overwrite" // Replace it without a backup
+	ConflictMerge     = "merge"     // Merge both sides' changes into the pact repo
+	ConflictBackup    = "backup"    // Back it up, then replace it
+)
+
+// ConflictChoices are the valid OnConflict values
+var ConflictChoices = []string{ConflictKeep, ConflictOverwrite, ConflictMerge, ConflictBackup}
+
+// maxMergeSize is the largest file read for merging or as a merge base
+const maxMergeSize = 1 << 20
+
+// Conflict is a target that differs from its pact source. Base, Local, and
+// Pact are only set for text files: the content pact last placed (nil if
+// it wasn't recorded), the file here, and the pact source.
+type Conflict struct {
+	Item  config.SyncItem
+	Base  []byte
+	Local []byte
+	Pact  []byte
+}
+
+func newConflict(item config.SyncItem) Conflict {
+	c := Conflict{Item: item}
+	local, err1 := readText(item.Target)
+	pact, err2 := readText(item.Source)
+	if err1 == nil && err2 == nil {
+		c.Local, c.Pact = local, pact
+		c.Base = state.LoadBase(item.Module, item.Name)
+	}
+	return c
+}
+
+// Text reports whether both sides are text files that can be merged
+func (c Conflict) Text() bool {
+	return c.Local != nil && c.Pact != nil
+}
+
+// Merge combines both sides' changes, three-way when the base is known, and
+// returns the result with how many changes conflict
+func (c Conflict) Merge() (string, int) {
+	if c.Base == nil {
+		return psync.Merge2(string(c.Local), string(c.Pact))
+	}
+	return psync.Merge3(string(c.Base), string(c.Local), string(c.Pact))
+}
+
+// resolveConflict picks what to do with a conflict: the configured choice,
+// else the user's, else a backup
+func resolveConflict(c Conflict, opts Options) string {
+	if opts.OnConflict != "" {
+		return opts.OnConflict
+	}
+	if opts.ResolveConflict != nil {
+		return opts.ResolveConflict(c)
+	}
+	return ConflictBackup
+}
+
+// mergeFile merges the changes made here into the pact source, backs up the
+// file here, and places the merged source
+func mergeFile(result Result, c Conflict, strategy string, opts Options) Result {
+	item := c.Item
+	merged, conflicts := c.Merge()
+	if conflicts > 0 {
+		if opts.EditMerge == nil {
+			result.Error = fmt.Errorf("%d change(s) here conflict with pact; run 'pact sync %s' in a terminal to resolve them, or use --on-conflict keep, overwrite, or backup", conflicts, item.Module)
+			return result
+		}
+		edited, err := opts.EditMerge(item, merged)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		if psync.HasConflictMarkers(edited) {
+			result.Error = fmt.Errorf("conflict markers left in the merge; nothing changed")
+			return result
+		}
+		merged = edited
+	}
+
+	info, err := os.Stat(item.Source)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	path, err := backup.Save(item.Target)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if err := os.WriteFile(item.Source, []byte(merged), info.Mode().Perm()); err != nil {
+		result.Error = err
+		return result
+	}
+	msg, err := placeFile(item, strategy)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = fmt.Sprintf("%s; merged the changes here into the pact repo ('pact push' shares them), backed up the old one as %s", msg, filepath.Base(filepath.Dir(path)))
+	return result
+}
+
+// recordBase keeps the content a text file was placed with, as the base for
+// merging later edits
+func recordBase(item config.SyncItem) {
+	if data, err := readText(item.Source); err == nil {
+		state.SaveBase(item.Module, item.Name, data)
+	}
+}
+
+// readText reads a small regular text file
+func readText(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.Mode().IsRegular() || info.Size() > maxMergeSize {
+		return nil, fmt.Errorf("%s isn't a small regular file", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data, 0) >= 0 || !utf8.Valid(data) {
+		return nil, fmt.Errorf("%s is binary", path)
+	}
+	return data, nil
+}
@@ -0,0 +1,101 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// killDelay is how long an interrupted command gets to clean up before it is
+// killed
+const killDelay = 10 * time.Second
+
+// Timeouts limit how long each step may run; zero means no limit
+type Timeouts struct {
+	Install  time.Duration // Each package, extension, app, or font install
+	Download time.Duration // Each file download
+	Hook     time.Duration // Each pre or post hook
+}
+
+// ParseTimeouts reads settings.timeouts, where each value is a duration like
+// "10m" or a number of seconds:
+//
+//	"timeouts": {"install": "15m", "download": "5m", "hook": 60}
+func ParseTimeouts(cfg *config.PactConfig) (Timeouts, error) {
+	var t Timeouts
+	fields := map[string]*time.Duration{
+		"install":  &t.Install,
+		"download": &t.Download,
+		"hook":     &t.Hook,
+	}
+	for key, value := range cfg.GetMap("settings.timeouts") {
+		field, ok := fields[key]
+		if !ok {
+			return t, fmt.Errorf("settings.timeouts: unknown timeout '%s' (use install, download, or hook)", key)
+		}
+		switch v := value.(type) {
+		case float64:
+			*field = time.Duration(v * float64(time.Second))
+		case string:
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return t, fmt.Errorf("settings.timeouts.%s: %w", key, err)
+			}
+			*field = d
+		default:
+			return t, fmt.Errorf("settings.timeouts.%s should be a duration like \"10m\"", key)
+		}
+	}
+	return t, nil
+}
+
+// ctx returns the run's context, or a background context when none is set
+func (o Options) ctx() context.Context {
+	if o.Context != nil {
+		return o.Context
+	}
+	return context.Background()
+}
+
+// withTimeout derives a context for one step, limited to d when d is set
+func (o Options) withTimeout(d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(o.ctx())
+	}
+	return context.WithTimeout(o.ctx(), d)
+}
+
+// Cancelled reports whether the run was cancelled, e.g. by Ctrl+C
+func (o Options) Cancelled() bool {
+	return o.ctx().Err() != nil
+}
+
+// command builds a command that is interrupted when ctx ends, and killed if
+// it hasn't exited killDelay later, so installers get a chance to clean up
+func command(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		// Windows can't deliver an interrupt to another process
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = killDelay
+	return cmd
+}
+
+// stepError explains why a step's context ended, if it did
+func stepError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("timed out (raise it in settings.timeouts)")
+	case context.Canceled:
+		return fmt.Errorf("cancelled")
+	}
+	return err
+}
@@ -0,0 +1,34 @@
+package apply
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestParseTimeouts(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"settings": {"timeouts": {"install": "15m", "hook": 90}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseTimeouts(cfg)
+	if err != nil {
+		t.Fatalf("ParseTimeouts() error = %v", err)
+	}
+	want := Timeouts{Install: 15 * time.Minute, Hook: 90 * time.Second}
+	if got != want {
+		t.Fatalf("ParseTimeouts() = %+v, want %+v", got, want)
+	}
+
+	for _, bad := range []string{
+		`{"settings": {"timeouts": {"instal": "5m"}}}`,
+		`{"settings": {"timeouts": {"download": "soon"}}}`,
+		`{"settings": {"timeouts": {"hook": true}}}`,
+	} {
+		cfg, _ := config.Parse([]byte(bad))
+		if _, err := ParseTimeouts(cfg); err == nil {
+			t.Fatalf("ParseTimeouts(%s) succeeded, want an error", bad)
+		}
+	}
+}
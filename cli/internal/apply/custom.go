@@ -0,0 +1,463 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/archive"
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// CustomSource describes where a custom CLI tool is installed from.
+// Exactly one of Repo, URL, or Script is set.
+//
+// Asset and BinPath may contain placeholders: {version} (tag without a
+// leading "v"), {tag}, {os}, and {arch}.
+type CustomSource struct {
+	Repo    string // GitHub "owner/name", installed from release assets
+	URL     string // Direct download URL (binary or archive)
+	Script  string // Install script URL, run with sh or PowerShell
+	Version string // Pinned release tag (empty = latest)
+	Asset   string // Glob the release asset name must match (e.g. "mytool-{version}-{os}.tgz")
+	BinPath string // Path of the binary inside the archive (e.g. "bin/mytool")
+}
+
+// defaultCustomSources are used when cli.customSources doesn't mention a tool
+var defaultCustomSources = map[string]CustomSource{
+	"pact":   {Repo: "cloudboy-jh/pact"},
+	"churn":  {Repo: "cloudboy-jh/churn"},
+	"annotr": {Repo: "cloudboy-jh/annotr"},
+}
+
+// getCustomSource resolves the source for a custom tool from
+// cli.customSources, falling back to the built-in defaults.
+//
+// Entries may be a string ("owner/name" or an https URL) or an object:
+//
+//	"customSources": {
+//	  "mytool": {"repo": "me/mytool", "version": "v1.2.0", "asset": "*{os}*{arch}*"},
+//	  "odd":    {"repo": "me/odd", "assetPattern": "odd-{version}-{os}.tgz", "binPath": "bin/odd"},
+//	  "other":  {"url": "https://example.com/other-linux-amd64"},
+//	  "thing":  {"script": "https://example.com/install.sh"}
+//	}
+func getCustomSource(cfg *config.PactConfig, tool string) (CustomSource, bool) {
+	sources := cfg.GetMap("cli.customSources")
+	if entry, ok := sources[tool]; ok {
+		switch v := entry.(type) {
+		case string:
+			if strings.HasPrefix(v, "http://") || strings.HasPrefix(v, "https://") {
+				return CustomSource{URL: v}, true
+			}
+			return CustomSource{Repo: v}, true
+		case map[string]any:
+			src := CustomSource{}
+			src.Repo, _ = v["repo"].(string)
+			src.URL, _ = v["url"].(string)
+			src.Script, _ = v["script"].(string)
+			src.Version, _ = v["version"].(string)
+			src.Asset, _ = v["asset"].(string)
+			if pattern, ok := v["assetPattern"].(string); ok {
+				src.Asset = pattern
+			}
+			src.BinPath, _ = v["binPath"].(string)
+			if src.Repo != "" || src.URL != "" || src.Script != "" {
+				return src, true
+			}
+		}
+	}
+
+	src, ok := defaultCustomSources[tool]
+	return src, ok
+}
+
+// installCustomTool installs a tool from its configured custom source
+func installCustomTool(cfg *config.PactConfig, tool string, opts Options) Result {
+	result := Result{
+		Category: "install",
+		Module:   "cli",
+		Name:     tool,
+	}
+
+	// Check if already installed
+	if isToolInstalled(tool) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already installed"
+		return result
+	}
+
+	src, ok := getCustomSource(cfg, tool)
+	if !ok {
+		// Try to install via package manager as fallback
+		pm := detectPackageManager()
+		if pm != "" {
+			return installTool(pm, tool, opts)
+		}
+		result.Error = fmt.Errorf("no source in cli.customSources and no package manager available")
+		return result
+	}
+
+	if src.Script != "" && opts.ForbidScripts {
+		result.Error = fmt.Errorf("install scripts are forbidden by policy")
+		return result
+	}
+	if src.Script == "" {
+		if err := allowPath(customInstallPath(tool, opts), opts); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	binDir := filepath.Dir(customInstallPath(tool, opts))
+	if opts.DryRun {
+		switch {
+		case src.Script != "":
+			return planned(result, "download and run install script %s", src.Script)
+		case src.URL != "":
+			result = planned(result, "download %s to %s", src.URL, customInstallPath(tool, opts))
+		default:
+			result = planned(result, "download the latest %s release to %s", src.Repo, customInstallPath(tool, opts))
+		}
+		if runtime.GOOS == "windows" && !pathContains(os.Getenv("PATH"), binDir) {
+			result.Message += fmt.Sprintf(" and add %s to your PATH", binDir)
+		}
+		return result
+	}
+
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
+	var err error
+	switch {
+	case src.Script != "":
+		err = runInstallScript(ctx, src.Script)
+		result.Message = fmt.Sprintf("installed via script %s", src.Script)
+		result.Backend = "script"
+	case src.URL != "":
+		err = installFromURL(ctx, tool, src.URL, expandAssetPlaceholders(src.BinPath, ""), customInstallPath(tool, opts))
+		result.Message = fmt.Sprintf("installed from %s", src.URL)
+		result.Backend = "binary"
+		result.Paths = []string{customInstallPath(tool, opts)}
+	default:
+		var tag string
+		tag, err = installFromRelease(ctx, tool, src, customInstallPath(tool, opts))
+		result.Message = fmt.Sprintf("installed %s from %s", tag, src.Repo)
+		result.Backend = "binary"
+		result.Paths = []string{customInstallPath(tool, opts)}
+	}
+
+	if err != nil {
+		result.Message = ""
+		result.Error = stepError(ctx, err)
+		return result
+	}
+
+	result.Success = true
+	if result.Backend == "binary" {
+		if added, err := addToUserPath(ctx, binDir, opts); err != nil {
+			result.Message += fmt.Sprintf("; %v", err)
+		} else if added {
+			result.Message += fmt.Sprintf("; added %s to your PATH, open a new terminal to use it", binDir)
+		}
+	}
+	return result
+}
+
+// releaseAsset is the release asset chosen for a custom tool
+type releaseAsset struct {
+	Tag  string
+	Name string
+	URL  string
+	Size int64
+}
+
+// findReleaseAsset looks up the GitHub release for src and picks the asset
+// matching its pattern (or this OS/arch)
+func findReleaseAsset(ctx context.Context, src CustomSource) (releaseAsset, error) {
+	releaseURL := fmt.Sprintf("https://api.github.com/repos/%s/releases/latest", src.Repo)
+	if src.Version != "" {
+		releaseURL = fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", src.Repo, src.Version)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", releaseURL, nil)
+	if err != nil {
+		return releaseAsset{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return releaseAsset{}, fmt.Errorf("failed to fetch release info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		if src.Version != "" {
+			return releaseAsset{}, fmt.Errorf("release %s not found for %s", src.Version, src.Repo)
+		}
+		return releaseAsset{}, fmt.Errorf("no releases found for %s", src.Repo)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name               string `json:"name"`
+			Size               int64  `json:"size"`
+			BrowserDownloadURL string `json:"browser_download_url"`
+		} `json:"assets"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return releaseAsset{}, fmt.Errorf("failed to parse release info: %w", err)
+	}
+
+	var names []string
+	for _, asset := range release.Assets {
+		names = append(names, asset.Name)
+	}
+
+	assetName, err := selectAsset(names, expandAssetPlaceholders(src.Asset, release.TagName))
+	if err != nil {
+		return releaseAsset{}, err
+	}
+
+	for _, asset := range release.Assets {
+		if asset.Name == assetName {
+			return releaseAsset{Tag: release.TagName, Name: asset.Name, URL: asset.BrowserDownloadURL, Size: asset.Size}, nil
+		}
+	}
+	return releaseAsset{}, fmt.Errorf("asset %s not found", assetName)
+}
+
+// CheckCustomSource reports whether a custom tool's source can be reached:
+// a release with an asset for this OS, or a URL that answers. Tools with no
+// custom source are fine, since they fall back to the package manager.
+func CheckCustomSource(ctx context.Context, cfg *config.PactConfig, tool string) error {
+	src, ok := getCustomSource(cfg, tool)
+	if !ok {
+		return nil
+	}
+	if src.Repo != "" {
+		_, err := findReleaseAsset(ctx, src)
+		return err
+	}
+
+	url := src.URL
+	if url == "" {
+		url = src.Script
+	}
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// installFromRelease downloads the matching asset from a GitHub release and
+// returns the release tag that was installed
+func installFromRelease(ctx context.Context, tool string, src CustomSource, installPath string) (string, error) {
+	asset, err := findReleaseAsset(ctx, src)
+	if err != nil {
+		return "", err
+	}
+
+	binPath := expandAssetPlaceholders(src.BinPath, asset.Tag)
+	if err := installFromURL(ctx, tool, asset.URL, binPath, installPath); err != nil {
+		return "", err
+	}
+	return asset.Tag, nil
+}
+
+// expandAssetPlaceholders fills {version}, {tag}, {os}, and {arch} in an
+// asset pattern or bin path
+func expandAssetPlaceholders(s, tag string) string {
+	if s == "" {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{version}", strings.TrimPrefix(tag, "v"),
+		"{tag}", tag,
+		"{os}", runtime.GOOS,
+		"{arch}", runtime.GOARCH,
+	).Replace(s)
+}
+
+// selectAsset picks the release asset for this OS/arch. With a pattern
+// (already expanded), the asset name must match it as a glob; otherwise the
+// name is matched heuristically.
+func selectAsset(names []string, pattern string) (string, error) {
+	osName := runtime.GOOS
+	arch := runtime.GOARCH
+
+	if pattern != "" {
+		pattern = strings.ToLower(pattern)
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, strings.ToLower(name)); ok {
+				return name, nil
+			}
+		}
+		return "", fmt.Errorf("no release asset matches %q", pattern)
+	}
+
+	// Find the right asset for this OS/arch
+	if arch == "amd64" {
+		arch = "x86_64"
+	}
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if strings.Contains(lower, osName) && (strings.Contains(lower, arch) || strings.Contains(lower, "amd64") || strings.Contains(lower, "x64")) {
+			return name, nil
+		}
+	}
+
+	return "", fmt.Errorf("no compatible release found for %s/%s", osName, arch)
+}
+
+// installFromURL downloads a binary or archive and installs the tool.
+// For archives, binPath locates the binary inside it; when empty the
+// archive is searched for a file named after the tool.
+func installFromURL(ctx context.Context, tool, downloadURL, binPath, installPath string) error {
+	tmpFile := filepath.Join(os.TempDir(), "pact-"+tool+"-download")
+	if err := downloadFile(ctx, downloadURL, tmpFile); err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile)
+
+	os.MkdirAll(filepath.Dir(installPath), 0755)
+
+	// Handle tar.gz or zip
+	lower := strings.ToLower(downloadURL)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") || strings.HasSuffix(lower, ".zip") {
+		return installFromArchive(ctx, tmpFile, lower, tool, binPath, installPath)
+	}
+
+	// Direct binary
+	if err := copyFile(tmpFile, installPath); err != nil {
+		return err
+	}
+	return os.Chmod(installPath, 0755)
+}
+
+// customInstallPath is where a custom tool's binary is installed; user scope
+// keeps it in ~/.local/bin
+func customInstallPath(tool string, opts Options) string {
+	home, _ := os.UserHomeDir()
+	if runtime.GOOS == "windows" {
+		return filepath.Join(home, "bin", tool+".exe")
+	}
+	if opts.UserScope() {
+		return filepath.Join(home, ".local", "bin", tool)
+	}
+	return filepath.Join("/usr/local/bin", tool)
+}
+
+// installFromArchive extracts an archive to a scratch dir and installs only
+// the tool's binary, so READMEs and licenses don't land in the bin dir
+func installFromArchive(ctx context.Context, src, name, tool, binPath, installPath string) error {
+	extractDir, err := os.MkdirTemp("", "pact-extract-"+tool+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(extractDir)
+
+	match := archive.Binary(tool, binPath)
+	if strings.HasSuffix(name, ".zip") {
+		err = archive.ExtractZip(ctx, src, extractDir, match)
+	} else {
+		err = archive.ExtractTarGz(ctx, src, extractDir, match)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", filepath.Base(name), err)
+	}
+
+	binary, err := findArchiveBinary(extractDir, tool, binPath)
+	if err != nil {
+		return err
+	}
+
+	if err := copyFile(binary, installPath); err != nil {
+		return err
+	}
+	return os.Chmod(installPath, 0755)
+}
+
+// findArchiveBinary locates the tool binary in an extracted archive
+func findArchiveBinary(extractDir, tool, binPath string) (string, error) {
+	if binPath != "" {
+		candidate := filepath.Join(extractDir, filepath.FromSlash(binPath))
+		// binPath comes from pact.json; it can't reach outside the archive
+		rel, err := filepath.Rel(extractDir, candidate)
+		if filepath.IsAbs(binPath) || strings.HasPrefix(binPath, "/") || err != nil ||
+			rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("binPath %q is outside the archive", binPath)
+		}
+		if runtime.GOOS == "windows" && filepath.Ext(candidate) == "" {
+			if _, err := os.Stat(candidate + ".exe"); err == nil {
+				return candidate + ".exe", nil
+			}
+		}
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+		return "", fmt.Errorf("binPath %q not found in archive", binPath)
+	}
+
+	var found string
+	filepath.Walk(extractDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || found != "" {
+			return nil
+		}
+		base := info.Name()
+		if base == tool || base == tool+".exe" {
+			found = p
+		}
+		return nil
+	})
+
+	if found == "" {
+		return "", fmt.Errorf("no %s binary found in archive (set binPath in cli.customSources)", tool)
+	}
+	return found, nil
+}
+
+// runInstallScript downloads an install script and runs it with the
+// platform shell
+func runInstallScript(ctx context.Context, scriptURL string) error {
+	ext := ".sh"
+	if runtime.GOOS == "windows" {
+		ext = ".ps1"
+	}
+
+	tmpFile := filepath.Join(os.TempDir(), "pact-install-script"+ext)
+	if err := downloadFile(ctx, scriptURL, tmpFile); err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile)
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = command(ctx, "powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", tmpFile)
+	} else {
+		cmd = command(ctx, "sh", tmpFile)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("install script failed: %v: %s", err, string(output))
+	}
+	return nil
+}
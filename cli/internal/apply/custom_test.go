@@ -0,0 +1,24 @@
+package apply
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindArchiveBinaryStaysInArchive(t *testing.T) {
+	root := t.TempDir()
+	extractDir := filepath.Join(root, "extract")
+	os.MkdirAll(filepath.Join(extractDir, "bin"), 0755)
+	os.WriteFile(filepath.Join(extractDir, "bin", "rg"), []byte("rg"), 0755)
+	os.WriteFile(filepath.Join(root, "evil"), []byte("evil"), 0755)
+
+	if got, err := findArchiveBinary(extractDir, "rg", "bin/rg"); err != nil || got != filepath.Join(extractDir, "bin", "rg") {
+		t.Fatalf("findArchiveBinary(bin/rg) = %q, %v", got, err)
+	}
+	for _, binPath := range []string{"../evil", "bin/../../evil", filepath.Join(root, "evil"), "/etc/passwd"} {
+		if got, err := findArchiveBinary(extractDir, "rg", binPath); err == nil {
+			t.Fatalf("findArchiveBinary(%q) = %q, want an error", binPath, got)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	psync "github.com/cloudboy-jh/pact/internal/sync"
+)
+
+// applyDirenv sets up shell.direnv:
+//
+//	"direnv": {
+//	  "whitelist": {"prefix": ["~/work"], "exact": ["~/code/app/.envrc"]},
+//	  "templates": {"~/code/*": "shell/envrc/default.envrc"}
+//	}
+//
+// The whitelist is merged into direnv.toml. Each template is copied from the
+// pact repo into every directory matching its pattern that has no .envrc yet.
+func applyDirenv(cfg *config.PactConfig, opts Options) []Result {
+	if !cfg.HasKey("shell.direnv") {
+		return nil
+	}
+
+	var results []Result
+	if !containsArg(cfg.GetStringSlice("shell.tools"), "direnv") {
+		if !isToolInstalled("direnv") {
+			if pm := detectPackageManager(); pm != "" {
+				result := installTool(pm, "direnv", opts)
+				result.Module = "shell"
+				results = append(results, result)
+				if !result.Success {
+					return results
+				}
+			}
+		}
+		if initResult := injectToolInit(cfg, "direnv", opts); initResult.Message != "" {
+			results = append(results, initResult)
+		}
+	}
+
+	if cfg.HasKey("shell.direnv.whitelist") {
+		results = append(results, writeDirenvWhitelist(cfg, opts))
+	}
+
+	templates := cfg.GetMap("shell.direnv.templates")
+	patterns := make([]string, 0, len(templates))
+	for pattern := range templates {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		source, _ := templates[pattern].(string)
+		results = append(results, distributeEnvrc(pattern, source, opts))
+	}
+	return results
+}
+
+// direnvWhitelistTOML renders direnv.toml's [whitelist] table
+func direnvWhitelistTOML(prefix, exact []string) string {
+	list := func(paths []string) string {
+		quoted := make([]string, len(paths))
+		for i, p := range paths {
+			quoted[i] = fmt.Sprintf("%q", p)
+		}
+		return "[" + strings.Join(quoted, ", ") + "]"
+	}
+
+	var b strings.Builder
+	b.WriteString("[whitelist]\n")
+	if len(prefix) > 0 {
+		b.WriteString("prefix = " + list(prefix) + "\n")
+	}
+	if len(exact) > 0 {
+		b.WriteString("exact = " + list(exact) + "\n")
+	}
+	return b.String()
+}
+
+func writeDirenvWhitelist(cfg *config.PactConfig, opts Options) Result {
+	result := Result{
+		Category: "configure",
+		Module:   "shell",
+		Name:     "direnv-whitelist",
+	}
+
+	expand := func(paths []string) []string {
+		var out []string
+		for _, p := range paths {
+			if expanded, err := config.ExpandPath(p); err == nil {
+				out = append(out, expanded)
+			}
+		}
+		return out
+	}
+	managed := direnvWhitelistTOML(
+		expand(cfg.GetStringSlice("shell.direnv.whitelist.prefix")),
+		expand(cfg.GetStringSlice("shell.direnv.whitelist.exact")),
+	)
+
+	path := filepath.Join(config.DirenvConfigDir(), "direnv.toml")
+	if err := allowPath(path, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	content := []byte(managed)
+	local, err := os.ReadFile(path)
+	if err == nil {
+		content = psync.MergeTOML(local, content)
+		if string(content) == string(local) {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already set"
+			return result
+		}
+	}
+
+	if opts.DryRun {
+		return planned(result, "update the whitelist in %s", path)
+	}
+	if err := opts.Undo.File(path); err != nil {
+		result.Error = fmt.Errorf("failed to back up %s: %w", path, err)
+		return result
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Success = true
+	result.Message = "updated " + path
+	return result
+}
+
+// distributeEnvrc copies a template .envrc into matching project directories
+// that don't have one
+func distributeEnvrc(pattern, source string, opts Options) Result {
+	result := Result{
+		Category: "file",
+		Module:   "shell",
+		Name:     "envrc " + pattern,
+	}
+
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	template, err := os.ReadFile(filepath.Join(pactDir, source))
+	if err != nil {
+		result.Error = fmt.Errorf("template not found: %s", source)
+		return result
+	}
+
+	expanded, err := config.ExpandPath(pattern)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	matches, err := filepath.Glob(expanded)
+	if err != nil {
+		result.Error = fmt.Errorf("bad pattern %s: %w", pattern, err)
+		return result
+	}
+
+	var targets []string
+	for _, dir := range matches {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			continue
+		}
+		envrc := filepath.Join(dir, ".envrc")
+		if _, err := os.Lstat(envrc); err == nil || allowPath(envrc, opts) != nil {
+			continue
+		}
+		targets = append(targets, envrc)
+	}
+
+	if len(targets) == 0 {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "no projects without an .envrc"
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "write .envrc in %d project(s)", len(targets))
+	}
+
+	for _, envrc := range targets {
+		if err := opts.Undo.File(envrc); err != nil {
+			result.Error = fmt.Errorf("failed to record %s: %w", envrc, err)
+			return result
+		}
+		if err := os.WriteFile(envrc, template, 0644); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Message = fmt.Sprintf("wrote .envrc in %d project(s); run 'direnv allow' in each unless whitelisted", len(targets))
+	return result
+}
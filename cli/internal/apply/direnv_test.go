@@ -0,0 +1,15 @@
+package apply
+
+import "testing"
+
+func TestDirenvWhitelistTOML(t *testing.T) {
+	got := direnvWhitelistTOML([]string{"/home/me/work"}, []string{`C:\code\app\.envrc`})
+	want := "[whitelist]\nprefix = [\"/home/me/work\"]\nexact = [\"C:\\\\code\\\\app\\\\.envrc\"]\n"
+	if got != want {
+		t.Fatalf("direnvWhitelistTOML() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := direnvWhitelistTOML(nil, []string{"/a/.envrc"}); got != "[whitelist]\nexact = [\"/a/.envrc\"]\n" {
+		t.Fatalf("direnvWhitelistTOML() with no prefix = %q", got)
+	}
+}
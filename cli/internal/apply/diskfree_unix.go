@@ -0,0 +1,14 @@
+//go:build !windows
+
+package apply
+
+import "golang.org/x/sys/unix"
+
+// freeSpace returns the bytes available to the user on path's filesystem
+func freeSpace(path string) (int64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), nil
+}
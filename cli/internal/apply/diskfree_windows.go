@@ -0,0 +1,18 @@
+//go:build windows
+
+package apply
+
+import "golang.org/x/sys/windows"
+
+// freeSpace returns the bytes available to the user on path's volume
+func freeSpace(path string) (int64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree); err != nil {
+		return 0, err
+	}
+	return int64(free), nil
+}
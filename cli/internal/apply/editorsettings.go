@@ -0,0 +1,120 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// settingsEditors are the editors whose editor.<editor> entry can hold
+// settings, keybindings, and snippets
+var settingsEditors = []string{"vscode", "cursor"}
+
+// applyEditorSettings applies editor.vscode and editor.cursor beyond their
+// extensions:
+//
+//	"vscode": {
+//	  "settings": {"editor.fontSize": 14, "[python]": {"editor.tabSize": 4}},
+//	  "keybindings": "editor/vscode/keybindings.json",
+//	  "snippets": "editor/vscode/snippets",
+//	  "strategy": "merge"
+//	}
+//
+// settings is merged into the user settings.json, pact's keys winning and
+// the rest kept; objects such as language overrides are merged key by key.
+// keybindings and snippets are paths in the pact repo, placed the way the
+// keybindings and snippets modules place them.
+func applyEditorSettings(cfg *config.PactConfig, opts Options) []Result {
+	var results []Result
+	for _, editor := range settingsEditors {
+		prefix := "editor." + editor
+		if settings := cfg.GetMap(prefix + ".settings"); len(settings) > 0 {
+			results = append(results, mergeEditorSettings(editor, settings, opts))
+		}
+
+		strategy := cfg.GetString(prefix + ".strategy")
+		if strategy == "" {
+			strategy = "merge"
+		}
+		keybindings := cfg.GetString(prefix + ".keybindings")
+		snippets := cfg.GetString(prefix + ".snippets")
+		if keybindings == "" && snippets == "" {
+			continue
+		}
+		if strategy != "merge" && strategy != "replace" {
+			results = append(results, Result{Category: "file", Module: "editor", Name: editor, Error: fmt.Errorf("unknown strategy '%s' (use merge or replace)", strategy)})
+			continue
+		}
+		pactDir, err := config.GetPactDir()
+		if err != nil {
+			results = append(results, Result{Category: "file", Module: "editor", Name: editor, Error: err})
+			continue
+		}
+		if keybindings != "" {
+			result := placeKeybindings(editorSource{editor, keybindings, strategy}, "editor", pactDir, opts)
+			result.Name = editor + "-keybindings"
+			results = append(results, result)
+		}
+		if snippets != "" {
+			result := placeSnippets(editorSource{editor, snippets, strategy}, "editor", pactDir, opts)
+			result.Name = editor + "-snippets"
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// mergeEditorSettings merges settings into an editor's settings.json
+func mergeEditorSettings(editor string, settings map[string]any, opts Options) Result {
+	result := Result{Category: "file", Module: "editor", Name: editor + "-settings"}
+
+	dir := config.EditorConfigDir(editor)
+	if dir == "" {
+		result.Error = fmt.Errorf("can't find %s's settings directory", editor)
+		return result
+	}
+	target := filepath.Join(dir, "settings.json")
+
+	local, err := os.ReadFile(target)
+	if err != nil && !os.IsNotExist(err) {
+		result.Error = err
+		return result
+	}
+	content, err := mergeSettings(local, settings)
+	if err != nil {
+		result.Error = fmt.Errorf("can't merge into %s: %w", target, err)
+		return result
+	}
+	return writeManagedFiles(result, []managedFile{{target, content}}, "merge", opts)
+}
+
+// mergeSettings sets pact's settings in a local settings.json, keeping the
+// keys pact doesn't set
+func mergeSettings(local []byte, settings map[string]any) ([]byte, error) {
+	var before, after map[string]any
+	if err := unmarshalJSONC(local, &before); err != nil {
+		return nil, err
+	}
+	unmarshalJSONC(local, &after)
+	if after == nil {
+		after = make(map[string]any)
+	}
+	mergeJSONObject(after, settings)
+	return marshalMerged(local, before, after)
+}
+
+// mergeJSONObject copies src's keys into dst, merging objects present in
+// both instead of replacing them
+func mergeJSONObject(dst, src map[string]any) {
+	for key, value := range src {
+		if from, ok := value.(map[string]any); ok {
+			if into, ok := dst[key].(map[string]any); ok {
+				mergeJSONObject(into, from)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
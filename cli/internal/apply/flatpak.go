@@ -0,0 +1,169 @@
+package apply
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
+)
+
+// applyFlatpak adds the remotes in apps.linux.flatpak and sets each app's
+// sandbox overrides
+func applyFlatpak(flatpak config.FlatpakConfig, opts Options) []Result {
+	if len(flatpak.Remotes) == 0 && len(flatpak.Overrides) == 0 {
+		return nil
+	}
+	if !isToolInstalled("flatpak") {
+		return []Result{{Category: "app", Module: "apps", Name: "flatpak", Error: fmt.Errorf("apps.linux.flatpak needs flatpak; install it with your package manager")}}
+	}
+
+	var results []Result
+	remotes := flatpakRemotes()
+	for _, remote := range flatpak.Remotes {
+		results = append(results, addFlatpakRemote(remote, remotes, opts))
+	}
+	for _, override := range flatpak.Overrides {
+		results = append(results, setFlatpakOverride(override, opts))
+	}
+	return results
+}
+
+// ensureFlathub adds the flathub remote when any of apps install with
+// Flatpak and it isn't there yet
+func ensureFlathub(apps []config.Package, opts Options) []Result {
+	if runtime.GOOS != "linux" || !isToolInstalled("flatpak") {
+		return nil
+	}
+	for _, app := range apps {
+		if m, _ := appManager(app, runtime.GOOS); m != nil && m.Name() == "flatpak" {
+			remote := config.FlatpakRemote{Name: pkg.FlathubRemote, URL: pkg.FlathubURL}
+			return []Result{addFlatpakRemote(remote, flatpakRemotes(), opts)}
+		}
+	}
+	return nil
+}
+
+// flatpakRemotes returns the names of the configured remotes, system and
+// user
+func flatpakRemotes() map[string]bool {
+	remotes := make(map[string]bool)
+	output, err := exec.Command("flatpak", "remotes", "--columns=name").Output()
+	if err != nil {
+		return remotes
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			remotes[name] = true
+		}
+	}
+	return remotes
+}
+
+// addFlatpakRemote adds remote unless a remote by its name exists
+func addFlatpakRemote(remote config.FlatpakRemote, remotes map[string]bool, opts Options) Result {
+	result := Result{Category: "app", Module: "apps", Name: remote.Name + "-remote"}
+	if remotes[remote.Name] {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already added"
+		return result
+	}
+
+	cmd := command(opts.ctx(), "flatpak", "remote-add", "--if-not-exists", remote.Name, remote.URL)
+	userScopeArgs(cmd, opts)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	opts.Undo.Setting("flatpak remote "+remote.Name, []string{"flatpak", "remote-delete", remote.Name})
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(opts.ctx(), fmt.Errorf("flatpak remote-add failed: %s", strings.TrimSpace(string(output))))
+		return result
+	}
+	result.Success = true
+	result.Message = "added " + remote.URL
+	return result
+}
+
+// setFlatpakOverride makes an app's user overrides exactly its flags. The
+// flags are applied in a scratch Flatpak directory first, so the result can
+// be compared with the app's current overrides file and written only when it
+// differs.
+func setFlatpakOverride(override config.FlatpakOverride, opts Options) Result {
+	result := Result{Category: "app", Module: "apps", Name: override.App + "-overrides"}
+
+	scratch, err := os.MkdirTemp("", "pact-flatpak-")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	defer os.RemoveAll(scratch)
+
+	var want []byte
+	if len(override.Flags) > 0 {
+		cmd := exec.Command("flatpak", append([]string{"override", "--user", override.App}, override.Flags...)...)
+		cmd.Env = append(os.Environ(), "FLATPAK_USER_DIR="+scratch)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = fmt.Errorf("flatpak override rejected the flags: %s", strings.TrimSpace(string(output)))
+			return result
+		}
+		if want, err = os.ReadFile(filepath.Join(scratch, "overrides", override.App)); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	path := filepath.Join(flatpakUserDir(), "overrides", override.App)
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		result.Error = err
+		return result
+	}
+	if bytes.Equal(current, want) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already set"
+		return result
+	}
+
+	if err := allowPath(path, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "run flatpak override --user %s %s", override.App, strings.Join(override.Flags, " "))
+	}
+	if err := opts.Undo.File(path); err != nil {
+		result.Error = err
+		return result
+	}
+	if len(want) == 0 {
+		err = os.Remove(path)
+	} else if err = os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		err = os.WriteFile(path, want, 0644)
+	}
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = fmt.Sprintf("set %d override(s)", len(override.Flags))
+	return result
+}
+
+// flatpakUserDir is the per-user Flatpak installation
+func flatpakUserDir() string {
+	if dir := os.Getenv("FLATPAK_USER_DIR"); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "flatpak")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "share", "flatpak")
+}
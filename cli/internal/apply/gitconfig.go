@@ -0,0 +1,50 @@
+package apply
+
+import (
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// applyGitConfig writes git.config to the global gitconfig, for settings
+// pact has no key of its own for:
+//
+//	"config": {"pull.rebase": true, "core.editor": "nvim", "alias": {"co": "checkout"}}
+//
+// Keys already set to their value are skipped.
+func applyGitConfig(cfg *config.PactConfig, opts Options) []Result {
+	settings := cfg.GitConfig()
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var results []Result
+	for _, key := range keys {
+		if current, ok := globalGitConfig(opts, key); ok && current == settings[key] {
+			results = append(results, Result{
+				Category: "configure",
+				Module:   "git",
+				Name:     key,
+				Success:  true,
+				Skipped:  true,
+				Message:  "already set",
+			})
+			continue
+		}
+		results = append(results, setGitConfig(key, settings[key], opts))
+	}
+	return results
+}
+
+// globalGitConfig reads a global git config value
+func globalGitConfig(opts Options, key string) (string, bool) {
+	output, err := exec.CommandContext(opts.ctx(), "git", "config", "--global", "--get", key).Output()
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimRight(string(output), "\n"), true
+}
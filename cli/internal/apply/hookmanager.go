@@ -0,0 +1,178 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// hookManager describes a git hook framework pact can install and set up
+type hookManager struct {
+	packages map[string]string // Package name per package manager
+	manual   string            // How to install it where it isn't packaged
+	install  []string          // Installs the hooks in a repo
+}
+
+var hookManagers = map[string]hookManager{
+	"pre-commit": {
+		packages: map[string]string{
+			"brew":   "pre-commit",
+			"apt":    "pre-commit",
+			"dnf":    "pre-commit",
+			"pacman": "python-pre-commit",
+			"scoop":  "pre-commit",
+		},
+		manual:  "pipx install pre-commit",
+		install: []string{"pre-commit", "install", "--install-hooks"},
+	},
+	"lefthook": {
+		packages: map[string]string{
+			"brew":   "lefthook",
+			"winget": "evilmartians.lefthook",
+			"scoop":  "lefthook",
+		},
+		manual:  "npm install -g lefthook",
+		install: []string{"lefthook", "install"},
+	},
+}
+
+// gitTemplateDir is where the global hook goes so new clones get it
+const gitTemplateDir = "~/.git-template"
+
+// applyHookManager installs git.hookManager and sets up hooks, either a tool
+// name or an object:
+//
+//	"hookManager": {"tool": "pre-commit", "global": true, "repos": ["~/code/app"]}
+//
+// global installs pre-commit's hook in git's init.templateDir so every new
+// clone runs it; repos get the framework's install command.
+func applyHookManager(cfg *config.PactConfig, opts Options) []Result {
+	tool := cfg.GetString("git.hookManager")
+	if tool == "" {
+		tool = cfg.GetString("git.hookManager.tool")
+	}
+	if tool == "" {
+		return nil
+	}
+
+	hm, ok := hookManagers[tool]
+	if !ok {
+		return []Result{{
+			Category: "configure",
+			Module:   "git",
+			Name:     "hookManager",
+			Error:    fmt.Errorf("unknown hook manager '%s' (use pre-commit or lefthook)", tool),
+		}}
+	}
+
+	var results []Result
+	if !isToolInstalled(tool) {
+		pm := detectPackageManager()
+		pkg, ok := hm.packages[pm]
+		if !ok {
+			results = append(results, Result{
+				Category: "install",
+				Module:   "git",
+				Name:     tool,
+				Error:    fmt.Errorf("%s isn't packaged for %s; install it manually (%s)", tool, pm, hm.manual),
+			})
+			return results
+		}
+		result := installTool(pm, pkg, opts)
+		result.Module = "git"
+		result.Name = tool
+		results = append(results, result)
+		if !result.Success {
+			return results
+		}
+	}
+
+	if cfg.Get("git.hookManager.global") == true {
+		results = append(results, installGlobalHook(tool, opts)...)
+	}
+
+	for _, repo := range cfg.GetStringSlice("git.hookManager.repos") {
+		results = append(results, installRepoHooks(tool, hm, repo, opts))
+	}
+	return results
+}
+
+// installGlobalHook writes pre-commit's hook into the git template directory
+func installGlobalHook(tool string, opts Options) []Result {
+	result := Result{
+		Category: "configure",
+		Module:   "git",
+		Name:     tool + " template",
+	}
+	if tool != "pre-commit" {
+		result.Error = fmt.Errorf("global hooks are only supported for pre-commit; list repos instead")
+		return []Result{result}
+	}
+
+	dir, err := config.ExpandPath(gitTemplateDir)
+	if err != nil {
+		result.Error = err
+		return []Result{result}
+	}
+
+	cmd := exec.CommandContext(opts.ctx(), "pre-commit", "init-templatedir", dir)
+	if opts.DryRun {
+		return []Result{
+			planned(result, "run %s", commandLine(cmd)),
+			setGitConfig("init.templateDir", gitTemplateDir, opts),
+		}
+	}
+
+	if err := opts.Undo.File(filepath.Join(dir, "hooks", "pre-commit")); err != nil {
+		result.Error = fmt.Errorf("failed to back up hook: %w", err)
+		return []Result{result}
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = fmt.Errorf("%s failed: %s", commandLine(cmd), strings.TrimSpace(string(output)))
+		return []Result{result}
+	}
+	result.Success = true
+	result.Message = "new clones run pre-commit"
+	return []Result{result, setGitConfig("init.templateDir", gitTemplateDir, opts)}
+}
+
+// installRepoHooks runs the framework's install command in a repo
+func installRepoHooks(tool string, hm hookManager, repo string, opts Options) Result {
+	result := Result{
+		Category: "configure",
+		Module:   "git",
+		Name:     tool + " " + repo,
+	}
+
+	dir, err := config.ExpandPath(repo)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not a git repo here"
+		return result
+	}
+
+	// --install-hooks downloads each hook's environment
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+	cmd := command(ctx, hm.install[0], hm.install[1:]...)
+	cmd.Dir = dir
+	if opts.DryRun {
+		return planned(result, "run %s in %s", commandLine(cmd), dir)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(ctx, fmt.Errorf("%s failed: %s", commandLine(cmd), strings.TrimSpace(string(output))))
+		return result
+	}
+	result.Success = true
+	result.Message = "hooks installed"
+	return result
+}
@@ -0,0 +1,137 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// HookOutput receives the output of pre/post hooks. Set it to nil to
+// silence hooks (e.g. when emitting machine-readable output).
+var HookOutput io.Writer = os.Stdout
+
+// moduleHooks returns the hooks to run around a module: top-level hooks run
+// around every module, then the module's own
+//
+//	"hooks": {"post": ["echo synced $PACT_MODULE"]},
+//	"shell": {"hooks": {"post": ["reload-shell.sh"]}}
+func moduleHooks(cfg *config.PactConfig, module, stage string) []string {
+	hooks := cfg.GetStringSlice("hooks." + stage)
+	return append(hooks, cfg.GetStringSlice(module+".hooks."+stage)...)
+}
+
+// runHooks runs the pre or post hooks of a module. Post hooks get a
+// summary of the module's results in their environment.
+func runHooks(cfg *config.PactConfig, module, stage string, results []Result, opts Options) []Result {
+	var out []Result
+	for _, hook := range moduleHooks(cfg, module, stage) {
+		out = append(out, runHook(hook, module, stage, results, opts))
+	}
+	return out
+}
+
+func runHook(hook, module, stage string, results []Result, opts Options) Result {
+	result := Result{
+		Category: "hook",
+		Module:   module,
+		Name:     stage + ": " + hook,
+	}
+	if opts.ForbidScripts {
+		if HookOutput != nil {
+			fmt.Fprintf(HookOutput, "Warning: not running %s's %s hook; scripts are forbidden by policy\n", module, stage)
+		}
+		result.Success = true
+		result.Skipped = true
+		result.Message = "scripts are forbidden by policy"
+		return result
+	}
+
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Hook)
+	defer cancel()
+
+	cmd := hookCommand(ctx, hook)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+
+	cmd.Env = append(os.Environ(), hookEnv(module, stage, results)...)
+	if pactDir, err := config.GetPactDir(); err == nil {
+		cmd.Dir = pactDir
+	}
+	cmd.Stdout = HookOutput
+	cmd.Stderr = HookOutput
+	if err := cmd.Run(); err != nil {
+		result.Error = fmt.Errorf("%s hook failed: %w", stage, stepError(ctx, err))
+		return result
+	}
+
+	result.Success = true
+	result.Message = "ran"
+	return result
+}
+
+// hookCommand runs a script from .pact/hooks when the hook names one, and
+// otherwise runs the hook as a shell command
+func hookCommand(ctx context.Context, hook string) *exec.Cmd {
+	if pactDir, err := config.GetPactDir(); err == nil {
+		script := filepath.Join(pactDir, "hooks", strings.TrimPrefix(filepath.ToSlash(hook), "hooks/"))
+		if info, err := os.Stat(script); err == nil && !info.IsDir() {
+			switch {
+			case strings.HasSuffix(script, ".ps1"):
+				return command(ctx, "powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", script)
+			case runtime.GOOS == "windows":
+				return command(ctx, "cmd", "/C", script)
+			case info.Mode()&0111 != 0:
+				return command(ctx, script)
+			default:
+				return command(ctx, "sh", script)
+			}
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return command(ctx, "powershell", "-NoProfile", "-Command", hook)
+	}
+	return command(ctx, "sh", "-c", hook)
+}
+
+// hookEnv describes the module, and for post hooks its results, to a hook
+func hookEnv(module, stage string, results []Result) []string {
+	env := []string{
+		"PACT_MODULE=" + module,
+		"PACT_HOOK=" + stage,
+	}
+	if pactDir, err := config.GetPactDir(); err == nil {
+		env = append(env, "PACT_DIR="+pactDir)
+	}
+	if stage != "post" {
+		return env
+	}
+
+	var applied, skipped, failed int
+	var changed []string
+	for _, r := range results {
+		switch {
+		case r.Error != nil || !r.Success:
+			failed++
+		case r.Skipped:
+			skipped++
+		default:
+			applied++
+			changed = append(changed, r.Name)
+		}
+	}
+	return append(env,
+		fmt.Sprintf("PACT_APPLIED=%d", applied),
+		fmt.Sprintf("PACT_SKIPPED=%d", skipped),
+		fmt.Sprintf("PACT_FAILED=%d", failed),
+		"PACT_CHANGED="+strings.Join(changed, ","),
+	)
+}
@@ -0,0 +1,34 @@
+package apply
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestHookEnv(t *testing.T) {
+	results := []Result{
+		{Name: "starship", Success: true},
+		{Name: "zoxide", Success: true, Skipped: true},
+		{Name: "fzf", Error: errors.New("not found")},
+		{Name: "aliases", Success: true},
+	}
+
+	env := strings.Join(hookEnv("shell", "post", results), "\n")
+	for _, want := range []string{"PACT_MODULE=shell", "PACT_HOOK=post", "PACT_APPLIED=2", "PACT_SKIPPED=1", "PACT_FAILED=1", "PACT_CHANGED=starship,aliases"} {
+		if !strings.Contains(env, want) {
+			t.Fatalf("hookEnv() is missing %s:\n%s", want, env)
+		}
+	}
+
+	if env := strings.Join(hookEnv("shell", "pre", nil), "\n"); strings.Contains(env, "PACT_APPLIED") {
+		t.Fatalf("pre hooks got results: %s", env)
+	}
+}
+
+func TestRunHookForbidScripts(t *testing.T) {
+	result := runHook("touch ran", "shell", "pre", nil, Options{ForbidScripts: true})
+	if !result.Skipped || result.Error != nil {
+		t.Fatalf("runHook() = %+v, want skipped by policy", result)
+	}
+}
@@ -0,0 +1,67 @@
+package apply
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// InstallItem installs one item that pact.json lists but this machine lacks,
+// the way 'pact sync' would, so read and diff can fix drift an item at a
+// time. kind and value are those of the item's detect.DiffItem. With
+// opts.DryRun the Result's Message is the install plan. ok is false for
+// items only a sync of their whole module applies, such as git settings.
+func InstallItem(cfg *config.PactConfig, module, kind, name string, value any, opts Options) (result Result, ok bool) {
+	result, ok = installItem(cfg, module, kind, name, value, opts)
+	if ok && !opts.DryRun {
+		results := []Result{result}
+		recordInstalls(results)
+		recordUndo(opts.Undo, results)
+	}
+	return result, ok
+}
+
+func installItem(cfg *config.PactConfig, module, kind, name string, value any, opts Options) (Result, bool) {
+	s, _ := value.(string)
+	switch module + "/" + kind {
+	case "cli/tool", "shell/tool":
+		pm := detectPackageManager()
+		if pm == "" {
+			return Result{Category: "install", Module: module, Name: name, Error: fmt.Errorf("no supported package manager found (brew, apt, winget)")}, true
+		}
+		for _, p := range cfg.GetPackages(module + ".tools") {
+			if p.Name == name {
+				result := installSystemPackage(pm, p, opts)
+				result.Module = module
+				return result, true
+			}
+		}
+		result := installTool(pm, name, opts)
+		result.Module = module
+		return result, true
+	case "cli/custom":
+		return installCustomTool(cfg, name, opts), true
+	case "cli/package":
+		return installPackage(s, name, installedPackages(s), opts), true
+	case "editor/editor":
+		return installEditor(name, opts), true
+	case "editor/extension":
+		return installExtension(s, name, opts), true
+	case "apps/mas":
+		results := applyMas([]config.MasApp{{ID: s, Name: name}}, opts)
+		return results[len(results)-1], true
+	case "apps/startup":
+		return addStartupItem(name, opts), true
+	case "apps/app":
+		for _, app := range cfg.Apps(runtime.GOOS).Install {
+			if app.Name == name {
+				if setup := ensureFlathub([]config.Package{app}, opts); len(setup) > 0 && setup[0].Error != nil {
+					return setup[0], true
+				}
+				return installApp(app, opts), true
+			}
+		}
+	}
+	return Result{}, false
+}
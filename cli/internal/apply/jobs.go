@@ -0,0 +1,41 @@
+package apply
+
+import "sync"
+
+// parallelManagers are the package managers that can run several installs
+// at once; apt, dnf, and pacman hold a global lock, so their installs stay
+// one at a time
+var parallelManagers = map[string]bool{
+	"brew":  true,
+	"scoop": true,
+}
+
+// installAll runs install for every item, up to jobs at a time, and returns
+// the results in the order of items so output reads the same either way
+func installAll(items []string, jobs int, install func(string) Result) []Result {
+	results := make([]Result, len(items))
+	if jobs < 2 || len(items) < 2 {
+		for i, item := range items {
+			results[i] = install(item)
+		}
+		return results
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(jobs, len(items)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = install(items[i])
+			}
+		}()
+	}
+	for i := range items {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+	return results
+}
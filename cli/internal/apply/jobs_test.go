@@ -0,0 +1,34 @@
+package apply
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInstallAllKeepsOrder(t *testing.T) {
+	tools := []string{"bat", "fd", "jq", "ripgrep", "zoxide", "fzf"}
+
+	var running, peak int32
+	results := installAll(tools, 3, func(tool string) Result {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return Result{Name: tool, Success: true}
+	})
+
+	for i, r := range results {
+		if r.Name != tools[i] {
+			t.Fatalf("results[%d] = %s, want %s", i, r.Name, tools[i])
+		}
+	}
+	if peak > 3 {
+		t.Fatalf("ran %d installs at once, want at most 3", peak)
+	}
+}
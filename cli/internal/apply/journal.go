@@ -0,0 +1,201 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/cloudboy-jh/pact/internal/undo"
+)
+
+// recordInstalls adds everything that was actually installed to the install
+// journal so it can be uninstalled later with the same backend
+func recordInstalls(results []Result) {
+	journal, err := state.LoadJournal()
+	if err != nil {
+		return
+	}
+
+	changed := false
+	for _, r := range results {
+		if !r.Success || r.Skipped || r.Backend == "" {
+			continue
+		}
+		entry := state.Entry{
+			Module:  r.Module,
+			Name:    r.Name,
+			Backend: r.Backend,
+			Paths:   r.Paths,
+		}
+		if r.Package != r.Name {
+			entry.Package = r.Package
+		}
+		journal.Record(entry)
+		changed = true
+	}
+
+	if changed {
+		journal.Save()
+	}
+}
+
+// recordUndo adds everything that was actually installed to the sync's undo
+// journal
+func recordUndo(journal *undo.Journal, results []Result) {
+	for _, r := range results {
+		if !r.Success || r.Skipped || r.Backend == "" {
+			continue
+		}
+		entry := state.Entry{Module: r.Module, Name: r.Name, Backend: r.Backend, Paths: r.Paths}
+		if r.Package != r.Name {
+			entry.Package = r.Package
+		}
+		journal.Install(entry)
+	}
+}
+
+// recordHistory stores the last-applied time and outcome of every module in
+// results, plus any explicitly applied modules that produced no results
+func recordHistory(results []Result, modules ...string) {
+	applied := make(map[string]int)
+	failed := make(map[string][]state.FailedItem)
+	for _, m := range modules {
+		applied[m] += 0
+	}
+	for _, r := range results {
+		if r.Error != nil || (!r.Success && !r.Skipped) {
+			item := state.FailedItem{Category: r.Category, Name: r.Name}
+			if r.Error != nil {
+				item.Error = r.Error.Error()
+			}
+			failed[r.Module] = append(failed[r.Module], item)
+		} else {
+			applied[r.Module]++
+		}
+	}
+
+	for m := range applied {
+		state.RecordModuleRun(m, applied[m], failed[m])
+	}
+	for m := range failed {
+		if _, ok := applied[m]; !ok {
+			state.RecordModuleRun(m, 0, failed[m])
+		}
+	}
+}
+
+// recordApplied stores the hashes of what each module in results (and any
+// explicitly applied modules) now has on this machine, and the tools it
+// installed, so status can tell synced from pending or drifted
+func recordApplied(cfg *config.PactConfig, results []Result, modules ...string) {
+	failed := make(map[string]bool)
+	placed := make(map[string]map[string]bool)
+	installed := make(map[string][]string)
+	for _, m := range modules {
+		failed[m] = false
+	}
+	for _, r := range results {
+		if r.Error != nil || r.Deferred {
+			failed[r.Module] = true
+			continue
+		}
+		if _, ok := failed[r.Module]; !ok {
+			failed[r.Module] = false
+		}
+		if r.Category == "install" && r.Success {
+			installed[r.Module] = append(installed[r.Module], r.Name)
+		}
+		if r.Category == "file" && r.Success && !r.Kept {
+			if placed[r.Module] == nil {
+				placed[r.Module] = make(map[string]bool)
+			}
+			placed[r.Module][r.Name] = true
+		}
+	}
+
+	for module, moduleFailed := range failed {
+		items, _ := cfg.GetSyncItemsForModule(module)
+		var files []config.SyncItem
+		for _, item := range items {
+			if placed[module][item.Name] {
+				files = append(files, item)
+			}
+		}
+		state.RecordApplied(cfg, module, !moduleFailed, files, installed[module])
+	}
+}
+
+// recordDeferred replaces the deferred items of every module in results (and
+// any explicitly applied modules) with the ones deferred this run
+func recordDeferred(results []Result, modules ...string) {
+	var items []state.DeferredItem
+	for _, r := range results {
+		modules = append(modules, r.Module)
+		if r.Deferred {
+			items = append(items, state.DeferredItem{Module: r.Module, Name: r.Name, Since: time.Now()})
+		}
+	}
+	state.SetDeferred(modules, items)
+}
+
+// Uninstall removes something pact installed, using the backend recorded in
+// the install journal
+func Uninstall(entry state.Entry) error {
+	name := entry.Package
+	if name == "" {
+		name = entry.Name
+	}
+
+	var cmd *exec.Cmd
+	switch entry.Backend {
+	case "brew-cask":
+		args := pkg.Brew{}.UninstallApp(name)
+		cmd = exec.Command(args[0], args[1:]...)
+	case "brew-tap":
+		cmd = exec.Command("brew", "untap", name)
+	case "mas":
+		cmd = exec.Command("sudo", "mas", "uninstall", name)
+	case "npm":
+		cmd = exec.Command("npm", "uninstall", "-g", name)
+	case "pipx":
+		cmd = exec.Command("pipx", "uninstall", name)
+	case "cargo":
+		cmd = exec.Command("cargo", "uninstall", name)
+	case "code", "vscode":
+		cmd = exec.Command("code", "--uninstall-extension", name)
+	case "cursor":
+		cmd = exec.Command("cursor", "--uninstall-extension", name)
+	case "binary", "files":
+		for _, p := range entry.Paths {
+			if err := os.RemoveAll(p); err != nil {
+				return err
+			}
+		}
+		if entry.Module == "terminal" && runtime.GOOS == "linux" {
+			exec.Command("fc-cache", "-f").Run()
+		}
+		return nil
+	case "startup":
+		return removeStartupItem(entry)
+	case "script":
+		return fmt.Errorf("installed by a script; remove it manually")
+	default:
+		m := pkg.Get(entry.Backend)
+		if m == nil {
+			return fmt.Errorf("don't know how to uninstall from %s", entry.Backend)
+		}
+		args := m.Uninstall(name)
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, string(output))
+	}
+	return nil
+}
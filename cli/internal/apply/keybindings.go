@@ -0,0 +1,468 @@
+package apply
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// editorModuleKeys are keys of the keybindings and snippets modules that
+// aren't editors
+var editorModuleKeys = map[string]bool{
+	"strategy":     true,
+	"confirm":      true,
+	"files":        true,
+	"hooks":        true,
+	"needs":        true,
+	"description":  true,
+	"descriptions": true,
+}
+
+// editorSource is one editor's entry in the keybindings or snippets module
+type editorSource struct {
+	editor   string
+	source   string // Relative to the pact repo
+	strategy string // "merge" or "replace"
+}
+
+// editorSources reads a keybindings or snippets module, where each editor
+// maps to a path in the pact repo or to {"source": ..., "strategy": ...}:
+//
+//	"keybindings": {
+//	  "strategy": "merge",
+//	  "vscode": "keybindings/vscode.json",
+//	  "zed": {"source": "keybindings/zed.json", "strategy": "replace"}
+//	}
+//
+// merge (the default) keeps local entries pact doesn't define; replace
+// writes pact's copy as is.
+func editorSources(cfg *config.PactConfig, module string) ([]editorSource, []Result) {
+	entries := cfg.GetMap(module)
+	defaultStrategy := cfg.GetString(module + ".strategy")
+	if defaultStrategy == "" {
+		defaultStrategy = "merge"
+	}
+
+	editors := make([]string, 0, len(entries))
+	for editor := range entries {
+		if !editorModuleKeys[editor] {
+			editors = append(editors, editor)
+		}
+	}
+	sort.Strings(editors)
+
+	var sources []editorSource
+	var failed []Result
+	for _, editor := range editors {
+		entry := editorSource{editor: editor, strategy: defaultStrategy}
+		switch v := entries[editor].(type) {
+		case string:
+			entry.source = v
+		case map[string]any:
+			entry.source, _ = v["source"].(string)
+			if s, ok := v["strategy"].(string); ok {
+				entry.strategy = s
+			}
+		}
+
+		var err error
+		switch {
+		case !containsArg(config.KeybindingEditors, editor):
+			err = fmt.Errorf("unsupported editor '%s' (use %s)", editor, strings.Join(config.KeybindingEditors, ", "))
+		case entry.source == "":
+			err = fmt.Errorf("no source set")
+		case entry.strategy != "merge" && entry.strategy != "replace":
+			err = fmt.Errorf("unknown strategy '%s' (use merge or replace)", entry.strategy)
+		}
+		if err != nil {
+			failed = append(failed, Result{Category: "file", Module: module, Name: editor, Error: err})
+			continue
+		}
+		sources = append(sources, entry)
+	}
+	return sources, failed
+}
+
+// managedFile is a file pact is about to write with its new content
+type managedFile struct {
+	path    string
+	content []byte
+}
+
+// applyKeybindings places each editor's keybindings, merging them into the
+// editor's own file for VS Code, Cursor, and Zed
+func applyKeybindings(cfg *config.PactConfig, opts Options) []Result {
+	sources, results := editorSources(cfg, "keybindings")
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return append(results, Result{Category: "file", Module: "keybindings", Name: "keybindings", Error: err})
+	}
+
+	for _, s := range sources {
+		results = append(results, placeKeybindings(s, "keybindings", pactDir, opts))
+	}
+
+	return append(results, applyModuleFiles(cfg, "keybindings", opts)...)
+}
+
+// applySnippets copies each editor's snippet files into its snippets
+// directory. With merge, JSON snippet files that already exist keep the
+// snippets pact doesn't define.
+func applySnippets(cfg *config.PactConfig, opts Options) []Result {
+	sources, results := editorSources(cfg, "snippets")
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return append(results, Result{Category: "file", Module: "snippets", Name: "snippets", Error: err})
+	}
+
+	for _, s := range sources {
+		results = append(results, placeSnippets(s, "snippets", pactDir, opts))
+	}
+
+	return append(results, applyModuleFiles(cfg, "snippets", opts)...)
+}
+
+// placeKeybindings writes one editor's keybindings from the pact repo,
+// reported under module
+func placeKeybindings(s editorSource, module, pactDir string, opts Options) Result {
+	result := Result{Category: "file", Module: module, Name: s.editor}
+	managed, err := os.ReadFile(filepath.Join(pactDir, s.source))
+	if err != nil {
+		result.Error = fmt.Errorf("source not found: %s", s.source)
+		return result
+	}
+
+	target := config.KeybindingsPath(s.editor)
+	content, mode := managed, "replace"
+	if local, err := os.ReadFile(target); err == nil && s.strategy == "merge" {
+		switch s.editor {
+		case "vscode", "cursor":
+			content, err = mergeVSCodeKeybindings(local, managed)
+			mode = "merge"
+		case "zed":
+			content, err = mergeZedKeymap(local, managed)
+			mode = "merge"
+		}
+		if err != nil {
+			result.Error = fmt.Errorf("can't merge into %s: %w", target, err)
+			return result
+		}
+	}
+	return writeManagedFiles(result, []managedFile{{target, content}}, mode, opts)
+}
+
+// placeSnippets writes one editor's snippets from the pact repo, reported
+// under module
+func placeSnippets(s editorSource, module, pactDir string, opts Options) Result {
+	result := Result{Category: "file", Module: module, Name: s.editor}
+	files, err := snippetFiles(filepath.Join(pactDir, s.source), config.SnippetsDir(s.editor))
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	mode := "replace"
+	for i, f := range files {
+		local, err := os.ReadFile(f.path)
+		if err != nil || s.strategy != "merge" || !isSnippetJSON(f.path) {
+			continue
+		}
+		if files[i].content, err = mergeSnippets(local, f.content); err != nil {
+			result.Error = fmt.Errorf("can't merge into %s: %w", f.path, err)
+			return result
+		}
+		mode = "merge"
+	}
+	return writeManagedFiles(result, files, mode, opts)
+}
+
+// snippetFiles reads a snippet file, or every file under a snippet
+// directory, paired with where it goes in dest
+func snippetFiles(source, dest string) ([]managedFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("source not found: %s", source)
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		return []managedFile{{filepath.Join(dest, filepath.Base(source)), content}}, nil
+	}
+
+	var files []managedFile
+	err = filepath.Walk(source, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files = append(files, managedFile{filepath.Join(dest, rel), content})
+		return nil
+	})
+	return files, err
+}
+
+func isSnippetJSON(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".json" || ext == ".code-snippets"
+}
+
+// writeManagedFiles writes the files that differ from what's on disk,
+// reporting them as one result; mode is "merge" when any were merged
+func writeManagedFiles(result Result, files []managedFile, mode string, opts Options) Result {
+	var changed []managedFile
+	for _, f := range files {
+		if local, err := os.ReadFile(f.path); err == nil && bytes.Equal(local, f.content) {
+			continue
+		}
+		if err := allowPath(f.path, opts); err != nil {
+			result.Error = err
+			return result
+		}
+		changed = append(changed, f)
+	}
+
+	if len(changed) == 0 {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already up to date"
+		return result
+	}
+
+	describe := changed[0].path
+	if len(changed) > 1 {
+		describe = fmt.Sprintf("%d files in %s", len(changed), filepath.Dir(changed[0].path))
+	}
+	if opts.DryRun {
+		if mode == "merge" {
+			return planned(result, "merge into %s", describe)
+		}
+		return planned(result, "write %s", describe)
+	}
+
+	for _, f := range changed {
+		if err := opts.Undo.File(f.path); err != nil {
+			result.Error = fmt.Errorf("failed to back up %s: %w", f.path, err)
+			return result
+		}
+		if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+			result.Error = err
+			return result
+		}
+		if err := os.WriteFile(f.path, f.content, 0644); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+
+	result.Success = true
+	result.Message = "wrote " + describe
+	if mode == "merge" {
+		result.Message = "merged into " + describe
+	}
+	return result
+}
+
+// mergeVSCodeKeybindings adds pact's keybindings to a local keybindings.json.
+// Local entries bound to the same key and when clause as a pact entry are
+// dropped; the rest keep their order ahead of pact's, so pact's win.
+func mergeVSCodeKeybindings(local, managed []byte) ([]byte, error) {
+	var localEntries, managedEntries []json.RawMessage
+	if err := unmarshalJSONC(local, &localEntries); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSONC(managed, &managedEntries); err != nil {
+		return nil, err
+	}
+
+	type binding struct {
+		Key  string `json:"key"`
+		When string `json:"when"`
+	}
+	identity := func(entry json.RawMessage) binding {
+		var b binding
+		json.Unmarshal(entry, &b)
+		b.Key = strings.ToLower(b.Key)
+		return b
+	}
+
+	bound := make(map[binding]bool)
+	for _, entry := range managedEntries {
+		bound[identity(entry)] = true
+	}
+	merged := []json.RawMessage{}
+	for _, entry := range localEntries {
+		if !bound[identity(entry)] {
+			merged = append(merged, entry)
+		}
+	}
+	merged = append(merged, managedEntries...)
+	return marshalMerged(local, localEntries, merged)
+}
+
+// mergeZedKeymap adds pact's bindings to a local Zed keymap.json. Blocks
+// with the same context are combined, pact's binding winning for a key.
+func mergeZedKeymap(local, managed []byte) ([]byte, error) {
+	var localBlocks, managedBlocks []map[string]json.RawMessage
+	if err := unmarshalJSONC(local, &localBlocks); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSONC(managed, &managedBlocks); err != nil {
+		return nil, err
+	}
+
+	contextOf := func(block map[string]json.RawMessage) string {
+		var c string
+		json.Unmarshal(block["context"], &c)
+		return c
+	}
+
+	merged := make([]map[string]json.RawMessage, 0, len(localBlocks))
+	for _, block := range localBlocks {
+		copied := make(map[string]json.RawMessage, len(block))
+		for k, v := range block {
+			copied[k] = v
+		}
+		merged = append(merged, copied)
+	}
+
+	for _, block := range managedBlocks {
+		var target map[string]json.RawMessage
+		for _, existing := range merged {
+			if contextOf(existing) == contextOf(block) {
+				target = existing
+				break
+			}
+		}
+		if target == nil {
+			merged = append(merged, block)
+			continue
+		}
+
+		bindings := map[string]json.RawMessage{}
+		json.Unmarshal(target["bindings"], &bindings)
+		var add map[string]json.RawMessage
+		json.Unmarshal(block["bindings"], &add)
+		for key, action := range add {
+			bindings[key] = action
+		}
+		for k, v := range block {
+			target[k] = v
+		}
+		encoded, err := json.Marshal(bindings)
+		if err != nil {
+			return nil, err
+		}
+		target["bindings"] = encoded
+	}
+	return marshalMerged(local, localBlocks, merged)
+}
+
+// mergeSnippets adds pact's snippets to a local snippet file, replacing
+// snippets with the same name
+func mergeSnippets(local, managed []byte) ([]byte, error) {
+	var localSnippets, managedSnippets map[string]json.RawMessage
+	if err := unmarshalJSONC(local, &localSnippets); err != nil {
+		return nil, err
+	}
+	if err := unmarshalJSONC(managed, &managedSnippets); err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]json.RawMessage, len(localSnippets)+len(managedSnippets))
+	for name, snippet := range localSnippets {
+		merged[name] = snippet
+	}
+	for name, snippet := range managedSnippets {
+		merged[name] = snippet
+	}
+	return marshalMerged(local, localSnippets, merged)
+}
+
+// marshalMerged formats a merge result, returning the local file untouched
+// when the merge added nothing so its comments survive
+func marshalMerged(local []byte, before, after any) ([]byte, error) {
+	out, err := json.MarshalIndent(after, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if original, err := json.Marshal(before); err == nil {
+		var compact bytes.Buffer
+		if json.Compact(&compact, out) == nil && bytes.Equal(compact.Bytes(), original) {
+			return local, nil
+		}
+	}
+	return append(out, '\n'), nil
+}
+
+// unmarshalJSONC decodes JSON with comments and trailing commas, as VS Code
+// and Zed allow. A file with nothing but comments decodes as empty.
+func unmarshalJSONC(data []byte, v any) error {
+	data = stripJSONC(data)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stripJSONC removes // and /* */ comments and trailing commas outside of
+// strings
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return out
+			}
+			i += end + 3
+		case c == ']' || c == '}':
+			last := len(bytes.TrimRight(out, " \t\r\n"))
+			if last > 0 && out[last-1] == ',' {
+				out = append(out[:last-1], out[last:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+	return out
+}
@@ -0,0 +1,131 @@
+package apply
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestStripJSONC(t *testing.T) {
+	in := `// Place your key bindings in this file
+[
+  /* a block
+     comment */
+  {"key": "ctrl+k", "command": "a // not a comment",},
+]`
+	var got []map[string]string
+	if err := json.Unmarshal(stripJSONC([]byte(in)), &got); err != nil {
+		t.Fatalf("stripJSONC() left invalid JSON: %v", err)
+	}
+	want := []map[string]string{{"key": "ctrl+k", "command": "a // not a comment"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("stripJSONC() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeVSCodeKeybindings(t *testing.T) {
+	local := []byte(`// comment
+[
+  {"key": "ctrl+k", "command": "old"},
+  {"key": "ctrl+j", "command": "mine"}
+]`)
+	managed := []byte(`[{"key": "Ctrl+K", "command": "new"}]`)
+
+	out, err := mergeVSCodeKeybindings(local, managed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []map[string]string
+	json.Unmarshal(out, &got)
+	want := []map[string]string{
+		{"key": "ctrl+j", "command": "mine"},
+		{"key": "Ctrl+K", "command": "new"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergeVSCodeKeybindings() = %v, want %v", got, want)
+	}
+
+	// Merging again changes nothing
+	again, err := mergeVSCodeKeybindings(out, managed)
+	if err != nil || string(again) != string(out) {
+		t.Fatalf("second merge = %s, %v; want it unchanged", again, err)
+	}
+}
+
+func TestMergeZedKeymap(t *testing.T) {
+	local := []byte(`[
+  {"context": "Editor", "bindings": {"ctrl-d": "editor::Delete", "ctrl-s": "workspace::Save"}}
+]`)
+	managed := []byte(`[
+  {"context": "Editor", "bindings": {"ctrl-d": "editor::DuplicateLine"}},
+  {"context": "Terminal", "bindings": {"ctrl-t": "terminal::New"}}
+]`)
+
+	out, err := mergeZedKeymap(local, managed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []struct {
+		Context  string            `json:"context"`
+		Bindings map[string]string `json:"bindings"`
+	}
+	json.Unmarshal(out, &got)
+	if len(got) != 2 {
+		t.Fatalf("mergeZedKeymap() has %d blocks, want 2:\n%s", len(got), out)
+	}
+	if got[0].Bindings["ctrl-d"] != "editor::DuplicateLine" || got[0].Bindings["ctrl-s"] != "workspace::Save" {
+		t.Fatalf("Editor bindings = %v", got[0].Bindings)
+	}
+	if got[1].Context != "Terminal" {
+		t.Fatalf("second block context = %q, want Terminal", got[1].Context)
+	}
+}
+
+func TestMergeSnippets(t *testing.T) {
+	local := []byte(`{"log": {"prefix": "log", "body": "console.log()"}, "mine": {"prefix": "m", "body": "x"}}`)
+	managed := []byte(`{"log": {"prefix": "log", "body": "console.log($1)"}}`)
+
+	out, err := mergeSnippets(local, managed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]struct{ Body string }
+	json.Unmarshal(out, &got)
+	if got["log"].Body != "console.log($1)" || got["mine"].Body != "x" {
+		t.Fatalf("mergeSnippets() = %s", out)
+	}
+}
+
+func TestMergeSettings(t *testing.T) {
+	local := []byte(`{
+  // my font
+  "editor.fontSize": 12,
+  "workbench.colorTheme": "Nord",
+  "[python]": {"editor.tabSize": 2, "editor.rulers": [88]},
+}`)
+	settings := map[string]any{
+		"editor.fontSize": float64(14),
+		"[python]":        map[string]any{"editor.tabSize": float64(4)},
+	}
+
+	out, err := mergeSettings(local, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	json.Unmarshal(out, &got)
+	want := map[string]any{
+		"editor.fontSize":      float64(14),
+		"workbench.colorTheme": "Nord",
+		"[python]":             map[string]any{"editor.tabSize": float64(4), "editor.rulers": []any{float64(88)}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergeSettings() = %v, want %v", got, want)
+	}
+
+	// Merging again leaves the file alone
+	again, err := mergeSettings(out, settings)
+	if err != nil || string(again) != string(out) {
+		t.Fatalf("second merge = %s, %v; want it unchanged", again, err)
+	}
+}
@@ -0,0 +1,146 @@
+package apply
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// licensePrompt is an installer prompt that waits for a license to be
+// accepted, which never happens with no terminal attached
+type licensePrompt struct {
+	patterns []string // Output that shows the prompt (lowercase)
+	notice   string   // What the user has to do
+	fix      []string // Command to run interactively before retrying, if any
+}
+
+var licensePrompts = []licensePrompt{
+	{
+		patterns: []string{"agreeing to the xcode/ios license", "you have not agreed to the xcode license"},
+		notice:   "accept the Xcode license with 'sudo xcodebuild -license'",
+		fix:      []string{"sudo", "xcodebuild", "-license"},
+	},
+	{
+		patterns: []string{"no developer tools were found"},
+		notice:   "finish installing the Xcode Command Line Tools (xcode-select --install)",
+	},
+	{
+		patterns: []string{"do you agree to all the source agreements terms", "do you agree to the terms", "package agreements were not agreed to"},
+		notice:   "review and accept the winget agreement",
+	},
+	{
+		patterns: []string{"do you accept the eula license terms", "configuring ttf-mscorefonts-installer"},
+		notice:   "review and accept the package's EULA",
+	},
+}
+
+// LicenseError reports an install that stopped on a license prompt
+type LicenseError struct {
+	Command string
+	Notice  string
+}
+
+func (e *LicenseError) Error() string {
+	return fmt.Sprintf("%s is waiting on a license: %s, then sync again", e.Command, e.Notice)
+}
+
+// matchLicensePrompt returns the license prompt in an installer's output
+func matchLicensePrompt(output string) *licensePrompt {
+	output = strings.ToLower(output)
+	for i := range licensePrompts {
+		for _, pattern := range licensePrompts[i].patterns {
+			if strings.Contains(output, pattern) {
+				return &licensePrompts[i]
+			}
+		}
+	}
+	return nil
+}
+
+// licenseWatcher collects a command's output and kills it as soon as it
+// shows a license prompt
+type licenseWatcher struct {
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	output bytes.Buffer
+	prompt *licensePrompt
+
+	// line is where the unfinished last line starts. Prompts fit on one
+	// line, so only it and what follows need matching on each write.
+	line int
+}
+
+func (w *licenseWatcher) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.output.Write(p)
+	if w.prompt == nil {
+		unmatched := w.output.Bytes()[w.line:]
+		if w.prompt = matchLicensePrompt(string(unmatched)); w.prompt != nil && w.cmd.Process != nil {
+			w.cmd.Process.Kill()
+		}
+		if i := bytes.LastIndexByte(unmatched, '\n'); i >= 0 {
+			w.line += i + 1
+		}
+	}
+	return len(p), nil
+}
+
+// terminalMu keeps license prompts and interactive re-runs from overlapping
+var terminalMu sync.Mutex
+
+// runInstall runs an install command like CombinedOutput, except a command
+// that blocks on a license prompt is stopped instead of hanging. With
+// opts.AcceptLicense set and agreed to, the step is re-run attached to the
+// terminal so the license can be accepted.
+func runInstall(cmd *exec.Cmd, opts Options) ([]byte, error) {
+	w := &licenseWatcher{cmd: cmd}
+	cmd.Stdout = w
+	cmd.Stderr = w
+	err := cmd.Run()
+	if w.prompt == nil {
+		return w.output.Bytes(), err
+	}
+
+	licenseErr := &LicenseError{Command: commandLine(cmd), Notice: w.prompt.notice}
+	// Parallel installs take turns at the terminal
+	terminalMu.Lock()
+	defer terminalMu.Unlock()
+	if opts.AcceptLicense == nil || !opts.AcceptLicense(licenseErr.Command, licenseErr.Notice) {
+		return w.output.Bytes(), licenseErr
+	}
+
+	if len(w.prompt.fix) > 0 {
+		if err := runAttached(exec.Command(w.prompt.fix[0], w.prompt.fix[1:]...)); err != nil {
+			return nil, licenseErr
+		}
+	}
+	retry := exec.Command(cmd.Path, cmd.Args[1:]...)
+	retry.Env = cmd.Env
+	return nil, runAttached(retry)
+}
+
+// installError describes a failed install, leaving out the installer's
+// output when it stopped on a license prompt
+func installError(err error, output []byte) error {
+	if _, ok := err.(*LicenseError); ok {
+		return err
+	}
+	return fmt.Errorf("%v: %s", err, string(output))
+}
+
+// AttachedOutput receives the output of commands run on the user's
+// terminal. sync --ci points it at stderr, keeping stdout for its report.
+var AttachedOutput io.Writer = os.Stdout
+
+// runAttached runs a command on the user's terminal
+func runAttached(cmd *exec.Cmd) error {
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = AttachedOutput
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
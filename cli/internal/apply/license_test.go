@@ -0,0 +1,32 @@
+package apply
+
+import (
+	"os/exec"
+	"testing"
+)
+
+func TestMatchLicensePrompt(t *testing.T) {
+	xcode := "Error: You have not agreed to the Xcode license. Please resolve this by running:\n  sudo xcodebuild -license accept"
+	if p := matchLicensePrompt(xcode); p == nil || len(p.fix) == 0 {
+		t.Fatalf("matchLicensePrompt() missed the Xcode license prompt")
+	}
+
+	winget := "The `msstore` source requires that you view the following agreements before using.\nDo you agree to all the source agreements terms?\n[Y] Yes  [N] No:"
+	if p := matchLicensePrompt(winget); p == nil {
+		t.Fatalf("matchLicensePrompt() missed the winget agreement prompt")
+	}
+
+	if p := matchLicensePrompt("==> Pouring jq--1.7.1.arm64_sonoma.bottle.tar.gz\n🍺  jq was successfully installed!"); p != nil {
+		t.Fatalf("matchLicensePrompt() matched a normal install: %s", p.notice)
+	}
+}
+
+func TestLicenseWatcherSplitWrites(t *testing.T) {
+	w := &licenseWatcher{cmd: &exec.Cmd{}}
+	for _, chunk := range []string{"Found Microsoft Store source\nDo you agree to all the ", "source agreements ", "terms?\n[Y] Yes  [N] No: "} {
+		w.Write([]byte(chunk))
+	}
+	if w.prompt == nil {
+		t.Fatalf("licenseWatcher missed a prompt written in pieces")
+	}
+}
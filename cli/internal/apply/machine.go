@@ -0,0 +1,233 @@
+package apply
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// applyMachine sets up the OS on a new machine:
+//
+//	"machine": {"hostname": "jack-mbp", "timezone": "America/New_York", "locale": "en_US.UTF-8"}
+//
+// Each value is a string or an object per OS ({"darwin": ..., "windows": ...}),
+// since Windows names time zones ("Eastern Standard Time") and locales
+// ("en-US") its own way. The hostname is only changed after confirmation.
+func applyMachine(cfg *config.PactConfig, opts Options) []Result {
+	return applySystemSettings("machine", machineSettings(cfg, runtime.GOOS), opts)
+}
+
+// machineSettings maps the machine module to goos's settings
+func machineSettings(cfg *config.PactConfig, goos string) []systemSetting {
+	hostname := osValue(cfg.Get("machine.hostname"), goos)
+	timezone := osValue(cfg.Get("machine.timezone"), goos)
+	locale := osValue(cfg.Get("machine.locale"), goos)
+
+	var settings []systemSetting
+	switch goos {
+	case "darwin":
+		if hostname != "" {
+			// ComputerName is the display name; LocalHostName (Bonjour)
+			// and HostName only allow letters, digits, and hyphens
+			local := localHostname(hostname)
+			settings = append(settings, systemSetting{
+				name:    "hostname",
+				read:    []string{"scutil", "--get", "ComputerName"},
+				want:    hostname,
+				write:   []string{"sudo", "sh", "-c", `scutil --set ComputerName "$1" && scutil --set LocalHostName "$2" && scutil --set HostName "$2"`, "sh", hostname, local},
+				parse:   strings.TrimSpace,
+				confirm: true,
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"sudo", "sh", "-c", `scutil --set ComputerName "$1" && scutil --set LocalHostName "$2" && scutil --set HostName "$2"`, "sh", current, localHostname(current)}
+				},
+			})
+		}
+		if timezone != "" {
+			settings = append(settings, systemSetting{
+				name:  "timezone",
+				read:  []string{"readlink", "/etc/localtime"},
+				want:  timezone,
+				write: []string{"sudo", "systemsetup", "-settimezone", timezone},
+				parse: zoneFromLocaltime,
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"sudo", "systemsetup", "-settimezone", current}
+				},
+			})
+		}
+		if locale != "" {
+			// AppleLocale has no encoding suffix
+			settings = append(settings, defaultsSetting("locale", "-g", "AppleLocale", "-string", strings.SplitN(locale, ".", 2)[0]))
+		}
+
+	case "linux":
+		if hostname != "" {
+			settings = append(settings, systemSetting{
+				name:    "hostname",
+				read:    []string{"hostname"},
+				want:    hostname,
+				write:   []string{"sudo", "hostnamectl", "set-hostname", hostname},
+				confirm: true,
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"sudo", "hostnamectl", "set-hostname", current}
+				},
+			})
+		}
+		if timezone != "" {
+			settings = append(settings, systemSetting{
+				name:  "timezone",
+				read:  []string{"timedatectl", "show", "--property=Timezone", "--value"},
+				want:  timezone,
+				write: []string{"sudo", "timedatectl", "set-timezone", timezone},
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"sudo", "timedatectl", "set-timezone", current}
+				},
+			})
+		}
+		if locale != "" {
+			settings = append(settings, systemSetting{
+				name:  "locale",
+				read:  []string{"localectl", "status"},
+				want:  locale,
+				write: []string{"sudo", "localectl", "set-locale", "LANG=" + locale},
+				parse: langFromLocalectl,
+				after: "takes effect at next login",
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"sudo", "localectl", "set-locale", "LANG=" + current}
+				},
+			})
+		}
+
+	case "windows":
+		if hostname != "" {
+			setting := systemSetting{
+				name:    "hostname",
+				read:    []string{"hostname"},
+				want:    hostname,
+				write:   renameComputer(hostname),
+				confirm: true,
+				after:   "takes effect after a restart",
+				restore: func(current string) []string {
+					if !validWindowsHostname(current) {
+						return nil
+					}
+					return renameComputer(current)
+				},
+			}
+			if !validWindowsHostname(hostname) {
+				setting.err = fmt.Errorf("%q isn't a valid Windows computer name: use up to 15 letters, digits, and hyphens, not all digits", hostname)
+			}
+			settings = append(settings, setting)
+		}
+		if timezone != "" {
+			settings = append(settings, systemSetting{
+				name:  "timezone",
+				read:  []string{"tzutil", "/g"},
+				want:  timezone,
+				write: []string{"tzutil", "/s", timezone},
+				parse: strings.TrimSpace,
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"tzutil", "/s", current}
+				},
+			})
+		}
+		if locale != "" {
+			settings = append(settings, systemSetting{
+				name:  "locale",
+				read:  []string{"powershell", "-NoProfile", "-Command", "(Get-Culture).Name"},
+				want:  locale,
+				write: []string{"powershell", "-NoProfile", "-Command", "Set-Culture " + psQuote(locale)},
+				after: "takes effect at next sign-in",
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"powershell", "-NoProfile", "-Command", "Set-Culture " + psQuote(current)}
+				},
+			})
+		}
+	}
+	return settings
+}
+
+// osValue reads a string, or the current OS's entry of a per-OS object
+func osValue(v any, goos string) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		s, _ := v[goos].(string)
+		return s
+	}
+	return ""
+}
+
+var notHostnameChars = regexp.MustCompile(`[^A-Za-z0-9-]+`)
+
+// windowsHostnamePattern is a Windows computer name: DNS's letters, digits,
+// and hyphens, within NetBIOS's 15 characters
+var windowsHostnamePattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,13}[A-Za-z0-9])?$`)
+
+// validWindowsHostname reports whether Windows accepts name as a computer
+// name; it can't be all digits
+func validWindowsHostname(name string) bool {
+	return windowsHostnamePattern.MatchString(name) && strings.Trim(name, "0123456789") != ""
+}
+
+// renameComputer is the command that renames a Windows machine
+func renameComputer(name string) []string {
+	return []string{"powershell", "-NoProfile", "-Command", "Rename-Computer -NewName " + psQuote(name) + " -Force"}
+}
+
+// psQuote quotes s as a PowerShell string literal
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// localHostname turns a display name like "Jack's MacBook" into a valid
+// host name ("Jacks-MacBook")
+func localHostname(name string) string {
+	name = strings.ReplaceAll(name, "'", "")
+	return strings.Trim(notHostnameChars.ReplaceAllString(name, "-"), "-")
+}
+
+// zoneFromLocaltime extracts "America/New_York" from the /etc/localtime
+// link target ".../zoneinfo/America/New_York"
+func zoneFromLocaltime(output string) string {
+	_, zone, found := strings.Cut(strings.TrimSpace(output), "zoneinfo/")
+	if !found {
+		return ""
+	}
+	return zone
+}
+
+// langFromLocalectl extracts LANG from 'localectl status'
+// ("System Locale: LANG=en_US.UTF-8")
+func langFromLocalectl(output string) string {
+	for _, field := range strings.Fields(output) {
+		if value, found := strings.CutPrefix(field, "LANG="); found {
+			return value
+		}
+	}
+	return ""
+}
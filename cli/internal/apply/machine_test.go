@@ -0,0 +1,71 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestMachineSettings(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"machine": {
+		"hostname": "Jack's MacBook",
+		"timezone": {"darwin": "America/New_York", "windows": "Eastern Standard Time"},
+		"locale": "en_US.UTF-8"
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	darwin := machineSettings(cfg, "darwin")
+	if len(darwin) != 3 {
+		t.Fatalf("darwin has %d settings, want 3", len(darwin))
+	}
+	if host := darwin[0]; !host.confirm || host.write[len(host.write)-1] != "Jacks-MacBook" {
+		t.Fatalf("darwin hostname = %+v, want a confirmed change with LocalHostName Jacks-MacBook", host)
+	}
+	if darwin[2].want != "en_US" {
+		t.Fatalf("darwin locale = %q, want en_US", darwin[2].want)
+	}
+
+	// No linux timezone entry
+	linux := machineSettings(cfg, "linux")
+	if len(linux) != 2 || linux[1].name != "locale" {
+		t.Fatalf("linux settings = %+v, want hostname and locale", linux)
+	}
+	if linux[0].restore("") != nil {
+		t.Fatalf("restore with no previous hostname should be nil")
+	}
+
+	windows := machineSettings(cfg, "windows")
+	if windows[0].err == nil {
+		t.Fatalf("windows accepted the hostname %q", windows[0].want)
+	}
+	if tz := windows[1]; tz.want != "Eastern Standard Time" {
+		t.Fatalf("windows timezone = %q", tz.want)
+	}
+}
+
+func TestValidWindowsHostname(t *testing.T) {
+	for name, want := range map[string]bool{
+		"JACK-PC":                 true,
+		"dev01":                   true,
+		"12345":                   false,
+		"-jack":                   false,
+		"jack'; Restart-Computer": false,
+		"a-name-that-is-too-long": false,
+	} {
+		if got := validWindowsHostname(name); got != want {
+			t.Fatalf("validWindowsHostname(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMachineParsers(t *testing.T) {
+	if got := zoneFromLocaltime("/var/db/timezone/zoneinfo/America/New_York\n"); got != "America/New_York" {
+		t.Fatalf("zoneFromLocaltime() = %q", got)
+	}
+	status := "   System Locale: LANG=en_US.UTF-8\n       VC Keymap: us\n"
+	if got := langFromLocalectl(status); got != "en_US.UTF-8" {
+		t.Fatalf("langFromLocalectl() = %q", got)
+	}
+}
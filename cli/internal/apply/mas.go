@@ -0,0 +1,92 @@
+package apply
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// applyMas installs the App Store apps in apps.darwin.mas with the mas CLI,
+// installing mas itself through Homebrew first when it's missing
+func applyMas(apps []config.MasApp, opts Options) []Result {
+	if len(apps) == 0 {
+		return nil
+	}
+
+	var results []Result
+	if !isToolInstalled("mas") {
+		if !isToolInstalled("brew") {
+			return []Result{{Category: "install", Module: "apps", Name: "mas", Error: fmt.Errorf("mas isn't installed and Homebrew isn't available to install it")}}
+		}
+		result := installTool("brew", "mas", opts)
+		result.Module = "apps"
+		results = append(results, result)
+		if result.Error != nil {
+			return results
+		}
+	}
+
+	installed := masInstalled(opts)
+	for _, app := range apps {
+		name := app.Name
+		if name == "" {
+			name = app.ID
+		}
+		result := Result{Category: "app", Module: "apps", Name: name}
+		if installed[app.ID] {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already installed"
+			results = append(results, result)
+			continue
+		}
+		if opts.LowBandwidth {
+			results = append(results, deferResult(result))
+			continue
+		}
+		results = append(results, installMasApp(app.ID, result, opts))
+	}
+	return results
+}
+
+func installMasApp(id string, result Result, opts Options) Result {
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
+	cmd := command(ctx, "mas", "install", id)
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+
+	output, err := runInstall(cmd, opts)
+	if err != nil {
+		result.Error = stepError(ctx, installError(err, output))
+		return result
+	}
+	result.Success = true
+	result.Message = "installed"
+	result.Backend = "mas"
+	result.Package = id
+	return result
+}
+
+// masInstalled returns the IDs of installed App Store apps; `mas list` prints
+// one per line, like "497799835  Xcode  (15.0)". Before mas is installed (in
+// a dry run) nothing counts as installed.
+func masInstalled(opts Options) map[string]bool {
+	installed := make(map[string]bool)
+	output, err := command(opts.ctx(), "mas", "list").Output()
+	if err != nil {
+		return installed
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			installed[fields[0]] = true
+		}
+	}
+	return installed
+}
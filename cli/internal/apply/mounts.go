@@ -0,0 +1,156 @@
+package apply
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// fstabUpdate replaces the fstab entry for a mount point ($2) with a new
+// line ($1) and creates the mount point for the user who ran sudo
+const fstabUpdate = `mkdir -p "$2" && chown "$SUDO_UID:$SUDO_GID" "$2" && ` +
+	`awk -v t="$2" '$2 != t' /etc/fstab > /etc/fstab.pact && printf '%s\n' "$1" >> /etc/fstab.pact && ` +
+	`cat /etc/fstab.pact > /etc/fstab && rm /etc/fstab.pact`
+
+// fstabRemove drops the fstab entry for a mount point ($1)
+const fstabRemove = `awk -v t="$1" '$2 != t' /etc/fstab > /etc/fstab.pact && cat /etc/fstab.pact > /etc/fstab && rm /etc/fstab.pact`
+
+// applyMounts sets up network shares:
+//
+//	"mounts": {
+//	  "media": {"source": "//nas.local/media", "target": "~/mnt/media", "type": "smb"},
+//	  "code": {"source": "nas.local:/export/code", "target": "~/mnt/code", "type": "nfs"}
+//	}
+//
+// Linux gets a user-mountable fstab entry ('mount ~/mnt/media' mounts it
+// without sudo); extra mount options go in "options". Windows maps SMB
+// shares to a drive letter target ("Z:") with 'net use'.
+func applyMounts(cfg *config.PactConfig, opts Options) []Result {
+	return applySystemSettings("mounts", mountSettings(cfg, runtime.GOOS), opts)
+}
+
+// mountSettings maps the mounts module to goos's settings
+func mountSettings(cfg *config.PactConfig, goos string) []systemSetting {
+	names, entries := namedEntries(cfg, "mounts")
+
+	var settings []systemSetting
+	for _, name := range names {
+		entry, _ := entries[name].(map[string]any)
+		source, _ := entry["source"].(string)
+		target, _ := entry["target"].(string)
+		kind, _ := entry["type"].(string)
+		options, _ := entry["options"].(string)
+		if kind == "" {
+			kind = "smb"
+		}
+
+		var err error
+		switch {
+		case source == "" || target == "":
+			err = fmt.Errorf("needs a source and a target")
+		case kind != "smb" && kind != "nfs":
+			err = fmt.Errorf("unknown type '%s' (use smb or nfs)", kind)
+		}
+		if err != nil {
+			settings = append(settings, systemSetting{name: name, err: err})
+			continue
+		}
+		if kind == "smb" {
+			source = "//" + strings.TrimLeft(strings.TrimPrefix(strings.ReplaceAll(source, `\`, "/"), "smb:"), "/")
+		}
+
+		switch goos {
+		case "linux":
+			mountPoint, err := config.ExpandPath(target)
+			if err != nil {
+				settings = append(settings, systemSetting{name: name, err: err})
+				continue
+			}
+			fsType := map[string]string{"smb": "cifs", "nfs": "nfs"}[kind]
+			mountOptions := "noauto,user"
+			if options != "" {
+				mountOptions += "," + options
+			}
+			line := strings.Join([]string{fstabEscape(source), fstabEscape(mountPoint), fsType, mountOptions, "0", "0"}, " ")
+			settings = append(settings, systemSetting{
+				name:  name,
+				read:  []string{"cat", "/etc/fstab"},
+				want:  fstabEscape(source),
+				write: []string{"sudo", "sh", "-c", fstabUpdate, "sh", line, mountPoint},
+				parse: func(output string) string { return fstabSource(output, fstabEscape(mountPoint)) },
+				after: "mount it with 'mount " + mountPoint + "'",
+				restore: func(current string) []string {
+					if current != "" {
+						return nil
+					}
+					return []string{"sudo", "sh", "-c", fstabRemove, "sh", fstabEscape(mountPoint)}
+				},
+			})
+
+		case "windows":
+			if kind != "smb" {
+				settings = append(settings, systemSetting{name: name, manual: "NFS needs the Windows Client for NFS; map it with 'mount' there"})
+				continue
+			}
+			drive := strings.ToUpper(strings.TrimSuffix(target, `\`))
+			if len(drive) != 2 || drive[1] != ':' {
+				settings = append(settings, systemSetting{name: name, err: fmt.Errorf("target should be a drive letter like Z: on Windows")})
+				continue
+			}
+			unc := strings.ReplaceAll(source, "/", `\`)
+			settings = append(settings, systemSetting{
+				name:  name,
+				read:  []string{"net", "use", drive},
+				want:  unc,
+				write: []string{"net", "use", drive, unc, "/persistent:yes"},
+				parse: netUseRemote,
+				restore: func(current string) []string {
+					if current != "" {
+						return nil
+					}
+					return []string{"net", "use", drive, "/delete", "/y"}
+				},
+			})
+
+		case "darwin":
+			command := "mount_smbfs " + source
+			if kind == "nfs" {
+				command = "mount_nfs " + source
+			}
+			settings = append(settings, systemSetting{
+				name:   name,
+				manual: fmt.Sprintf("macOS has no user fstab; mount it with 'mkdir -p %s && %s %s'", target, command, target),
+			})
+		}
+	}
+	return settings
+}
+
+// fstabEscape escapes spaces the way fstab expects
+func fstabEscape(s string) string {
+	return strings.ReplaceAll(s, " ", `\040`)
+}
+
+// fstabSource returns the source of the fstab entry for a mount point
+func fstabSource(fstab, mountPoint string) string {
+	for _, line := range strings.Split(fstab, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && !strings.HasPrefix(fields[0], "#") && fields[1] == mountPoint {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// netUseRemote extracts the share from 'net use Z:' ("Remote name  \\nas\media")
+func netUseRemote(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "Remote name") {
+			fields := strings.Fields(line)
+			return fields[len(fields)-1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestMountSettings(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"mounts": {
+		"media": {"source": "smb://nas.local/Media Files", "target": "/mnt/media", "options": "vers=3.0"},
+		"drive": {"source": "\\\\nas.local\\media", "target": "z:"},
+		"code": {"source": "nas.local:/export/code", "target": "/mnt/code", "type": "nfs"}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linux := mountSettings(cfg, "linux")
+	media := linux[2]
+	if media.want != `//nas.local/Media\040Files` {
+		t.Fatalf("linux media source = %q", media.want)
+	}
+	if line := media.write[len(media.write)-2]; line != `//nas.local/Media\040Files /mnt/media cifs noauto,user,vers=3.0 0 0` {
+		t.Fatalf("fstab line = %q", line)
+	}
+	if linux[0].write[len(linux[0].write)-2] != "nas.local:/export/code /mnt/code nfs noauto,user 0 0" {
+		t.Fatalf("nfs fstab line = %q", linux[0].write)
+	}
+
+	windows := mountSettings(cfg, "windows")
+	if windows[1].want != `\\nas.local\media` || windows[1].write[2] != "Z:" {
+		t.Fatalf("windows drive = %+v", windows[1])
+	}
+	if windows[2].err == nil {
+		t.Fatalf("a path target on Windows should be an error")
+	}
+}
+
+func TestMountParsers(t *testing.T) {
+	fstab := "# /mnt/media was here\nUUID=abc / ext4 defaults 0 1\n//nas/media /mnt/media cifs noauto,user 0 0\n"
+	if got := fstabSource(fstab, "/mnt/media"); got != "//nas/media" {
+		t.Fatalf("fstabSource() = %q", got)
+	}
+	netUse := "Local name        Z:\r\nRemote name       \\\\nas\\media\r\nResource type     Disk\r\n"
+	if got := netUseRemote(netUse); got != `\\nas\media` {
+		t.Fatalf("netUseRemote() = %q", got)
+	}
+}
@@ -0,0 +1,140 @@
+package apply
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// OutdatedPackage is a package pact manages that has an update available
+type OutdatedPackage struct {
+	Module  string `json:"module"`
+	Name    string `json:"name"` // As pact.json names it
+	Backend string `json:"backend"`
+	pkg.Outdated
+}
+
+// Outdated asks each package manager on this machine that can list updates
+// which of the packages pact installs, or pact.json lists, have one. A
+// manager that can't be asked is reported in errs and left out.
+func Outdated(cfg *config.PactConfig, opts Options) (outdated []OutdatedPackage, errs []error) {
+	for _, m := range pkg.Available() {
+		u, ok := m.(pkg.Upgrader)
+		if !ok {
+			continue
+		}
+		managed := managedPackages(cfg, m.Name())
+		if len(managed) == 0 {
+			continue
+		}
+
+		ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+		args := u.Outdated()
+		cmd := command(ctx, args[0], args[1:]...)
+		output, err := cmd.Output()
+		err = stepError(ctx, err)
+		cancel()
+		// Some managers exit non-zero when anything is outdated
+		if _, exited := err.(*exec.ExitError); err != nil && !(exited && len(output) > 0) {
+			errs = append(errs, fmt.Errorf("%s: %v", commandLine(cmd), err))
+			continue
+		}
+		for _, o := range u.ParseOutdated(string(output)) {
+			if item, ok := managed[o.Package]; ok {
+				outdated = append(outdated, OutdatedPackage{Module: item.module, Name: item.name, Backend: m.Name(), Outdated: o})
+			}
+		}
+	}
+	return outdated, errs
+}
+
+// managedItem is the pact.json item a package is installed for
+type managedItem struct {
+	module string
+	name   string
+}
+
+// managedPackages maps the names the given manager knows pact's packages by
+// to their items: what the install journal says pact installed with it,
+// plus the tools and apps pact.json would install with it
+func managedPackages(cfg *config.PactConfig, manager string) map[string]managedItem {
+	managed := make(map[string]managedItem)
+	add := func(name string, item managedItem) {
+		if manager == "brew" {
+			// Tap formulae are listed by their short name
+			name = name[strings.LastIndex(name, "/")+1:]
+		}
+		managed[name] = item
+	}
+
+	if journal, err := state.LoadJournal(); err == nil {
+		for _, e := range journal.Entries {
+			if strings.TrimSuffix(e.Backend, "-cask") != manager {
+				continue
+			}
+			name := e.Package
+			if name == "" {
+				name = e.Name
+			}
+			add(name, managedItem{e.Module, e.Name})
+		}
+	}
+
+	if detectPackageManager() == manager {
+		for _, module := range []string{"cli", "shell"} {
+			for _, p := range cfg.GetPackages(module + ".tools") {
+				name, _ := p.For(manager)
+				add(name, managedItem{module, p.Name})
+			}
+		}
+	}
+	if manager == "brew" {
+		for _, cask := range cfg.GetStringSlice("cli.casks") {
+			add(cask, managedItem{"cli", cask})
+		}
+	}
+	for _, app := range cfg.Apps(runtime.GOOS).Install {
+		if m, name := appManager(app, runtime.GOOS); m != nil && m.Name() == manager {
+			add(name, managedItem{"apps", app.Name})
+		}
+	}
+	return managed
+}
+
+// Upgrade upgrades an outdated package with the manager that reported it
+func Upgrade(p OutdatedPackage, opts Options) Result {
+	result := Result{Category: "install", Module: p.Module, Name: p.Name}
+
+	u, ok := pkg.Get(p.Backend).(pkg.Upgrader)
+	if !ok {
+		result.Error = fmt.Errorf("%s can't upgrade packages", p.Backend)
+		return result
+	}
+
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
+	args := u.Upgrade(p.Package)
+	cmd := command(ctx, args[0], args[1:]...)
+	userScopeArgs(cmd, opts)
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+
+	output, err := runInstall(cmd, opts)
+	if err != nil {
+		result.Error = stepError(ctx, installError(err, output))
+		return result
+	}
+	result.Success = true
+	result.Message = fmt.Sprintf("upgraded %s → %s", p.Installed, p.Available)
+	return result
+}
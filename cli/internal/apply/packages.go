@@ -0,0 +1,125 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// packageTools are the commands that install each cli.<manager> list
+var packageTools = map[string]string{"npm": "npm", "pip": "pipx", "cargo": "cargo", "go": "go"}
+
+// applyPackages installs the global language packages in cli.npm, cli.pip,
+// cli.cargo, and cli.go, one at a time since each manager locks its own
+// install directory
+func applyPackages(cfg *config.PactConfig, opts Options) []Result {
+	var results []Result
+	for _, manager := range config.PackageManagers {
+		specs := cfg.GetStringSlice("cli." + manager)
+		if len(specs) == 0 {
+			continue
+		}
+		// cli.tools may be about to install it
+		tool := packageTools[manager]
+		if !opts.DryRun && !isToolInstalled(tool) {
+			results = append(results, Result{
+				Category: "install",
+				Module:   "cli",
+				Name:     manager,
+				Error:    fmt.Errorf("cli.%s needs %s; add it to cli.tools", manager, tool),
+			})
+			continue
+		}
+
+		installed := installedPackages(manager)
+		results = append(results, installAll(specs, 1, func(spec string) Result {
+			return installPackage(manager, spec, installed, opts)
+		})...)
+	}
+	return results
+}
+
+// installedPackages returns the lowercased names of manager's installed
+// global packages
+func installedPackages(manager string) map[string]bool {
+	installed := make(map[string]bool)
+	args := config.PackageListCommand(manager)
+	if args == nil {
+		return installed
+	}
+	// npm ls exits non-zero over problems with any one package
+	output, _ := exec.Command(args[0], args[1:]...).Output()
+	for _, name := range config.ParsePackageList(manager, output) {
+		installed[strings.ToLower(name)] = true
+	}
+	return installed
+}
+
+// installPackage installs one cli.<manager> entry
+func installPackage(manager, spec string, installed map[string]bool, opts Options) Result {
+	name := config.PackageName(manager, spec)
+	result := Result{Category: "install", Module: "cli", Name: name, Package: name}
+
+	var binary string
+	if manager == "go" {
+		result.Name = config.GoBinaryName(spec)
+		binary = filepath.Join(config.GoBinDir(), result.Name)
+		if runtime.GOOS == "windows" {
+			binary += ".exe"
+		}
+		if _, err := os.Stat(binary); err == nil {
+			installed[strings.ToLower(name)] = true
+		}
+	}
+	if installed[strings.ToLower(name)] {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already installed"
+		return result
+	}
+
+	ctx, cancel := opts.withTimeout(opts.Timeouts.Install)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch manager {
+	case "npm":
+		cmd = command(ctx, "npm", "install", "-g", spec)
+		result.Backend = "npm"
+	case "pip":
+		cmd = command(ctx, "pipx", "install", spec)
+		result.Backend = "pipx"
+	case "cargo":
+		cmd = command(ctx, "cargo", "install", spec)
+		result.Backend = "cargo"
+	case "go":
+		if !strings.Contains(spec, "@") {
+			spec += "@latest"
+		}
+		cmd = command(ctx, "go", "install", spec)
+		result.Backend = "binary"
+		result.Paths = []string{binary}
+	}
+
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	output, err := runInstall(cmd, opts)
+	if err != nil {
+		result.Backend = ""
+		result.Error = stepError(ctx, installError(err, output))
+		return result
+	}
+
+	result.Success = true
+	result.Message = "installed with " + packageTools[manager]
+	return result
+}
@@ -0,0 +1,112 @@
+package apply
+
+import (
+	"fmt"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// diffTool describes a git diff pager pact can install and configure
+type diffTool struct {
+	binary   string
+	packages map[string]string // Package name per package manager
+}
+
+var diffTools = map[string]diffTool{
+	"delta": {
+		binary: "delta",
+		packages: map[string]string{
+			"brew":   "git-delta",
+			"apt":    "git-delta",
+			"dnf":    "git-delta",
+			"pacman": "git-delta",
+			"winget": "dandavison.delta",
+			"scoop":  "delta",
+			"choco":  "delta",
+		},
+	},
+	"difftastic": {
+		binary: "difft",
+		packages: map[string]string{
+			"brew":   "difftastic",
+			"pacman": "difftastic",
+			"winget": "Wilfred.difftastic",
+			"scoop":  "difftastic",
+			"choco":  "difftastic",
+		},
+	},
+}
+
+// applyGitPager installs and configures git.pager, either a tool name or an
+// object:
+//
+//	"pager": {"tool": "delta", "theme": "Dracula", "sideBySide": true}
+func applyGitPager(cfg *config.PactConfig, opts Options) []Result {
+	tool := cfg.GetString("git.pager")
+	if tool == "" {
+		tool = cfg.GetString("git.pager.tool")
+	}
+	if tool == "" {
+		return nil
+	}
+	theme := cfg.GetString("git.pager.theme")
+
+	dt, ok := diffTools[tool]
+	if !ok {
+		return []Result{{
+			Category: "configure",
+			Module:   "git",
+			Name:     "pager",
+			Error:    fmt.Errorf("unknown pager '%s' (use delta or difftastic)", tool),
+		}}
+	}
+
+	var results []Result
+	if !isToolInstalled(dt.binary) {
+		pm := detectPackageManager()
+		pkg, ok := dt.packages[pm]
+		if !ok {
+			results = append(results, Result{
+				Category: "install",
+				Module:   "git",
+				Name:     tool,
+				Error:    fmt.Errorf("%s isn't packaged for %s; install it manually (cargo install %s)", tool, pm, tool),
+			})
+			return results
+		}
+		result := installTool(pm, pkg, opts)
+		result.Module = "git"
+		result.Name = tool
+		results = append(results, result)
+		if !result.Success {
+			return results
+		}
+	}
+
+	settings := [][2]string{}
+	switch tool {
+	case "delta":
+		settings = append(settings,
+			[2]string{"core.pager", "delta"},
+			[2]string{"interactive.diffFilter", "delta --color-only"},
+			[2]string{"delta.navigate", "true"},
+		)
+		if theme != "" {
+			settings = append(settings, [2]string{"delta.syntax-theme", theme})
+		}
+		if cfg.Get("git.pager.sideBySide") == true {
+			settings = append(settings, [2]string{"delta.side-by-side", "true"})
+		}
+	case "difftastic":
+		external := "difft"
+		if theme == "light" || theme == "dark" {
+			external += " --background=" + theme
+		}
+		settings = append(settings, [2]string{"diff.external", external})
+	}
+
+	for _, setting := range settings {
+		results = append(results, setGitConfig(setting[0], setting[1], opts))
+	}
+	return results
+}
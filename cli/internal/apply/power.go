@@ -0,0 +1,271 @@
+package apply
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// powerSource is a power source a timeout can be set for
+type powerSource struct {
+	name     string // "ac" or "battery"
+	pmset    string // pmset flag
+	powercfg string // powercfg suffix
+	pmsetTag string // section in 'pmset -g custom'
+}
+
+var powerSources = []powerSource{
+	{name: "ac", pmset: "-c", powercfg: "ac", pmsetTag: "AC Power:"},
+	{name: "battery", pmset: "-b", powercfg: "dc", pmsetTag: "Battery Power:"},
+}
+
+// windowsPowerPlans are the GUIDs of Windows' built-in power plans
+var windowsPowerPlans = map[string]string{
+	"balanced":    "381b4222-f694-41f0-9685-ff5bb260df2e",
+	"performance": "8c5e7fda-e8bf-4a96-9a85-a6e23a8c635c",
+	"powersaver":  "a1841308-3541-4fab-bc81-f71556f20b4a",
+}
+
+// windowsLidActions are the LIDACTION values
+var windowsLidActions = map[string]int{"nothing": 0, "sleep": 1, "hibernate": 2, "shutdown": 3}
+
+// applyPower tunes sleep and power settings:
+//
+//	"power": {"displaySleep": 10, "sleep": {"ac": 0, "battery": 15}, "hibernate": 60, "lid": "sleep", "plan": "balanced"}
+//
+// Timeouts are minutes (0 for never), either one value or per power source.
+// lid is sleep, hibernate, shutdown, or nothing; plan is balanced,
+// performance, or powersaver.
+func applyPower(cfg *config.PactConfig, opts Options) []Result {
+	return applySystemSettings("power", powerSettings(cfg, runtime.GOOS, isToolInstalled("gsettings"), isToolInstalled("powerprofilesctl")), opts)
+}
+
+// powerSettings maps the power module to goos's settings
+func powerSettings(cfg *config.PactConfig, goos string, hasGsettings, hasPowerProfiles bool) []systemSetting {
+	lid := cfg.GetString("power.lid")
+	plan := cfg.GetString("power.plan")
+
+	var settings []systemSetting
+	if lid != "" {
+		if _, ok := windowsLidActions[lid]; !ok {
+			settings = append(settings, systemSetting{name: "lid", err: fmt.Errorf("unknown lid action %q (use sleep, hibernate, shutdown, or nothing)", lid)})
+			lid = ""
+		}
+	}
+	if plan != "" {
+		if _, ok := windowsPowerPlans[plan]; !ok {
+			settings = append(settings, systemSetting{name: "plan", err: fmt.Errorf("unknown power plan %q (use balanced, performance, or powersaver)", plan)})
+			plan = ""
+		}
+	}
+
+	switch goos {
+	case "darwin":
+		for _, t := range []struct{ key, name, pmset string }{
+			{"power.displaySleep", "display-sleep", "displaysleep"},
+			{"power.sleep", "sleep", "sleep"},
+		} {
+			for _, src := range powerSources {
+				if minutes, ok := powerMinutes(cfg.Get(t.key), src.name); ok {
+					settings = append(settings, pmsetSetting(t.name+"-"+src.name, src, t.pmset, strconv.Itoa(minutes)))
+				}
+			}
+		}
+		if cfg.Get("power.hibernate") != nil {
+			settings = append(settings, systemSetting{name: "hibernate", manual: "macOS decides when to hibernate"})
+		}
+		if lid != "" {
+			settings = append(settings, systemSetting{name: "lid", manual: "macOS always sleeps when the lid closes without an external display"})
+		}
+		if plan != "" {
+			for _, src := range powerSources {
+				settings = append(settings, pmsetSetting("low-power-mode-"+src.name, src, "lowpowermode", flag(plan == "powersaver", "1", "0")))
+			}
+		}
+
+	case "linux":
+		if !hasGsettings {
+			if cfg.HasKey("power.displaySleep") || cfg.HasKey("power.sleep") {
+				settings = append(settings, systemSetting{name: "timeouts", manual: "needs GNOME's gsettings"})
+			}
+		} else {
+			// GNOME blanks the screen after the same idle delay on any source
+			if minutes, ok := powerMinutes(cfg.Get("power.displaySleep"), "ac"); ok {
+				settings = append(settings, gsettingsSetting("display-sleep", "org.gnome.desktop.session", "idle-delay", strconv.Itoa(minutes*60)))
+			}
+			for _, src := range powerSources {
+				if minutes, ok := powerMinutes(cfg.Get("power.sleep"), src.name); ok {
+					settings = append(settings, gsettingsSetting("sleep-"+src.name, "org.gnome.settings-daemon.plugins.power",
+						"sleep-inactive-"+src.name+"-timeout", strconv.Itoa(minutes*60)))
+				}
+			}
+		}
+		if cfg.Get("power.hibernate") != nil {
+			settings = append(settings, systemSetting{name: "hibernate", manual: "set HibernateDelaySec in /etc/systemd/sleep.conf"})
+		}
+		if lid != "" {
+			action := map[string]string{"nothing": "ignore", "sleep": "suspend", "hibernate": "hibernate", "shutdown": "poweroff"}[lid]
+			settings = append(settings, systemSetting{name: "lid", manual: "set HandleLidSwitch=" + action + " in /etc/systemd/logind.conf"})
+		}
+		if plan != "" {
+			profile := map[string]string{"balanced": "balanced", "performance": "performance", "powersaver": "power-saver"}[plan]
+			if hasPowerProfiles {
+				settings = append(settings, systemSetting{
+					name:  "plan",
+					read:  []string{"powerprofilesctl", "get"},
+					want:  profile,
+					write: []string{"powerprofilesctl", "set", profile},
+					restore: func(current string) []string {
+						if current == "" {
+							return nil
+						}
+						return []string{"powerprofilesctl", "set", current}
+					},
+				})
+			} else {
+				settings = append(settings, systemSetting{name: "plan", manual: "needs power-profiles-daemon (powerprofilesctl)"})
+			}
+		}
+
+	case "windows":
+		for _, t := range []struct{ key, name, change, subgroup, setting string }{
+			{"power.displaySleep", "display-sleep", "monitor-timeout", "SUB_VIDEO", "VIDEOIDLE"},
+			{"power.sleep", "sleep", "standby-timeout", "SUB_SLEEP", "STANDBYIDLE"},
+			{"power.hibernate", "hibernate", "hibernate-timeout", "SUB_SLEEP", "HIBERNATEIDLE"},
+		} {
+			for _, src := range powerSources {
+				minutes, ok := powerMinutes(cfg.Get(t.key), src.name)
+				if !ok {
+					continue
+				}
+				change := t.change + "-" + src.powercfg
+				settings = append(settings, systemSetting{
+					name:  t.name + "-" + src.name,
+					read:  []string{"powercfg", "/query", "SCHEME_CURRENT", t.subgroup, t.setting},
+					want:  fmt.Sprintf("0x%08x", minutes*60),
+					write: []string{"powercfg", "/change", change, strconv.Itoa(minutes)},
+					parse: powercfgIndex(src),
+					restore: func(current string) []string {
+						seconds, err := strconv.ParseInt(strings.TrimPrefix(current, "0x"), 16, 64)
+						if err != nil {
+							return nil
+						}
+						return []string{"powercfg", "/change", change, strconv.FormatInt(seconds/60, 10)}
+					},
+				})
+			}
+		}
+		if lid != "" {
+			for _, src := range powerSources {
+				set := fmt.Sprintf("powercfg /set%svalueindex SCHEME_CURRENT SUB_BUTTONS LIDACTION %%s && powercfg /setactive SCHEME_CURRENT", src.powercfg)
+				settings = append(settings, systemSetting{
+					name:  "lid-" + src.name,
+					read:  []string{"powercfg", "/query", "SCHEME_CURRENT", "SUB_BUTTONS", "LIDACTION"},
+					want:  fmt.Sprintf("0x%08x", windowsLidActions[lid]),
+					write: []string{"cmd", "/c", fmt.Sprintf(set, strconv.Itoa(windowsLidActions[lid]))},
+					parse: powercfgIndex(src),
+					restore: func(current string) []string {
+						value, err := strconv.ParseInt(strings.TrimPrefix(current, "0x"), 16, 64)
+						if err != nil {
+							return nil
+						}
+						return []string{"cmd", "/c", fmt.Sprintf(set, strconv.FormatInt(value, 10))}
+					},
+				})
+			}
+		}
+		if plan != "" {
+			settings = append(settings, systemSetting{
+				name:  "plan",
+				read:  []string{"powercfg", "/getactivescheme"},
+				want:  windowsPowerPlans[plan],
+				write: []string{"powercfg", "/setactive", windowsPowerPlans[plan]},
+				parse: activeSchemeGUID,
+				restore: func(current string) []string {
+					if current == "" {
+						return nil
+					}
+					return []string{"powercfg", "/setactive", current}
+				},
+			})
+		}
+	}
+	return settings
+}
+
+// powerMinutes reads a timeout for a power source: one number for every
+// source, or {"ac": 0, "battery": 15}
+func powerMinutes(v any, source string) (int, bool) {
+	if m, ok := v.(map[string]any); ok {
+		v = m[source]
+	}
+	minutes, ok := v.(float64)
+	if !ok || minutes < 0 {
+		return 0, false
+	}
+	return int(minutes), true
+}
+
+// pmsetSetting sets a pmset key for one power source
+func pmsetSetting(name string, src powerSource, key, value string) systemSetting {
+	return systemSetting{
+		name:  name,
+		read:  []string{"pmset", "-g", "custom"},
+		want:  value,
+		write: []string{"sudo", "pmset", src.pmset, key, value},
+		parse: func(output string) string { return pmsetValue(output, src.pmsetTag, key) },
+		restore: func(current string) []string {
+			if current == "" {
+				return nil
+			}
+			return []string{"sudo", "pmset", src.pmset, key, current}
+		},
+	}
+}
+
+// pmsetValue finds key's value in a section of 'pmset -g custom'
+func pmsetValue(output, section, key string) string {
+	inSection := false
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, " ") {
+			inSection = strings.TrimSpace(line) == section
+			continue
+		}
+		fields := strings.Fields(line)
+		if inSection && len(fields) >= 2 && fields[0] == key {
+			return fields[1]
+		}
+	}
+	return ""
+}
+
+// powercfgIndex reads a source's "Current AC/DC Power Setting Index" from
+// 'powercfg /query'
+func powercfgIndex(src powerSource) func(string) string {
+	prefix := fmt.Sprintf("Current %s Power Setting Index:", strings.ToUpper(src.powercfg))
+	return func(output string) string {
+		for _, line := range strings.Split(output, "\n") {
+			if value, found := strings.CutPrefix(strings.TrimSpace(line), prefix); found {
+				return strings.TrimSpace(value)
+			}
+		}
+		return ""
+	}
+}
+
+// activeSchemeGUID extracts the GUID from 'powercfg /getactivescheme'
+// ("Power Scheme GUID: 381b4222-...  (Balanced)")
+func activeSchemeGUID(output string) string {
+	_, rest, found := strings.Cut(output, "GUID:")
+	if !found {
+		return ""
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
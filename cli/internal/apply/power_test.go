@@ -0,0 +1,75 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestPowerSettings(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"power": {
+		"displaySleep": 10,
+		"sleep": {"ac": 0, "battery": 15},
+		"lid": "sleep",
+		"plan": "powersaver"
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"display-sleep-ac":      "0x00000258",
+		"display-sleep-battery": "0x00000258",
+		"sleep-ac":              "0x00000000",
+		"sleep-battery":         "0x00000384",
+		"lid-ac":                "0x00000001",
+		"lid-battery":           "0x00000001",
+		"plan":                  windowsPowerPlans["powersaver"],
+	}
+	windows := powerSettings(cfg, "windows", false, false)
+	if len(windows) != len(want) {
+		t.Fatalf("windows has %d settings, want %d", len(windows), len(want))
+	}
+	for _, s := range windows {
+		if s.want != want[s.name] {
+			t.Fatalf("windows %s = %q, want %q", s.name, s.want, want[s.name])
+		}
+	}
+
+	darwin := powerSettings(cfg, "darwin", false, false)
+	var lowPower int
+	for _, s := range darwin {
+		if s.name == "low-power-mode-battery" || s.name == "low-power-mode-ac" {
+			lowPower++
+			if s.want != "1" {
+				t.Fatalf("darwin %s = %q, want 1", s.name, s.want)
+			}
+		}
+	}
+	if lowPower != 2 {
+		t.Fatalf("darwin low power settings = %d, want 2", lowPower)
+	}
+
+	bad, _ := config.Parse([]byte(`{"power": {"lid": "explode"}}`))
+	if s := powerSettings(bad, "linux", true, true); len(s) != 1 || s[0].err == nil {
+		t.Fatalf("unknown lid action = %+v, want an error", s)
+	}
+}
+
+func TestPowerParsers(t *testing.T) {
+	pmset := "Battery Power:\n lidwake              1\n sleep                1\nAC Power:\n lidwake              1\n sleep                0\n"
+	if got := pmsetValue(pmset, "AC Power:", "sleep"); got != "0" {
+		t.Fatalf("pmsetValue(AC) = %q, want 0", got)
+	}
+	if got := pmsetValue(pmset, "Battery Power:", "sleep"); got != "1" {
+		t.Fatalf("pmsetValue(Battery) = %q, want 1", got)
+	}
+
+	query := "    Current AC Power Setting Index: 0x00000258\r\n    Current DC Power Setting Index: 0x0000012c\r\n"
+	if got := powercfgIndex(powerSources[1])(query); got != "0x0000012c" {
+		t.Fatalf("powercfgIndex(dc) = %q", got)
+	}
+	if got := activeSchemeGUID("Power Scheme GUID: 381b4222-f694-41f0-9685-ff5bb260df2e  (Balanced)"); got != windowsPowerPlans["balanced"] {
+		t.Fatalf("activeSchemeGUID() = %q", got)
+	}
+}
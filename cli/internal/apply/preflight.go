@@ -0,0 +1,195 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Sizes used when nothing better can be looked up
+const (
+	appEstimate      = 500 << 20 // A typical GUI app install
+	fontEstimate     = 100 << 20 // A Nerd Font family zip
+	archiveExpansion = 3         // Extracted font archives are about 3x the zip
+	diskHeadroom     = 1 << 30   // Keep this much free after installing
+)
+
+var preflightClient = &http.Client{Timeout: 10 * time.Second}
+
+// SizeEstimate is the disk space one item is expected to need
+type SizeEstimate struct {
+	Module    string
+	Name      string
+	Bytes     int64
+	Estimated bool // A rough guess rather than a looked-up size
+	Manual    bool // Not downloaded by pact, so not counted in Required
+}
+
+// PreflightReport compares what a sync will download against free space
+type PreflightReport struct {
+	Items    []SizeEstimate
+	Required int64
+	Free     int64 // -1 when free space couldn't be determined
+	Path     string
+}
+
+// OK reports whether there's room for everything plus some headroom
+func (r *PreflightReport) OK() bool {
+	return r.Free < 0 || r.Required == 0 || r.Required+diskHeadroom <= r.Free
+}
+
+// Preflight estimates the download and install size of the fonts, apps,
+// custom tools, and LLM models the given modules would install, and checks
+// it against free space in the home directory
+func Preflight(cfg *config.PactConfig, modules []string, opts Options) *PreflightReport {
+	home, _ := os.UserHomeDir()
+	report := &PreflightReport{Path: home, Free: -1}
+	ctx := opts.ctx()
+
+	for _, module := range modules {
+		switch module {
+		case "cli":
+			for _, tool := range cfg.GetStringSlice("cli.custom") {
+				if isToolInstalled(tool) {
+					continue
+				}
+				if size, ok := customToolSize(ctx, cfg, tool); ok {
+					report.Items = append(report.Items, SizeEstimate{Module: "cli", Name: tool, Bytes: size})
+				}
+			}
+		case "terminal":
+			font := cfg.GetString("terminal.font")
+			if font == "" || opts.LowBandwidth || isFontInstalled(font) {
+				continue
+			}
+			item := SizeEstimate{Module: "terminal", Name: font, Bytes: fontEstimate, Estimated: true}
+			if size := remoteSize(ctx, nerdFontURL(font)); size > 0 {
+				item.Bytes = size * archiveExpansion
+				item.Estimated = false
+			}
+			report.Items = append(report.Items, item)
+		case "apps":
+			if opts.LowBandwidth {
+				continue
+			}
+			for _, app := range cfg.Apps(runtime.GOOS).Install {
+				if isAppInstalled(app) {
+					continue
+				}
+				report.Items = append(report.Items, SizeEstimate{Module: "apps", Name: app.Name, Bytes: appEstimate, Estimated: true})
+			}
+		case "llm":
+			if cfg.GetString("llm.local.runtime") != "ollama" {
+				continue
+			}
+			pulled := ""
+			if output, err := exec.CommandContext(ctx, "ollama", "list").Output(); err == nil {
+				pulled = string(output)
+			}
+			for _, model := range cfg.GetStringSlice("llm.local.models") {
+				if strings.Contains(pulled, model) {
+					continue
+				}
+				if size := ollamaModelSize(ctx, model); size > 0 {
+					report.Items = append(report.Items, SizeEstimate{Module: "llm", Name: model, Bytes: size, Manual: true})
+				}
+			}
+		}
+	}
+
+	for _, item := range report.Items {
+		if !item.Manual {
+			report.Required += item.Bytes
+		}
+	}
+	if free, err := freeSpace(home); err == nil {
+		report.Free = free
+	}
+	return report
+}
+
+// customToolSize returns the download size of a custom tool's release asset
+// or URL
+func customToolSize(ctx context.Context, cfg *config.PactConfig, tool string) (int64, bool) {
+	src, ok := getCustomSource(cfg, tool)
+	if !ok {
+		return 0, false
+	}
+	switch {
+	case src.Repo != "":
+		asset, err := findReleaseAsset(ctx, src)
+		if err != nil {
+			return 0, false
+		}
+		return asset.Size, true
+	case src.URL != "":
+		size := remoteSize(ctx, src.URL)
+		return size, size > 0
+	}
+	return 0, false
+}
+
+// remoteSize returns a download's Content-Length, or 0 if unknown
+func remoteSize(ctx context.Context, url string) int64 {
+	req, err := http.NewRequestWithContext(ctx, "HEAD", url, nil)
+	if err != nil {
+		return 0
+	}
+	resp, err := preflightClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ContentLength < 0 {
+		return 0
+	}
+	return resp.ContentLength
+}
+
+// ollamaModelSize sums the layer sizes of a model's manifest in the ollama
+// registry
+func ollamaModelSize(ctx context.Context, model string) int64 {
+	name, tag, ok := strings.Cut(model, ":")
+	if !ok {
+		tag = "latest"
+	}
+	if !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("https://registry.ollama.ai/v2/%s/manifests/%s", name, tag), nil)
+	if err != nil {
+		return 0
+	}
+	req.Header.Set("Accept", "application/vnd.docker.distribution.manifest.v2+json")
+	resp, err := preflightClient.Do(req)
+	if err != nil {
+		return 0
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0
+	}
+
+	var manifest struct {
+		Layers []struct {
+			Size int64 `json:"size"`
+		} `json:"layers"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return 0
+	}
+	var total int64
+	for _, layer := range manifest.Layers {
+		total += layer.Size
+	}
+	return total
+}
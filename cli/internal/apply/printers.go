@@ -0,0 +1,137 @@
+package apply
+
+import (
+	"fmt"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// namedModuleKeys are keys of the printers and mounts modules that aren't
+// entries
+var namedModuleKeys = map[string]bool{
+	"confirm":      true,
+	"files":        true,
+	"hooks":        true,
+	"needs":        true,
+	"description":  true,
+	"descriptions": true,
+}
+
+// namedEntries returns a module's entries by name, sorted
+func namedEntries(cfg *config.PactConfig, module string) ([]string, map[string]any) {
+	entries := cfg.GetMap(module)
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		if !namedModuleKeys[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, entries
+}
+
+// applyPrinters adds printers by URL:
+//
+//	"printers": {"office": {"url": "ipp://printer.local/ipp/print", "default": true}}
+//
+// An entry can also be just the URL. macOS and Linux add them to CUPS as
+// driverless (IPP Everywhere) printers; Windows connects to shared printers
+// (\\server\printer).
+func applyPrinters(cfg *config.PactConfig, opts Options) []Result {
+	return applySystemSettings("printers", printerSettings(cfg, runtime.GOOS), opts)
+}
+
+// printerSettings maps the printers module to goos's settings
+func printerSettings(cfg *config.PactConfig, goos string) []systemSetting {
+	names, entries := namedEntries(cfg, "printers")
+
+	var settings []systemSetting
+	var defaultPrinter string
+	for _, name := range names {
+		var url string
+		switch v := entries[name].(type) {
+		case string:
+			url = v
+		case map[string]any:
+			url, _ = v["url"].(string)
+			if v["default"] == true {
+				defaultPrinter = name
+			}
+		}
+		if url == "" {
+			settings = append(settings, systemSetting{name: name, err: fmt.Errorf("no url set")})
+			continue
+		}
+
+		switch goos {
+		case "darwin", "linux":
+			settings = append(settings, systemSetting{
+				name:  name,
+				read:  []string{"lpstat", "-v", name},
+				want:  url,
+				write: []string{"sudo", "lpadmin", "-p", name, "-E", "-v", url, "-m", "everywhere"},
+				restore: func(current string) []string {
+					if current == "" {
+						return []string{"sudo", "lpadmin", "-x", name}
+					}
+					return []string{"sudo", "lpadmin", "-p", name, "-v", current}
+				},
+			})
+
+		case "windows":
+			if !strings.HasPrefix(url, `\\`) {
+				settings = append(settings, systemSetting{name: name, manual: "add IPP printers in Settings > Bluetooth & devices > Printers & scanners"})
+				continue
+			}
+			if name == defaultPrinter {
+				defaultPrinter = url
+			}
+			settings = append(settings, systemSetting{
+				name:  name,
+				read:  []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf("Get-Printer -Name '%s' | ForEach-Object Name", url)},
+				want:  url,
+				write: []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf("Add-Printer -ConnectionName '%s'", url)},
+				parse: strings.TrimSpace,
+				restore: func(current string) []string {
+					if current != "" {
+						return nil
+					}
+					return []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf("Remove-Printer -Name '%s'", url)}
+				},
+			})
+		}
+	}
+
+	if defaultPrinter == "" {
+		return settings
+	}
+	switch goos {
+	case "darwin", "linux":
+		settings = append(settings, systemSetting{
+			name:  "default",
+			read:  []string{"lpstat", "-d"},
+			want:  defaultPrinter,
+			write: []string{"lpoptions", "-d", defaultPrinter},
+			restore: func(current string) []string {
+				if current == "" || current == "destination" {
+					return nil
+				}
+				return []string{"lpoptions", "-d", current}
+			},
+		})
+	case "windows":
+		if strings.HasPrefix(defaultPrinter, `\\`) {
+			settings = append(settings, systemSetting{
+				name:  "default",
+				read:  []string{"powershell", "-NoProfile", "-Command", "Get-CimInstance Win32_Printer -Filter 'Default=True' | ForEach-Object Name"},
+				want:  defaultPrinter,
+				write: []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf("(New-Object -ComObject WScript.Network).SetDefaultPrinter('%s')", defaultPrinter)},
+				parse: strings.TrimSpace,
+			})
+		}
+	}
+	return settings
+}
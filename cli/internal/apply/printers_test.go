@@ -0,0 +1,56 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestPrinterSettings(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"printers": {
+		"description": "Office printers",
+		"office": {"url": "ipp://printer.local/ipp/print", "default": true},
+		"lab": "\\\\print01\\lab",
+		"broken": {}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	linux := printerSettings(cfg, "linux")
+	if len(linux) != 4 {
+		t.Fatalf("linux has %d settings, want broken, lab, office, and default", len(linux))
+	}
+	if linux[0].name != "broken" || linux[0].err == nil {
+		t.Fatalf("printer without a url = %+v, want an error", linux[0])
+	}
+	if got := linux[2].restore(""); got[len(got)-1] != "office" || got[2] != "-x" {
+		t.Fatalf("restore of a new printer = %v, want it removed", got)
+	}
+	if linux[3].name != "default" || linux[3].want != "office" {
+		t.Fatalf("default = %+v, want office", linux[3])
+	}
+
+	// Windows only connects to shared printers
+	windows := printerSettings(cfg, "windows")
+	if windows[1].want != `\\print01\lab` || windows[2].manual == "" {
+		t.Fatalf("windows settings = %+v", windows)
+	}
+}
+
+func TestModuleKeysSkipNeeds(t *testing.T) {
+	cfg, _ := config.Parse([]byte(`{
+		"printers": {"needs": ["cli"], "office": "ipp://printer.local/ipp/print"},
+		"keybindings": {"needs": ["editor"], "zed": "keybindings/zed.json"}
+	}`))
+	if names, _ := namedEntries(cfg, "printers"); len(names) != 1 || names[0] != "office" {
+		t.Fatalf("printers = %v, want [office]", names)
+	}
+	sources, results := editorSources(cfg, "keybindings")
+	if len(results) != 0 || len(sources) != 1 || sources[0].editor != "zed" {
+		t.Fatalf("keybindings = %v, %v; want zed only", sources, results)
+	}
+	if items := cfg.ModuleItems("printers"); len(items) != 0 {
+		t.Fatalf("printers items = %v, want none", items)
+	}
+}
@@ -0,0 +1,89 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/pkg"
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// applyRemove uninstalls the apps in apps.<os>.remove, such as preinstalled
+// OEM apps. Each removal is confirmed first, and recorded so 'pact undo'
+// can reinstall it.
+func applyRemove(remove []string, opts Options) []Result {
+	if len(remove) == 0 {
+		return nil
+	}
+	m := pkg.Detect(runtime.GOOS)
+
+	var results []Result
+	for _, app := range remove {
+		results = append(results, removeApp(app, m, opts))
+	}
+	return results
+}
+
+// removeApp uninstalls app with m, as an app where m tells apps apart
+func removeApp(app string, m pkg.Manager, opts Options) Result {
+	result := Result{Category: "app", Module: "apps", Name: app + "-remove", Package: app}
+	if m == nil {
+		result.Error = fmt.Errorf("no package manager available")
+		return result
+	}
+
+	query, uninstall, reinstall := m.Query(app), m.Uninstall(app), m.Install(app)
+	if am, ok := m.(pkg.AppManager); ok {
+		query, uninstall, reinstall = am.QueryApp(app), am.UninstallApp(app), am.InstallApp(app)
+	}
+	if query == nil {
+		result.Error = fmt.Errorf("can't remove apps with %s, which can't tell whether they're installed", m.Name())
+		return result
+	}
+	if exec.Command(query[0], query[1:]...).Run() != nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not installed"
+		return result
+	}
+
+	cmd := exec.CommandContext(opts.ctx(), uninstall[0], uninstall[1:]...)
+	if err := allowCommand(cmd, opts); err != nil {
+		return refused(result, err)
+	}
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	if opts.Confirm == nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "needs confirmation; run 'pact sync apps' in a terminal"
+		return result
+	}
+	if !opts.Confirm(fmt.Sprintf("Uninstall %s with %s?", app, m.Name())) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "declined"
+		return result
+	}
+
+	opts.Undo.Setting("apps "+app, reinstall)
+	// sudo may ask for a password
+	cmd.Stdin = os.Stdin
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(opts.ctx(), fmt.Errorf("%s failed: %s", commandLine(cmd), strings.TrimSpace(string(output))))
+		return result
+	}
+
+	// pact no longer owns an app it installed earlier and has now removed
+	if journal, err := state.LoadJournal(); err == nil && journal.Remove("apps", app) {
+		journal.Save()
+	}
+
+	result.Success = true
+	result.Message = "uninstalled with " + m.Name()
+	return result
+}
@@ -0,0 +1,113 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Install scopes for Options.Scope
+const (
+	ScopeSystem = "system" // Default: system package managers and paths are fine
+	ScopeUser   = "user"   // Only the user's home is touched; nothing needs admin
+)
+
+// UserScope reports whether this run may only touch the user's home
+func (o Options) UserScope() bool {
+	return o.Scope == ScopeUser
+}
+
+// ScopeError reports a change user scope doesn't allow
+type ScopeError struct {
+	What string
+	Why  string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%s %s; not allowed with --scope user", e.What, e.Why)
+}
+
+// allowPath rejects writes outside the home directory in user scope
+func allowPath(p string, opts Options) error {
+	if !opts.UserScope() {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	if withinDir(p, home) || withinDir(p, os.TempDir()) {
+		return nil
+	}
+	return &ScopeError{What: p, Why: "is outside your home directory"}
+}
+
+// allowCommand rejects commands that install system-wide in user scope, and
+// gets sudo commands ready to run otherwise
+func allowCommand(cmd *exec.Cmd, opts Options) error {
+	if !opts.UserScope() {
+		return escalate(cmd, opts)
+	}
+
+	line := strings.ToUpper(strings.Join(cmd.Args, " "))
+	switch name := filepath.Base(cmd.Args[0]); {
+	case name == "sudo":
+		return &ScopeError{What: commandLine(cmd), Why: "needs admin rights"}
+	case name == "choco":
+		return &ScopeError{What: "choco", Why: "installs machine-wide"}
+	case strings.Contains(line, "HKLM") || strings.Contains(line, "HKEY_LOCAL_MACHINE"):
+		return &ScopeError{What: commandLine(cmd), Why: "writes to HKLM"}
+	case name == "brew":
+		if prefix := brewPrefix(); prefix != "" && allowPath(prefix, opts) != nil {
+			return &ScopeError{What: "brew", Why: "installs into " + prefix}
+		}
+	}
+	return nil
+}
+
+// userScopeArgs adds the flags that keep an install in the user's home
+func userScopeArgs(cmd *exec.Cmd, opts Options) {
+	if !opts.UserScope() || len(cmd.Args) < 2 {
+		return
+	}
+	switch filepath.Base(cmd.Args[0]) {
+	case "winget":
+		cmd.Args = append(cmd.Args, "--scope", "user")
+	case "brew":
+		if containsArg(cmd.Args, "--cask") {
+			home, _ := os.UserHomeDir()
+			cmd.Args = append(cmd.Args, "--appdir="+filepath.Join(home, "Applications"))
+		}
+	case "flatpak":
+		if !containsArg(cmd.Args, "--user") {
+			cmd.Args = append(cmd.Args, "--user")
+		}
+	}
+}
+
+func containsArg(args []string, arg string) bool {
+	for _, a := range args {
+		if a == arg {
+			return true
+		}
+	}
+	return false
+}
+
+// brewPrefix returns where Homebrew installs, or "" without Homebrew
+var brewPrefix = sync.OnceValue(func() string {
+	output, err := exec.Command("brew", "--prefix").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+})
+
+// withinDir reports whether p is dir or inside it
+func withinDir(p, dir string) bool {
+	if dir == "" {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(p))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,59 @@
+package apply
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestUserScope(t *testing.T) {
+	user := Options{Scope: ScopeUser}
+	home, _ := os.UserHomeDir()
+
+	if err := allowPath(filepath.Join(home, ".zshrc"), user); err != nil {
+		t.Fatalf("allowPath(~/.zshrc) = %v", err)
+	}
+	if err := allowPath(home+"-other/.zshrc", user); err == nil {
+		t.Fatalf("allowPath() allowed a sibling of the home dir")
+	}
+	if err := allowPath("/usr/local/bin/jq", user); err == nil {
+		t.Fatalf("allowPath(/usr/local/bin/jq) allowed a system path")
+	}
+	if err := allowPath("/usr/local/bin/jq", Options{}); err != nil {
+		t.Fatalf("allowPath() restricted the default scope: %v", err)
+	}
+
+	if err := allowCommand(exec.Command("sudo", "apt", "install", "-y", "jq"), user); err == nil {
+		t.Fatalf("allowCommand() allowed sudo")
+	}
+
+	cmd := exec.Command("winget", "install", "--id", "jqlang.jq", "-e", "--silent")
+	userScopeArgs(cmd, user)
+	if !containsArg(cmd.Args, "--scope") {
+		t.Fatalf("userScopeArgs() = %v, want --scope user", cmd.Args)
+	}
+}
+
+func TestNoSudo(t *testing.T) {
+	cmd := exec.Command("sudo", "apt", "install", "-y", "jq")
+	err := allowCommand(cmd, Options{NoSudo: true})
+	if err == nil {
+		t.Fatalf("allowCommand() allowed sudo with NoSudo")
+	}
+	result := refused(Result{Name: "jq"}, err)
+	if !result.Skipped || result.Error != nil {
+		t.Fatalf("refused() = %+v, want a skipped result", result)
+	}
+
+	if err := allowCommand(exec.Command("brew", "install", "jq"), Options{NoSudo: true}); err != nil {
+		t.Fatalf("allowCommand() refused a command without sudo: %v", err)
+	}
+
+	if os.Geteuid() == 0 {
+		cmd := exec.Command("sudo", "true")
+		if err := allowCommand(cmd, Options{}); err != nil || cmd.Args[0] != "true" {
+			t.Fatalf("allowCommand() as root = %v %v, want sudo dropped", err, cmd.Args)
+		}
+	}
+}
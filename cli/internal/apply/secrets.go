@@ -0,0 +1,130 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/crypto"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+)
+
+// applySecrets decrypts secrets.enc from the pact repo into the keychain.
+// Secrets already in the keychain are kept, even when secrets.enc has a
+// different value, so a sync never clobbers a key rotated on this machine.
+func applySecrets(cfg *config.PactConfig, opts Options) []Result {
+	result := Result{Category: "configure", Module: "secrets", Name: "keychain"}
+
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		result.Error = err
+		return []Result{result}
+	}
+	data, err := os.ReadFile(filepath.Join(pactDir, crypto.SecretsFile))
+	if os.IsNotExist(err) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not in the pact repo; run 'pact secret seal' on a machine that has the secrets"
+		return []Result{result}
+	}
+	if err != nil {
+		result.Error = err
+		return []Result{result}
+	}
+	if opts.DryRun {
+		return []Result{planned(result, "decrypt %s into the keychain", crypto.SecretsFile)}
+	}
+
+	identities, err := secretIdentities(cfg)
+	if err != nil {
+		result.Error = err
+		return []Result{result}
+	}
+	if len(identities) == 0 && !crypto.IsGPG(data) {
+		result.Error = fmt.Errorf("no age key on this machine; run 'pact secret keygen --import'")
+		return []Result{result}
+	}
+	bundle, err := crypto.Open(opts.ctx(), data, identities)
+	if err != nil {
+		result.Error = stepError(opts.ctx(), err)
+		return []Result{result}
+	}
+
+	var results []Result
+	for _, name := range sortedKeys(bundle.Secrets) {
+		results = append(results, storeSecret(name, "", bundle.Secrets[name]))
+	}
+	projects := make([]string, 0, len(bundle.Projects))
+	for project := range bundle.Projects {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+	for _, project := range projects {
+		for _, name := range sortedKeys(bundle.Projects[project]) {
+			results = append(results, storeSecret(name, project, bundle.Projects[project][name]))
+		}
+	}
+	return results
+}
+
+// storeSecret puts a decrypted secret in the keychain unless it's already set
+func storeSecret(name, project, value string) Result {
+	result := Result{Category: "configure", Module: "secrets", Name: name}
+	get, set := keyring.GetSecret, keyring.SetSecret
+	if project != "" {
+		result.Name = project + "/" + name
+		get = func(name string) (string, error) { return keyring.GetProjectSecret(project, name) }
+		set = func(name, value string) error { return keyring.SetProjectSecret(project, name, value) }
+	}
+
+	if current, err := get(name); err == nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already in keychain"
+		if current != value {
+			result.Message = "keychain has a different value; kept it"
+		}
+		return result
+	}
+	if err := set(name, value); err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = "stored in keychain"
+	return result
+}
+
+// secretIdentities returns the age keys that can open secrets.enc: the key
+// file at secrets.identity, then the key in the keychain
+func secretIdentities(cfg *config.PactConfig) ([]string, error) {
+	var identities []string
+	if path := cfg.GetString("secrets.identity"); path != "" {
+		expanded, err := config.ExpandPath(path)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(expanded)
+		if err != nil {
+			return nil, fmt.Errorf("reading secrets.identity: %w", err)
+		}
+		if identities, err = crypto.ParseIdentities(data); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if identity, err := keyring.GetAgeIdentity(); err == nil {
+		identities = append(identities, identity)
+	}
+	return identities, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
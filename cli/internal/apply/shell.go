@@ -0,0 +1,162 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// nuAutoload is where init scripts for nushell are saved; nu sources every
+// file in vendor/autoload at startup, since its `source` needs a file that
+// exists at parse time
+const nuAutoload = `($nu.data-dir | path join "vendor/autoload")`
+
+// currentShell returns the user's login shell from $SHELL (zsh, bash, fish,
+// nu, xonsh, or whatever else is set)
+func currentShell() string {
+	name := filepath.Base(os.Getenv("SHELL"))
+	if name == "." || name == string(filepath.Separator) {
+		return ""
+	}
+	if name == "nushell" {
+		return "nu"
+	}
+	return name
+}
+
+// shellRCFile returns the config file pact writes init lines to for a shell,
+// or "" when pact doesn't know how to configure it
+func shellRCFile(shell string) string {
+	home, _ := os.UserHomeDir()
+
+	switch shell {
+	case "zsh":
+		return filepath.Join(home, ".zshrc")
+	case "bash":
+		return filepath.Join(home, ".bashrc")
+	case "fish":
+		dir := config.FishConfigDir()
+		if dir == "" {
+			return ""
+		}
+		return filepath.Join(dir, "config.fish")
+	case "nu":
+		dir := config.NushellConfigDir()
+		if dir == "" {
+			return ""
+		}
+		return filepath.Join(dir, "config.nu")
+	case "xonsh":
+		return filepath.Join(home, ".xonshrc")
+	}
+	return ""
+}
+
+// unsupportedShell is the skipped result for a shell pact can't configure
+func unsupportedShell(result Result, shell string) Result {
+	result.Success = true
+	result.Skipped = true
+	if shell == "" {
+		result.Message = "SHELL is not set; add the init line manually"
+	} else {
+		result.Message = fmt.Sprintf("unsupported shell '%s'; add the init line manually", shell)
+	}
+	return result
+}
+
+// promptInitLine returns the init line for a prompt tool in the given shell
+func promptInitLine(shell, promptTool, themePath string) string {
+	switch promptTool {
+	case "oh-my-posh":
+		initCmd := fmt.Sprintf("oh-my-posh init %s --config '%s'", shell, themePath)
+		switch shell {
+		case "zsh", "bash":
+			return fmt.Sprintf(`eval "$(%s)"`, initCmd)
+		case "fish":
+			return initCmd + " | source"
+		case "nu":
+			return nuAutoloadInit(initCmd+" --print", "oh-my-posh")
+		case "xonsh":
+			return fmt.Sprintf("execx($(%s))", initCmd)
+		}
+	case "starship":
+		switch shell {
+		case "zsh", "bash":
+			return fmt.Sprintf(`eval "$(starship init %s)"`, shell)
+		case "fish":
+			return "starship init fish | source"
+		case "nu":
+			return nuAutoloadInit("starship init nu", "starship")
+		case "xonsh":
+			return "execx($(starship init xonsh))"
+		}
+	}
+	return ""
+}
+
+// shellInitTools are the shell tools that need an init line
+var shellInitTools = map[string]bool{
+	"zoxide": true,
+	"fzf":    true,
+	"direnv": true,
+}
+
+// toolInitLine returns the init line for a shell tool in the given shell, or
+// "" when the tool has no integration for that shell
+func toolInitLine(shell, tool string) string {
+	switch tool {
+	case "zoxide":
+		switch shell {
+		case "zsh", "bash", "fish":
+			return shellEvalInit(shell, "zoxide init "+shell)
+		case "nu":
+			return nuAutoloadInit("zoxide init nushell", "zoxide")
+		case "xonsh":
+			return "execx($(zoxide init xonsh), 'exec', __xonsh__.ctx, filename='zoxide')"
+		}
+	case "fzf":
+		switch shell {
+		case "zsh", "bash":
+			return fmt.Sprintf(`[ -f ~/.fzf.%s ] && source ~/.fzf.%s`, shell, shell)
+		case "fish":
+			return "fzf --fish | source"
+		}
+	case "direnv":
+		switch shell {
+		case "zsh", "bash", "fish":
+			return shellEvalInit(shell, "direnv hook "+shell)
+		case "nu":
+			return nuDirenvHook
+		}
+	}
+	return ""
+}
+
+// shellEvalInit evaluates a command's output in a POSIX shell or fish
+func shellEvalInit(shell, command string) string {
+	if shell == "fish" {
+		return command + " | source"
+	}
+	return fmt.Sprintf(`eval "$(%s)"`, command)
+}
+
+// nuDirenvHook loads direnv's environment on every directory change; direnv
+// has no nushell hook of its own
+const nuDirenvHook = `$env.config.hooks.env_change.PWD = ($env.config.hooks.env_change.PWD? | default [] | append {||
+    if (which direnv | is-empty) { return }
+    direnv export json | from json | default {} | load-env
+    if 'ENV_CONVERSIONS' in $env and 'PATH' in $env.ENV_CONVERSIONS {
+        $env.PATH = do $env.ENV_CONVERSIONS.PATH.from_string $env.PATH
+    }
+})`
+
+// nuAutoloadInit saves a tool's generated nu script into the autoload dir
+func nuAutoloadInit(command, name string) string {
+	return strings.Join([]string{
+		fmt.Sprintf("mkdir %s", nuAutoload),
+		fmt.Sprintf(`%s | save -f (%s | path join "%s.nu")`, command, nuAutoload, name),
+	}, "\n")
+}
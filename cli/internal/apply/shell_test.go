@@ -0,0 +1,26 @@
+package apply
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFishInit(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if got, want := shellRCFile("fish"), filepath.Join(dir, "fish", "config.fish"); got != want {
+		t.Fatalf("shellRCFile(fish) = %q, want %q", got, want)
+	}
+
+	tests := []struct{ got, want string }{
+		{promptInitLine("fish", "oh-my-posh", "/t/x.omp.json"), "oh-my-posh init fish --config '/t/x.omp.json' | source"},
+		{promptInitLine("fish", "starship", ""), "starship init fish | source"},
+		{toolInitLine("fish", "zoxide"), "zoxide init fish | source"},
+		{toolInitLine("fish", "direnv"), "direnv hook fish | source"},
+	}
+	for _, tt := range tests {
+		if got, want := tt.got, tt.want; got != want {
+			t.Fatalf("init line = %q, want %q", got, want)
+		}
+	}
+}
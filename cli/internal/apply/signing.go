@@ -0,0 +1,275 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+)
+
+// applySigning sets up commit signing from git.signing:
+//
+//	"signing": {"format": "ssh", "key": "~/.ssh/id_ed25519", "tags": true}
+//	"signing": {"format": "openpgp", "key": "3AA5C34371567BD2", "import": "GPG_SIGNING_KEY"}
+//
+// With ssh, the default (and all that "signing": true asks for), key is a
+// private key's path, the ssh module's key if unset; it's generated if
+// missing, or written from the pact secret named in "import". With openpgp,
+// "import" names a secret holding an armored private key that's imported if
+// key isn't in gpg's keyring; without a key, the key for git.email is used,
+// generated if there's none. Commits are signed unless "commits" is false,
+// tags only if "tags" is true.
+func applySigning(cfg *config.PactConfig, opts Options) []Result {
+	signing := cfg.Get("git.signing")
+	if _, ok := signing.(map[string]any); !ok && signing != true {
+		return nil
+	}
+
+	var key Result
+	var signingKey string
+	format := cfg.GetString("git.signing.format")
+	switch format {
+	case "", "ssh":
+		format = "ssh"
+		key, signingKey = sshSigningKey(cfg, opts)
+	case "openpgp", "gpg":
+		format = "openpgp"
+		key, signingKey = gpgSigningKey(cfg, opts)
+	default:
+		return []Result{{
+			Category: "configure",
+			Module:   "git",
+			Name:     "signing",
+			Error:    fmt.Errorf("unknown signing format '%s' (use ssh or openpgp)", format),
+		}}
+	}
+	results := []Result{key}
+	if key.Error != nil {
+		return results
+	}
+
+	settings := [][2]string{{"gpg.format", format}}
+	if signingKey != "" {
+		settings = append(settings, [2]string{"user.signingkey", signingKey})
+	}
+	if cfg.Get("git.signing.commits") != false {
+		settings = append(settings, [2]string{"commit.gpgsign", "true"})
+	}
+	if cfg.Get("git.signing.tags") == true {
+		settings = append(settings, [2]string{"tag.gpgsign", "true"})
+	}
+	for _, setting := range settings {
+		results = append(results, setGitConfig(setting[0], setting[1], opts))
+	}
+	return results
+}
+
+// sshSigningKey makes sure the SSH signing key exists and returns the
+// user.signingkey value for it: the public key's path, or the key itself
+// when git.signing.key is given as "ssh-ed25519 AAAA..."
+func sshSigningKey(cfg *config.PactConfig, opts Options) (Result, string) {
+	result := Result{Category: "configure", Module: "git", Name: "signing-key"}
+
+	keyPath := cfg.GetString("git.signing.key")
+	if strings.HasPrefix(keyPath, "ssh-") || strings.HasPrefix(keyPath, "key::") {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "literal key"
+		return result, keyPath
+	}
+	if keyPath == "" {
+		keyPath = cfg.GetString("ssh.key")
+	}
+	if keyPath == "" {
+		keyPath = cfg.GetString("ssh.key.path")
+	}
+	if keyPath == "" {
+		keyPath = defaultSSHKey
+	}
+	path, err := config.ExpandPath(strings.TrimSuffix(keyPath, ".pub"))
+	if err != nil {
+		result.Error = err
+		return result, ""
+	}
+
+	if name := cfg.GetString("git.signing.import"); name != "" {
+		return importSSHKey(name, path, opts), path + ".pub"
+	}
+	key := generateSSHKey(cfg, path, opts)
+	key.Module, key.Name = result.Module, result.Name
+	return key, path + ".pub"
+}
+
+// importSSHKey writes the private key held in the named secret to path,
+// unless a key is there, and derives its .pub
+func importSSHKey(secret, path string, opts Options) Result {
+	result := Result{Category: "configure", Module: "git", Name: "signing-key"}
+
+	if _, err := os.Stat(path); err == nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already exists"
+		return result
+	}
+	if err := allowPath(path, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "write secret %s to %s", secret, path)
+	}
+	value, err := keyring.GetSecret(secret)
+	if err != nil || value == "" {
+		result.Error = fmt.Errorf("signing key secret %s isn't set (run 'pact secret set %s')", secret, secret)
+		return result
+	}
+	if !isToolInstalled("ssh-keygen") {
+		result.Error = fmt.Errorf("ssh-keygen not found; install OpenSSH")
+		return result
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		result.Error = err
+		return result
+	}
+	if !strings.HasSuffix(value, "\n") {
+		value += "\n"
+	}
+	if err := os.WriteFile(path, []byte(value), 0600); err != nil {
+		result.Error = err
+		return result
+	}
+	public, err := exec.CommandContext(opts.ctx(), "ssh-keygen", "-y", "-f", path).Output()
+	if err != nil {
+		os.Remove(path)
+		result.Error = stepError(opts.ctx(), fmt.Errorf("secret %s isn't a private key ssh-keygen can read", secret))
+		return result
+	}
+	if err := os.WriteFile(path+".pub", public, 0644); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Success = true
+	result.Message = "imported to " + path
+	return result
+}
+
+// gpgSigningKey makes sure the GPG signing key is in the keyring, importing
+// or generating it, and returns its ID for user.signingkey. In a dry run a
+// key still to be generated has no ID yet, and "" is returned.
+func gpgSigningKey(cfg *config.PactConfig, opts Options) (Result, string) {
+	result := Result{Category: "configure", Module: "git", Name: "signing-key"}
+
+	if !isToolInstalled("gpg") {
+		result.Error = fmt.Errorf("gpg not found; install GnuPG")
+		return result, ""
+	}
+	keyID := cfg.GetString("git.signing.key")
+	email := cfg.GetString("git.email")
+	lookup := keyID
+	if lookup == "" {
+		lookup = email
+	}
+	if lookup == "" {
+		result.Error = fmt.Errorf("set git.signing.key or git.email to pick the GPG key")
+		return result, ""
+	}
+
+	if fpr := gpgSecretKey(opts, lookup); fpr != "" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "gpg key in keyring"
+		if keyID == "" {
+			keyID = fpr
+		}
+		return result, keyID
+	}
+
+	if secret := cfg.GetString("git.signing.import"); secret != "" {
+		if opts.DryRun {
+			return planned(result, "import secret %s with gpg", secret), keyID
+		}
+		value, err := keyring.GetSecret(secret)
+		if err != nil || value == "" {
+			result.Error = fmt.Errorf("signing key secret %s isn't set (run 'pact secret set %s')", secret, secret)
+			return result, ""
+		}
+		cmd := exec.CommandContext(opts.ctx(), "gpg", "--batch", "--import")
+		cmd.Stdin = strings.NewReader(value)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("gpg --import failed: %s", strings.TrimSpace(string(output))))
+			return result, ""
+		}
+		fpr := gpgSecretKey(opts, lookup)
+		if fpr == "" {
+			result.Error = fmt.Errorf("secret %s doesn't hold a private key for %s", secret, lookup)
+			return result, ""
+		}
+		if keyID == "" {
+			keyID = fpr
+		}
+		result.Success = true
+		result.Message = "gpg key imported"
+		return result, keyID
+	}
+
+	if keyID != "" {
+		result.Error = fmt.Errorf("gpg key %s isn't in the keyring; set git.signing.import to a secret holding it", keyID)
+		return result, ""
+	}
+	userID := email
+	if name := cfg.GetString("git.user"); name != "" {
+		userID = fmt.Sprintf("%s <%s>", name, email)
+	}
+	cmd := exec.CommandContext(opts.ctx(), "gpg", "--batch", "--passphrase", "", "--quick-generate-key", userID, "ed25519", "sign", "never")
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd)), ""
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(opts.ctx(), fmt.Errorf("gpg failed to generate a key: %s", strings.TrimSpace(string(output))))
+		return result, ""
+	}
+	fpr := gpgSecretKey(opts, email)
+	if fpr == "" {
+		result.Error = fmt.Errorf("gpg generated a key, but it isn't listed for %s", email)
+		return result, ""
+	}
+	result.Success = true
+	result.Message = "gpg key generated for " + userID
+	return result, fpr
+}
+
+// gpgSecretKey returns the fingerprint of the first secret key matching
+// id (a key ID, fingerprint, or email), or "" if there's none
+func gpgSecretKey(opts Options, id string) string {
+	output, err := exec.CommandContext(opts.ctx(), "gpg", "--batch", "--with-colons", "--list-secret-keys", id).Output()
+	if err != nil {
+		return ""
+	}
+	return firstFingerprint(string(output))
+}
+
+// firstFingerprint picks the primary key's fingerprint out of gpg's
+// --with-colons listing
+func firstFingerprint(listing string) string {
+	inKey := false
+	for _, line := range strings.Split(listing, "\n") {
+		fields := strings.Split(line, ":")
+		switch fields[0] {
+		case "sec":
+			inKey = true
+		case "ssb":
+			inKey = false
+		case "fpr":
+			if inKey && len(fields) > 9 {
+				return fields[9]
+			}
+		}
+	}
+	return ""
+}
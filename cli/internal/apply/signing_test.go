@@ -0,0 +1,18 @@
+package apply
+
+import "testing"
+
+func TestFirstFingerprint(t *testing.T) {
+	listing := `sec:u:255:22:3AA5C34371567BD2:1700000000:::u:::scESC:::+:::ed25519:::0:
+fpr:::::::::4F9E5B0A1C2D3E4F56789ABC3AA5C34371567BD2:
+uid:u::::1700000000::ABCDEF::Jane Doe <jane@example.com>::::::::::0:
+ssb:u:255:18:1122334455667788:1700000000::::::e:::+:::cv25519::
+fpr:::::::::0000000000000000000000001122334455667788:
+`
+	if got, want := firstFingerprint(listing), "4F9E5B0A1C2D3E4F56789ABC3AA5C34371567BD2"; got != want {
+		t.Fatalf("firstFingerprint() = %q, want %q", got, want)
+	}
+	if got := firstFingerprint(""); got != "" {
+		t.Fatalf("firstFingerprint(\"\") = %q, want empty", got)
+	}
+}
@@ -0,0 +1,135 @@
+package apply
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// windowsSoundScheme switches Windows to a sound scheme the way the Sound
+// control panel does, copying the scheme's sound for every event into the
+// current one; ".None" silences every event
+const windowsSoundScheme = `$scheme = '%s'
+Set-ItemProperty -Path 'HKCU:\AppEvents\Schemes' -Name '(Default)' -Value $scheme
+Get-ChildItem 'HKCU:\AppEvents\Schemes\Apps' | Get-ChildItem | ForEach-Object {
+  $sound = ''
+  if ($scheme -ne '.None' -and (Test-Path "$($_.PSPath)\$scheme")) { $sound = (Get-ItemProperty "$($_.PSPath)\$scheme").'(default)' }
+  if (Test-Path "$($_.PSPath)\.Current") { Set-ItemProperty "$($_.PSPath)\.Current" -Name '(Default)' -Value $sound }
+}`
+
+// applySound quiets a new machine:
+//
+//	"sound": {"alerts": false, "startupChime": false, "doNotDisturb": true}
+//
+// alerts turns system alert and interface sounds on or off, startupChime the
+// boot sound, and doNotDisturb notification banners. Each is left alone when
+// unset.
+func applySound(cfg *config.PactConfig, opts Options) []Result {
+	return applySystemSettings("sound", soundSettings(cfg, runtime.GOOS, isToolInstalled("gsettings")), opts)
+}
+
+// soundSettings maps the sound module to goos's settings
+func soundSettings(cfg *config.PactConfig, goos string, hasGsettings bool) []systemSetting {
+	alerts, hasAlerts := cfg.Get("sound.alerts").(bool)
+	chime, hasChime := cfg.Get("sound.startupChime").(bool)
+	dnd := cfg.Get("sound.doNotDisturb")
+
+	var settings []systemSetting
+	switch goos {
+	case "darwin":
+		if hasAlerts {
+			settings = append(settings,
+				defaultsSetting("alert-sounds", "-g", "com.apple.sound.beep.volume", "-float", flag(alerts, "1", "0")),
+				defaultsSetting("interface-sounds", "com.apple.systemsound", "com.apple.sound.uiaudio.enabled", "-int", flag(alerts, "1", "0")),
+			)
+		}
+		if hasChime {
+			mute := flag(chime, "%00", "%01")
+			settings = append(settings, systemSetting{
+				name:  "startup-chime",
+				read:  []string{"nvram", "StartupMute"},
+				want:  mute,
+				write: []string{"sudo", "nvram", "StartupMute=" + mute},
+				restore: func(current string) []string {
+					if current == "" {
+						return []string{"sudo", "nvram", "-d", "StartupMute"}
+					}
+					return []string{"sudo", "nvram", "StartupMute=" + current}
+				},
+			})
+		}
+		if dnd != nil {
+			settings = append(settings, systemSetting{
+				name:   "do-not-disturb",
+				manual: "macOS doesn't let scripts change Focus; set it in System Settings > Focus",
+			})
+		}
+
+	case "linux":
+		if hasChime {
+			settings = append(settings, systemSetting{name: "startup-chime", manual: "Linux doesn't play one"})
+		}
+		if !hasGsettings {
+			if hasAlerts || dnd != nil {
+				settings = append(settings, systemSetting{name: "sound", manual: "needs GNOME's gsettings"})
+			}
+			break
+		}
+		if hasAlerts {
+			settings = append(settings,
+				gsettingsSetting("alert-sounds", "org.gnome.desktop.sound", "event-sounds", flag(alerts, "true", "false")),
+				gsettingsSetting("terminal-bell", "org.gnome.desktop.wm.preferences", "audible-bell", flag(alerts, "true", "false")),
+			)
+		}
+		if on, ok := dnd.(bool); ok {
+			settings = append(settings, gsettingsSetting("do-not-disturb", "org.gnome.desktop.notifications", "show-banners", flag(on, "false", "true")))
+		} else if dnd != nil {
+			settings = append(settings, systemSetting{
+				name:   "do-not-disturb",
+				manual: "GNOME has no Do Not Disturb schedule; set doNotDisturb to true or false",
+			})
+		}
+
+	case "windows":
+		if hasAlerts {
+			scheme := flag(alerts, ".Default", ".None")
+			settings = append(settings, systemSetting{
+				name:  "alert-sounds",
+				read:  []string{"reg", "query", `HKCU\AppEvents\Schemes`, "/ve"},
+				want:  scheme,
+				write: []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf(windowsSoundScheme, scheme)},
+				restore: func(current string) []string {
+					if current == "" {
+						current = ".Default"
+					}
+					return []string{"powershell", "-NoProfile", "-Command", fmt.Sprintf(windowsSoundScheme, current)}
+				},
+			})
+		}
+		if hasChime {
+			disable := 1
+			if chime {
+				disable = 0
+			}
+			settings = append(settings, registryDWORD("startup-chime",
+				`HKLM\SOFTWARE\Microsoft\Windows\CurrentVersion\Authentication\LogonUI\BootAnimation`,
+				"DisableStartupSound", disable))
+		}
+		if dnd != nil {
+			settings = append(settings, systemSetting{
+				name:   "do-not-disturb",
+				manual: "Windows doesn't let scripts change Do Not Disturb; set it in Settings > System > Notifications",
+			})
+		}
+	}
+	return settings
+}
+
+// flag picks the value for a boolean setting
+func flag(on bool, yes, no string) string {
+	if on {
+		return yes
+	}
+	return no
+}
@@ -0,0 +1,51 @@
+package apply
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestSoundSettings(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"sound": {"alerts": false, "startupChime": false, "doNotDisturb": true}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"alert-sounds":     "0",
+		"interface-sounds": "0",
+		"startup-chime":    "%01",
+	}
+	darwin := soundSettings(cfg, "darwin", false)
+	for _, s := range darwin {
+		if s.manual != "" {
+			continue
+		}
+		if s.want != want[s.name] {
+			t.Fatalf("darwin %s = %q, want %q", s.name, s.want, want[s.name])
+		}
+		delete(want, s.name)
+	}
+	if len(want) > 0 {
+		t.Fatalf("darwin settings missing %v", want)
+	}
+
+	linux := soundSettings(cfg, "linux", true)
+	var dnd *systemSetting
+	for i := range linux {
+		if linux[i].name == "do-not-disturb" {
+			dnd = &linux[i]
+		}
+	}
+	if dnd == nil || dnd.want != "false" || dnd.write[len(dnd.write)-1] != "false" {
+		t.Fatalf("linux do not disturb = %+v, want banners off", dnd)
+	}
+	if got := dnd.restore("true"); got[len(got)-1] != "true" {
+		t.Fatalf("restore = %v, want it to set true back", got)
+	}
+
+	if len(soundSettings(cfg, "linux", false)) != 2 {
+		t.Fatalf("without gsettings, want only the chime and a manual note")
+	}
+}
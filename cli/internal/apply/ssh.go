@@ -0,0 +1,356 @@
+package apply
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/auth"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/keyring"
+	"golang.org/x/crypto/ssh"
+)
+
+// defaultSSHKey is the key the ssh module generates when "key" isn't set
+const defaultSSHKey = "~/.ssh/id_ed25519"
+
+// sshConfigBlock marks pact's Host entries in ~/.ssh/config. It goes at the
+// top, since ssh takes the first value it finds for each option.
+var sshConfigBlock = managedBlock{start: "# Pact: hosts", end: "# Pact: end hosts", top: true, perm: 0600}
+
+// applySSH gets a new machine's SSH set up:
+//
+//	"ssh": {
+//	  "key": {"path": "~/.ssh/id_ed25519", "passphrase": "SSH_KEY_PASSPHRASE"},
+//	  "agent": true,
+//	  "github": true,
+//	  "hosts": {"nas": {"HostName": "nas.local", "User": "admin", "Port": 2222}}
+//	}
+//
+// The key (just a path also works) is generated as ed25519 if it's missing,
+// protected by the pact secret named in "passphrase" if set. "agent" adds it
+// to the SSH agent, "github" uploads its public key with pact's GitHub
+// token, and "hosts" are written as Host entries to ~/.ssh/config. pact undo
+// leaves a generated key in place.
+func applySSH(cfg *config.PactConfig, opts Options) []Result {
+	keyPath := cfg.GetString("ssh.key")
+	if keyPath == "" {
+		keyPath = cfg.GetString("ssh.key.path")
+	}
+	if keyPath == "" {
+		keyPath = defaultSSHKey
+	}
+	path, err := config.ExpandPath(keyPath)
+	if err != nil {
+		return []Result{{Category: "configure", Module: "ssh", Name: "key", Error: err}}
+	}
+
+	key := generateSSHKey(cfg, path, opts)
+	results := []Result{key}
+	if key.Error == nil {
+		if cfg.Get("ssh.agent") == true {
+			results = append(results, addSSHKeyToAgent(cfg, path, opts))
+		}
+		if cfg.Get("ssh.github") == true {
+			results = append(results, uploadSSHKey(path, opts))
+		}
+	}
+	if hosts := cfg.GetMap("ssh.hosts"); len(hosts) > 0 {
+		results = append(results, writeSSHHosts(hosts, opts))
+	}
+	return results
+}
+
+// generateSSHKey creates an ed25519 key at path unless one is there
+func generateSSHKey(cfg *config.PactConfig, path string, opts Options) Result {
+	result := Result{Category: "configure", Module: "ssh", Name: "key"}
+
+	if _, err := os.Stat(path); err == nil {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already exists"
+		return result
+	}
+	if err := allowPath(path, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "generate an ed25519 key at %s", path)
+	}
+	passphrase := ""
+	if name := cfg.GetString("ssh.key.passphrase"); name != "" {
+		value, err := keyring.GetSecret(name)
+		if err != nil || value == "" {
+			result.Error = fmt.Errorf("passphrase secret %s isn't set (run 'pact secret set %s')", name, name)
+			return result
+		}
+		passphrase = value
+	}
+	comment := cfg.GetString("ssh.key.comment")
+	if comment == "" {
+		comment = cfg.GetString("git.email")
+	}
+	if comment == "" {
+		host, _ := os.Hostname()
+		comment = "pact@" + host
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		result.Error = err
+		return result
+	}
+	private, public, err := newSSHKey(comment, passphrase)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if err := os.WriteFile(path, private, 0600); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := os.WriteFile(path+".pub", public, 0644); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Success = true
+	result.Message = "generated " + path
+	return result
+}
+
+// newSSHKey returns a new ed25519 key in OpenSSH's formats, the private key
+// encrypted with passphrase when it's set. It's made here rather than with
+// ssh-keygen so the passphrase is never in a command line others can read.
+func newSSHKey(comment, passphrase string) (private, public []byte, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(priv, comment, []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(priv, comment)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return nil, nil, err
+	}
+	public = append(bytes.TrimSpace(ssh.MarshalAuthorizedKey(sshPub)), []byte(" "+comment+"\n")...)
+	return pem.EncodeToMemory(block), public, nil
+}
+
+// addSSHKeyToAgent adds the key to the running SSH agent
+func addSSHKeyToAgent(cfg *config.PactConfig, path string, opts Options) Result {
+	result := Result{Category: "configure", Module: "ssh", Name: "agent"}
+
+	if runtime.GOOS != "windows" && os.Getenv("SSH_AUTH_SOCK") == "" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "no SSH agent running"
+		return result
+	}
+	if !isToolInstalled("ssh-add") {
+		result.Error = fmt.Errorf("ssh-add not found; install OpenSSH")
+		return result
+	}
+	if public, err := publicKey(path); err == nil {
+		listed, _ := exec.CommandContext(opts.ctx(), "ssh-add", "-L").Output()
+		if strings.Contains(string(listed), public) {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already added"
+			return result
+		}
+	}
+
+	args := []string{path}
+	if runtime.GOOS == "darwin" {
+		// Keep the passphrase in the macOS keychain so it survives a reboot
+		args = []string{"--apple-use-keychain", path}
+	}
+	cmd := exec.CommandContext(opts.ctx(), "ssh-add", args...)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	if cfg.GetString("ssh.key.passphrase") != "" {
+		// ssh-add asks for the passphrase itself
+		if err := runAttached(cmd); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("ssh-add failed: %w", err))
+			return result
+		}
+	} else if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(opts.ctx(), fmt.Errorf("ssh-add failed: %s", strings.TrimSpace(string(output))))
+		return result
+	}
+
+	result.Success = true
+	result.Message = "added"
+	return result
+}
+
+// uploadSSHKey adds the key's public half to the user's GitHub account
+func uploadSSHKey(path string, opts Options) Result {
+	result := Result{Category: "configure", Module: "ssh", Name: "github"}
+
+	token, err := keyring.GetToken()
+	if err != nil || token == "" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "no GitHub token stored (run 'pact init')"
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "upload %s.pub to GitHub if it isn't there", path)
+	}
+	public, err := publicKey(path)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	keys, err := auth.ListSSHKeys(opts.ctx(), token)
+	if err != nil {
+		result.Error = sshGitHubError(err, path)
+		return result
+	}
+	for _, k := range keys {
+		if k == public {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already on GitHub"
+			return result
+		}
+	}
+
+	host, _ := os.Hostname()
+	if err := auth.AddSSHKey(opts.ctx(), token, "pact: "+host, public); err != nil {
+		result.Error = sshGitHubError(err, path)
+		return result
+	}
+	result.Success = true
+	result.Message = "uploaded to GitHub"
+	return result
+}
+
+// sshGitHubError says how to add the key by hand when the token can't
+func sshGitHubError(err error, path string) error {
+	if errors.Is(err, auth.ErrNoKeyScope) {
+		return fmt.Errorf("%w; add %s.pub at https://github.com/settings/ssh/new", err, path)
+	}
+	return err
+}
+
+// publicKey reads the key's .pub file as "type base64", without the comment
+func publicKey(path string) (string, error) {
+	data, err := os.ReadFile(path + ".pub")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return "", fmt.Errorf("%s.pub isn't a public key", path)
+	}
+	return fields[0] + " " + fields[1], nil
+}
+
+// writeSSHHosts writes ssh.hosts to pact's block in ~/.ssh/config
+func writeSSHHosts(hosts map[string]any, opts Options) Result {
+	result := Result{Category: "configure", Module: "ssh", Name: "config"}
+
+	path, err := config.ExpandPath("~/.ssh/config")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	if !opts.DryRun {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			result.Error = err
+			return result
+		}
+	}
+	changed, err := sshConfigBlock.write(path, sshHostLines(hosts), opts)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Success = true
+	switch {
+	case !changed:
+		result.Skipped = true
+		result.Message = "already configured"
+	case opts.DryRun:
+		result = planned(result, "write %d host(s) to %s", len(hosts), path)
+	default:
+		result.Message = fmt.Sprintf("%d host(s) written to %s", len(hosts), path)
+	}
+	return result
+}
+
+// sshHostLines renders hosts as ssh_config Host entries. A host is an object
+// of options, or just its HostName; a list repeats the option.
+func sshHostLines(hosts map[string]any) []string {
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for i, name := range names {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "Host "+name)
+
+		options, ok := hosts[name].(map[string]any)
+		if !ok {
+			options = map[string]any{"HostName": hosts[name]}
+		}
+		keys := make([]string, 0, len(options))
+		for k := range options {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			values, ok := options[k].([]any)
+			if !ok {
+				values = []any{options[k]}
+			}
+			for _, v := range values {
+				lines = append(lines, fmt.Sprintf("  %s %s", k, sshValue(v)))
+			}
+		}
+	}
+	return lines
+}
+
+// sshValue formats a pact.json value for ssh_config; strings are written as
+// given, so a path with spaces needs its own quotes
+func sshValue(v any) string {
+	switch val := v.(type) {
+	case bool:
+		if val {
+			return "yes"
+		}
+		return "no"
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
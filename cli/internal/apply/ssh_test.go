@@ -0,0 +1,66 @@
+package apply
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSSHHostLines(t *testing.T) {
+	lines := sshHostLines(map[string]any{
+		"nas": map[string]any{"HostName": "nas.local", "Port": float64(2222), "ForwardAgent": true, "LocalForward": []any{"8080 localhost:80", "5432 db:5432"}},
+		"box": "box.example.com",
+	})
+	want := `Host box
+  HostName box.example.com
+
+Host nas
+  ForwardAgent yes
+  HostName nas.local
+  LocalForward 8080 localhost:80
+  LocalForward 5432 db:5432
+  Port 2222`
+	if got := strings.Join(lines, "\n"); got != want {
+		t.Fatalf("sshHostLines() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestManagedBlockTop(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("Host *\n  User me\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := sshConfigBlock.write(path, []string{"Host nas", "  User admin"}, Options{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	want := "# Pact: hosts\nHost nas\n  User admin\n# Pact: end hosts\n\nHost *\n  User me\n"
+	if string(data) != want {
+		t.Fatalf("config =\n%s", data)
+	}
+}
+
+func TestNewSSHKey(t *testing.T) {
+	private, public, err := newSSHKey("me@example.com", "hunter2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ssh.ParseRawPrivateKey(private); err == nil {
+		t.Fatal("key isn't encrypted with the passphrase")
+	}
+	key, err := ssh.ParseRawPrivateKeyWithPassphrase(private, []byte("hunter2"))
+	if err != nil {
+		t.Fatalf("ParseRawPrivateKeyWithPassphrase() error = %v", err)
+	}
+	signer, _ := ssh.NewSignerFromKey(key)
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey(public)
+	if err != nil || comment != "me@example.com" || !bytes.Equal(pub.Marshal(), signer.PublicKey().Marshal()) {
+		t.Fatalf("public key %q doesn't match (%v)", public, err)
+	}
+}
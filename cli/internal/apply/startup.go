@@ -0,0 +1,313 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// runKey is where Windows keeps the current user's startup commands
+const runKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`
+
+// applyStartup makes each app in apps.<os>.startup launch at login: a Login
+// Item on macOS, an autostart .desktop entry on Linux, and a Run registry
+// value on Windows. Startup items pact added for apps no longer listed are
+// removed.
+func applyStartup(startup []string, opts Options) []Result {
+	var results []Result
+	for _, app := range startup {
+		results = append(results, addStartupItem(app, opts))
+	}
+	return append(results, pruneStartupItems(startup, opts)...)
+}
+
+// addStartupItem makes app launch at login
+func addStartupItem(app string, opts Options) Result {
+	result := Result{Category: "app", Module: "apps", Name: app + "-startup", Package: app}
+
+	if startupItemExists(app) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already starts at login"
+		return result
+	}
+
+	switch runtime.GOOS {
+	case "darwin":
+		path := findMacApp(app)
+		if path == "" {
+			result.Error = fmt.Errorf("can't find %s in /Applications", app)
+			return result
+		}
+		script := fmt.Sprintf(`tell application "System Events" to make login item at end with properties {path:%q, hidden:false}`, path)
+		cmd := exec.CommandContext(opts.ctx(), "osascript", "-e", script)
+		if opts.DryRun {
+			return planned(result, "add %s to Login Items", path)
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("couldn't add the login item: %s", strings.TrimSpace(string(output))))
+			return result
+		}
+	case "linux":
+		name, content, err := autostartEntry(app)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		path := filepath.Join(autostartDir(), name+".desktop")
+		if err := allowPath(path, opts); err != nil {
+			result.Error = err
+			return result
+		}
+		if opts.DryRun {
+			return planned(result, "write %s", path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			result.Error = err
+			return result
+		}
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			result.Error = err
+			return result
+		}
+		result.Paths = []string{path}
+	case "windows":
+		command, err := windowsAppCommand(app)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		cmd := exec.CommandContext(opts.ctx(), "reg", "add", runKey, "/v", runValueName(app), "/t", "REG_SZ", "/d", `"`+command+`"`, "/f")
+		if opts.DryRun {
+			return planned(result, "run %s", commandLine(cmd))
+		}
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("reg add failed: %s", strings.TrimSpace(string(output))))
+			return result
+		}
+	default:
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not supported on " + runtime.GOOS
+		return result
+	}
+
+	result.Success = true
+	result.Message = "starts at login"
+	result.Backend = "startup"
+	return result
+}
+
+// pruneStartupItems removes the startup items pact added for apps that are
+// no longer in startup
+func pruneStartupItems(startup []string, opts Options) []Result {
+	journal, err := state.LoadJournal()
+	if err != nil {
+		return nil
+	}
+	listed := make(map[string]bool)
+	for _, app := range startup {
+		listed[strings.ToLower(app)] = true
+	}
+
+	var results []Result
+	changed := false
+	for _, entry := range append([]state.Entry(nil), journal.Entries...) {
+		if entry.Module != "apps" || entry.Backend != "startup" || listed[strings.ToLower(startupApp(entry))] {
+			continue
+		}
+		result := Result{Category: "app", Module: "apps", Name: entry.Name}
+		if opts.DryRun {
+			results = append(results, planned(result, "remove %s from startup, no longer in apps.%s.startup", startupApp(entry), runtime.GOOS))
+			continue
+		}
+		if err := removeStartupItem(entry); err != nil {
+			result.Error = err
+			results = append(results, result)
+			continue
+		}
+		journal.Remove(entry.Module, entry.Name)
+		changed = true
+		result.Success = true
+		result.Message = "removed, no longer in apps." + runtime.GOOS + ".startup"
+		results = append(results, result)
+	}
+	if changed {
+		journal.Save()
+	}
+	return results
+}
+
+// startupApp is the app a startup journal entry is for
+func startupApp(entry state.Entry) string {
+	if entry.Package != "" {
+		return entry.Package
+	}
+	return strings.TrimSuffix(entry.Name, "-startup")
+}
+
+// removeStartupItem undoes addStartupItem for a journal entry
+func removeStartupItem(entry state.Entry) error {
+	app := startupApp(entry)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		name := app
+		if path := findMacApp(app); path != "" {
+			name = strings.TrimSuffix(filepath.Base(path), ".app")
+		}
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf(`tell application "System Events" to delete login item %q`, name))
+	case "windows":
+		cmd = exec.Command("reg", "delete", runKey, "/v", runValueName(app), "/f")
+	default:
+		for _, p := range entry.Paths {
+			if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// startupItemExists reports whether app already launches at login
+func startupItemExists(app string) bool {
+	switch runtime.GOOS {
+	case "darwin":
+		output, err := exec.Command("osascript", "-e", `tell application "System Events" to get the name of every login item`).Output()
+		if err != nil {
+			return false
+		}
+		for _, name := range strings.Split(strings.TrimSpace(string(output)), ", ") {
+			if appKey(name) == appKey(app) {
+				return true
+			}
+		}
+	case "linux":
+		name, _, err := autostartEntry(app)
+		if err != nil {
+			name = app
+		}
+		_, err = os.Stat(filepath.Join(autostartDir(), name+".desktop"))
+		return err == nil
+	case "windows":
+		return exec.Command("reg", "query", runKey, "/v", runValueName(app)).Run() == nil
+	}
+	return false
+}
+
+// appKey normalizes an app name for matching "visual-studio-code" against
+// "Visual Studio Code"
+func appKey(name string) string {
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSuffix(name, ".app")))
+}
+
+// findMacApp returns the path of app's bundle, given as a path or a name
+func findMacApp(app string) string {
+	if strings.HasSuffix(app, ".app") && filepath.IsAbs(app) {
+		return app
+	}
+	home, _ := os.UserHomeDir()
+	for _, dir := range []string{"/Applications", filepath.Join(home, "Applications"), "/System/Applications"} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry.Name(), ".app") && appKey(entry.Name()) == appKey(app) {
+				return filepath.Join(dir, entry.Name())
+			}
+		}
+	}
+	return ""
+}
+
+// autostartDir is where Linux desktops look for apps to launch at login
+func autostartDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "autostart")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "autostart")
+}
+
+// applicationDirs are where Linux apps, including Flatpak and Snap ones,
+// install their .desktop entries
+func applicationDirs() []string {
+	home, _ := os.UserHomeDir()
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return []string{
+		filepath.Join(dataHome, "applications"),
+		filepath.Join(dataHome, "flatpak", "exports", "share", "applications"),
+		"/var/lib/flatpak/exports/share/applications",
+		"/var/lib/snapd/desktop/applications",
+		"/usr/local/share/applications",
+		"/usr/share/applications",
+	}
+}
+
+// autostartEntry returns the .desktop entry that launches app at login, and
+// the name to give it: a copy of the app's own entry, found by name or by
+// the last part of a Flatpak ID, else a minimal one running app from PATH
+func autostartEntry(app string) (string, []byte, error) {
+	for _, dir := range applicationDirs() {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := strings.CutSuffix(entry.Name(), ".desktop")
+			if !ok {
+				continue
+			}
+			last := name[strings.LastIndex(name, ".")+1:]
+			if appKey(name) != appKey(app) && appKey(last) != appKey(app) {
+				continue
+			}
+			content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			return name, content, nil
+		}
+	}
+
+	if _, err := exec.LookPath(app); err != nil {
+		return "", nil, fmt.Errorf("can't find a desktop entry or command for %s", app)
+	}
+	content := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=%s\nExec=%s\n", app, app)
+	return app, []byte(content), nil
+}
+
+// runValueName is the Run registry value for app: its program's name when
+// app is a path
+func runValueName(app string) string {
+	if filepath.IsAbs(app) {
+		return strings.TrimSuffix(filepath.Base(app), filepath.Ext(app))
+	}
+	return app
+}
+
+// windowsAppCommand returns the program to run for app: app itself when it's
+// a full path, else the app found on PATH
+func windowsAppCommand(app string) (string, error) {
+	if filepath.IsAbs(app) {
+		return app, nil
+	}
+	path, err := exec.LookPath(app)
+	if err != nil {
+		return "", fmt.Errorf("can't find %s on PATH; list the full path of its program", app)
+	}
+	return path, nil
+}
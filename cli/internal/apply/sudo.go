@@ -0,0 +1,136 @@
+package apply
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// sudoRefresh is how often a run's sudo credential is refreshed, well
+// inside sudo's default 5 to 15 minute timeout
+const sudoRefresh = time.Minute
+
+// Sudo gets sudo's password at most once per run and keeps the credential
+// fresh until Close, so a batch of installs never stops to ask again
+type Sudo struct {
+	prompt bool
+	once   sync.Once
+	err    error
+	stop   chan struct{}
+}
+
+// NewSudo starts a run's sudo session. With prompt set the password is asked
+// for on the terminal before the first command that needs it; without,
+// commands that need a password fail instead of waiting for one.
+func NewSudo(prompt bool) *Sudo {
+	return &Sudo{prompt: prompt, stop: make(chan struct{})}
+}
+
+// Close stops refreshing the credential
+func (s *Sudo) Close() {
+	if s == nil {
+		return
+	}
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+}
+
+// ready makes sure sudo can run without asking, asking for the password
+// the first time it's needed
+func (s *Sudo) ready() error {
+	s.once.Do(func() {
+		if exec.Command("sudo", "-n", "true").Run() != nil {
+			if !s.prompt {
+				s.err = errors.New("sudo needs a password; run in a terminal, or skip admin steps with --no-sudo")
+				return
+			}
+			// Parallel installs wait while the password is typed
+			terminalMu.Lock()
+			err := runAttached(exec.Command("sudo", "-v", "-p", "[sudo] password for %u (pact needs admin rights): "))
+			terminalMu.Unlock()
+			if err != nil {
+				s.err = fmt.Errorf("sudo: %v", err)
+				return
+			}
+		}
+		go s.refresh()
+	})
+	return s.err
+}
+
+func (s *Sudo) refresh() {
+	ticker := time.NewTicker(sudoRefresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			exec.Command("sudo", "-n", "-v").Run()
+		}
+	}
+}
+
+// SudoError reports a step that needs admin rights and couldn't have them
+type SudoError struct {
+	Command string
+	Skipped bool // Left out with --no-sudo rather than failed
+	Err     error
+}
+
+func (e *SudoError) Error() string {
+	if e.Skipped {
+		return e.Command + " needs sudo; skipped with --no-sudo"
+	}
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+// escalate gets a sudo command ready to run. As root, sudo is dropped. With
+// --no-sudo the step is refused; otherwise the run's session gets the
+// password once and the command runs with -n, failing rather than hanging
+// on a password prompt nobody can answer.
+func escalate(cmd *exec.Cmd, opts Options) error {
+	if filepath.Base(cmd.Args[0]) != "sudo" || len(cmd.Args) < 2 {
+		return nil
+	}
+	if opts.NoSudo {
+		return &SudoError{Command: commandLine(cmd), Skipped: true}
+	}
+	if opts.DryRun {
+		return nil
+	}
+	if os.Geteuid() == 0 {
+		cmd.Path, cmd.Err = exec.LookPath(cmd.Args[1])
+		cmd.Args = cmd.Args[1:]
+		return nil
+	}
+	if opts.Sudo == nil {
+		return nil
+	}
+	if err := opts.Sudo.ready(); err != nil {
+		return &SudoError{Command: commandLine(cmd), Err: err}
+	}
+	cmd.Args = append([]string{cmd.Args[0], "-n"}, cmd.Args[1:]...)
+	return nil
+}
+
+// refused is the result of a step allowCommand wouldn't let run: skipped
+// with --no-sudo, failed otherwise
+func refused(result Result, err error) Result {
+	var sudoErr *SudoError
+	if errors.As(err, &sudoErr) && sudoErr.Skipped {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "needs sudo; skipped with --no-sudo"
+		return result
+	}
+	result.Error = err
+	return result
+}
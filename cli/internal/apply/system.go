@@ -0,0 +1,180 @@
+package apply
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// systemSetting is one OS preference set through defaults (macOS),
+// gsettings (GNOME), or the registry (Windows)
+type systemSetting struct {
+	name  string
+	read  []string // Prints the current value as its last field
+	want  string   // What read prints once the setting is applied
+	write []string // Applies the setting
+
+	// parse extracts the current value from read's output when it isn't
+	// the last field
+	parse func(output string) string
+
+	// confirm asks before changing the setting; after is added to the
+	// result once it's set
+	confirm bool
+	after   string
+
+	// restore returns the command that puts back the value read printed
+	// before the sync ("" when it wasn't set), for 'pact undo'; nil when it
+	// can't be put back
+	restore func(current string) []string
+
+	// manual explains how to change the setting by hand where pact can't
+	manual string
+
+	// err is a problem with the setting's value in pact.json
+	err error
+}
+
+// applySystemSettings applies each setting that isn't already in place
+func applySystemSettings(module string, settings []systemSetting, opts Options) []Result {
+	var results []Result
+	for _, s := range settings {
+		result := Result{Category: "configure", Module: module, Name: s.name}
+		if s.err != nil {
+			result.Error = s.err
+			results = append(results, result)
+			continue
+		}
+		if s.manual != "" {
+			result.Success = true
+			result.Skipped = true
+			result.Message = s.manual
+			results = append(results, result)
+			continue
+		}
+
+		current := readSystemSetting(opts, s)
+		if current == s.want {
+			result.Success = true
+			result.Skipped = true
+			result.Message = "already set"
+			results = append(results, result)
+			continue
+		}
+
+		cmd := command(opts.ctx(), s.write[0], s.write[1:]...)
+		if err := allowCommand(cmd, opts); err != nil {
+			results = append(results, refused(result, err))
+			continue
+		}
+		if opts.DryRun {
+			results = append(results, planned(result, "run %s", commandLine(cmd)))
+			continue
+		}
+		if s.confirm {
+			question := fmt.Sprintf("Set %s %s to %s?", module, s.name, s.want)
+			if current != "" {
+				question = fmt.Sprintf("Change %s %s from %s to %s?", module, s.name, current, s.want)
+			}
+			if opts.Confirm == nil {
+				result.Success = true
+				result.Skipped = true
+				result.Message = "needs confirmation; run 'pact sync " + module + "' in a terminal"
+				results = append(results, result)
+				continue
+			}
+			if !opts.Confirm(question) {
+				result.Success = true
+				result.Skipped = true
+				result.Message = "declined"
+				results = append(results, result)
+				continue
+			}
+		}
+
+		if s.restore != nil {
+			if restore := s.restore(current); restore != nil {
+				opts.Undo.Setting(module+" "+s.name, restore)
+			}
+		}
+		// sudo may ask for a password
+		cmd.Stdin = os.Stdin
+		if output, err := cmd.CombinedOutput(); err != nil {
+			result.Error = stepError(opts.ctx(), fmt.Errorf("%s failed: %s", commandLine(cmd), strings.TrimSpace(string(output))))
+			results = append(results, result)
+			continue
+		}
+		result.Success = true
+		result.Message = "set"
+		if s.after != "" {
+			result.Message += "; " + s.after
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// readSystemSetting returns the current value (by default the last field
+// read prints), or "" when the setting isn't set
+func readSystemSetting(opts Options, s systemSetting) string {
+	output, err := command(opts.ctx(), s.read[0], s.read[1:]...).Output()
+	if err != nil {
+		return ""
+	}
+	if s.parse != nil {
+		return s.parse(string(output))
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[len(fields)-1]
+}
+
+// defaultsSetting sets a macOS defaults key
+func defaultsSetting(name, domain, key, kind, value string) systemSetting {
+	return systemSetting{
+		name:  name,
+		read:  []string{"defaults", "read", domain, key},
+		want:  value,
+		write: []string{"defaults", "write", domain, key, kind, value},
+		restore: func(current string) []string {
+			if current == "" {
+				return []string{"defaults", "delete", domain, key}
+			}
+			return []string{"defaults", "write", domain, key, kind, current}
+		},
+	}
+}
+
+// gsettingsSetting sets a GNOME gsettings key
+func gsettingsSetting(name, schema, key, value string) systemSetting {
+	return systemSetting{
+		name:  name,
+		read:  []string{"gsettings", "get", schema, key},
+		want:  value,
+		write: []string{"gsettings", "set", schema, key, value},
+		restore: func(current string) []string {
+			if current == "" {
+				return []string{"gsettings", "reset", schema, key}
+			}
+			return []string{"gsettings", "set", schema, key, current}
+		},
+	}
+}
+
+// registryDWORD sets a REG_DWORD value with reg.exe
+func registryDWORD(name, key, value string, data int) systemSetting {
+	return systemSetting{
+		name:  name,
+		read:  []string{"reg", "query", key, "/v", value},
+		want:  fmt.Sprintf("0x%x", data),
+		write: []string{"reg", "add", key, "/v", value, "/t", "REG_DWORD", "/d", fmt.Sprint(data), "/f"},
+		restore: func(current string) []string {
+			if current == "" {
+				return []string{"reg", "delete", key, "/v", value, "/f"}
+			}
+			return []string{"reg", "add", key, "/v", value, "/t", "REG_DWORD", "/d", current, "/f"}
+		},
+	}
+}
@@ -0,0 +1,490 @@
+package apply
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// terminalSettings are the settings terminal.<emulator> gets
+type terminalSettings struct {
+	font      string
+	fallback  []string
+	size      float64
+	ligatures *bool
+	theme     string
+}
+
+// applyTerminalEmulators applies the font and theme under each
+// terminal.<emulator>, along with the font, fallback, fontSize, and
+// ligatures set for every emulator at the top of terminal:
+//
+//	"terminal": {
+//	  "font": "JetBrainsMono Nerd Font",
+//	  "fallback": ["Symbols Nerd Font"],
+//	  "ligatures": false,
+//	  "ghostty": {"fontSize": 14, "theme": "catppuccin-mocha"},
+//	  "windows-terminal": {"font": "CaskaydiaCove Nerd Font", "theme": "One Half Dark"}
+//	}
+//
+// Each is written into the emulator's own config file, leaving the rest of
+// it alone. Alacritty's theme is a theme file to import, kitty's is set with
+// 'kitten themes', and iTerm2 gets a "pact" dynamic profile with the font.
+// Alacritty has no fallback fonts or ligatures, and kitty picks fallbacks
+// itself.
+func applyTerminalEmulators(cfg *config.PactConfig, opts Options) []Result {
+	var results []Result
+	for _, emulator := range config.TerminalEmulators {
+		if !cfg.HasKey("terminal." + emulator) {
+			continue
+		}
+		font := cfg.TerminalFont(emulator)
+		s := terminalSettings{font: font.Family, fallback: font.Fallback, size: font.Size, ligatures: font.Ligatures}
+		s.theme = cfg.GetString("terminal." + emulator + ".theme")
+
+		if emulator == "iterm2" {
+			results = append(results, writeITermProfile(s, opts))
+			continue
+		}
+		results = append(results, writeTerminalConfig(emulator, s, opts))
+		if emulator == "kitty" && s.theme != "" {
+			results = append(results, setKittyTheme(s.theme, opts))
+		}
+	}
+	return results
+}
+
+// writeTerminalConfig sets the font and theme in an emulator's config file
+func writeTerminalConfig(emulator string, s terminalSettings, opts Options) Result {
+	result := Result{Category: "configure", Module: "terminal", Name: emulator}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	path := config.TerminalConfigPath(emulator, runtime.GOOS, home)
+	if path == "" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not available on " + runtime.GOOS
+		return result
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		result.Error = err
+		return result
+	}
+	updated, err := updateTerminalConfig(emulator, string(data), s)
+	if err != nil {
+		result.Error = fmt.Errorf("%s: %w", path, err)
+		return result
+	}
+	if updated == string(data) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already configured"
+		return result
+	}
+	return writeTerminalFile(result, path, []byte(updated), opts)
+}
+
+// writeTerminalFile writes an emulator's config, recording it for undo
+func writeTerminalFile(result Result, path string, data []byte, opts Options) Result {
+	if err := allowPath(path, opts); err != nil {
+		result.Error = err
+		return result
+	}
+	if opts.DryRun {
+		return planned(result, "update %s", path)
+	}
+	opts.Undo.File(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		result.Error = err
+		return result
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		result.Error = err
+		return result
+	}
+	result.Success = true
+	result.Message = "updated " + path
+	return result
+}
+
+// updateTerminalConfig returns an emulator's config with the settings in s
+// set. Kitty's theme is left to setKittyTheme.
+func updateTerminalConfig(emulator, data string, s terminalSettings) (string, error) {
+	size := strconv.FormatFloat(s.size, 'f', -1, 64)
+	switch emulator {
+	case "ghostty":
+		// Each font-family after the first is a fallback
+		if s.font != "" {
+			var families []string
+			for _, font := range append([]string{s.font}, s.fallback...) {
+				families = append(families, "font-family = "+font)
+			}
+			data = setConfigLines(data, "font-family", "=", families)
+		}
+		if s.size > 0 {
+			data = setConfigLine(data, "font-size", "=", "font-size = "+size)
+		}
+		if s.ligatures != nil {
+			features := []string{"font-feature = +calt", "font-feature = +liga"}
+			if !*s.ligatures {
+				features = []string{"font-feature = -calt", "font-feature = -liga", "font-feature = -dlig"}
+			}
+			data = setConfigLines(data, "font-feature", "=", features)
+		}
+		if s.theme != "" {
+			data = setConfigLine(data, "theme", "=", "theme = "+s.theme)
+		}
+	case "kitty":
+		if s.font != "" {
+			data = setConfigLine(data, "font_family", " ", "font_family "+s.font)
+		}
+		if s.size > 0 {
+			data = setConfigLine(data, "font_size", " ", "font_size "+size)
+		}
+		if s.ligatures != nil {
+			disable := "always"
+			if *s.ligatures {
+				disable = "never"
+			}
+			data = setConfigLine(data, "disable_ligatures", " ", "disable_ligatures "+disable)
+		}
+	case "alacritty":
+		if s.font != "" {
+			data = setTOMLKey(data, "font.normal", "family", strconv.Quote(s.font))
+		}
+		if s.size > 0 {
+			data = setTOMLKey(data, "font", "size", size)
+		}
+		if s.theme != "" {
+			data = setTOMLKey(data, "general", "import", "["+strconv.Quote(s.theme)+"]")
+		}
+	case "windows-terminal":
+		return updateWindowsTerminal(data, s)
+	}
+	return data, nil
+}
+
+// setConfigLine replaces the first "key<sep>value" line with line, or
+// appends line when there's none. sep is "=" for ghostty, " " for kitty.
+func setConfigLine(data, key, sep, line string) string {
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	if data == "" {
+		lines = nil
+	}
+	for i, l := range lines {
+		if configLineKey(l, key, sep) {
+			lines[i] = line
+			return strings.Join(lines, "\n") + "\n"
+		}
+	}
+	return strings.Join(append(lines, line), "\n") + "\n"
+}
+
+// setConfigLines replaces every "key<sep>value" line with set, where the
+// first of them was, for keys like ghostty's font-family that can repeat
+func setConfigLines(data, key, sep string, set []string) string {
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	if data == "" {
+		lines = nil
+	}
+	var kept []string
+	at := -1
+	for _, l := range lines {
+		if !configLineKey(l, key, sep) {
+			kept = append(kept, l)
+			continue
+		}
+		if at < 0 {
+			at = len(kept)
+		}
+	}
+	if at < 0 {
+		at = len(kept)
+	}
+	kept = append(kept[:at], append(append([]string(nil), set...), kept[at:]...)...)
+	return strings.Join(kept, "\n") + "\n"
+}
+
+// configLineKey reports whether l is a "key<sep>value" line
+func configLineKey(l, key, sep string) bool {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(l), key)
+	if !ok || rest == "" {
+		return false
+	}
+	if sep != " " {
+		return strings.HasPrefix(strings.TrimLeft(rest, " \t"), sep)
+	}
+	return rest[0] == ' ' || rest[0] == '\t'
+}
+
+// setTOMLKey sets key in [table] of a TOML file to value, a TOML literal,
+// adding the key or the table if they're missing
+func setTOMLKey(data, table, key, value string) string {
+	lines := strings.Split(strings.TrimRight(data, "\n"), "\n")
+	if data == "" {
+		lines = nil
+	}
+	header := "[" + table + "]"
+	in, last := false, -1
+	for i, l := range lines {
+		trimmed := strings.TrimSpace(l)
+		if strings.HasPrefix(trimmed, "[") {
+			if in {
+				break
+			}
+			in = trimmed == header
+			if in {
+				last = i
+			}
+			continue
+		}
+		if !in {
+			continue
+		}
+		if k, _, ok := strings.Cut(trimmed, "="); ok && strings.TrimSpace(k) == key {
+			lines[i] = key + " = " + value
+			return strings.Join(lines, "\n") + "\n"
+		}
+		if trimmed != "" {
+			last = i
+		}
+	}
+
+	line := key + " = " + value
+	if last >= 0 {
+		lines = append(lines[:last+1], append([]string{line}, lines[last+1:]...)...)
+		return strings.Join(lines, "\n") + "\n"
+	}
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+	return strings.Join(append(lines, header, line), "\n") + "\n"
+}
+
+// updateWindowsTerminal sets the font and color scheme in the defaults every
+// Windows Terminal profile inherits
+func updateWindowsTerminal(data string, s terminalSettings) (string, error) {
+	var before, after map[string]any
+	if err := unmarshalJSONC([]byte(data), &before); err != nil {
+		return "", err
+	}
+	unmarshalJSONC([]byte(data), &after)
+	if after == nil {
+		after = make(map[string]any)
+	}
+
+	profiles, ok := after["profiles"].(map[string]any)
+	if !ok {
+		if _, isList := after["profiles"].([]any); isList {
+			return "", fmt.Errorf("profiles is a list; open the settings in Windows Terminal once to update them")
+		}
+		profiles = make(map[string]any)
+		after["profiles"] = profiles
+	}
+	defaults, ok := profiles["defaults"].(map[string]any)
+	if !ok {
+		defaults = make(map[string]any)
+		profiles["defaults"] = defaults
+	}
+	if s.font != "" || s.size > 0 || s.ligatures != nil {
+		font, ok := defaults["font"].(map[string]any)
+		if !ok {
+			font = make(map[string]any)
+			defaults["font"] = font
+		}
+		if s.font != "" {
+			// Fallbacks follow the face, comma-separated
+			font["face"] = strings.Join(append([]string{s.font}, s.fallback...), ", ")
+		}
+		if s.size > 0 {
+			font["size"] = s.size
+		}
+		if s.ligatures != nil {
+			features, ok := font["features"].(map[string]any)
+			if !ok {
+				features = make(map[string]any)
+				font["features"] = features
+			}
+			on := 0.0
+			if *s.ligatures {
+				on = 1
+			}
+			features["calt"], features["liga"] = on, on
+		}
+	}
+	if s.theme != "" {
+		defaults["colorScheme"] = s.theme
+	}
+
+	out, err := marshalMerged([]byte(data), before, after)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// setKittyTheme sets kitty's theme with 'kitten themes', which downloads it
+// and includes it from kitty.conf
+func setKittyTheme(theme string, opts Options) Result {
+	result := Result{Category: "configure", Module: "terminal", Name: "kitty-theme"}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	path := config.TerminalConfigPath("kitty", runtime.GOOS, home)
+	if path == "" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not available on " + runtime.GOOS
+		return result
+	}
+	if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), "# BEGIN_KITTY_THEME\n# "+theme+"\n") {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already set"
+		return result
+	}
+
+	cmd := exec.CommandContext(opts.ctx(), "kitty", "+kitten", "themes", "--reload-in=none", theme)
+	if opts.DryRun {
+		return planned(result, "run %s", commandLine(cmd))
+	}
+	if !isToolInstalled("kitty") {
+		result.Error = fmt.Errorf("kitty isn't installed, so its theme can't be set")
+		return result
+	}
+	opts.Undo.File(path)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		result.Error = stepError(opts.ctx(), fmt.Errorf("kitten themes failed: %s", strings.TrimSpace(string(output))))
+		return result
+	}
+	result.Success = true
+	result.Message = theme
+	return result
+}
+
+// writeITermProfile writes a "pact" iTerm2 dynamic profile with the font.
+// iTerm2 wants the font's PostScript name, such as JetBrainsMonoNF-Regular,
+// and has no themes by name. It takes one fallback, as its non-ASCII font.
+func writeITermProfile(s terminalSettings, opts Options) Result {
+	result := Result{Category: "configure", Module: "terminal", Name: "iterm2"}
+
+	if runtime.GOOS != "darwin" {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "not available on " + runtime.GOOS
+		return result
+	}
+	if s.font == "" {
+		result.Error = fmt.Errorf("terminal.iterm2 needs a font; iTerm2 has no themes by name")
+		return result
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		result.Error = err
+		return result
+	}
+
+	size := s.size
+	if size == 0 {
+		size = 13
+	}
+	fontSize := strconv.FormatFloat(size, 'f', -1, 64)
+	settings := map[string]any{
+		"Name":                        "pact",
+		"Guid":                        "pact",
+		"Dynamic Profile Parent Name": "Default",
+		"Normal Font":                 fmt.Sprintf("%s %s", s.font, fontSize),
+	}
+	if len(s.fallback) > 0 {
+		settings["Use Non-ASCII Font"] = true
+		settings["Non Ascii Font"] = fmt.Sprintf("%s %s", s.fallback[0], fontSize)
+	}
+	if s.ligatures != nil {
+		settings["ASCII Ligatures"] = *s.ligatures
+		settings["Non-ASCII Ligatures"] = *s.ligatures
+	}
+	profile := map[string]any{"Profiles": []any{settings}}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	data = append(data, '\n')
+
+	path := config.ITermProfilePath(home)
+	if existing, err := os.ReadFile(path); err == nil && string(existing) == string(data) {
+		result.Success = true
+		result.Skipped = true
+		result.Message = "already configured"
+		return result
+	}
+	result = writeTerminalFile(result, path, data, opts)
+	if result.Success && !opts.DryRun {
+		result.Message = "wrote the pact profile; pick it in iTerm2's Profiles settings"
+	}
+	return result
+}
+
+// applyEditorTerminalFonts sets the font, fallback, fontSize, and
+// ligatures at the top of terminal in the integrated terminal of each
+// VS Code-like editor pact.json has an editor.<editor> entry for, so its
+// terminal matches the emulators'
+func applyEditorTerminalFonts(cfg *config.PactConfig, opts Options) []Result {
+	font := cfg.TerminalFont("")
+	settings := editorTerminalSettings(font)
+	if len(settings) == 0 {
+		return nil
+	}
+
+	var results []Result
+	for _, editor := range settingsEditors {
+		if !cfg.HasKey("editor." + editor) {
+			continue
+		}
+		result := mergeEditorSettings(editor, settings, opts)
+		result.Category = "configure"
+		result.Module = "terminal"
+		result.Name = editor + "-terminal"
+		results = append(results, result)
+	}
+	return results
+}
+
+// editorTerminalSettings is font as VS Code's terminal.integrated settings,
+// its families a CSS font list
+func editorTerminalSettings(font config.TerminalFont) map[string]any {
+	settings := make(map[string]any)
+	if families := font.Families(); len(families) > 0 {
+		var quoted []string
+		for _, family := range families {
+			if strings.ContainsAny(family, " ,") {
+				family = "'" + family + "'"
+			}
+			quoted = append(quoted, family)
+		}
+		settings["terminal.integrated.fontFamily"] = strings.Join(quoted, ", ")
+	}
+	if font.Size > 0 {
+		settings["terminal.integrated.fontSize"] = font.Size
+	}
+	if font.Ligatures != nil {
+		settings["terminal.integrated.fontLigatures.enabled"] = *font.Ligatures
+	}
+	return settings
+}
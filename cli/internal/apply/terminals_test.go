@@ -0,0 +1,68 @@
+package apply
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestUpdateTerminalConfig(t *testing.T) {
+	s := terminalSettings{font: "JetBrainsMono Nerd Font", size: 14, theme: "nord"}
+
+	ghostty, err := updateTerminalConfig("ghostty", "font-size = 12\nfont-family-bold = Foo\n", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "font-size = 14\nfont-family-bold = Foo\nfont-family = JetBrainsMono Nerd Font\ntheme = nord\n"; ghostty != want {
+		t.Fatalf("ghostty config = %q, want %q", ghostty, want)
+	}
+
+	kitty, _ := updateTerminalConfig("kitty", "font_family Menlo\n", s)
+	if want := "font_family JetBrainsMono Nerd Font\nfont_size 14\n"; kitty != want {
+		t.Fatalf("kitty config = %q, want %q", kitty, want)
+	}
+
+	alacritty, _ := updateTerminalConfig("alacritty", "[font]\nsize = 11\n\n[window]\nopacity = 0.9\n", s)
+	for _, want := range []string{"[font]\nsize = 14\n", "[font.normal]\nfamily = \"JetBrainsMono Nerd Font\"\n", "[general]\nimport = [\"nord\"]\n", "[window]\nopacity = 0.9\n"} {
+		if !strings.Contains(alacritty, want) {
+			t.Fatalf("alacritty config %q lacks %q", alacritty, want)
+		}
+	}
+	if again, _ := updateTerminalConfig("alacritty", alacritty, s); again != alacritty {
+		t.Fatalf("updating alacritty twice changed it:\n%s\n%s", alacritty, again)
+	}
+
+	wt, err := updateTerminalConfig("windows-terminal", `{"profiles": {"list": []}}`, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(wt, `"face": "JetBrainsMono Nerd Font"`) || !strings.Contains(wt, `"colorScheme": "nord"`) {
+		t.Fatalf("windows terminal settings = %s", wt)
+	}
+
+	off := false
+	s.fallback, s.ligatures = []string{"Symbols Nerd Font", "Noto Color Emoji"}, &off
+	ghostty, _ = updateTerminalConfig("ghostty", "font-family = Menlo\nfont-family = Apple Color Emoji\nfont-feature = +ss01\ntheme = nord\n", s)
+	if want := "font-family = JetBrainsMono Nerd Font\nfont-family = Symbols Nerd Font\nfont-family = Noto Color Emoji\nfont-feature = -calt\nfont-feature = -liga\nfont-feature = -dlig\ntheme = nord\nfont-size = 14\n"; ghostty != want {
+		t.Fatalf("ghostty config = %q, want %q", ghostty, want)
+	}
+	if again, _ := updateTerminalConfig("ghostty", ghostty, s); again != ghostty {
+		t.Fatalf("updating ghostty twice changed it:\n%s\n%s", ghostty, again)
+	}
+	if kitty, _ := updateTerminalConfig("kitty", "", s); !strings.Contains(kitty, "disable_ligatures always\n") {
+		t.Fatalf("kitty config = %q", kitty)
+	}
+	wt, _ = updateTerminalConfig("windows-terminal", wt, s)
+	if !strings.Contains(wt, `"face": "JetBrainsMono Nerd Font, Symbols Nerd Font, Noto Color Emoji"`) || !strings.Contains(wt, `"calt": 0`) {
+		t.Fatalf("windows terminal settings = %s", wt)
+	}
+
+	vscode := editorTerminalSettings(config.TerminalFont{Family: s.font, Fallback: s.fallback, Size: 13, Ligatures: &off})
+	if got := vscode["terminal.integrated.fontFamily"]; got != "'JetBrainsMono Nerd Font', 'Symbols Nerd Font', 'Noto Color Emoji'" {
+		t.Fatalf("fontFamily = %v", got)
+	}
+	if vscode["terminal.integrated.fontSize"] != 13.0 || vscode["terminal.integrated.fontLigatures.enabled"] != false {
+		t.Fatalf("vscode settings = %v", vscode)
+	}
+}
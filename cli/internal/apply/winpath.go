@@ -0,0 +1,80 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// envVar matches a Windows %VARIABLE% reference
+var envVar = regexp.MustCompile(`%([^%]+)%`)
+
+// userPathMu keeps custom tools installing in parallel from each
+// overwriting the PATH the other just added to
+var userPathMu sync.Mutex
+
+// addToUserPath puts dir on the user's PATH on Windows when it isn't there,
+// in the user environment so new shells get it and in this process so later
+// steps find what's installed there. It reports whether dir was missing,
+// meaning terminals opened before need reopening to see it.
+func addToUserPath(ctx context.Context, dir string, opts Options) (bool, error) {
+	if runtime.GOOS != "windows" {
+		return false, nil
+	}
+	userPathMu.Lock()
+	defer userPathMu.Unlock()
+	if pathContains(os.Getenv("PATH"), dir) {
+		return false, nil
+	}
+
+	output, err := command(ctx, "powershell", "-NoProfile", "-Command", "[Environment]::GetEnvironmentVariable('Path', 'User')").Output()
+	if err != nil {
+		return false, fmt.Errorf("could not read your PATH: %v", err)
+	}
+	current := strings.TrimSpace(string(output))
+	os.Setenv("PATH", os.Getenv("PATH")+string(os.PathListSeparator)+dir)
+	if pathContains(current, dir) {
+		// Added before, but this terminal predates it
+		return true, nil
+	}
+
+	value := dir
+	if current != "" {
+		value = strings.TrimRight(current, ";") + ";" + dir
+	}
+	opts.Undo.Setting("user PATH", setUserPath(current))
+	args := setUserPath(value)
+	if output, err := command(ctx, args[0], args[1:]...).CombinedOutput(); err != nil {
+		return false, fmt.Errorf("could not add %s to your PATH: %s", dir, strings.TrimSpace(string(output)))
+	}
+	return true, nil
+}
+
+// setUserPath is the command that sets the user's PATH, telling running
+// programs like Explorer so new terminals pick it up
+func setUserPath(value string) []string {
+	return []string{"powershell", "-NoProfile", "-Command",
+		fmt.Sprintf("[Environment]::SetEnvironmentVariable('Path', '%s', 'User')", strings.ReplaceAll(value, "'", "''"))}
+}
+
+// pathContains reports whether a Windows PATH value lists dir, ignoring
+// case and trailing separators and expanding %VARIABLES%
+func pathContains(list, dir string) bool {
+	want := strings.TrimRight(dir, `\/`)
+	for _, entry := range strings.Split(list, ";") {
+		entry = envVar.ReplaceAllStringFunc(entry, func(v string) string {
+			if value, ok := os.LookupEnv(strings.Trim(v, "%")); ok {
+				return value
+			}
+			return v
+		})
+		if strings.EqualFold(strings.TrimRight(strings.TrimSpace(entry), `\/`), want) {
+			return true
+		}
+	}
+	return false
+}
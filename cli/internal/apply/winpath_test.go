@@ -0,0 +1,20 @@
+package apply
+
+import (
+	"testing"
+)
+
+func TestPathContains(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\me`)
+	list := `C:\Windows\system32;%USERPROFILE%\bin\;C:\Tools`
+
+	if !pathContains(list, `C:\Users\me\bin`) {
+		t.Fatalf("expected %%USERPROFILE%%\\bin to match")
+	}
+	if !pathContains(list, `c:\tools\`) {
+		t.Fatalf("expected a match ignoring case and trailing separators")
+	}
+	if pathContains(list, `C:\Users\me\bin\sub`) {
+		t.Fatalf("matched a directory that isn't listed")
+	}
+}
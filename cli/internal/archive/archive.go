@@ -0,0 +1,291 @@
+// Package archive extracts .tar.gz and .zip release archives without relying
+// on tar or unzip being installed, which stock Windows doesn't guarantee
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Match picks the entries to extract by their slash-separated path in the
+// archive; nil extracts everything
+type Match func(name string) bool
+
+// Binary matches a file named tool (or tool.exe) anywhere in the archive, or
+// only the entry at binPath when it's set
+func Binary(tool, binPath string) Match {
+	if binPath != "" {
+		binPath = path.Clean(strings.TrimPrefix(binPath, "./"))
+		return func(name string) bool {
+			return name == binPath || name == binPath+".exe"
+		}
+	}
+	return func(name string) bool {
+		base := path.Base(name)
+		return base == tool || base == tool+".exe"
+	}
+}
+
+// Extract extracts src into destDir, choosing the format by its extension
+func Extract(ctx context.Context, src, destDir string, match Match) error {
+	if strings.HasSuffix(src, ".zip") {
+		return ExtractZip(ctx, src, destDir, match)
+	}
+	return ExtractTarGz(ctx, src, destDir, match)
+}
+
+// ExtractTarGz extracts the matching entries of a gzipped tarball into destDir
+func ExtractTarGz(ctx context.Context, src, destDir string, match Match) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	root, err := realDir(destDir)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := entryName(hdr.Name)
+		if err != nil {
+			return err
+		}
+		if name == "" || (match != nil && !match(name)) {
+			continue
+		}
+		if hdr.Typeflag == tar.TypeSymlink {
+			if err := symlink(root, name, hdr.Linkname); err != nil {
+				return err
+			}
+			continue
+		}
+		target, err := place(root, name)
+		if err != nil {
+			return err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := writeFile(target, tr, os.FileMode(hdr.Mode)); err != nil {
+				return err
+			}
+		}
+		// Hard links, devices, and FIFOs have no place in a release archive
+	}
+}
+
+// ExtractZip extracts the matching entries of a zip file into destDir
+func ExtractZip(ctx context.Context, src, destDir string, match Match) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	root, err := realDir(destDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if ctx != nil && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		name, err := entryName(f.Name)
+		if err != nil {
+			return err
+		}
+		if name == "" || (match != nil && !match(name)) {
+			continue
+		}
+
+		mode := f.Mode()
+		if mode&os.ModeSymlink != 0 {
+			link, err := readZipFile(f)
+			if err != nil {
+				return err
+			}
+			if err := symlink(root, name, string(link)); err != nil {
+				return err
+			}
+			continue
+		}
+		target, err := place(root, name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = writeFile(target, rc, mode)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// entryName cleans an entry's name, refusing names that would escape the
+// extract directory. The root entry ("./") has no name.
+func entryName(entry string) (string, error) {
+	name := path.Clean(strings.ReplaceAll(entry, `\`, "/"))
+	if name == "." {
+		return "", nil
+	}
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("archive entry %q is outside the extract directory", entry)
+	}
+	return name, nil
+}
+
+// realDir creates destDir and returns its path with links resolved
+func realDir(destDir string) (string, error) {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(destDir)
+}
+
+// place returns where the entry name really goes under root, following
+// the links earlier entries extracted, and refuses it if that's outside
+func place(root, name string) (string, error) {
+	target, err := resolve(root, name, 0)
+	if err != nil {
+		return "", err
+	}
+	if !inside(root, target) {
+		return "", fmt.Errorf("archive entry %q is outside the extract directory", name)
+	}
+	return target, nil
+}
+
+// resolve walks rel from dir, a path with no links in it, following each
+// link it passes through the way the OS would. Parts that don't exist yet
+// are kept as they are.
+func resolve(dir, rel string, depth int) (string, error) {
+	if depth > 40 {
+		return "", fmt.Errorf("too many links in archive path %q", rel)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			dir = filepath.Dir(dir)
+			continue
+		}
+		next := filepath.Join(dir, part)
+		info, err := os.Lstat(next)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			dir = next
+			continue
+		}
+		link, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(link) {
+			dir, link = filepath.VolumeName(link)+string(filepath.Separator), link[len(filepath.VolumeName(link)):]
+		}
+		if dir, err = resolve(dir, link, depth+1); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// inside reports whether target is root or under it
+func inside(root, target string) bool {
+	rel, err := filepath.Rel(root, target)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// symlink creates the entry name as a link to link, refusing ones that are
+// absolute or lead outside root through the links already extracted
+func symlink(root, name, link string) error {
+	parent, err := place(root, path.Dir(name))
+	if err != nil {
+		return err
+	}
+	if filepath.IsAbs(link) || filepath.VolumeName(link) != "" {
+		return fmt.Errorf("archive link %s points outside the extract directory", path.Base(name))
+	}
+	resolved, err := resolve(parent, filepath.FromSlash(link), 0)
+	if err != nil {
+		return err
+	}
+	if !inside(root, resolved) {
+		return fmt.Errorf("archive link %s points outside the extract directory", path.Base(name))
+	}
+	target := filepath.Join(parent, path.Base(name))
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	os.Remove(target)
+	return os.Symlink(link, target)
+}
+
+// writeFile writes r to target, keeping the entry's permission bits
+func writeFile(target string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	perm := mode.Perm() | 0600
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, r)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
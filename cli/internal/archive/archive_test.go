@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTarGz(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, body := range files {
+		hdr := &tar.Header{Name: name, Mode: 0755, Size: int64(len(body)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write([]byte(body))
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func writeZip(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for name, body := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(body))
+	}
+	zw.Close()
+}
+
+func TestExtractBinary(t *testing.T) {
+	files := map[string]string{
+		"rg-14.1.0/rg":        "binary",
+		"rg-14.1.0/README.md": "readme",
+		"rg-14.1.0/doc/rg.1":  "man page",
+	}
+	for _, ext := range []string{".tar.gz", ".zip"} {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "rg"+ext)
+		if ext == ".zip" {
+			writeZip(t, src, files)
+		} else {
+			writeTarGz(t, src, files)
+		}
+		dest := filepath.Join(dir, "out")
+		if err := Extract(context.Background(), src, dest, Binary("rg", "")); err != nil {
+			t.Fatalf("%s: Extract() error = %v", ext, err)
+		}
+		if data, err := os.ReadFile(filepath.Join(dest, "rg-14.1.0", "rg")); err != nil || string(data) != "binary" {
+			t.Fatalf("%s: rg = %q, %v", ext, data, err)
+		}
+		if _, err := os.Stat(filepath.Join(dest, "rg-14.1.0", "README.md")); err == nil {
+			t.Fatalf("%s: README.md was extracted", ext)
+		}
+	}
+}
+
+func TestExtractRejectsTraversal(t *testing.T) {
+	for _, name := range []string{"../evil", "a/../../evil", "/etc/evil"} {
+		dir := t.TempDir()
+		src := filepath.Join(dir, "bad.tar.gz")
+		writeTarGz(t, src, map[string]string{name: "x"})
+		err := ExtractTarGz(context.Background(), src, filepath.Join(dir, "out"), nil)
+		if err == nil || !strings.Contains(err.Error(), "outside") {
+			t.Fatalf("%s: ExtractTarGz() error = %v, want outside error", name, err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "evil")); err == nil {
+			t.Fatalf("%s: escaped the extract directory", name)
+		}
+	}
+}
+
+func TestExtractRejectsChainedLinks(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "bad.tar.gz")
+	f, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	// Each link looks inside on its own; together x leads to dir
+	for _, hdr := range []*tar.Header{
+		{Name: "y", Linkname: ".", Typeflag: tar.TypeSymlink},
+		{Name: "x", Linkname: "y/..", Typeflag: tar.TypeSymlink},
+		{Name: "x/evil", Mode: 0644, Size: 1, Typeflag: tar.TypeReg},
+	} {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			tw.Write([]byte("x"))
+		}
+	}
+	tw.Close()
+	gz.Close()
+	f.Close()
+
+	dest := filepath.Join(dir, "out")
+	err = ExtractTarGz(context.Background(), src, dest, nil)
+	if err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Fatalf("ExtractTarGz() error = %v, want outside error", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "evil")); err == nil {
+		t.Fatal("escaped the extract directory through links")
+	}
+	if target, err := os.Readlink(filepath.Join(dest, "y")); err != nil || target != "." {
+		t.Fatalf("link inside the extract directory = %q, %v", target, err)
+	}
+}
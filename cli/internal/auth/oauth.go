@@ -2,7 +2,9 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,8 +21,8 @@ const (
 	deviceCodeURL = "https://github.com/login/device/code"
 	tokenURL      = "https://github.com/login/oauth/access_token"
 
-	// Scopes needed for pact
-	scopes = "repo"
+	// Scopes needed for pact: the pact repo, and SSH keys for the ssh module
+	scopes = "repo write:public_key"
 )
 
 // GetClientID returns the GitHub OAuth client ID from env or default
@@ -49,12 +51,12 @@ type TokenResponse struct {
 }
 
 // RequestDeviceCode initiates the device flow
-func RequestDeviceCode() (*DeviceCodeResponse, error) {
+func RequestDeviceCode(ctx context.Context) (*DeviceCodeResponse, error) {
 	data := url.Values{}
 	data.Set("client_id", GetClientID())
 	data.Set("scope", scopes)
 
-	req, err := http.NewRequest("POST", deviceCodeURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", deviceCodeURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return nil, err
 	}
@@ -82,7 +84,7 @@ func RequestDeviceCode() (*DeviceCodeResponse, error) {
 }
 
 // PollForToken polls GitHub for the access token
-func PollForToken(deviceCode string, interval int) (string, error) {
+func PollForToken(ctx context.Context, deviceCode string, interval int) (string, error) {
 	data := url.Values{}
 	data.Set("client_id", GetClientID())
 	data.Set("device_code", deviceCode)
@@ -92,7 +94,7 @@ func PollForToken(deviceCode string, interval int) (string, error) {
 	pollInterval := time.Duration(interval) * time.Second
 
 	for {
-		req, err := http.NewRequest("POST", tokenURL, bytes.NewBufferString(data.Encode()))
+		req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewBufferString(data.Encode()))
 		if err != nil {
 			return "", err
 		}
@@ -121,12 +123,16 @@ func PollForToken(deviceCode string, interval int) (string, error) {
 			return tokenResp.AccessToken, nil
 		case "authorization_pending":
 			// User hasn't authorized yet, keep polling
-			time.Sleep(pollInterval)
+			if err := sleep(ctx, pollInterval); err != nil {
+				return "", err
+			}
 			continue
 		case "slow_down":
 			// We're polling too fast, increase interval
 			pollInterval += 5 * time.Second
-			time.Sleep(pollInterval)
+			if err := sleep(ctx, pollInterval); err != nil {
+				return "", err
+			}
 			continue
 		case "expired_token":
 			return "", fmt.Errorf("device code expired, please try again")
@@ -138,11 +144,30 @@ func PollForToken(deviceCode string, interval int) (string, error) {
 	}
 }
 
+// sleep waits for d, returning early with ctx's error when it ends
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // GetUser fetches the authenticated user's info
-func GetUser(token string) (*GitHubUser, error) {
-	req, err := http.NewRequest("GET", "https://api.github.com/user", nil)
+func GetUser(ctx context.Context, token string) (*GitHubUser, error) {
+	user, _, err := getUser(ctx, token)
+	return user, err
+}
+
+// getUser fetches the authenticated user's info, and the response headers
+// that describe the token
+func getUser(ctx context.Context, token string) (*GitHubUser, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user", nil)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github+json")
@@ -150,25 +175,25 @@ func GetUser(token string) (*GitHubUser, error) {
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("failed to get user: status %d", resp.StatusCode)
+		return nil, nil, fmt.Errorf("failed to get user: status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var user GitHubUser
 	if err := json.Unmarshal(body, &user); err != nil {
-		return nil, fmt.Errorf("failed to parse user: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse user: %w", err)
 	}
 
-	return &user, nil
+	return &user, resp.Header, nil
 }
 
 // GitHubUser represents a GitHub user
@@ -179,33 +204,117 @@ type GitHubUser struct {
 	Name      string `json:"name"`
 }
 
+// Repo is what pact needs to know about a user's my-pact repo
+type Repo struct {
+	Private     bool `json:"private"`
+	Permissions struct {
+		Push bool `json:"push"`
+	} `json:"permissions"`
+}
+
+// GetRepo fetches the user's my-pact repo, or returns nil if it doesn't exist
+func GetRepo(ctx context.Context, token, username string) (*Repo, error) {
+	return GetNamedRepo(ctx, token, username, "my-pact")
+}
+
+// GetNamedRepo fetches owner/name, or returns nil if it doesn't exist or the
+// token can't see it
+func GetNamedRepo(ctx context.Context, token, owner, name string) (*Repo, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s", owner, name)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+	case 404:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("failed to get repo: status %d", resp.StatusCode)
+	}
+
+	var repo Repo
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return nil, err
+	}
+	return &repo, nil
+}
+
 // RepoExists checks if the user's my-pact repo exists
-func RepoExists(token, username string) (bool, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/my-pact", username)
-	req, err := http.NewRequest("GET", url, nil)
+func RepoExists(ctx context.Context, token, username string) (bool, error) {
+	repo, err := GetRepo(ctx, token, username)
+	return repo != nil, err
+}
+
+// RepoIsPrivate reports whether the user's my-pact repo is private
+func RepoIsPrivate(ctx context.Context, token, username string) (bool, error) {
+	repo, err := GetRepo(ctx, token, username)
 	if err != nil {
 		return false, err
 	}
+	if repo == nil {
+		return false, fmt.Errorf("failed to get repo: status 404")
+	}
+	return repo.Private, nil
+}
+
+// SetRepoPrivate makes the user's my-pact repo private or public
+func SetRepoPrivate(ctx context.Context, token, username string, private bool) error {
+	jsonData, err := json.Marshal(map[string]bool{"private": private})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/my-pact", username)
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
 
 	client := &http.Client{Timeout: 30 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return false, err
+		return fmt.Errorf("failed to update repo: %w", err)
 	}
 	defer resp.Body.Close()
 
-	return resp.StatusCode == 200, nil
+	if resp.StatusCode != 200 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("failed to update repo: status %d, body: %s", resp.StatusCode, string(body))
+	}
+	return nil
 }
 
 // CreateRepo creates the user's my-pact repo
-func CreateRepo(token string) error {
+func CreateRepo(ctx context.Context, token string, private bool) error {
+	return createRepo(ctx, token, private, true)
+}
+
+// CreateEmptyRepo creates the user's my-pact repo without an initial commit,
+// ready for existing history to be pushed
+func CreateEmptyRepo(ctx context.Context, token string, private bool) error {
+	return createRepo(ctx, token, private, false)
+}
+
+func createRepo(ctx context.Context, token string, private, autoInit bool) error {
 	payload := map[string]interface{}{
 		"name":        "my-pact",
 		"description": "My development environment configuration - managed by pact",
-		"private":     false,
-		"auto_init":   true,
+		"private":     private,
+		"auto_init":   autoInit,
 	}
 
 	jsonData, err := json.Marshal(payload)
@@ -213,7 +322,7 @@ func CreateRepo(token string) error {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.github.com/user/repos", bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/user/repos", bytes.NewBuffer(jsonData))
 	if err != nil {
 		return err
 	}
@@ -235,3 +344,107 @@ func CreateRepo(token string) error {
 
 	return nil
 }
+
+// ErrNoKeyScope means the token can't manage SSH keys; tokens from before
+// pact asked for write:public_key can't
+var ErrNoKeyScope = errors.New("the GitHub token isn't allowed to manage SSH keys")
+
+// ListSSHKeys returns the public keys on the user's GitHub account, each as
+// "type base64" without a comment
+func ListSSHKeys(ctx context.Context, token string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://api.github.com/user/keys?per_page=100", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list SSH keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+	case 403, 404:
+		return nil, ErrNoKeyScope
+	default:
+		return nil, fmt.Errorf("failed to list SSH keys: status %d", resp.StatusCode)
+	}
+
+	var keys []struct {
+		Key string `json:"key"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, err
+	}
+	list := make([]string, len(keys))
+	for i, k := range keys {
+		list[i] = k.Key
+	}
+	return list, nil
+}
+
+// AddSSHKey adds a public key to the user's GitHub account
+func AddSSHKey(ctx context.Context, token, title, key string) error {
+	jsonData, err := json.Marshal(map[string]string{"title": title, "key": key})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/user/keys", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to add SSH key: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 201:
+		return nil
+	case 403, 404:
+		return ErrNoKeyScope
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("failed to add SSH key: status %d, body: %s", resp.StatusCode, string(body))
+}
+
+// RevokeToken asks GitHub to revoke token. It works for any token pact might
+// hold, OAuth or personal access token, so a replaced token can be
+// invalidated without the OAuth app's secret.
+func RevokeToken(ctx context.Context, token string) error {
+	jsonData, err := json.Marshal(map[string][]string{"credentials": {token}})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.github.com/credentials/revoke", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 202 || resp.StatusCode == 200 {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	return fmt.Errorf("failed to revoke token: status %d, body: %s", resp.StatusCode, string(body))
+}
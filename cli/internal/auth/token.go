@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"strings"
+)
+
+// Kinds of GitHub token, told apart by their prefix
+const (
+	KindOAuth       = "OAuth app token"
+	KindClassic     = "classic personal access token"
+	KindFineGrained = "fine-grained personal access token"
+	KindApp         = "GitHub App token"
+	KindUnknown     = "token"
+)
+
+// pactScopes are the scopes pact asks for when signing in
+var pactScopes = strings.Fields(scopes)
+
+// TokenInfo is who a token signs in as and what it's allowed to do
+type TokenInfo struct {
+	Login  string
+	Kind   string
+	Scopes []string // Classic and OAuth tokens only; fine-grained tokens have per-repo permissions
+}
+
+// TokenKind names the kind of token from its prefix
+func TokenKind(token string) string {
+	switch {
+	case strings.HasPrefix(token, "gho_"):
+		return KindOAuth
+	case strings.HasPrefix(token, "ghp_"):
+		return KindClassic
+	case strings.HasPrefix(token, "github_pat_"):
+		return KindFineGrained
+	case strings.HasPrefix(token, "ghu_"), strings.HasPrefix(token, "ghs_"):
+		return KindApp
+	}
+	return KindUnknown
+}
+
+// CheckToken asks GitHub who token signs in as and which scopes it has
+func CheckToken(ctx context.Context, token string) (*TokenInfo, error) {
+	user, header, err := getUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	info := &TokenInfo{Login: user.Login, Kind: TokenKind(token)}
+	if _, ok := header["X-Oauth-Scopes"]; ok {
+		info.Scopes = []string{}
+		for _, scope := range strings.Split(header.Get("X-OAuth-Scopes"), ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				info.Scopes = append(info.Scopes, scope)
+			}
+		}
+		if info.Kind == KindUnknown {
+			info.Kind = KindClassic
+		}
+	}
+	return info, nil
+}
+
+// HasScopes reports whether the token's access is granted in scopes, rather
+// than per-repo permissions that can only be checked against a repo
+func (t *TokenInfo) HasScopes() bool {
+	return t.Scopes != nil
+}
+
+// HasScope reports whether the token was granted scope
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// MissingScopes lists the scopes pact can't work without that the token
+// lacks
+func (t *TokenInfo) MissingScopes() []string {
+	if t.HasScopes() && !t.HasScope("repo") {
+		return []string{"repo"}
+	}
+	return nil
+}
+
+// ExtraScopes lists the token's scopes that pact never uses
+func (t *TokenInfo) ExtraScopes() []string {
+	var extra []string
+	for _, s := range t.Scopes {
+		used := false
+		for _, p := range pactScopes {
+			used = used || s == p
+		}
+		if !used {
+			extra = append(extra, s)
+		}
+	}
+	return extra
+}
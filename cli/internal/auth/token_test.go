@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenScopes(t *testing.T) {
+	info := &TokenInfo{Kind: KindClassic, Scopes: []string{"repo", "admin:org", "gist"}}
+	if got := info.MissingScopes(); got != nil {
+		t.Fatalf("MissingScopes() = %v, want none", got)
+	}
+	if got, want := info.ExtraScopes(), []string{"admin:org", "gist"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ExtraScopes() = %v, want %v", got, want)
+	}
+
+	info = &TokenInfo{Kind: KindClassic, Scopes: []string{}}
+	if got, want := info.MissingScopes(), []string{"repo"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("MissingScopes() = %v, want %v", got, want)
+	}
+
+	// Fine-grained tokens have no scopes to check
+	info = &TokenInfo{Kind: KindFineGrained}
+	if info.HasScopes() || info.MissingScopes() != nil || info.ExtraScopes() != nil {
+		t.Fatalf("fine-grained token: %+v", info)
+	}
+}
+
+func TestTokenKind(t *testing.T) {
+	for token, want := range map[string]string{
+		"gho_abc":         KindOAuth,
+		"ghp_abc":         KindClassic,
+		"github_pat_11AB": KindFineGrained,
+		"ghs_abc":         KindApp,
+		"abc123":          KindUnknown,
+	} {
+		if got := TokenKind(token); got != want {
+			t.Fatalf("TokenKind(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package backup keeps the files a sync would otherwise overwrite, so
+// 'pact restore-backup' can put them back
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/state"
+	"github.com/cloudboy-jh/pact/internal/undo"
+)
+
+// manifestName lists a set's files, inside the set's directory
+const manifestName = "backups.json"
+
+// Set is the files one pact run moved aside, kept in
+// .pact/state/backups/<timestamp>
+type Set struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Files   []File    `json:"files"`
+
+	dir string
+}
+
+// File is one backed-up file or directory
+type File struct {
+	Path   string `json:"path"`   // Where it was
+	Backup string `json:"backup"` // Its name in the set's directory
+}
+
+// current is this run's set, created on the first Save
+var current *Set
+
+// Save moves path into this run's backup set and returns where it went
+func Save(path string) (string, error) {
+	if current == nil {
+		set, err := newSet()
+		if err != nil {
+			return "", err
+		}
+		current = set
+	}
+
+	f := File{Path: path, Backup: fmt.Sprintf("%d-%s", len(current.Files), filepath.Base(path))}
+	dst := filepath.Join(current.dir, f.Backup)
+	if err := move(path, dst); err != nil {
+		return "", fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	current.Files = append(current.Files, f)
+	return dst, current.save()
+}
+
+func newSet() (*Set, error) {
+	root, err := state.BackupsDir()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	id := now.Format("20060102-150405")
+	// Two runs in the same second get their own sets
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(root, id)); os.IsNotExist(err) {
+			break
+		}
+		id = fmt.Sprintf("%s-%d", now.Format("20060102-150405"), n)
+	}
+	set := &Set{ID: id, Created: now, dir: filepath.Join(root, id)}
+	return set, os.MkdirAll(set.dir, 0755)
+}
+
+// List returns the backup sets, newest first
+func List() ([]*Set, error) {
+	root, err := state.BackupsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sets []*Set
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if set, err := load(filepath.Join(root, entry.Name())); err == nil && len(set.Files) > 0 {
+			sets = append(sets, set)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Created.After(sets[j].Created) })
+	return sets, nil
+}
+
+// Load returns the set with the given ID, or the newest for "latest"
+func Load(id string) (*Set, error) {
+	sets, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		if set.ID == id || id == "latest" {
+			return set, nil
+		}
+	}
+	if id == "latest" {
+		return nil, fmt.Errorf("no backups")
+	}
+	return nil, fmt.Errorf("no backup named %s (run 'pact restore-backup' to list them)", id)
+}
+
+func load(dir string) (*Set, error) {
+	data, err := os.ReadFile(filepath.Join(dir, manifestName))
+	if err != nil {
+		return nil, err
+	}
+	var set Set
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	set.dir = dir
+	return &set, nil
+}
+
+func (s *Set) save() error {
+	if len(s.Files) == 0 {
+		return os.RemoveAll(s.dir)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.dir, manifestName), data, 0644)
+}
+
+// Restore puts the set's files back where they were: all of them, or only
+// the given paths. Whatever pact placed there since is removed if it's a
+// symlink and backed up itself otherwise.
+func (s *Set) Restore(paths ...string) ([]string, error) {
+	only := make(map[string]bool)
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		only[abs] = true
+	}
+
+	var restored []string
+	var kept []File
+	var firstErr error
+	for _, f := range s.Files {
+		if len(only) > 0 && !only[f.Path] {
+			kept = append(kept, f)
+			continue
+		}
+		if err := s.restore(f); err != nil {
+			kept = append(kept, f)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		restored = append(restored, f.Path)
+		delete(only, f.Path)
+	}
+	for p := range only {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("%s isn't in backup %s", p, s.ID)
+		}
+	}
+
+	s.Files = kept
+	if err := s.save(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return restored, firstErr
+}
+
+func (s *Set) restore(f File) error {
+	if info, err := os.Lstat(f.Path); err == nil {
+		if info.Mode()&os.ModeSymlink != 0 {
+			if err := os.Remove(f.Path); err != nil {
+				return err
+			}
+		} else if _, err := Save(f.Path); err != nil {
+			return err
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	if err := move(filepath.Join(s.dir, f.Backup), f.Path); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", f.Path, err)
+	}
+	return nil
+}
+
+// move renames src to dst, copying when they're on different filesystems
+func move(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := undo.CopyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
@@ -0,0 +1,46 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveRestore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".pact"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	rc := filepath.Join(dir, ".zshrc")
+	os.WriteFile(rc, []byte("export EDITOR=vim\n"), 0644)
+	if _, err := Save(rc); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, err := os.Lstat(rc); !os.IsNotExist(err) {
+		t.Fatalf(".zshrc still in place after Save()")
+	}
+
+	// What pact placed gets removed when it's a link
+	os.Symlink(filepath.Join(dir, ".pact", "zshrc"), rc)
+
+	set, err := Load("latest")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	restored, err := set.Restore()
+	if err != nil || len(restored) != 1 {
+		t.Fatalf("Restore() = %v, %v", restored, err)
+	}
+	if data, _ := os.ReadFile(rc); string(data) != "export EDITOR=vim\n" {
+		t.Fatalf(".zshrc = %q after Restore()", data)
+	}
+	if sets, _ := List(); len(sets) != 0 {
+		t.Fatalf("List() = %d sets after restoring everything, want 0", len(sets))
+	}
+}
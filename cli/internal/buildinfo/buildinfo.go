@@ -0,0 +1,89 @@
+// Package buildinfo holds version metadata embedded at build time, falling
+// back to what the Go toolchain records for `go install` and `go build`.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Set at build time via ldflags, e.g.
+//
+//	-X github.com/cloudboy-jh/pact/internal/buildinfo.Version=1.2.3
+var (
+	Version = "dev"
+	Commit  = ""
+	Date    = ""
+	Channel = ""
+)
+
+// Info is the resolved build metadata
+type Info struct {
+	Version string
+	Commit  string
+	Date    string
+	Channel string // "stable", "prerelease", or "dev"
+	Dirty   bool   // Built from a modified working tree
+}
+
+// Get returns the build metadata, filling anything not set via ldflags from
+// the binary's embedded build info
+func Get() Info {
+	info := Info{
+		Version: strings.TrimPrefix(Version, "v"),
+		Commit:  Commit,
+		Date:    Date,
+		Channel: Channel,
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		// `go install module@v1.2.3` records the module version
+		if info.Version == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = strings.TrimPrefix(bi.Main.Version, "v")
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = setting.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = setting.Value
+				}
+			case "vcs.modified":
+				info.Dirty = setting.Value == "true"
+			}
+		}
+	}
+
+	if info.Channel == "" {
+		info.Channel = channelFor(info.Version)
+	}
+	return info
+}
+
+// ShortCommit returns the first 7 characters of the commit, with a -dirty
+// suffix for modified trees
+func (i Info) ShortCommit() string {
+	commit := i.Commit
+	if len(commit) > 7 {
+		commit = commit[:7]
+	}
+	if commit != "" && i.Dirty {
+		commit += "-dirty"
+	}
+	return commit
+}
+
+func channelFor(version string) string {
+	v, ok := ParseSemver(version)
+	switch {
+	case !ok:
+		return "dev"
+	case v.Pre != "":
+		return "prerelease"
+	default:
+		return "stable"
+	}
+}
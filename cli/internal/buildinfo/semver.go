@@ -0,0 +1,101 @@
+package buildinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Semver is a parsed semantic version (https://semver.org)
+type Semver struct {
+	Major, Minor, Patch int
+	Pre                 string // Pre-release identifiers, without the "-"
+}
+
+// ParseSemver parses "1.2.3", "v1.2.3-rc.1", or "1.2.3+build". Build
+// metadata is ignored.
+func ParseSemver(s string) (Semver, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	s, _, _ = strings.Cut(s, "+")
+	core, pre, _ := strings.Cut(s, "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Semver{}, false
+	}
+	nums := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, false
+		}
+		nums[i] = n
+	}
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2], Pre: pre}, true
+}
+
+// Compare returns -1, 0, or 1 as version a is older than, equal to, or newer
+// than b. Unparseable versions (like "dev") sort before every release.
+func Compare(a, b string) int {
+	va, okA := ParseSemver(a)
+	vb, okB := ParseSemver(b)
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	}
+
+	for _, d := range []int{va.Major - vb.Major, va.Minor - vb.Minor, va.Patch - vb.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+	return comparePre(va.Pre, vb.Pre)
+}
+
+// comparePre orders pre-release strings: a release outranks any pre-release,
+// numeric identifiers compare numerically and sort before alphanumeric ones,
+// and a longer list of otherwise equal identifiers is newer
+func comparePre(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		na, errA := strconv.Atoi(as[i])
+		nb, errB := strconv.Atoi(bs[i])
+		switch {
+		case errA == nil && errB == nil:
+			if na != nb {
+				return sign(na - nb)
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(as) - len(bs))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
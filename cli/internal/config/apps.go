@@ -0,0 +1,308 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AppsConfig is one OS's entry in the apps module, apps.<os>:
+//
+//	"darwin": {
+//	  "install": ["brave", "discord"],
+//	  "mas": {"Xcode": 497799835},
+//	  "shortcuts": {"brave": "cmd+shift+b"},
+//	  "startup": ["discord"],
+//	  "remove": ["garageband"]
+//	}
+type AppsConfig struct {
+	Install   []Package     // Apps to install
+	Mas       []MasApp      // App Store apps, macOS only
+	Shortcuts []AppShortcut // Keyboard shortcuts that open an app, by app
+	Startup   []string      // Apps to launch at login
+	Remove    []string      // Apps to uninstall
+	Flatpak   FlatpakConfig // Flatpak remotes and permissions, Linux only
+}
+
+// FlatpakConfig is apps.linux.flatpak:
+//
+//	"flatpak": {
+//	  "remotes": {"flathub-beta": "https://flathub.org/beta-repo/flathub-beta.flatpakrepo"},
+//	  "overrides": {"org.mozilla.firefox": ["--filesystem=~/Downloads", "--nosocket=x11"]}
+//	}
+//
+// overrides are 'flatpak override' flags, replacing the app's user overrides.
+type FlatpakConfig struct {
+	Remotes   []FlatpakRemote
+	Overrides []FlatpakOverride
+}
+
+// FlatpakRemote is a Flatpak repository to add
+type FlatpakRemote struct {
+	Name string
+	URL  string
+}
+
+// FlatpakOverride is the sandbox permissions to give one Flatpak app
+type FlatpakOverride struct {
+	App   string
+	Flags []string
+}
+
+// AppShortcut is a keyboard shortcut that opens an app
+type AppShortcut struct {
+	App  string `json:"app"`
+	Keys string `json:"keys"`
+}
+
+// appsOSKeys are the keys of the apps module that hold an AppsConfig, and
+// appsKeys the keys each can have
+var (
+	appsOSKeys = []string{"darwin", "linux", "windows"}
+	appsKeys   = []string{"install", "mas", "shortcuts", "startup", "remove", "flatpak"}
+)
+
+// Apps returns apps.<goos>. Entries that don't fit the schema are left out;
+// Validate reports them.
+func (c *PactConfig) Apps(goos string) AppsConfig {
+	prefix := "apps." + goos + "."
+	apps := AppsConfig{
+		Install: packageItems(c.Get(prefix + "install")),
+		Startup: stringItems(c.Get(prefix + "startup")),
+		Remove:  stringItems(c.Get(prefix + "remove")),
+	}
+	if goos == "darwin" {
+		apps.Mas = c.GetMasApps()
+	}
+	if goos == "linux" {
+		apps.Flatpak = c.flatpak()
+	}
+	if shortcuts, ok := c.Get(prefix + "shortcuts").(map[string]any); ok {
+		for app, v := range shortcuts {
+			if keys, ok := v.(string); ok {
+				apps.Shortcuts = append(apps.Shortcuts, AppShortcut{App: app, Keys: keys})
+			}
+		}
+		sort.Slice(apps.Shortcuts, func(i, j int) bool { return apps.Shortcuts[i].App < apps.Shortcuts[j].App })
+	}
+	return apps
+}
+
+// flatpak returns apps.linux.flatpak
+func (c *PactConfig) flatpak() FlatpakConfig {
+	var flatpak FlatpakConfig
+	for name, v := range c.GetMap("apps.linux.flatpak.remotes") {
+		if url, ok := v.(string); ok && url != "" {
+			flatpak.Remotes = append(flatpak.Remotes, FlatpakRemote{Name: name, URL: url})
+		}
+	}
+	sort.Slice(flatpak.Remotes, func(i, j int) bool { return flatpak.Remotes[i].Name < flatpak.Remotes[j].Name })
+	for app, v := range c.GetMap("apps.linux.flatpak.overrides") {
+		flatpak.Overrides = append(flatpak.Overrides, FlatpakOverride{App: app, Flags: stringItems(v)})
+	}
+	sort.Slice(flatpak.Overrides, func(i, j int) bool { return flatpak.Overrides[i].App < flatpak.Overrides[j].App })
+	return flatpak
+}
+
+// stringItems returns the strings in a JSON array, skipping anything else
+func stringItems(v any) []string {
+	list, _ := v.([]any)
+	var items []string
+	for _, item := range list {
+		if s, ok := item.(string); ok && s != "" {
+			items = append(items, s)
+		}
+	}
+	return items
+}
+
+// validateApps checks apps.<os> against AppsConfig, naming the exact path of
+// each problem
+func validateApps(apps map[string]any) []string {
+	var problems []string
+	for _, goos := range appsOSKeys {
+		v, ok := apps[goos]
+		if !ok {
+			continue
+		}
+		entry, ok := v.(map[string]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("apps.%s should be an object", goos))
+			continue
+		}
+
+		keys := make([]string, 0, len(entry))
+		for key := range entry {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			path := "apps." + goos + "." + key
+			switch key {
+			case "install":
+				problems = append(problems, validatePackageList(entry[key], path)...)
+			case "startup", "remove":
+				problems = append(problems, validateStringList(entry[key], path)...)
+			case "mas":
+				if goos != "darwin" {
+					problems = append(problems, fmt.Sprintf("%s only works under apps.darwin", path))
+					continue
+				}
+				problems = append(problems, validateMas(entry[key], path)...)
+			case "flatpak":
+				if goos != "linux" {
+					problems = append(problems, fmt.Sprintf("%s only works under apps.linux", path))
+					continue
+				}
+				problems = append(problems, validateFlatpak(entry[key], path)...)
+			case "shortcuts":
+				shortcuts, ok := entry[key].(map[string]any)
+				if !ok {
+					problems = append(problems, fmt.Sprintf("%s should be an object of apps to keys", path))
+					continue
+				}
+				names := make([]string, 0, len(shortcuts))
+				for name := range shortcuts {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					if _, ok := shortcuts[name].(string); !ok {
+						problems = append(problems, fmt.Sprintf("%s.%s should be a string such as \"cmd+shift+b\"", path, name))
+					}
+				}
+			default:
+				problems = append(problems, fmt.Sprintf("%s is unknown (use %s)", path, strings.Join(appsKeys, ", ")))
+			}
+		}
+	}
+	return problems
+}
+
+// validateStringList checks that v is an array of strings
+func validateStringList(v any, path string) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s should be a list of strings", path)}
+	}
+	var problems []string
+	for i, item := range list {
+		if _, ok := item.(string); !ok {
+			problems = append(problems, fmt.Sprintf("%s[%d] should be a string", path, i))
+		}
+	}
+	return problems
+}
+
+// validateFlatpak checks apps.linux.flatpak
+func validateFlatpak(v any, path string) []string {
+	flatpak, ok := v.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s should be an object with remotes and overrides", path)}
+	}
+	var problems []string
+	for _, key := range sortedKeys(flatpak) {
+		switch key {
+		case "remotes":
+			remotes, ok := flatpak[key].(map[string]any)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.remotes should be an object of names to URLs", path))
+				continue
+			}
+			for _, name := range sortedKeys(remotes) {
+				if url, ok := remotes[name].(string); !ok || url == "" {
+					problems = append(problems, fmt.Sprintf("%s.remotes.%s should be a URL", path, name))
+				}
+			}
+		case "overrides":
+			overrides, ok := flatpak[key].(map[string]any)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.overrides should be an object of app IDs to flags", path))
+				continue
+			}
+			for _, app := range sortedKeys(overrides) {
+				appPath := path + ".overrides." + app
+				problems = append(problems, validateStringList(overrides[app], appPath)...)
+				flags, _ := overrides[app].([]any)
+				for i, v := range flags {
+					if flag, ok := v.(string); ok && !strings.HasPrefix(flag, "--") {
+						problems = append(problems, fmt.Sprintf("%s[%d] should be a flag such as \"--filesystem=home\"", appPath, i))
+					}
+				}
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s.%s is unknown (use remotes, overrides)", path, key))
+		}
+	}
+	return problems
+}
+
+// validateMas checks apps.darwin.mas, a list of App Store IDs or an object
+// of names to IDs
+func validateMas(v any, path string) []string {
+	var problems []string
+	switch mas := v.(type) {
+	case []any:
+		for i, id := range mas {
+			if masID(id) == "" {
+				problems = append(problems, fmt.Sprintf("%s[%d] should be an App Store ID", path, i))
+			}
+		}
+	case map[string]any:
+		names := make([]string, 0, len(mas))
+		for name := range mas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if masID(mas[name]) == "" {
+				problems = append(problems, fmt.Sprintf("%s.%s should be an App Store ID", path, name))
+			}
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s should be a list of App Store IDs or an object of names to IDs", path))
+	}
+	return problems
+}
+
+// MasApp is a Mac App Store app, installed with the mas CLI
+type MasApp struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+// GetMasApps returns the App Store apps in apps.darwin.mas: an array of IDs,
+// or an object of names to IDs
+//
+//	"mas": {"Xcode": 497799835, "Things 3": 904280696}
+func (c *PactConfig) GetMasApps() []MasApp {
+	var apps []MasApp
+	switch mas := c.Get("apps.darwin.mas").(type) {
+	case []any:
+		for _, v := range mas {
+			if id := masID(v); id != "" {
+				apps = append(apps, MasApp{ID: id})
+			}
+		}
+	case map[string]any:
+		for name, v := range mas {
+			if id := masID(v); id != "" {
+				apps = append(apps, MasApp{ID: id, Name: name})
+			}
+		}
+		sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+	}
+	return apps
+}
+
+// masID reads an App Store ID written as a number or a string
+func masID(v any) string {
+	switch id := v.(type) {
+	case float64:
+		return strconv.FormatInt(int64(id), 10)
+	case string:
+		return id
+	}
+	return ""
+}
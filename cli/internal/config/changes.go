@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Changes describes how pact.json changed between two versions, e.g.
+// "added lazygit", "changed shell.prompt.theme to catppuccin". Either side
+// may be nil.
+func Changes(old, new *PactConfig) []string {
+	var before, after map[string]any
+	if old != nil {
+		before = old.Raw
+	}
+	if new != nil {
+		after = new.Raw
+	}
+
+	var changes []string
+	diffValues("", before, after, &changes)
+	return changes
+}
+
+func diffValues(path string, old, new any, changes *[]string) {
+	oldMap, oldIsMap := old.(map[string]any)
+	newMap, newIsMap := new.(map[string]any)
+	if (oldIsMap || old == nil) && (newIsMap || new == nil) && (oldIsMap || newIsMap) {
+		keys := make(map[string]bool)
+		for k := range oldMap {
+			keys[k] = true
+		}
+		for k := range newMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		for _, k := range sorted {
+			// Descriptions document the config; they aren't environment changes
+			if k == "description" || k == "descriptions" {
+				continue
+			}
+			diffValues(joinPath(path, k), oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := old.([]any)
+	newList, newIsList := new.([]any)
+	if (oldIsList || old == nil) && (newIsList || new == nil) && (oldIsList || newIsList) {
+		oldSet := listSet(oldList)
+		newSet := listSet(newList)
+		for _, item := range newList {
+			if s := formatValue(item); !oldSet[s] {
+				*changes = append(*changes, "added "+s)
+			}
+		}
+		for _, item := range oldList {
+			if s := formatValue(item); !newSet[s] {
+				*changes = append(*changes, "removed "+s)
+			}
+		}
+		return
+	}
+
+	switch {
+	case old == nil:
+		*changes = append(*changes, fmt.Sprintf("added %s", path))
+	case new == nil:
+		*changes = append(*changes, fmt.Sprintf("removed %s", path))
+	case formatValue(old) != formatValue(new):
+		*changes = append(*changes, fmt.Sprintf("changed %s to %s", path, formatValue(new)))
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func listSet(items []any) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[formatValue(item)] = true
+	}
+	return set
+}
+
+// formatValue renders a value for a change description; objects in lists
+// are identified by their name field when they have one
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case map[string]any:
+		if name, ok := val["name"].(string); ok {
+			return name
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return "{" + strings.Join(keys, ", ") + "}"
+	default:
+		return fmt.Sprint(val)
+	}
+}
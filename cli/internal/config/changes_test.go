@@ -0,0 +1,71 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	old, err := Parse([]byte(`{
+		"cli": {"tools": ["jq", "atom"]},
+		"shell": {"prompt": {"tool": "starship", "theme": "nord"}}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse(old) error: %v", err)
+	}
+	new, err := Parse([]byte(`{
+		"cli": {"tools": ["jq", "lazygit"], "description": "tools"},
+		"shell": {"prompt": {"tool": "starship", "theme": "catppuccin"}},
+		"git": {"user": "jh"}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse(new) error: %v", err)
+	}
+
+	got := Changes(old, new)
+	want := []string{
+		"added lazygit",
+		"removed atom",
+		"added git.user",
+		"changed shell.prompt.theme to catppuccin",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Changes() = %q, want %q", got, want)
+	}
+
+	if got := Changes(new, new); len(got) != 0 {
+		t.Fatalf("Changes(same) = %q, want none", got)
+	}
+}
+
+func TestMergeJSON(t *testing.T) {
+	base := []byte(`{"cli": {"tools": ["jq", "atom"]}, "shell": {"prompt": "starship"}, "git": {"user": "jh"}}`)
+	local := []byte(`{"cli": {"tools": ["jq", "lazygit"]}, "shell": {"prompt": "oh-my-posh"}, "git": {"user": "jh"}}`)
+	remote := []byte(`{"cli": {"tools": ["jq", "atom", "fzf"]}, "shell": {"prompt": "pure"}, "editor": {"default": "nvim"}}`)
+
+	var asked []string
+	merged, err := MergeJSON(base, local, remote, func(path string, l, r any) bool {
+		asked = append(asked, path)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("MergeJSON() error: %v", err)
+	}
+
+	got, err := Parse(merged)
+	if err != nil {
+		t.Fatalf("Parse(merged) error: %v", err)
+	}
+	if tools := got.GetStringSlice("cli.tools"); !reflect.DeepEqual(tools, []string{"jq", "lazygit", "fzf"}) {
+		t.Fatalf("cli.tools = %q, want [jq lazygit fzf]", tools)
+	}
+	if prompt := got.GetString("shell.prompt"); prompt != "pure" {
+		t.Fatalf("shell.prompt = %q, want pure", prompt)
+	}
+	if got.GetString("editor.default") != "nvim" || got.GetString("git.user") != "" {
+		t.Fatalf("merged = %s, want editor added and git removed", merged)
+	}
+	if !reflect.DeepEqual(asked, []string{"shell.prompt"}) {
+		t.Fatalf("resolve asked about %q, want only shell.prompt", asked)
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import "sort"
+
+// ModuleDescription returns the module's "description" field
+func (c *PactConfig) ModuleDescription(module string) string {
+	return c.GetString(module + ".description")
+}
+
+// ItemDescriptions returns descriptions for items in a module. Items that are
+// objects (files, custom sources, ...) carry their own "description"; items
+// in plain string lists are described in the module's "descriptions" map:
+//
+//	"cli": {
+//	  "description": "Tools every engineer on the team needs",
+//	  "tools": ["ripgrep", "jq"],
+//	  "descriptions": {"jq": "Used by the deploy scripts"}
+//	}
+func (c *PactConfig) ItemDescriptions(module string) map[string]string {
+	descriptions := make(map[string]string)
+
+	m := c.GetMap(module)
+	if m == nil {
+		return descriptions
+	}
+
+	collectItemDescriptions(m, descriptions)
+
+	if explicit, ok := m["descriptions"].(map[string]any); ok {
+		for name, d := range explicit {
+			if s, ok := d.(string); ok {
+				descriptions[name] = s
+			}
+		}
+	}
+	return descriptions
+}
+
+func collectItemDescriptions(node map[string]any, descriptions map[string]string) {
+	for key, val := range node {
+		if key == "descriptions" {
+			continue
+		}
+		child, ok := val.(map[string]any)
+		if !ok {
+			continue
+		}
+		if d, ok := child["description"].(string); ok {
+			descriptions[key] = d
+		}
+		collectItemDescriptions(child, descriptions)
+	}
+}
+
+// ModuleItems returns the named items in a module: entries of string lists
+// (tools, extensions, apps) and keys of "files" maps, sorted
+func (c *PactConfig) ModuleItems(module string) []string {
+	seen := make(map[string]bool)
+	if m := c.GetMap(module); m != nil {
+		collectModuleItems(m, seen)
+	}
+
+	items := make([]string, 0, len(seen))
+	for item := range seen {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return items
+}
+
+func collectModuleItems(node map[string]any, seen map[string]bool) {
+	for key, val := range node {
+		switch v := val.(type) {
+		case []any:
+			if key == "needs" {
+				// Modules this one needs, not its items
+				continue
+			}
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					seen[s] = true
+				}
+			}
+		case map[string]any:
+			if key == "descriptions" {
+				continue
+			}
+			if key == "files" {
+				for name := range v {
+					seen[name] = true
+				}
+				continue
+			}
+			collectModuleItems(v, seen)
+		}
+	}
+}
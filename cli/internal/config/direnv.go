@@ -0,0 +1,19 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DirenvConfigDir returns the directory holding direnv.toml and direnvrc.
+// direnv uses XDG_CONFIG_HOME when set and ~/.config on every OS otherwise.
+func DirenvConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "direnv")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "direnv")
+}
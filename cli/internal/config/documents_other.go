@@ -0,0 +1,14 @@
+//go:build !windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// GetDocumentsDir returns the user's Documents folder
+func GetDocumentsDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Documents")
+}
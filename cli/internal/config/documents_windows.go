@@ -0,0 +1,36 @@
+//go:build windows
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// GetDocumentsDir returns the user's Documents folder, following OneDrive
+// (or Group Policy) folder redirection recorded in the registry
+func GetDocumentsDir() string {
+	home, _ := os.UserHomeDir()
+	fallback := filepath.Join(home, "Documents")
+
+	key, err := registry.OpenKey(registry.CURRENT_USER,
+		`Software\Microsoft\Windows\CurrentVersion\Explorer\User Shell Folders`,
+		registry.QUERY_VALUE)
+	if err != nil {
+		return fallback
+	}
+	defer key.Close()
+
+	value, _, err := key.GetStringValue("Personal")
+	if err != nil || value == "" {
+		return fallback
+	}
+
+	expanded, err := registry.ExpandString(value)
+	if err != nil || expanded == "" {
+		return fallback
+	}
+	return expanded
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// GetIgnoredDrift returns the drift to leave out on every machine: names, or
+// glob patterns such as "com.corp.*", of items by module
+//
+//	"ignore": {"cli": ["python3"], "apps": ["com.corp.*"]}
+func (c *PactConfig) GetIgnoredDrift() map[string][]string {
+	ignore := make(map[string][]string)
+	for module := range c.GetMap("ignore") {
+		ignore[module] = c.GetStringSlice("ignore." + module)
+	}
+	return ignore
+}
+
+func validateIgnoredDrift(v any) []string {
+	ignore, ok := v.(map[string]any)
+	if !ok {
+		return []string{"ignore should be an object of module names to lists of items"}
+	}
+	var problems []string
+	for _, module := range sortedKeys(ignore) {
+		list, ok := ignore[module].([]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("ignore.%s should be a list of names", module))
+			continue
+		}
+		for i, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("ignore.%s[%d] should be a string", module, i))
+				continue
+			}
+			if _, err := filepath.Match(name, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("ignore.%s[%d] is a bad pattern: %s", module, i, name))
+			}
+		}
+	}
+	return problems
+}
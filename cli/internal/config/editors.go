@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// EditorConfigDir returns the user config directory of an editor pact can
+// place keybindings and snippets for ("vscode", "cursor", "zed", or
+// "neovim"), or "" for any other editor
+func EditorConfigDir(editor string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" {
+		xdg = filepath.Join(home, ".config")
+	}
+
+	switch editor {
+	case "vscode", "cursor":
+		app := "Code"
+		if editor == "cursor" {
+			app = "Cursor"
+		}
+		switch runtime.GOOS {
+		case "darwin":
+			return filepath.Join(home, "Library", "Application Support", app, "User")
+		case "windows":
+			return filepath.Join(windowsAppData(home), app, "User")
+		}
+		return filepath.Join(xdg, app, "User")
+	case "zed":
+		if runtime.GOOS == "windows" {
+			return filepath.Join(windowsAppData(home), "Zed")
+		}
+		return filepath.Join(xdg, "zed")
+	case "neovim":
+		if runtime.GOOS == "windows" {
+			if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
+				return filepath.Join(dir, "nvim")
+			}
+			return filepath.Join(home, "AppData", "Local", "nvim")
+		}
+		return filepath.Join(xdg, "nvim")
+	}
+	return ""
+}
+
+func windowsAppData(home string) string {
+	if dir := os.Getenv("APPDATA"); dir != "" {
+		return dir
+	}
+	return filepath.Join(home, "AppData", "Roaming")
+}
+
+// KeybindingEditors are the editors the keybindings and snippets modules
+// support
+var KeybindingEditors = []string{"vscode", "cursor", "zed", "neovim"}
+
+// KeybindingsPath returns where an editor reads user keybindings. Neovim
+// gets its own file in plugin/, which it loads after init.lua.
+func KeybindingsPath(editor string) string {
+	dir := EditorConfigDir(editor)
+	if dir == "" {
+		return ""
+	}
+	switch editor {
+	case "zed":
+		return filepath.Join(dir, "keymap.json")
+	case "neovim":
+		return filepath.Join(dir, "plugin", "pact-keybindings.lua")
+	}
+	return filepath.Join(dir, "keybindings.json")
+}
+
+// SnippetsDir returns the directory an editor loads user snippets from
+func SnippetsDir(editor string) string {
+	dir := EditorConfigDir(editor)
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "snippets")
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// keyOrder is the order keys appear in each object of a parsed pact.json, so
+// saving it changes only what was edited
+type keyOrder struct {
+	keys     []string
+	children map[string]*keyOrder // By key, or by index in an array
+}
+
+// readKeyOrder records the key order of every object in data, or returns
+// nil if it isn't valid JSON
+func readKeyOrder(data []byte) *keyOrder {
+	order, err := readValueOrder(json.NewDecoder(bytes.NewReader(data)))
+	if err != nil {
+		return nil
+	}
+	return order
+}
+
+// readValueOrder reads one value, returning the key order within it, or nil
+// if it's neither an object nor an array
+func readValueOrder(dec *json.Decoder) (*keyOrder, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil, nil
+	}
+
+	order := &keyOrder{children: make(map[string]*keyOrder)}
+	for i := 0; dec.More(); i++ {
+		name := strconv.Itoa(i)
+		if delim == '{' {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			name, _ = tok.(string)
+			order.keys = append(order.keys, name)
+		}
+		child, err := readValueOrder(dec)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			order.children[name] = child
+		}
+	}
+	if _, err := dec.Token(); err != nil { // The closing bracket
+		return nil, err
+	}
+	return order, nil
+}
+
+// child returns the order within a key or array index, if known
+func (o *keyOrder) child(name string) *keyOrder {
+	if o == nil {
+		return nil
+	}
+	return o.children[name]
+}
+
+// sorted returns m's keys in their original order, followed by new keys
+// sorted by name
+func (o *keyOrder) sorted(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	seen := make(map[string]bool)
+	if o != nil {
+		for _, k := range o.keys {
+			if _, ok := m[k]; ok && !seen[k] {
+				keys = append(keys, k)
+				seen[k] = true
+			}
+		}
+	}
+	var added []string
+	for k := range m {
+		if !seen[k] {
+			added = append(added, k)
+		}
+	}
+	sort.Strings(added)
+	return append(keys, added...)
+}
+
+// encodeJSON writes v indented by two spaces, like json.MarshalIndent, but
+// with object keys in order and <, > and & left as they are
+func encodeJSON(buf *bytes.Buffer, v any, order *keyOrder, indent string) error {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) == 0 {
+			buf.WriteString("{}")
+			return nil
+		}
+		buf.WriteString("{\n")
+		for i, k := range order.sorted(v) {
+			buf.WriteString(indent + "  ")
+			if err := encodeJSON(buf, k, nil, ""); err != nil {
+				return err
+			}
+			buf.WriteString(": ")
+			if err := encodeJSON(buf, v[k], order.child(k), indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "}")
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString("[]")
+			return nil
+		}
+		buf.WriteString("[\n")
+		for i, item := range v {
+			buf.WriteString(indent + "  ")
+			if err := encodeJSON(buf, item, order.child(strconv.Itoa(i)), indent+"  "); err != nil {
+				return err
+			}
+			if i < len(v)-1 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('\n')
+		}
+		buf.WriteString(indent + "]")
+	default:
+		var out bytes.Buffer
+		enc := json.NewEncoder(&out)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent(indent, "  ")
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		buf.Write(bytes.TrimSuffix(out.Bytes(), []byte("\n")))
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FishConfigDir returns the directory holding config.fish. fish uses
+// XDG_CONFIG_HOME when set and ~/.config otherwise.
+func FishConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "fish")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "fish")
+}
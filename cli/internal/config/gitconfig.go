@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// GitConfig returns git.config as git config keys and values. Objects nest
+// into dotted keys, so {"alias": {"co": "checkout"}} sets alias.co, and
+// booleans and numbers are written the way git reads them. Other values are
+// left out; Validate reports them.
+func (c *PactConfig) GitConfig() map[string]string {
+	settings := make(map[string]string)
+	flattenGitConfig(c.GetMap("git.config"), "", settings)
+	return settings
+}
+
+func flattenGitConfig(node map[string]any, prefix string, settings map[string]string) {
+	for key, val := range node {
+		if child, ok := val.(map[string]any); ok {
+			flattenGitConfig(child, prefix+key+".", settings)
+			continue
+		}
+		if s, ok := gitConfigValue(val); ok {
+			settings[prefix+key] = s
+		}
+	}
+}
+
+// gitConfigValue formats a pact.json value for git config
+func gitConfigValue(val any) (string, bool) {
+	switch v := val.(type) {
+	case string:
+		return v, true
+	case bool:
+		return strconv.FormatBool(v), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// validateGitConfig reports git.config values git config can't take
+func validateGitConfig(node map[string]any, prefix string) []string {
+	var problems []string
+	for key, val := range node {
+		if child, ok := val.(map[string]any); ok {
+			problems = append(problems, validateGitConfig(child, prefix+key+".")...)
+			continue
+		}
+		if _, ok := gitConfigValue(val); !ok {
+			problems = append(problems, fmt.Sprintf("git.config.%s%s should be a string, number, or boolean", prefix, key))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
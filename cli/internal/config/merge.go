@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// absent stands for a key one side of a merge doesn't have
+type absent struct{}
+
+// MergeJSON merges the changes this machine (local) and the remote each made
+// to pact.json since base, the version both last had; base may be nil.
+// Objects merge key by key and lists as a union, keeping removals either
+// side made. When both changed the same value differently, resolve picks:
+// it gets the key's path and both values (nil when removed) and returns
+// true to take the remote's.
+func MergeJSON(base, local, remote []byte, resolve func(path string, local, remote any) bool) ([]byte, error) {
+	var b, l, r any = absent{}, nil, nil
+	if base != nil {
+		if err := json.Unmarshal(base, &b); err != nil {
+			b = absent{}
+		}
+	}
+	if err := json.Unmarshal(local, &l); err != nil {
+		return nil, fmt.Errorf("pact.json here isn't valid JSON: %w", err)
+	}
+	if err := json.Unmarshal(remote, &r); err != nil {
+		return nil, fmt.Errorf("the remote's pact.json isn't valid JSON: %w", err)
+	}
+
+	merged := mergeValue("", b, l, r, resolve)
+	output, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(output, '\n'), nil
+}
+
+func mergeValue(path string, base, local, remote any, resolve func(string, any, any) bool) any {
+	switch {
+	case reflect.DeepEqual(local, remote):
+		return local
+	case reflect.DeepEqual(base, local):
+		return remote
+	case reflect.DeepEqual(base, remote):
+		return local
+	}
+
+	localMap, localIsMap := local.(map[string]any)
+	remoteMap, remoteIsMap := remote.(map[string]any)
+	if localIsMap && remoteIsMap {
+		baseMap, _ := base.(map[string]any)
+		keys := make(map[string]bool)
+		for k := range localMap {
+			keys[k] = true
+		}
+		for k := range remoteMap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+
+		merged := make(map[string]any, len(sorted))
+		for _, k := range sorted {
+			v := mergeValue(joinPath(path, k), lookup(baseMap, k), lookup(localMap, k), lookup(remoteMap, k), resolve)
+			if _, ok := v.(absent); !ok {
+				merged[k] = v
+			}
+		}
+		return merged
+	}
+
+	localList, localIsList := local.([]any)
+	remoteList, remoteIsList := remote.([]any)
+	if localIsList && remoteIsList {
+		baseList, _ := base.([]any)
+		return mergeList(baseList, localList, remoteList)
+	}
+
+	if resolve(path, present(local), present(remote)) {
+		return remote
+	}
+	return local
+}
+
+// mergeList keeps the local list's order, drops what the remote removed,
+// and appends what it added
+func mergeList(base, local, remote []any) []any {
+	baseSet, localSet, remoteSet := jsonSet(base), jsonSet(local), jsonSet(remote)
+	merged := make([]any, 0, len(local)+len(remote))
+	for _, item := range local {
+		if k := jsonKey(item); baseSet[k] && !remoteSet[k] {
+			continue
+		}
+		merged = append(merged, item)
+	}
+	for _, item := range remote {
+		if k := jsonKey(item); !baseSet[k] && !localSet[k] {
+			merged = append(merged, item)
+			localSet[k] = true
+		}
+	}
+	return merged
+}
+
+func lookup(m map[string]any, key string) any {
+	if v, ok := m[key]; ok {
+		return v
+	}
+	return absent{}
+}
+
+// present turns absent into nil for resolve
+func present(v any) any {
+	if _, ok := v.(absent); ok {
+		return nil
+	}
+	return v
+}
+
+func jsonSet(items []any) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[jsonKey(item)] = true
+	}
+	return set
+}
+
+func jsonKey(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
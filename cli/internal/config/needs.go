@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ModuleNeeds returns the modules a module says it depends on:
+//
+//	"editor": {"needs": ["cli"], ...}
+func (c *PactConfig) ModuleNeeds(module string) []string {
+	return c.GetStringSlice(module + ".needs")
+}
+
+// OrderByNeeds sorts modules so each comes after the modules it needs,
+// keeping the given order otherwise. Needs outside the list are ignored,
+// and a cycle is broken where it's found.
+func (c *PactConfig) OrderByNeeds(modules []string) []string {
+	in := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		in[m] = true
+	}
+
+	ordered := make([]string, 0, len(modules))
+	visited := make(map[string]bool, len(modules))
+	var visit func(m string)
+	visit = func(m string) {
+		if visited[m] {
+			return
+		}
+		visited[m] = true
+		for _, need := range c.ModuleNeeds(m) {
+			if in[need] {
+				visit(need)
+			}
+		}
+		ordered = append(ordered, m)
+	}
+	for _, m := range modules {
+		visit(m)
+	}
+	return ordered
+}
+
+// validateNeeds checks that every needs entry names a module in pact.json
+func (c *PactConfig) validateNeeds() []string {
+	modules := c.GetModules()
+	sort.Strings(modules)
+	known := make(map[string]bool, len(modules))
+	for _, m := range modules {
+		known[m] = true
+	}
+
+	var problems []string
+	for _, m := range modules {
+		v := c.Get(m + ".needs")
+		if v == nil {
+			continue
+		}
+		list, ok := v.([]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.needs should be a list of modules", m))
+			continue
+		}
+		for i, item := range list {
+			need, ok := item.(string)
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s.needs[%d] should be a string", m, i))
+			case !known[need]:
+				problems = append(problems, fmt.Sprintf("%s.needs: no module named %s", m, need))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// NushellPlaceholder can prefix a sync target to mean nushell's config dir,
+// e.g. "{nushell}/config.nu", since that dir differs per OS
+const NushellPlaceholder = "{nushell}"
+
+// NushellConfigDir returns the directory holding nushell's env.nu and
+// config.nu. Like nu, it prefers XDG_CONFIG_HOME when set and otherwise uses
+// the platform config dir (~/.config, ~/Library/Application Support, or
+// %APPDATA%).
+func NushellConfigDir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "nushell")
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "nushell")
+}
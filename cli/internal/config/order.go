@@ -0,0 +1,66 @@
+package config
+
+import (
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// ShuffleSeedEnv, when set to a number, shuffles the order modules and
+// files are returned in with that seed. It's for tests: a sync that only
+// works in sorted order has a hidden dependency that needs declaring.
+const ShuffleSeedEnv = "PACT_SHUFFLE_SEED"
+
+// ShuffleSeed returns the seed from PACT_SHUFFLE_SEED, if set
+func ShuffleSeed() (int64, bool) {
+	seed, err := strconv.ParseInt(os.Getenv(ShuffleSeedEnv), 10, 64)
+	return seed, err == nil
+}
+
+// shuffle reorders n elements with the PACT_SHUFFLE_SEED seed, the same
+// way every time for the same seed; without a seed it does nothing
+func shuffle(n int, swap func(i, j int)) {
+	if seed, ok := ShuffleSeed(); ok {
+		rand.New(rand.NewSource(seed)).Shuffle(n, swap)
+	}
+}
+
+// firstModules come before every other module, which follow by name, so
+// pickers and status list modules the same way every run
+var firstModules = []string{"cli", "shell", "git"}
+
+// SortModules orders modules the way every list of them is shown and
+// applied: cli, shell, and git first, then the rest by name
+func SortModules(modules []string) {
+	sort.SliceStable(modules, func(i, j int) bool {
+		return moduleLess(modules[i], modules[j])
+	})
+}
+
+func moduleLess(a, b string) bool {
+	ra, rb := moduleRank(a), moduleRank(b)
+	if ra != rb {
+		return ra < rb
+	}
+	return a < b
+}
+
+func moduleRank(module string) int {
+	for i, m := range firstModules {
+		if m == module {
+			return i
+		}
+	}
+	return len(firstModules)
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
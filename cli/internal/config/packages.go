@@ -0,0 +1,195 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PackageManagers are the language package managers whose global packages
+// the cli module lists under cli.<manager>:
+//
+//	"cli": {
+//	  "npm": ["typescript", "@biomejs/biome@1.9"],
+//	  "pip": ["black", "httpie==3.2"],
+//	  "cargo": ["cargo-watch"],
+//	  "go": ["golang.org/x/tools/gopls@latest"]
+//	}
+//
+// pip packages are installed with pipx.
+var PackageManagers = []string{"npm", "pip", "cargo", "go"}
+
+// PackageName returns the name of a cli.<manager> entry without its version:
+// "@biomejs/biome@1.9" is "@biomejs/biome", "httpie==3.2" is "httpie", and
+// "golang.org/x/tools/gopls@latest" is "golang.org/x/tools/gopls"
+func PackageName(manager, spec string) string {
+	switch manager {
+	case "npm":
+		// A scoped package starts with its own @
+		if i := strings.LastIndex(spec, "@"); i > 0 {
+			return spec[:i]
+		}
+	case "pip":
+		if i := strings.IndexAny(spec, "=<>!~[; "); i > 0 {
+			return spec[:i]
+		}
+	case "cargo", "go":
+		name, _, _ := strings.Cut(spec, "@")
+		return name
+	}
+	return spec
+}
+
+// GoBinaryName returns the command 'go install' builds for a package path,
+// skipping a major version suffix: "github.com/x/tool/v2" builds "tool"
+func GoBinaryName(pkg string) string {
+	pkg = PackageName("go", pkg)
+	base := path.Base(pkg)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = path.Base(path.Dir(pkg))
+	}
+	return base
+}
+
+// PackageListCommand returns the command listing manager's global packages,
+// parsed by ParsePackageList. Go has none; see GoBinDir.
+func PackageListCommand(manager string) []string {
+	switch manager {
+	case "npm":
+		return []string{"npm", "ls", "-g", "--depth=0", "--json"}
+	case "pip":
+		return []string{"pipx", "list", "--short"}
+	case "cargo":
+		return []string{"cargo", "install", "--list"}
+	}
+	return nil
+}
+
+// ParsePackageList returns the package names in PackageListCommand's output
+func ParsePackageList(manager string, output []byte) []string {
+	var names []string
+	switch manager {
+	case "npm":
+		var list struct {
+			Dependencies map[string]any `json:"dependencies"`
+		}
+		if json.Unmarshal(output, &list) != nil {
+			return nil
+		}
+		for name := range list.Dependencies {
+			// npm and corepack ship with node
+			if name != "npm" && name != "corepack" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	case "pip", "cargo":
+		// "black 24.2.0" from pipx, "ripgrep v14.1.0:" then indented
+		// binaries from cargo
+		for _, line := range strings.Split(string(output), "\n") {
+			if line == "" || line[0] == ' ' || line[0] == '\t' {
+				continue
+			}
+			names = append(names, strings.Fields(line)[0])
+		}
+	}
+	return names
+}
+
+// GoBinDir returns where 'go install' puts commands, or "" without Go
+func GoBinDir() string {
+	output, err := exec.Command("go", "env", "GOBIN", "GOPATH").Output()
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(output), "\n")
+	if gobin := strings.TrimSpace(lines[0]); gobin != "" {
+		return gobin
+	}
+	if len(lines) < 2 || strings.TrimSpace(lines[1]) == "" {
+		return ""
+	}
+	// GOPATH can list several directories; commands go in the first
+	return filepath.Join(filepath.SplitList(strings.TrimSpace(lines[1]))[0], "bin")
+}
+
+// Package is an entry in cli.tools or apps.<os>.install: a name, or an
+// object naming the package under the managers that call it something else
+//
+//	{"name": "ripgrep", "winget": "BurntSushi.ripgrep.MSVC", "apt": "ripgrep"}
+type Package struct {
+	Name  string
+	Names map[string]string // Package name by manager, where it differs
+}
+
+// For returns the package's name under manager, and whether the entry sets
+// one
+func (p Package) For(manager string) (string, bool) {
+	if name, ok := p.Names[manager]; ok {
+		return name, true
+	}
+	return p.Name, false
+}
+
+// GetPackages returns the packages in a list of names and Package objects
+func (c *PactConfig) GetPackages(path string) []Package {
+	return packageItems(c.Get(path))
+}
+
+// packageItems returns the Packages in a JSON array, skipping anything that
+// isn't one
+func packageItems(v any) []Package {
+	list, _ := v.([]any)
+	var packages []Package
+	for _, item := range list {
+		switch item := item.(type) {
+		case string:
+			if item != "" {
+				packages = append(packages, Package{Name: item})
+			}
+		case map[string]any:
+			name, _ := item["name"].(string)
+			if name == "" {
+				continue
+			}
+			p := Package{Name: name, Names: make(map[string]string)}
+			for manager, v := range item {
+				if s, ok := v.(string); ok && manager != "name" {
+					p.Names[manager] = s
+				}
+			}
+			packages = append(packages, p)
+		}
+	}
+	return packages
+}
+
+// validatePackageList checks a list of names and Package objects
+func validatePackageList(v any, path string) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s should be a list of names or packages", path)}
+	}
+	var problems []string
+	for i, item := range list {
+		switch item := item.(type) {
+		case string:
+		case map[string]any:
+			if name, ok := item["name"].(string); !ok || name == "" {
+				problems = append(problems, fmt.Sprintf("%s[%d] has no name", path, i))
+			}
+			for _, manager := range sortedKeys(item) {
+				if _, ok := item[manager].(string); !ok && manager != "name" {
+					problems = append(problems, fmt.Sprintf("%s[%d].%s should be a package name", path, i, manager))
+				}
+			}
+		default:
+			problems = append(problems, fmt.Sprintf("%s[%d] should be a name or an object such as {\"name\": \"ripgrep\", \"winget\": \"BurntSushi.ripgrep.MSVC\"}", path, i))
+		}
+	}
+	return problems
+}
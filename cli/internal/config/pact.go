@@ -1,17 +1,21 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 )
 
 // PactConfig represents a flexible pact.json - any structure is valid
 type PactConfig struct {
 	Raw map[string]any // The raw parsed JSON
+
+	order *keyOrder // Key order in the file, kept when saving
 }
 
 // SyncItem represents a single item to sync (for files that have source/target)
@@ -22,6 +26,11 @@ type SyncItem struct {
 	Target   string
 	Strategy string
 	IsDir    bool
+
+	// IgnoreLines and IgnoreKeys are churn a program writes on its own:
+	// line patterns and JSON keys that don't count as changes
+	IgnoreLines []string
+	IgnoreKeys  []string
 }
 
 // ModuleInfo represents information about a module for display
@@ -117,12 +126,17 @@ func Load() (*PactConfig, error) {
 		return nil, fmt.Errorf("failed to read pact.json: %w", err)
 	}
 
+	return Parse(data)
+}
+
+// Parse parses pact.json contents
+func Parse(data []byte) (*PactConfig, error) {
 	var raw map[string]any
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return nil, fmt.Errorf("failed to parse pact.json: %w", err)
 	}
 
-	return &PactConfig{Raw: raw}, nil
+	return &PactConfig{Raw: raw, order: readKeyOrder(data)}, nil
 }
 
 // Exists checks if pact.json exists
@@ -149,8 +163,15 @@ func GetCurrentOS() string {
 	}
 }
 
-// ExpandPath expands ~ to home directory
+// ExpandPath expands ~ to home directory and {nushell} to nushell's config dir
 func ExpandPath(path string) (string, error) {
+	if strings.HasPrefix(path, NushellPlaceholder+"/") {
+		dir := NushellConfigDir()
+		if dir == "" {
+			return "", fmt.Errorf("could not determine nushell config dir")
+		}
+		return filepath.Join(dir, path[len(NushellPlaceholder)+1:]), nil
+	}
 	if strings.HasPrefix(path, "~/") {
 		home, err := os.UserHomeDir()
 		if err != nil {
@@ -188,12 +209,22 @@ func (c *PactConfig) GetString(path string) string {
 
 // GetStringSlice returns a string slice from the config
 func (c *PactConfig) GetStringSlice(path string) []string {
-	val := c.Get(path)
+	return stringList(c.Get(path))
+}
+
+// stringList returns the strings in a JSON array, reading a Package object
+// such as {"name": "ripgrep", "winget": "..."} as its name
+func stringList(val any) []string {
 	if arr, ok := val.([]any); ok {
 		var result []string
 		for _, v := range arr {
-			if s, ok := v.(string); ok {
-				result = append(result, s)
+			switch v := v.(type) {
+			case string:
+				result = append(result, v)
+			case map[string]any:
+				if name, ok := v["name"].(string); ok {
+					result = append(result, name)
+				}
 			}
 		}
 		return result
@@ -217,36 +248,79 @@ func (c *PactConfig) HasKey(path string) bool {
 
 // GetTopLevelKeys returns all top-level keys in the config
 func (c *PactConfig) GetTopLevelKeys() []string {
-	var keys []string
-	for k := range c.Raw {
-		keys = append(keys, k)
-	}
-	return keys
+	return sortedKeys(c.Raw)
 }
 
-// GetModules returns all top-level keys that look like modules (objects, not primitives)
+// GetModules returns all top-level keys that look like modules (objects, not
+// primitives), in SortModules order
 func (c *PactConfig) GetModules() []string {
 	var modules []string
-	skip := map[string]bool{"name": true, "version": true, "secrets": true}
+	skip := map[string]bool{"name": true, "version": true, "secrets": true, "settings": true, "hooks": true, "ui": true, "ignore": true}
 
-	for k, v := range c.Raw {
+	for _, k := range sortedKeys(c.Raw) {
 		if skip[k] {
 			continue
 		}
-		if _, ok := v.(map[string]any); ok {
+		if _, ok := c.Raw[k].(map[string]any); ok {
 			modules = append(modules, k)
 		}
 	}
+	SortModules(modules)
+	shuffle(len(modules), func(i, j int) { modules[i], modules[j] = modules[j], modules[i] })
 	return modules
 }
 
-// GetSecrets returns the secrets array if it exists
+// GetSecrets returns the global secrets. "secrets" is either a plain array
+// or an object with "global" and per-project lists:
+//
+//	"secrets": {"global": ["OPENAI_API_KEY"], "projects": {"acme": ["STRIPE_KEY"]}}
 func (c *PactConfig) GetSecrets() []string {
+	if c.GetMap("secrets") != nil {
+		return c.GetStringSlice("secrets.global")
+	}
 	return c.GetStringSlice("secrets")
 }
 
+// GetSecretProjects returns the names of projects with scoped secrets
+func (c *PactConfig) GetSecretProjects() []string {
+	var projects []string
+	for name := range c.GetMap("secrets.projects") {
+		projects = append(projects, name)
+	}
+	sort.Strings(projects)
+	return projects
+}
+
+// GetProjectSecrets returns the secrets scoped to a project
+func (c *PactConfig) GetProjectSecrets(project string) []string {
+	projects := c.GetMap("secrets.projects")
+	if projects == nil {
+		return nil
+	}
+	list, ok := projects[project].([]any)
+	if !ok {
+		return nil
+	}
+	var result []string
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// GetSecretRecipients returns the keys secrets.enc is encrypted to: age
+// recipients (age1...) or GPG key IDs, fingerprints, or emails
+//
+//	"secrets": {"global": ["OPENAI_API_KEY"], "recipients": ["age1..."]}
+func (c *PactConfig) GetSecretRecipients() []string {
+	return c.GetStringSlice("secrets.recipients")
+}
+
 // GetSyncItems finds all items with source/target for syncing
-// Looks for "files" keys anywhere in the config tree
+// Looks for "files" keys anywhere in the config tree, by module in
+// SortModules order, then in sorted key order
 func (c *PactConfig) GetSyncItems() ([]SyncItem, error) {
 	pactDir, err := GetPactDir()
 	if err != nil {
@@ -255,6 +329,8 @@ func (c *PactConfig) GetSyncItems() ([]SyncItem, error) {
 
 	var items []SyncItem
 	c.findFilesRecursive(c.Raw, "", pactDir, &items)
+	sort.SliceStable(items, func(i, j int) bool { return moduleLess(items[i].Module, items[j].Module) })
+	shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
 	return items, nil
 }
 
@@ -267,8 +343,8 @@ func (c *PactConfig) findFilesRecursive(node any, module string, pactDir string,
 
 	// Check if this node has a "files" key
 	if files, ok := m["files"].(map[string]any); ok {
-		for name, fileEntry := range files {
-			if entry, ok := fileEntry.(map[string]any); ok {
+		for _, name := range sortedKeys(files) {
+			if entry, ok := files[name].(map[string]any); ok {
 				item := c.parseFileEntry(module, name, entry, pactDir)
 				if item != nil {
 					*items = append(*items, *item)
@@ -278,11 +354,11 @@ func (c *PactConfig) findFilesRecursive(node any, module string, pactDir string,
 	}
 
 	// Recurse into child objects
-	for key, val := range m {
+	for _, key := range sortedKeys(m) {
 		if key == "files" {
 			continue
 		}
-		if childMap, ok := val.(map[string]any); ok {
+		if childMap, ok := m[key].(map[string]any); ok {
 			nextModule := key
 			if module != "" {
 				nextModule = module // Keep the top-level module name
@@ -305,18 +381,21 @@ func (c *PactConfig) parseFileEntry(module, name string, entry map[string]any, p
 	}
 
 	strategy, _ := entry["strategy"].(string)
+	ignore, _ := entry["ignore"].(map[string]any)
 
 	sourcePath := filepath.Join(pactDir, source)
 	info, statErr := os.Stat(sourcePath)
 	isDir := statErr == nil && info.IsDir()
 
 	return &SyncItem{
-		Module:   module,
-		Name:     name,
-		Source:   sourcePath,
-		Target:   target,
-		Strategy: strategy,
-		IsDir:    isDir,
+		Module:      module,
+		Name:        name,
+		Source:      sourcePath,
+		Target:      target,
+		Strategy:    strategy,
+		IsDir:       isDir,
+		IgnoreLines: stringList(ignore["lines"]),
+		IgnoreKeys:  stringList(ignore["keys"]),
 	}
 }
 
@@ -342,14 +421,19 @@ func (c *PactConfig) resolveTarget(target any) (string, error) {
 func (c *PactConfig) GetAvailableModules() []ModuleInfo {
 	items, _ := c.GetSyncItems()
 
-	// Group by module
+	// Group by module, in the order the items came in
 	moduleMap := make(map[string][]string)
+	var names []string
 	for _, item := range items {
+		if _, ok := moduleMap[item.Module]; !ok {
+			names = append(names, item.Module)
+		}
 		moduleMap[item.Module] = append(moduleMap[item.Module], item.Name)
 	}
 
 	var modules []ModuleInfo
-	for name, fileNames := range moduleMap {
+	for _, name := range names {
+		fileNames := moduleMap[name]
 		modules = append(modules, ModuleInfo{
 			Name:      name,
 			FileCount: len(fileNames),
@@ -406,3 +490,98 @@ func (c *PactConfig) GetSyncItemsForModule(moduleName string) ([]SyncItem, error
 	}
 	return items, nil
 }
+
+// Save writes the config back to pact.json
+func (c *PactConfig) Save() error {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return err
+	}
+
+	output, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, output, 0644)
+}
+
+// Marshal returns the config as pact.json contents, with keys in the order
+// they were read and new ones after them
+func (c *PactConfig) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeJSON(&buf, c.Raw, c.order, ""); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// identityKeys belong to whoever owns a pact.json: their git identity,
+// signing key, and secrets
+var identityKeys = []string{"git.user", "git.email", "git.signing", "secrets"}
+
+// StripIdentity removes the owner's identity so the config can start someone
+// else's pact, naming it after username. Returns the keys removed.
+func (c *PactConfig) StripIdentity(username string) []string {
+	var removed []string
+	for _, key := range identityKeys {
+		parts := strings.Split(key, ".")
+		parent := c.Raw
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := parent[part].(map[string]any)
+			if !ok {
+				parent = nil
+				break
+			}
+			parent = next
+		}
+		if _, ok := parent[parts[len(parts)-1]]; ok {
+			delete(parent, parts[len(parts)-1])
+			removed = append(removed, key)
+		}
+	}
+	c.Raw["name"] = username
+	if _, ok := c.Raw["user"]; ok {
+		c.Raw["user"] = username
+	}
+	return removed
+}
+
+// RemoveValue removes value from the list at path, or deletes the key if it
+// holds that exact string. Reports whether anything was removed.
+func (c *PactConfig) RemoveValue(path, value string) bool {
+	parts := strings.Split(path, ".")
+	parent := c.Raw
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := parent[part].(map[string]any)
+		if !ok {
+			return false
+		}
+		parent = next
+	}
+
+	key := parts[len(parts)-1]
+	switch v := parent[key].(type) {
+	case string:
+		if v == value {
+			delete(parent, key)
+			return true
+		}
+	case []any:
+		kept := make([]any, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == value {
+				continue
+			}
+			if p, ok := item.(map[string]any); ok && p["name"] == value {
+				continue
+			}
+			kept = append(kept, item)
+		}
+		if len(kept) != len(v) {
+			parent[key] = kept
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStripIdentity(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"name": "octocat",
+		"git": {"user": "Octo Cat", "email": "octo@example.com", "defaultBranch": "main", "signing": {"key": "ABC123"}},
+		"secrets": ["OPENAI_API_KEY"],
+		"cli": {"tools": ["jq"]}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	removed := cfg.StripIdentity("me")
+	if want := []string{"git.user", "git.email", "git.signing", "secrets"}; !reflect.DeepEqual(removed, want) {
+		t.Fatalf("StripIdentity() = %v, want %v", removed, want)
+	}
+	if cfg.GetString("name") != "me" || cfg.HasKey("git.user") || cfg.HasKey("secrets") {
+		t.Fatalf("identity left behind: %v", cfg.Raw)
+	}
+	if cfg.GetString("git.defaultBranch") != "main" || len(cfg.GetStringSlice("cli.tools")) != 1 {
+		t.Fatalf("non-identity settings removed: %v", cfg.Raw)
+	}
+}
+
+func TestOrderByNeeds(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"editor": {"needs": ["cli", "shell"]},
+		"shell": {"needs": ["cli"]},
+		"cli": {},
+		"git": {"needs": ["git", "missing"]}
+	}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	got := cfg.OrderByNeeds([]string{"editor", "git", "shell", "cli"})
+	want := []string{"cli", "shell", "editor", "git"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("OrderByNeeds() = %v, want %v", got, want)
+	}
+	if problems := cfg.Validate(); len(problems) != 1 || problems[0] != "git.needs: no module named missing" {
+		t.Fatalf("Validate() = %q", problems)
+	}
+}
+
+func TestModuleOrder(t *testing.T) {
+	cfg, err := Parse([]byte(`{"shell": {}, "cli": {}, "git": {}, "editor": {}, "name": "me", "zed": {}}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := []string{"cli", "shell", "git", "editor", "zed"}
+	for i := 0; i < 5; i++ {
+		if got := cfg.GetModules(); !reflect.DeepEqual(got, want) {
+			t.Fatalf("GetModules() = %v, want %v", got, want)
+		}
+	}
+
+	t.Setenv(ShuffleSeedEnv, "7")
+	shuffled := cfg.GetModules()
+	if !reflect.DeepEqual(cfg.GetModules(), shuffled) {
+		t.Fatalf("GetModules() with a seed isn't repeatable")
+	}
+	if reflect.DeepEqual(shuffled, want) {
+		t.Fatalf("GetModules() with seed 7 = %v, want a different order", shuffled)
+	}
+}
+
+func TestGitConfig(t *testing.T) {
+	cfg, err := Parse([]byte(`{"git": {"config": {
+		"pull.rebase": true,
+		"merge": {"conflictstyle": "zdiff3"},
+		"alias": {"co": "checkout"},
+		"url": {"git@github.com:": {"insteadOf": "https://github.com/"}},
+		"gc.auto": 0,
+		"bad": ["x"]
+	}}}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	want := map[string]string{
+		"pull.rebase":                   "true",
+		"merge.conflictstyle":           "zdiff3",
+		"alias.co":                      "checkout",
+		"url.git@github.com:.insteadOf": "https://github.com/",
+		"gc.auto":                       "0",
+	}
+	if got := cfg.GitConfig(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("GitConfig() = %v, want %v", got, want)
+	}
+	if got := cfg.Validate(); !reflect.DeepEqual(got, []string{"git.config.bad should be a string, number, or boolean"}) {
+		t.Fatalf("Validate() = %q", got)
+	}
+}
+
+func TestPackageName(t *testing.T) {
+	for _, tt := range []struct{ manager, spec, name string }{
+		{"npm", "typescript", "typescript"},
+		{"npm", "@biomejs/biome@1.9", "@biomejs/biome"},
+		{"npm", "@biomejs/biome", "@biomejs/biome"},
+		{"pip", "httpie==3.2", "httpie"},
+		{"pip", "black[d]", "black"},
+		{"cargo", "ripgrep@14.1.0", "ripgrep"},
+		{"go", "golang.org/x/tools/gopls@latest", "golang.org/x/tools/gopls"},
+	} {
+		if got := PackageName(tt.manager, tt.spec); got != tt.name {
+			t.Fatalf("PackageName(%s, %s) = %s, want %s", tt.manager, tt.spec, got, tt.name)
+		}
+	}
+	if got := GoBinaryName("github.com/golangci/golangci-lint/v2/cmd/golangci-lint@v2.1.0"); got != "golangci-lint" {
+		t.Fatalf("GoBinaryName = %s", got)
+	}
+	if got := GoBinaryName("github.com/x/tool/v2"); got != "tool" {
+		t.Fatalf("GoBinaryName(v2) = %s", got)
+	}
+}
+
+func TestParsePackageList(t *testing.T) {
+	npm := `{"dependencies": {"typescript": {"version": "5.4.0"}, "npm": {}, "@biomejs/biome": {}}}`
+	if got, want := ParsePackageList("npm", []byte(npm)), []string{"@biomejs/biome", "typescript"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("npm = %v, want %v", got, want)
+	}
+	cargo := "cargo-watch v8.5.2:\n    cargo-watch\nripgrep v14.1.0:\n    rg\n"
+	if got, want := ParsePackageList("cargo", []byte(cargo)), []string{"cargo-watch", "ripgrep"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("cargo = %v, want %v", got, want)
+	}
+	if got, want := ParsePackageList("pip", []byte("black 24.2.0\nhttpie 3.2.2\n")), []string{"black", "httpie"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("pip = %v, want %v", got, want)
+	}
+}
+
+func TestGetPackages(t *testing.T) {
+	cfg, err := Parse([]byte(`{"cli": {"tools": ["jq", {"name": "ripgrep", "winget": "BurntSushi.ripgrep.MSVC"}, {"apt": "x"}]}}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	packages := cfg.GetPackages("cli.tools")
+	if len(packages) != 2 {
+		t.Fatalf("GetPackages() = %v, want jq and ripgrep", packages)
+	}
+	if name, ok := packages[1].For("winget"); !ok || name != "BurntSushi.ripgrep.MSVC" {
+		t.Fatalf("For(winget) = %s, %v", name, ok)
+	}
+	if name, ok := packages[1].For("apt"); ok || name != "ripgrep" {
+		t.Fatalf("For(apt) = %s, %v", name, ok)
+	}
+	if got, want := cfg.GetStringSlice("cli.tools"), []string{"jq", "ripgrep"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("GetStringSlice() = %v, want %v", got, want)
+	}
+}
+
+func TestMarshalKeepsFile(t *testing.T) {
+	data := `{
+  "name": "me",
+  "cli": {
+    "tools": [
+      "rg",
+      "fd"
+    ]
+  },
+  "alias": {
+    "ll": "ls -l && echo <done>"
+  },
+  "files": [
+    {
+      "target": "~/.gitconfig",
+      "source": "git/gitconfig"
+    }
+  ],
+  "empty": {}
+}
+`
+	cfg, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := cfg.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != data {
+		t.Fatalf("Marshal() changed an unedited pact.json:\n%s", out)
+	}
+
+	cfg.Raw["editor"] = map[string]any{"default": "nvim"}
+	out, _ = cfg.Marshal()
+	if !strings.HasSuffix(string(out), "\"empty\": {},\n  \"editor\": {\n    \"default\": \"nvim\"\n  }\n}\n") {
+		t.Fatalf("a new key should come after the existing ones:\n%s", out)
+	}
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+)
+
+// PowerShell profile scopes (see about_Profiles)
+const (
+	ProfileCurrentUserCurrentHost = "CurrentUserCurrentHost"
+	ProfileCurrentUserAllHosts    = "CurrentUserAllHosts"
+)
+
+// PowerShellEdition describes an installed PowerShell edition and where its
+// profiles live
+type PowerShellEdition struct {
+	Name       string // "pwsh" (PowerShell 7+) or "powershell" (Windows PowerShell 5.1)
+	ProfileDir string
+}
+
+// GetPowerShellEditions returns the PowerShell editions whose profiles pact
+// should manage. On Windows this is Windows PowerShell 5.1 (always present)
+// plus PowerShell 7 when pwsh is installed or its profile dir already exists.
+func GetPowerShellEditions() []PowerShellEdition {
+	var editions []PowerShellEdition
+
+	if runtime.GOOS != "windows" {
+		// pwsh on macOS/Linux follows XDG
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil
+		}
+		if _, err := exec.LookPath("pwsh"); err == nil {
+			editions = append(editions, PowerShellEdition{
+				Name:       "pwsh",
+				ProfileDir: filepath.Join(home, ".config", "powershell"),
+			})
+		}
+		return editions
+	}
+
+	docs := GetDocumentsDir()
+	ps7Dir := filepath.Join(docs, "PowerShell")
+	ps5Dir := filepath.Join(docs, "WindowsPowerShell")
+
+	_, pwshErr := exec.LookPath("pwsh")
+	if pwshErr == nil || dirExists(ps7Dir) {
+		editions = append(editions, PowerShellEdition{Name: "pwsh", ProfileDir: ps7Dir})
+	}
+	editions = append(editions, PowerShellEdition{Name: "powershell", ProfileDir: ps5Dir})
+
+	return editions
+}
+
+// GetPowerShellProfiles returns the profile paths for every managed edition
+// in the given scope. An empty scope means CurrentUserCurrentHost.
+func GetPowerShellProfiles(scope string) []string {
+	fileName := "Microsoft.PowerShell_profile.ps1"
+	if scope == ProfileCurrentUserAllHosts {
+		fileName = "profile.ps1"
+	}
+
+	var profiles []string
+	for _, edition := range GetPowerShellEditions() {
+		profiles = append(profiles, filepath.Join(edition.ProfileDir, fileName))
+	}
+	return profiles
+}
+
+// GetAllPowerShellProfiles returns every profile path pact knows about, in
+// both scopes, for detection
+func GetAllPowerShellProfiles() []string {
+	profiles := GetPowerShellProfiles(ProfileCurrentUserCurrentHost)
+	return append(profiles, GetPowerShellProfiles(ProfileCurrentUserAllHosts)...)
+}
+
+// PowerShellProfileScope returns the configured profile scope from
+// shell.powershell.profile, defaulting to CurrentUserCurrentHost
+func (c *PactConfig) PowerShellProfileScope() string {
+	switch c.GetString("shell.powershell.profile") {
+	case ProfileCurrentUserAllHosts, "AllHosts", "allHosts":
+		return ProfileCurrentUserAllHosts
+	default:
+		return ProfileCurrentUserCurrentHost
+	}
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,91 @@
+package config
+
+import "path/filepath"
+
+// TerminalEmulators are the terminal emulators the terminal module can
+// configure under terminal.<emulator>
+var TerminalEmulators = []string{"ghostty", "alacritty", "kitty", "windows-terminal", "iterm2"}
+
+// TerminalConfigPath returns where emulator reads its config file on goos,
+// with home as the home directory, or "" where it has none pact syncs.
+// iTerm2 keeps its settings in a binary plist, so pact gives it a dynamic
+// profile instead (see ITermProfilePath).
+func TerminalConfigPath(emulator, goos, home string) string {
+	switch emulator {
+	case "ghostty":
+		if goos != "windows" {
+			return filepath.Join(home, ".config", "ghostty", "config")
+		}
+	case "alacritty":
+		if goos == "windows" {
+			return filepath.Join(home, "AppData", "Roaming", "alacritty", "alacritty.toml")
+		}
+		return filepath.Join(home, ".config", "alacritty", "alacritty.toml")
+	case "kitty":
+		if goos != "windows" {
+			return filepath.Join(home, ".config", "kitty", "kitty.conf")
+		}
+	case "windows-terminal":
+		if goos == "windows" {
+			return filepath.Join(home, "AppData", "Local", "Packages", "Microsoft.WindowsTerminal_8wekyb3d8bbwe", "LocalState", "settings.json")
+		}
+	}
+	return ""
+}
+
+// ITermProfilePath is the iTerm2 dynamic profile pact writes terminal.iterm2
+// settings to
+func ITermProfilePath(home string) string {
+	return filepath.Join(home, "Library", "Application Support", "iTerm2", "DynamicProfiles", "pact.json")
+}
+
+// TerminalFont is the font setup the terminal module declares
+type TerminalFont struct {
+	Family    string
+	Fallback  []string // Fonts to use for glyphs Family lacks, in order
+	Size      float64
+	Ligatures *bool // Nil leaves the emulator's default
+}
+
+// TerminalFont returns the font terminal.<emulator> gets: the font,
+// fontSize, fallback, and ligatures set at the top of terminal, each
+// overridden by the emulator's own. With emulator "" it's just the top's,
+// which editors' integrated terminals get.
+//
+//	"terminal": {
+//	  "font": "JetBrainsMono Nerd Font",
+//	  "fallback": ["Symbols Nerd Font", "Noto Color Emoji"],
+//	  "fontSize": 14,
+//	  "ligatures": false,
+//	  "kitty": {"fontSize": 13}
+//	}
+func (c *PactConfig) TerminalFont(emulator string) TerminalFont {
+	prefixes := []string{"terminal"}
+	if emulator != "" {
+		prefixes = append(prefixes, "terminal."+emulator)
+	}
+	var font TerminalFont
+	for _, prefix := range prefixes {
+		if family := c.GetString(prefix + ".font"); family != "" {
+			font.Family = family
+		}
+		if c.HasKey(prefix + ".fallback") {
+			font.Fallback = c.GetStringSlice(prefix + ".fallback")
+		}
+		if size, ok := c.Get(prefix + ".fontSize").(float64); ok && size > 0 {
+			font.Size = size
+		}
+		if ligatures, ok := c.Get(prefix + ".ligatures").(bool); ok {
+			font.Ligatures = &ligatures
+		}
+	}
+	return font
+}
+
+// Families is the font followed by its fallbacks
+func (f TerminalFont) Families() []string {
+	if f.Family == "" {
+		return f.Fallback
+	}
+	return append([]string{f.Family}, f.Fallback...)
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// stringLists are keys that must hold a list of strings when set; cli.tools
+// may also hold Package objects
+var stringLists = []string{
+	"cli.tools",
+	"cli.custom",
+	"cli.taps",
+	"cli.casks",
+	"cli.npm",
+	"cli.pip",
+	"cli.cargo",
+	"cli.go",
+	"shell.tools",
+	"shell.direnv.whitelist.prefix",
+	"shell.direnv.whitelist.exact",
+	"git.hookManager.repos",
+	"editor.extensions",
+	"llm.providers",
+	"llm.local.models",
+	"secrets",
+	"secrets.global",
+	"secrets.recipients",
+}
+
+// objectModules are built-in modules that must be objects when set
+var objectModules = []string{"cli", "shell", "git", "editor", "keybindings", "snippets", "sound", "machine", "power", "printers", "mounts", "ssh", "terminal", "llm", "apps", "settings", "hooks", "ui"}
+
+// Validate reports structural problems that would make parts of pact.json
+// get skipped silently, such as a tools entry that isn't a list or a file
+// entry without a source
+func (c *PactConfig) Validate() []string {
+	var problems []string
+
+	for _, key := range []string{"name", "version"} {
+		if v, ok := c.Raw[key]; ok {
+			if _, isString := v.(string); !isString {
+				problems = append(problems, fmt.Sprintf("%s should be a string", key))
+			}
+		}
+	}
+
+	for _, key := range objectModules {
+		if v, ok := c.Raw[key]; ok {
+			if _, isMap := v.(map[string]any); !isMap {
+				problems = append(problems, fmt.Sprintf("%s should be an object", key))
+			}
+		}
+	}
+
+	for _, key := range stringLists {
+		v := c.Get(key)
+		if v == nil {
+			continue
+		}
+		// "secrets" is a list or an object of lists
+		if _, isMap := v.(map[string]any); isMap && key == "secrets" {
+			continue
+		}
+		if key == "cli.tools" {
+			problems = append(problems, validatePackageList(v, key)...)
+			continue
+		}
+		list, ok := v.([]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s should be a list of strings", key))
+			continue
+		}
+		for i, item := range list {
+			if _, ok := item.(string); !ok {
+				problems = append(problems, fmt.Sprintf("%s[%d] should be a string", key, i))
+			}
+		}
+	}
+
+	if gitConfig, ok := c.Get("git.config").(map[string]any); ok {
+		problems = append(problems, validateGitConfig(gitConfig, "")...)
+	} else if c.Get("git.config") != nil {
+		problems = append(problems, "git.config should be an object")
+	}
+
+	if apps, ok := c.Raw["apps"].(map[string]any); ok {
+		problems = append(problems, validateApps(apps)...)
+	}
+
+	if ignore, ok := c.Raw["ignore"]; ok {
+		problems = append(problems, validateIgnoredDrift(ignore)...)
+	}
+
+	problems = append(problems, c.validateNeeds()...)
+	problems = append(problems, validateFiles(c.Raw, "")...)
+	return problems
+}
+
+// validateFiles checks every "files" entry in the tree
+func validateFiles(node map[string]any, prefix string) []string {
+	var problems []string
+
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child, ok := node[key].(map[string]any)
+		if key == "files" {
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%sfiles should be an object", prefix))
+				continue
+			}
+			problems = append(problems, validateFileEntries(child, prefix+"files.")...)
+			continue
+		}
+		if ok {
+			problems = append(problems, validateFiles(child, prefix+key+".")...)
+		}
+	}
+	return problems
+}
+
+func validateFileEntries(files map[string]any, prefix string) []string {
+	var problems []string
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry, ok := files[name].(map[string]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s%s should be an object with source and target", prefix, name))
+			continue
+		}
+		if s, ok := entry["source"].(string); !ok || s == "" {
+			problems = append(problems, fmt.Sprintf("%s%s has no source", prefix, name))
+		}
+		switch target := entry["target"].(type) {
+		case string:
+		case map[string]any:
+			for goos, path := range target {
+				if _, ok := path.(string); !ok {
+					problems = append(problems, fmt.Sprintf("%s%s.target.%s should be a path", prefix, name, goos))
+				}
+			}
+		case nil:
+			problems = append(problems, fmt.Sprintf("%s%s has no target", prefix, name))
+		default:
+			problems = append(problems, fmt.Sprintf("%s%s.target should be a path or an object of paths per OS", prefix, name))
+		}
+		problems = append(problems, validateIgnore(entry["ignore"], prefix+name+".ignore")...)
+	}
+	return problems
+}
+
+// validateIgnore checks a file entry's ignore rules:
+//
+//	"ignore": {"lines": ["^SETUVAR __fish_initialized"], "keys": ["window.zoomLevel"]}
+func validateIgnore(ignore any, path string) []string {
+	if ignore == nil {
+		return nil
+	}
+	rules, ok := ignore.(map[string]any)
+	if !ok {
+		return []string{fmt.Sprintf("%s should be an object with lines and keys", path)}
+	}
+	var problems []string
+	for key, val := range rules {
+		if key != "lines" && key != "keys" {
+			problems = append(problems, fmt.Sprintf("%s.%s is unknown (use lines or keys)", path, key))
+			continue
+		}
+		list, ok := val.([]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s.%s should be an array of strings", path, key))
+			continue
+		}
+		for _, item := range list {
+			s, ok := item.(string)
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s should be an array of strings", path, key))
+				break
+			}
+			if key == "lines" {
+				if _, err := regexp.Compile(s); err != nil {
+					problems = append(problems, fmt.Sprintf("%s.lines has an invalid pattern %q: %v", path, s, err))
+				}
+			}
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	cfg, err := Parse([]byte(`{
+		"name": "me",
+		"cli": {"tools": "jq"},
+		"shell": {"tools": ["zoxide", 3], "files": {
+			"zshrc": {"source": "shell/.zshrc", "target": "~/.zshrc"},
+			"broken": {"target": "~/.broken"},
+			"weird": {"source": "x", "target": 5},
+			"fish": {"source": "x", "target": "~/x", "ignore": {"lines": ["(unclosed"]}}
+		}},
+		"git": "me"
+	}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	want := []string{
+		"git should be an object",
+		"cli.tools should be a list of names or packages",
+		"shell.tools[1] should be a string",
+		"shell.files.broken has no source",
+		"shell.files.fish.ignore.lines has an invalid pattern \"(unclosed\": error parsing regexp: missing closing ): `(unclosed`",
+		"shell.files.weird.target should be a path or an object of paths per OS",
+	}
+	if got := cfg.Validate(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Validate() =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestValidateClean(t *testing.T) {
+	for _, data := range []string{
+		`{"cli": {"tools": ["jq"]}, "secrets": ["API_KEY"]}`,
+		`{"secrets": {"global": ["API_KEY"], "recipients": ["age1xyz"]}}`,
+		`{"ignore": {"cli": ["python3"], "apps": ["com.corp.*"]}}`,
+	} {
+		cfg, _ := Parse([]byte(data))
+		if got := cfg.Validate(); len(got) != 0 {
+			t.Fatalf("Validate(%s) = %v, want none", data, got)
+		}
+	}
+}
+
+func TestIgnoredDrift(t *testing.T) {
+	cfg, _ := Parse([]byte(`{"ignore": {"cli": ["python3", "[bad"], "apps": "x"}}`))
+	want := []string{"ignore.apps should be a list of names", "ignore.cli[1] is a bad pattern: [bad"}
+	if got := cfg.Validate(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Validate() = %q, want %q", got, want)
+	}
+	if got := cfg.GetIgnoredDrift()["cli"]; len(got) != 2 || got[0] != "python3" {
+		t.Fatalf("GetIgnoredDrift() = %v", got)
+	}
+	if modules := cfg.GetModules(); len(modules) != 0 {
+		t.Fatalf("ignore shouldn't be a module: %v", modules)
+	}
+}
+
+func TestApps(t *testing.T) {
+	cfg, err := Parse([]byte(`{"apps": {
+		"darwin": {"install": [{"name": "brave", "brew": "brave-browser"}, 3, {"winget": "X"}], "mas": {"Xcode": 497799835, "Bad": true}, "shortcuts": {"brave": "cmd+shift+b", "zed": 1}, "startup": "discord"},
+		"linux": {"mas": [1], "instal": [], "flatpak": {
+			"remotes": {"flathub-beta": "https://flathub.org/beta-repo/flathub-beta.flatpakrepo", "bad": 1},
+			"overrides": {"org.mozilla.firefox": ["--filesystem=home", "filesystem=host"]}
+		}},
+		"windows": []
+	}}`))
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+
+	want := []string{
+		"apps.darwin.install[1] should be a name or an object such as {\"name\": \"ripgrep\", \"winget\": \"BurntSushi.ripgrep.MSVC\"}",
+		"apps.darwin.install[2] has no name",
+		"apps.darwin.mas.Bad should be an App Store ID",
+		"apps.darwin.shortcuts.zed should be a string such as \"cmd+shift+b\"",
+		"apps.darwin.startup should be a list of strings",
+		"apps.linux.flatpak.overrides.org.mozilla.firefox[1] should be a flag such as \"--filesystem=home\"",
+		"apps.linux.flatpak.remotes.bad should be a URL",
+		"apps.linux.instal is unknown (use install, mas, shortcuts, startup, remove, flatpak)",
+		"apps.linux.mas only works under apps.darwin",
+		"apps.windows should be an object",
+	}
+	if got := cfg.Validate(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Validate() =\n%q\nwant\n%q", got, want)
+	}
+
+	apps := cfg.Apps("darwin")
+	if want := []Package{{Name: "brave", Names: map[string]string{"brew": "brave-browser"}}}; !reflect.DeepEqual(apps.Install, want) || len(apps.Startup) != 0 {
+		t.Fatalf("Apps(darwin) install = %v, startup = %v", apps.Install, apps.Startup)
+	}
+	if want := []AppShortcut{{App: "brave", Keys: "cmd+shift+b"}}; !reflect.DeepEqual(apps.Shortcuts, want) {
+		t.Fatalf("Apps(darwin) shortcuts = %v, want %v", apps.Shortcuts, want)
+	}
+	if want := []MasApp{{ID: "497799835", Name: "Xcode"}}; !reflect.DeepEqual(apps.Mas, want) {
+		t.Fatalf("Apps(darwin) mas = %v, want %v", apps.Mas, want)
+	}
+
+	flatpak := cfg.Apps("linux").Flatpak
+	if want := []FlatpakRemote{{Name: "flathub-beta", URL: "https://flathub.org/beta-repo/flathub-beta.flatpakrepo"}}; !reflect.DeepEqual(flatpak.Remotes, want) {
+		t.Fatalf("Apps(linux) flatpak remotes = %v, want %v", flatpak.Remotes, want)
+	}
+	if len(flatpak.Overrides) != 1 || flatpak.Overrides[0].App != "org.mozilla.firefox" || len(flatpak.Overrides[0].Flags) != 2 {
+		t.Fatalf("Apps(linux) flatpak overrides = %v", flatpak.Overrides)
+	}
+}
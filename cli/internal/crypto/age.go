@@ -0,0 +1,340 @@
+// Package crypto implements encryption in the age v1 format
+// (https://age-encryption.org/v1), with passphrase and X25519 recipients,
+// so exported files can also be decrypted with the age CLI. GPG recipients
+// go through the gpg CLI.
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	ageIntro     = "age-encryption.org/v1"
+	scryptLabel  = "age-encryption.org/v1/scrypt"
+	chunkSize    = 64 * 1024
+	maxScryptLog = 22
+
+	// DefaultWorkFactor is the scrypt log2(N) used by the age CLI
+	DefaultWorkFactor = 18
+)
+
+// ErrBadPassphrase is returned when decryption fails because the passphrase
+// is wrong
+var ErrBadPassphrase = errors.New("incorrect passphrase")
+
+var b64 = base64.RawStdEncoding
+
+// stanza is one recipient's wrapped copy of the file key
+type stanza struct {
+	kind string
+	args []string
+	body []byte
+}
+
+// Encrypt encrypts plaintext with a passphrase using the given scrypt work
+// factor (log2 N)
+func Encrypt(plaintext []byte, passphrase string, workFactor int) ([]byte, error) {
+	fileKey := make([]byte, 16)
+	salt := make([]byte, 16)
+	for _, b := range [][]byte{fileKey, salt} {
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+	}
+
+	wrapKey, err := scryptKey(passphrase, salt, workFactor)
+	if err != nil {
+		return nil, err
+	}
+	body, err := wrapFileKey(wrapKey, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	return seal(fileKey, []stanza{{kind: "scrypt", args: []string{b64.EncodeToString(salt), strconv.Itoa(workFactor)}, body: body}}, plaintext)
+}
+
+// Decrypt decrypts an age file encrypted with a passphrase
+func Decrypt(data []byte, passphrase string) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	stanzas, header, mac, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+	// A passphrase file has exactly one stanza
+	if len(stanzas) != 1 || stanzas[0].kind != "scrypt" || len(stanzas[0].args) != 2 {
+		return nil, fmt.Errorf("file is not passphrase-encrypted")
+	}
+	s := stanzas[0]
+	salt, err := b64.DecodeString(s.args[0])
+	if err != nil || len(salt) != 16 {
+		return nil, fmt.Errorf("malformed scrypt salt")
+	}
+	workFactor, err := strconv.Atoi(s.args[1])
+	if err != nil || workFactor <= 0 || workFactor > maxScryptLog {
+		return nil, fmt.Errorf("unsupported scrypt work factor %s", s.args[1])
+	}
+
+	wrapKey, err := scryptKey(passphrase, salt, workFactor)
+	if err != nil {
+		return nil, err
+	}
+	fileKey, err := unwrapFileKey(wrapKey, s.body)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return open(r, fileKey, header, mac)
+}
+
+// seal writes the header for stanzas followed by the encrypted payload
+func seal(fileKey []byte, stanzas []stanza, plaintext []byte) ([]byte, error) {
+	var header bytes.Buffer
+	header.WriteString(ageIntro + "\n")
+	for _, s := range stanzas {
+		header.WriteString(strings.Join(append([]string{"->", s.kind}, s.args...), " ") + "\n")
+		writeWrapped(&header, b64.EncodeToString(s.body))
+	}
+	header.WriteString("---")
+
+	mac, err := headerMAC(fileKey, header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.Write(header.Bytes())
+	out.WriteString(" " + b64.EncodeToString(mac) + "\n")
+	out.Write(nonce)
+
+	payload, err := streamKey(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+	if err := sealStream(&out, payload, plaintext); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// readHeader parses an age header, returning its stanzas, the raw bytes the
+// MAC covers, and the MAC
+func readHeader(r *bufio.Reader) ([]stanza, []byte, []byte, error) {
+	var header bytes.Buffer
+
+	line, err := readLine(r, &header)
+	if err != nil || line != ageIntro {
+		return nil, nil, nil, fmt.Errorf("not an age encrypted file")
+	}
+
+	var stanzas []stanza
+	for {
+		peek, err := r.Peek(3)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("malformed age header")
+		}
+		if string(peek) == "---" {
+			break
+		}
+
+		line, err = readLine(r, &header)
+		if err != nil || !strings.HasPrefix(line, "-> ") {
+			return nil, nil, nil, fmt.Errorf("malformed age header")
+		}
+		args := strings.Fields(strings.TrimPrefix(line, "->"))
+		if len(args) == 0 {
+			return nil, nil, nil, fmt.Errorf("malformed age header")
+		}
+
+		// Stanza body: base64 lines, the last one shorter than 64 columns
+		var encoded strings.Builder
+		for {
+			line, err = readLine(r, &header)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("malformed age header")
+			}
+			encoded.WriteString(line)
+			if len(line) < 64 {
+				break
+			}
+		}
+		body, err := b64.DecodeString(encoded.String())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("malformed %s stanza", args[0])
+		}
+		stanzas = append(stanzas, stanza{kind: args[0], args: args[1:], body: body})
+	}
+
+	// MAC line: "--- <mac>"; the MAC covers the header through "---"
+	macLine, err := r.ReadString('\n')
+	if err != nil || !strings.HasPrefix(macLine, "--- ") {
+		return nil, nil, nil, fmt.Errorf("malformed age header")
+	}
+	header.WriteString("---")
+	mac, err := b64.DecodeString(strings.TrimSuffix(macLine[4:], "\n"))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("malformed header MAC")
+	}
+	return stanzas, header.Bytes(), mac, nil
+}
+
+// open checks the header MAC with the unwrapped file key and decrypts the
+// payload that follows the header
+func open(r *bufio.Reader, fileKey, header, mac []byte) ([]byte, error) {
+	expected, err := headerMAC(fileKey, header)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(mac, expected) {
+		return nil, fmt.Errorf("age header MAC mismatch")
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return nil, fmt.Errorf("truncated age payload")
+	}
+	payload, err := streamKey(fileKey, nonce)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return openStream(payload, ciphertext)
+}
+
+// wrapFileKey encrypts the file key for a stanza body
+func wrapFileKey(wrapKey, fileKey []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(wrapKey)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, make([]byte, chacha20poly1305.NonceSize), fileKey, nil), nil
+}
+
+// unwrapFileKey decrypts a stanza body; it fails when the key is wrong
+func unwrapFileKey(wrapKey, body []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(wrapKey)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, make([]byte, chacha20poly1305.NonceSize), body, nil)
+}
+
+func scryptKey(passphrase string, salt []byte, workFactor int) ([]byte, error) {
+	return scrypt.Key([]byte(passphrase), append([]byte(scryptLabel), salt...), 1<<workFactor, 8, 1, chacha20poly1305.KeySize)
+}
+
+func headerMAC(fileKey, header []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, fileKey, nil, []byte("header")), key); err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(header)
+	return h.Sum(nil), nil
+}
+
+func streamKey(fileKey, nonce []byte) ([]byte, error) {
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, fileKey, nonce, []byte("payload")), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// chunkNonce is an 11-byte big-endian counter followed by the last-chunk flag
+func chunkNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(nonce[3:11], counter)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func sealStream(w io.Writer, key, plaintext []byte) error {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return err
+	}
+	for counter := uint64(0); ; counter++ {
+		n := len(plaintext)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		last := n == len(plaintext)
+		if _, err := w.Write(aead.Seal(nil, chunkNonce(counter, last), plaintext[:n], nil)); err != nil {
+			return err
+		}
+		plaintext = plaintext[n:]
+		if last {
+			return nil
+		}
+	}
+}
+
+func openStream(key, ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	encChunk := chunkSize + aead.Overhead()
+
+	var out []byte
+	for counter := uint64(0); ; counter++ {
+		n := len(ciphertext)
+		if n > encChunk {
+			n = encChunk
+		}
+		last := n == len(ciphertext)
+		chunk, err := aead.Open(nil, chunkNonce(counter, last), ciphertext[:n], nil)
+		if err != nil {
+			return nil, fmt.Errorf("age payload is corrupted")
+		}
+		out = append(out, chunk...)
+		ciphertext = ciphertext[n:]
+		if last {
+			return out, nil
+		}
+	}
+}
+
+// readLine reads a header line, recording its raw bytes for the MAC
+func readLine(r *bufio.Reader, header *bytes.Buffer) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	header.WriteString(line)
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// writeWrapped writes a stanza body at 64 columns; a body that fills its
+// last line exactly is followed by an empty line
+func writeWrapped(w *bytes.Buffer, s string) {
+	for len(s) >= 64 {
+		w.WriteString(s[:64] + "\n")
+		s = s[64:]
+	}
+	w.WriteString(s + "\n")
+}
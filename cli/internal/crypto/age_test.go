@@ -0,0 +1,28 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 10, chunkSize, chunkSize + 1} {
+		plaintext := bytes.Repeat([]byte("s"), size)
+		encrypted, err := Encrypt(plaintext, "hunter2", 10)
+		if err != nil {
+			t.Fatalf("encrypt %d bytes: %v", size, err)
+		}
+
+		decrypted, err := Decrypt(encrypted, "hunter2")
+		if err != nil {
+			t.Fatalf("decrypt %d bytes: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("round trip of %d bytes changed the plaintext", size)
+		}
+
+		if _, err := Decrypt(encrypted, "wrong"); err != ErrBadPassphrase {
+			t.Fatalf("expected ErrBadPassphrase, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package crypto
+
+import (
+	"fmt"
+	"strings"
+)
+
+// age keys are Bech32 (BIP 173) without the 90 character limit
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var bech32Generator = []uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	var out []byte
+	for _, c := range hrp {
+		out = append(out, byte(c>>5))
+	}
+	out = append(out, 0)
+	for _, c := range hrp {
+		out = append(out, byte(c&31))
+	}
+	return out
+}
+
+// convertBits regroups data from frombits-wide to tobits-wide values
+func convertBits(data []byte, frombits, tobits uint, pad bool) ([]byte, error) {
+	var out []byte
+	acc, bits := uint32(0), uint(0)
+	maxv := uint32(1)<<tobits - 1
+	for _, b := range data {
+		if uint32(b)>>frombits != 0 {
+			return nil, fmt.Errorf("invalid data range")
+		}
+		acc = acc<<frombits | uint32(b)
+		bits += frombits
+		for bits >= tobits {
+			bits -= tobits
+			out = append(out, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			out = append(out, byte(acc<<(tobits-bits)&maxv))
+		}
+	} else if bits >= frombits || acc<<(tobits-bits)&maxv != 0 {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return out, nil
+}
+
+// bech32Encode encodes data with the human-readable part hrp; an uppercase
+// hrp gives an uppercase string
+func bech32Encode(hrp string, data []byte) (string, error) {
+	values, err := convertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	lower := strings.ToLower(hrp)
+	polymod := bech32Polymod(append(append(bech32HRPExpand(lower), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	var sb strings.Builder
+	sb.WriteString(lower + "1")
+	for _, v := range values {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(polymod>>uint(5*(5-i)))&31])
+	}
+	if hrp != lower {
+		return strings.ToUpper(sb.String()), nil
+	}
+	return sb.String(), nil
+}
+
+// bech32Decode returns the human-readable part (lowercased) and data of s
+func bech32Decode(s string) (string, []byte, error) {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return "", nil, fmt.Errorf("mixed case")
+	}
+	s = strings.ToLower(s)
+	pos := strings.LastIndexByte(s, '1')
+	if pos < 1 || pos+7 > len(s) {
+		return "", nil, fmt.Errorf("separator '1' at invalid position")
+	}
+	hrp := s[:pos]
+	var values []byte
+	for _, c := range s[pos+1:] {
+		i := strings.IndexRune(bech32Charset, c)
+		if i < 0 {
+			return "", nil, fmt.Errorf("invalid character %q", c)
+		}
+		values = append(values, byte(i))
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), values...)) != 1 {
+		return "", nil, fmt.Errorf("invalid checksum")
+	}
+	data, err := convertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+	return hrp, data, nil
+}
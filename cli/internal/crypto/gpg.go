@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const gpgArmorHeader = "-----BEGIN PGP MESSAGE-----"
+
+// IsGPG reports whether data is an ASCII-armored GPG message
+func IsGPG(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte(gpgArmorHeader))
+}
+
+// gpgEncrypt encrypts to GPG key IDs, fingerprints, or emails with the gpg CLI
+func gpgEncrypt(ctx context.Context, plaintext []byte, recipients []string) ([]byte, error) {
+	args := []string{"--batch", "--yes", "--armor", "--trust-model", "always", "--encrypt"}
+	for _, r := range recipients {
+		args = append(args, "--recipient", r)
+	}
+	return runGPG(ctx, plaintext, args...)
+}
+
+// gpgDecrypt decrypts with whatever secret key gpg has; gpg-agent asks for
+// the key's passphrase if needed
+func gpgDecrypt(ctx context.Context, data []byte) ([]byte, error) {
+	return runGPG(ctx, data, "--quiet", "--decrypt")
+}
+
+func runGPG(ctx context.Context, input []byte, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("gpg"); err != nil {
+		return nil, fmt.Errorf("gpg is not installed")
+	}
+	cmd := exec.CommandContext(ctx, "gpg", args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// gpg prefixes its own messages with "gpg: "
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, fmt.Errorf("gpg: %w", err)
+	}
+	return output, nil
+}
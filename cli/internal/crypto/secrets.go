@@ -0,0 +1,76 @@
+package crypto
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// SecretsFile is the encrypted secrets bundle committed to the pact repo
+const SecretsFile = "secrets.enc"
+
+// Bundle is the plaintext inside secrets.enc and 'pact secret export' files
+type Bundle struct {
+	Version  int                          `json:"version"`
+	Secrets  map[string]string            `json:"secrets"`
+	Projects map[string]map[string]string `json:"projects,omitempty"`
+}
+
+// Count returns the number of secrets in the bundle, project ones included
+func (b *Bundle) Count() int {
+	count := len(b.Secrets)
+	for _, secrets := range b.Projects {
+		count += len(secrets)
+	}
+	return count
+}
+
+// Seal encrypts a bundle to age recipients (age1...) or to GPG keys (IDs,
+// fingerprints, or emails). A file is one kind or the other, since age
+// can't decrypt GPG stanzas and gpg can't decrypt age ones.
+func Seal(ctx context.Context, bundle Bundle, recipients []string) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients")
+	}
+	var age, gpg []string
+	for _, r := range recipients {
+		if IsAgeRecipient(r) {
+			age = append(age, r)
+		} else {
+			gpg = append(gpg, r)
+		}
+	}
+	if len(age) > 0 && len(gpg) > 0 {
+		return nil, fmt.Errorf("recipients mix age keys and GPG keys; use one kind")
+	}
+
+	plaintext, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+	if len(gpg) > 0 {
+		return gpgEncrypt(ctx, plaintext, gpg)
+	}
+	return EncryptTo(plaintext, age)
+}
+
+// Open decrypts a sealed bundle: age files with the identities, GPG
+// messages with the gpg CLI
+func Open(ctx context.Context, data []byte, identities []string) (*Bundle, error) {
+	var plaintext []byte
+	var err error
+	if IsGPG(data) {
+		plaintext, err = gpgDecrypt(ctx, data)
+	} else {
+		plaintext, err = DecryptWith(data, identities)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return nil, fmt.Errorf("not a pact secrets bundle: %w", err)
+	}
+	return &bundle, nil
+}
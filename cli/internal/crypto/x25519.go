@@ -0,0 +1,200 @@
+package crypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	x25519Label     = "age-encryption.org/v1/X25519"
+	recipientPrefix = "age"
+	identityPrefix  = "AGE-SECRET-KEY-"
+)
+
+// ErrNoIdentity is returned when none of the identities can decrypt a file
+var ErrNoIdentity = errors.New("no identity matches any of the file's recipients")
+
+// IsAgeRecipient reports whether s looks like an age public key (age1...)
+func IsAgeRecipient(s string) bool {
+	return strings.HasPrefix(s, recipientPrefix+"1")
+}
+
+// GenerateIdentity creates an age X25519 key pair, returning the secret key
+// (AGE-SECRET-KEY-1...) and its recipient (age1...)
+func GenerateIdentity() (identity, recipient string, err error) {
+	secret := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	public, err := curve25519.X25519(secret, curve25519.Basepoint)
+	if err != nil {
+		return "", "", err
+	}
+	if identity, err = bech32Encode(identityPrefix, secret); err != nil {
+		return "", "", err
+	}
+	if recipient, err = bech32Encode(recipientPrefix, public); err != nil {
+		return "", "", err
+	}
+	return identity, recipient, nil
+}
+
+// Recipient returns the age1... recipient for an identity
+func Recipient(identity string) (string, error) {
+	secret, err := parseIdentity(identity)
+	if err != nil {
+		return "", err
+	}
+	public, err := curve25519.X25519(secret, curve25519.Basepoint)
+	if err != nil {
+		return "", err
+	}
+	return bech32Encode(recipientPrefix, public)
+}
+
+// ParseIdentities reads identities from an age key file: one
+// AGE-SECRET-KEY-1... per line, with # comments
+func ParseIdentities(data []byte) ([]string, error) {
+	var identities []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := parseIdentity(line); err != nil {
+			return nil, err
+		}
+		identities = append(identities, line)
+	}
+	return identities, scanner.Err()
+}
+
+// EncryptTo encrypts plaintext to age X25519 recipients
+func EncryptTo(plaintext []byte, recipients []string) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no recipients")
+	}
+	fileKey := make([]byte, 16)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, err
+	}
+
+	var stanzas []stanza
+	for _, r := range recipients {
+		public, err := parseRecipient(r)
+		if err != nil {
+			return nil, err
+		}
+		s, err := wrapX25519(fileKey, public)
+		if err != nil {
+			return nil, err
+		}
+		stanzas = append(stanzas, s)
+	}
+	return seal(fileKey, stanzas, plaintext)
+}
+
+// DecryptWith decrypts an age file with any of the X25519 identities
+func DecryptWith(data []byte, identities []string) ([]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	stanzas, header, mac, err := readHeader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range identities {
+		secret, err := parseIdentity(identity)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range stanzas {
+			if s.kind != "X25519" || len(s.args) != 1 {
+				continue
+			}
+			if fileKey, err := unwrapX25519(s, secret); err == nil {
+				return open(r, fileKey, header, mac)
+			}
+		}
+	}
+	return nil, ErrNoIdentity
+}
+
+func wrapX25519(fileKey, public []byte) (stanza, error) {
+	ephemeral := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeral); err != nil {
+		return stanza{}, err
+	}
+	share, err := curve25519.X25519(ephemeral, curve25519.Basepoint)
+	if err != nil {
+		return stanza{}, err
+	}
+	shared, err := curve25519.X25519(ephemeral, public)
+	if err != nil {
+		return stanza{}, err
+	}
+	wrapKey, err := x25519WrapKey(shared, share, public)
+	if err != nil {
+		return stanza{}, err
+	}
+	body, err := wrapFileKey(wrapKey, fileKey)
+	if err != nil {
+		return stanza{}, err
+	}
+	return stanza{kind: "X25519", args: []string{b64.EncodeToString(share)}, body: body}, nil
+}
+
+func unwrapX25519(s stanza, secret []byte) ([]byte, error) {
+	share, err := b64.DecodeString(s.args[0])
+	if err != nil || len(share) != curve25519.PointSize {
+		return nil, fmt.Errorf("malformed X25519 stanza")
+	}
+	// X25519 rejects the all-zero shared secret of a low-order share
+	shared, err := curve25519.X25519(secret, share)
+	if err != nil {
+		return nil, err
+	}
+	public, err := curve25519.X25519(secret, curve25519.Basepoint)
+	if err != nil {
+		return nil, err
+	}
+	wrapKey, err := x25519WrapKey(shared, share, public)
+	if err != nil {
+		return nil, err
+	}
+	return unwrapFileKey(wrapKey, s.body)
+}
+
+func x25519WrapKey(shared, share, public []byte) ([]byte, error) {
+	salt := append(append([]byte{}, share...), public...)
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(x25519Label)), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func parseRecipient(s string) ([]byte, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil || hrp != recipientPrefix || len(data) != curve25519.PointSize {
+		return nil, fmt.Errorf("invalid age recipient %q", s)
+	}
+	return data, nil
+}
+
+func parseIdentity(s string) ([]byte, error) {
+	hrp, data, err := bech32Decode(s)
+	if err != nil || hrp != strings.ToLower(identityPrefix) || len(data) != curve25519.ScalarSize {
+		return nil, fmt.Errorf("invalid age identity")
+	}
+	return data, nil
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBech32(t *testing.T) {
+	// A valid string from BIP 173
+	const valid = "abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxw"
+	hrp, data, err := bech32Decode(valid)
+	if err != nil || hrp != "abcdef" {
+		t.Fatalf("bech32Decode() = %q, %v", hrp, err)
+	}
+	if got, _ := bech32Encode(hrp, data); got != valid {
+		t.Fatalf("bech32Encode() = %s, want %s", got, valid)
+	}
+	if _, _, err := bech32Decode("abcdef1qpzry9x8gf2tvdw0s3jn54khce6mua7lmqqqxx"); err == nil {
+		t.Fatal("bech32Decode() accepted a bad checksum")
+	}
+}
+
+func TestRecipient(t *testing.T) {
+	identity, recipient, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(identity, "AGE-SECRET-KEY-1") || !IsAgeRecipient(recipient) {
+		t.Fatalf("GenerateIdentity() = %s, %s", identity, recipient)
+	}
+	if got, err := Recipient(identity); err != nil || got != recipient {
+		t.Fatalf("Recipient() = %s, %v; want %s", got, err, recipient)
+	}
+}
+
+func TestEncryptToDecryptWith(t *testing.T) {
+	alice, aliceRecipient, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, bobRecipient, err := GenerateIdentity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stranger, _, _ := GenerateIdentity()
+
+	plaintext := []byte(`{"secrets": {"API_KEY": "s3cret"}}`)
+	encrypted, err := EncryptTo(plaintext, []string{aliceRecipient, bobRecipient})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, identity := range []string{alice, bob} {
+		decrypted, err := DecryptWith(encrypted, []string{stranger, identity})
+		if err != nil {
+			t.Fatalf("DecryptWith() error: %v", err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("DecryptWith() = %s, want %s", decrypted, plaintext)
+		}
+	}
+
+	if _, err := DecryptWith(encrypted, []string{stranger}); err != ErrNoIdentity {
+		t.Fatalf("expected ErrNoIdentity, got %v", err)
+	}
+	if _, err := Decrypt(encrypted, "hunter2"); err == nil {
+		t.Fatal("Decrypt() with a passphrase succeeded on a recipient file")
+	}
+}
@@ -0,0 +1,169 @@
+package detect
+
+import (
+	"os/exec"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// AppsDetected holds detected GUI apps
+type AppsDetected struct {
+	Mas     []config.MasApp `json:"mas,omitempty"`     // nil when mas isn't installed
+	Startup []string        `json:"startup,omitempty"` // Apps launched at login; nil when they can't be listed
+	Flatpak []string        `json:"flatpak,omitempty"` // Flatpak app IDs; nil when flatpak isn't installed
+	Snap    []string        `json:"snap,omitempty"`    // Snaps, less bases and runtimes; nil when snap isn't installed
+}
+
+// DetectApps detects App Store apps installed through mas, Linux apps
+// installed with Flatpak or Snap, and the apps that launch at login
+func DetectApps() AppsDetected {
+	return AppsDetected{Mas: MasApps(), Startup: GetStartupApps(), Flatpak: FlatpakApps(), Snap: SnapApps()}
+}
+
+// FlatpakApps returns the IDs of the installed Flatpak apps, or nil when
+// flatpak isn't installed
+func FlatpakApps() []string {
+	if !isToolInstalled("flatpak") {
+		return nil
+	}
+	output, err := exec.Command("flatpak", "list", "--app", "--columns=application").Output()
+	if err != nil {
+		return nil
+	}
+	apps := []string{}
+	for _, line := range strings.Split(string(output), "\n") {
+		// Older flatpak prints a header even when piped
+		if id := strings.TrimSpace(line); id != "" && id != "Application ID" {
+			apps = append(apps, id)
+		}
+	}
+	return apps
+}
+
+// SnapApps returns the installed snaps, or nil when snap isn't installed
+func SnapApps() []string {
+	if !isToolInstalled("snap") {
+		return nil
+	}
+	output, err := exec.Command("snap", "list").Output()
+	if err != nil {
+		return nil
+	}
+	return parseSnapList(string(output))
+}
+
+// snapRuntimes are prefixes of snaps that other snaps pull in, rather than
+// apps anyone installs
+var snapRuntimes = []string{"core", "bare", "snapd", "gnome-", "gtk-common-themes", "kde-frameworks-", "kf5-", "kf6-", "mesa-", "qt-common-themes"}
+
+// parseSnapList parses `snap list`, skipping the header, bases, and the
+// runtimes apps depend on
+func parseSnapList(output string) []string {
+	apps := []string{}
+	for i, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if i == 0 || len(fields) == 0 {
+			continue
+		}
+		notes := fields[len(fields)-1]
+		if notes == "base" || notes == "core" || notes == "snapd" || hasAnyPrefix(fields[0], snapRuntimes) {
+			continue
+		}
+		apps = append(apps, fields[0])
+	}
+	return apps
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MasApps returns the App Store apps mas lists as installed, or nil when mas
+// isn't installed
+func MasApps() []config.MasApp {
+	if !isToolInstalled("mas") {
+		return nil
+	}
+	output, err := exec.Command("mas", "list").Output()
+	if err != nil {
+		return nil
+	}
+	return parseMasList(string(output))
+}
+
+// parseMasList parses `mas list` lines like "497799835  Xcode  (15.0)"
+func parseMasList(output string) []config.MasApp {
+	apps := []config.MasApp{}
+	for _, line := range strings.Split(output, "\n") {
+		id, rest, _ := strings.Cut(strings.TrimSpace(line), " ")
+		if id == "" || strings.Trim(id, "0123456789") != "" {
+			continue
+		}
+		name := strings.TrimSpace(rest)
+		if i := strings.LastIndex(name, "("); i > 0 {
+			name = strings.TrimSpace(name[:i])
+		}
+		apps = append(apps, config.MasApp{ID: id, Name: name})
+	}
+	return apps
+}
+
+// parseLoginItems parses AppleScript's list of login item names,
+// "Discord, Rectangle"
+func parseLoginItems(output string) []string {
+	items := []string{}
+	for _, name := range strings.Split(strings.TrimSpace(output), ", ") {
+		if name = strings.TrimSpace(name); name != "" {
+			items = append(items, name)
+		}
+	}
+	return items
+}
+
+// parseRunKey parses `reg query` output for a Run key, taking the value
+// names of lines like "    Discord    REG_SZ    C:\...\Update.exe"
+func parseRunKey(output string) []string {
+	items := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.HasPrefix(line, "    ") {
+			continue
+		}
+		fields := strings.Fields(line)
+		for i, field := range fields {
+			if strings.HasPrefix(field, "REG_") && i > 0 {
+				items = append(items, strings.Join(fields[:i], " "))
+				break
+			}
+		}
+	}
+	return items
+}
+
+// autostartEnabled reports whether a .desktop autostart entry runs, not
+// being Hidden or turned off in GNOME
+func autostartEnabled(data string) bool {
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch strings.TrimSpace(key) {
+		case "Hidden":
+			if value == "true" {
+				return false
+			}
+		case "X-GNOME-Autostart-enabled":
+			if value == "false" {
+				return false
+			}
+		}
+	}
+	return true
+}
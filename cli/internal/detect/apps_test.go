@@ -0,0 +1,47 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestParseMasList(t *testing.T) {
+	got := parseMasList("497799835  Xcode           (15.0)\n  904280696  Things 3 (3.19)\nNo installed apps found\n")
+	want := []config.MasApp{{ID: "497799835", Name: "Xcode"}, {ID: "904280696", Name: "Things 3"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseMasList() = %v, want %v", got, want)
+	}
+}
+
+func TestParseStartupApps(t *testing.T) {
+	if got, want := parseLoginItems("Discord, Rectangle, Visual Studio Code\n"), []string{"Discord", "Rectangle", "Visual Studio Code"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseLoginItems() = %v, want %v", got, want)
+	}
+
+	reg := "\r\nHKEY_CURRENT_USER\\Software\\Microsoft\\Windows\\CurrentVersion\\Run\r\n    Discord    REG_SZ    \"C:\\Users\\me\\AppData\\Local\\Discord\\Update.exe\" --processStart Discord.exe\r\n    Microsoft Teams    REG_SZ    C:\\teams.exe\r\n\r\n"
+	if got, want := parseRunKey(reg), []string{"Discord", "Microsoft Teams"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseRunKey() = %v, want %v", got, want)
+	}
+
+	if autostartEnabled("[Desktop Entry]\nExec=discord\nX-GNOME-Autostart-enabled=false\n") {
+		t.Fatal("autostartEnabled() = true for a disabled entry")
+	}
+	if !containsStartupApp([]string{"com.discordapp.Discord"}, "discord") {
+		t.Fatal("containsStartupApp() didn't match a Flatpak ID by its last part")
+	}
+}
+
+func TestParseSnapList(t *testing.T) {
+	output := `Name               Version          Rev    Tracking         Publisher     Notes
+core22             20240111         1122   latest/stable    canonical✓    base
+firefox            122.0-2          3728   latest/stable/…  mozilla✓      -
+gnome-42-2204      0+git.510a601    176    latest/stable/…  canonical✓    -
+snapd              2.61.1           20671  latest/stable    canonical✓    snapd
+code               8b3775030e       148    latest/stable    vscode✓       classic
+`
+	if got, want := parseSnapList(output), []string{"firefox", "code"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseSnapList() = %v, want %v", got, want)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
 )
 
 // configLocation defines where to look for a config file
@@ -39,6 +42,30 @@ func getConfigLocations() []configLocation {
 			paths:      []string{filepath.Join(home, ".profile"), filepath.Join(home, ".zprofile")},
 			destSubdir: "shell",
 		},
+		{
+			name:       "config.fish",
+			module:     "shell",
+			paths:      []string{filepath.Join(config.FishConfigDir(), "config.fish")},
+			destSubdir: "shell/fish",
+		},
+		{
+			name:       "config.nu",
+			module:     "shell",
+			paths:      []string{filepath.Join(config.NushellConfigDir(), "config.nu")},
+			destSubdir: "shell/nushell",
+		},
+		{
+			name:       "env.nu",
+			module:     "shell",
+			paths:      []string{filepath.Join(config.NushellConfigDir(), "env.nu")},
+			destSubdir: "shell/nushell",
+		},
+		{
+			name:       "direnvrc",
+			module:     "shell",
+			paths:      []string{filepath.Join(config.DirenvConfigDir(), "direnvrc")},
+			destSubdir: "shell/direnv",
+		},
 
 		// Git configs
 		{
@@ -53,6 +80,13 @@ func getConfigLocations() []configLocation {
 			paths:      []string{filepath.Join(home, ".gitignore_global"), filepath.Join(home, ".gitignore")},
 			destSubdir: "git",
 		},
+		{
+			name:       "pre-commit",
+			module:     "git",
+			paths:      []string{filepath.Join(home, ".config/pre-commit")},
+			destSubdir: "git",
+			isDir:      true,
+		},
 
 		// Tool configs
 		{
@@ -69,105 +103,135 @@ func getConfigLocations() []configLocation {
 		},
 	}
 
-	// Editor configs - platform specific
-	switch runtime.GOOS {
-	case "darwin":
+	// PowerShell profiles - Windows only
+	if runtime.GOOS == "windows" {
 		locations = append(locations,
 			configLocation{
+				name:       "powershell-profile",
+				module:     "shell",
+				paths:      config.GetPowerShellProfiles(config.ProfileCurrentUserCurrentHost),
+				destSubdir: "shell",
+			},
+			configLocation{
+				name:       "powershell-profile-allhosts",
+				module:     "shell",
+				paths:      config.GetPowerShellProfiles(config.ProfileCurrentUserAllHosts),
+				destSubdir: "shell",
+			},
+		)
+	}
+
+	return append(locations, osLocations(runtime.GOOS, home)...)
+}
+
+// osLocations returns the config locations that differ by platform, so
+// pact.json gets a target per OS for them
+func osLocations(goos, home string) []configLocation {
+	return append(editorLocations(goos, home), terminalLocations(goos, home)...)
+}
+
+// terminalConfigNames name each emulator's config file in .pact/terminal/
+var terminalConfigNames = map[string]string{
+	"ghostty":          "ghostty-config",
+	"alacritty":        "alacritty.toml",
+	"kitty":            "kitty.conf",
+	"windows-terminal": "windows-terminal-settings.json",
+}
+
+// terminalLocations returns the terminal emulator config locations for goos
+func terminalLocations(goos, home string) []configLocation {
+	var locations []configLocation
+	for _, emulator := range config.TerminalEmulators {
+		if path := config.TerminalConfigPath(emulator, goos, home); path != "" {
+			locations = append(locations, configLocation{
+				name:       terminalConfigNames[emulator],
+				module:     "terminal",
+				paths:      []string{path},
+				destSubdir: "terminal",
+			})
+		}
+	}
+	return locations
+}
+
+// editorLocations returns the editor config locations for goos, which differ
+// by platform
+func editorLocations(goos, home string) []configLocation {
+	switch goos {
+	case "darwin":
+		return []configLocation{
+			{
 				name:       "nvim",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, ".config/nvim")},
 				destSubdir: "editor",
 				isDir:      true,
 			},
-			configLocation{
+			{
 				name:       "vscode-settings",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, "Library/Application Support/Code/User/settings.json")},
 				destSubdir: "editor/vscode",
 			},
-			configLocation{
-				name:       "vscode-keybindings",
-				module:     "editor",
-				paths:      []string{filepath.Join(home, "Library/Application Support/Code/User/keybindings.json")},
-				destSubdir: "editor/vscode",
-			},
-			configLocation{
+			{
 				name:       "cursor-settings",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, "Library/Application Support/Cursor/User/settings.json")},
 				destSubdir: "editor/cursor",
 			},
-			configLocation{
+			{
 				name:       "zed-settings",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, ".config/zed/settings.json")},
 				destSubdir: "editor/zed",
 			},
-		)
+		}
 	case "linux":
-		locations = append(locations,
-			configLocation{
+		return []configLocation{
+			{
 				name:       "nvim",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, ".config/nvim")},
 				destSubdir: "editor",
 				isDir:      true,
 			},
-			configLocation{
+			{
 				name:       "vscode-settings",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, ".config/Code/User/settings.json")},
 				destSubdir: "editor/vscode",
 			},
-			configLocation{
-				name:       "vscode-keybindings",
-				module:     "editor",
-				paths:      []string{filepath.Join(home, ".config/Code/User/keybindings.json")},
-				destSubdir: "editor/vscode",
-			},
-		)
+		}
 	case "windows":
-		locations = append(locations,
-			configLocation{
-				name:   "powershell-profile",
-				module: "shell",
-				paths: []string{
-					filepath.Join(home, "Documents/PowerShell/Microsoft.PowerShell_profile.ps1"),
-					filepath.Join(home, "Documents/WindowsPowerShell/Microsoft.PowerShell_profile.ps1"),
-				},
-				destSubdir: "shell",
-			},
-			configLocation{
+		return []configLocation{
+			{
 				name:       "nvim",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, "AppData/Local/nvim")},
 				destSubdir: "editor",
 				isDir:      true,
 			},
-			configLocation{
+			{
 				name:       "vscode-settings",
 				module:     "editor",
 				paths:      []string{filepath.Join(home, "AppData/Roaming/Code/User/settings.json")},
 				destSubdir: "editor/vscode",
 			},
-			configLocation{
-				name:       "vscode-keybindings",
-				module:     "editor",
-				paths:      []string{filepath.Join(home, "AppData/Roaming/Code/User/keybindings.json")},
-				destSubdir: "editor/vscode",
-			},
-		)
+		}
 	}
 
-	return locations
+	return nil
 }
 
-// DiscoverConfigFiles finds config files on the system
-func DiscoverConfigFiles() []ConfigFile {
+// DiscoverConfigFiles finds config files on the system, only looking where
+// the given modules keep theirs when any are given
+func DiscoverConfigFiles(modules ...string) []ConfigFile {
 	var found []ConfigFile
 
 	for _, loc := range getConfigLocations() {
+		if len(modules) > 0 && !containsModule(modules, loc.module) {
+			continue
+		}
 		for _, p := range loc.paths {
 			info, err := os.Stat(p)
 			if err != nil {
@@ -197,6 +261,78 @@ func DiscoverConfigFiles() []ConfigFile {
 	return found
 }
 
+func containsModule(modules []string, module string) bool {
+	for _, m := range modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigFileAt describes the file or directory at path for adopting into
+// .pact/: where 'pact read' would put it when it's a known config, otherwise
+// a module named after it, so ~/.tmux.conf goes in tmux/tmux.conf and
+// ~/.config/wezterm/wezterm.lua in wezterm/wezterm.lua
+func ConfigFileAt(path string, isDir bool) ConfigFile {
+	path = filepath.Clean(path)
+	for _, loc := range getConfigLocations() {
+		for _, p := range loc.paths {
+			if filepath.Clean(p) == path {
+				return ConfigFile{
+					Name:       loc.name,
+					SourcePath: path,
+					DestPath:   filepath.Join(loc.destSubdir, loc.name),
+					Module:     loc.module,
+					Exists:     true,
+					IsDir:      isDir,
+				}
+			}
+		}
+	}
+
+	name := strings.TrimPrefix(filepath.Base(path), ".")
+	module, _, _ := strings.Cut(name, ".")
+	home, _ := os.UserHomeDir()
+	if rel, err := filepath.Rel(filepath.Join(home, ".config"), path); err == nil && !strings.HasPrefix(rel, "..") && rel != "." {
+		module, _, _ = strings.Cut(filepath.ToSlash(rel), "/")
+	}
+	return ConfigFile{
+		Name:       name,
+		SourcePath: path,
+		DestPath:   filepath.Join(module, name),
+		Module:     strings.ToLower(module),
+		Exists:     true,
+		IsDir:      isDir,
+	}
+}
+
+// OSTargets returns per-OS targets for pact.json when cf is an editor or
+// terminal config, which lives somewhere different on each platform, and
+// nil otherwise
+func OSTargets(cf ConfigFile) map[string]any {
+	home, _ := os.UserHomeDir()
+	current := false
+	for _, loc := range osLocations(runtime.GOOS, home) {
+		if loc.name == cf.Name && filepath.Clean(loc.paths[0]) == filepath.Clean(cf.SourcePath) {
+			current = true
+		}
+	}
+	if !current {
+		return nil
+	}
+
+	targets := map[string]any{}
+	for _, goos := range []string{"darwin", "linux", "windows"} {
+		for _, loc := range osLocations(goos, "~") {
+			if loc.name == cf.Name {
+				targets[goos] = filepath.ToSlash(loc.paths[0])
+			}
+		}
+	}
+	return targets
+}
+
 // CopyConfigFile copies a config file to the pact directory
 func CopyConfigFile(cf ConfigFile, pactDir string) error {
 	destPath := filepath.Join(pactDir, cf.DestPath)
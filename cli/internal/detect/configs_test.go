@@ -0,0 +1,27 @@
+package detect
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFileAt(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		path, module, dest string
+	}{
+		{".tmux.conf", "tmux", "tmux/tmux.conf"},
+		{".config/wezterm/wezterm.lua", "wezterm", "wezterm/wezterm.lua"},
+		{".config/kitty/kitty.conf", "terminal", "terminal/kitty.conf"},
+		{".gitconfig", "git", "git/gitconfig"},
+	}
+	for _, tt := range tests {
+		cf := ConfigFileAt(filepath.Join(home, tt.path), false)
+		if cf.Module != tt.module || filepath.ToSlash(cf.DestPath) != tt.dest {
+			t.Fatalf("ConfigFileAt(%s) = %s, %s; want %s, %s", tt.path, cf.Module, cf.DestPath, tt.module, tt.dest)
+		}
+	}
+}
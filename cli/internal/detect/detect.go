@@ -1,7 +1,9 @@
 package detect
 
 import (
+	"context"
 	"runtime"
+	"time"
 )
 
 // DetectedConfig holds everything found on the machine
@@ -12,14 +14,20 @@ type DetectedConfig struct {
 	Editor      EditorDetected   `json:"editor,omitempty"`
 	Terminal    TerminalDetected `json:"terminal,omitempty"`
 	LLM         LLMDetected      `json:"llm,omitempty"`
+	Apps        AppsDetected     `json:"apps,omitempty"`
+	Keybindings []EditorFile     `json:"keybindings,omitempty"`
+	Snippets    []EditorFile     `json:"snippets,omitempty"`
 	Secrets     []SecretDetected `json:"secrets,omitempty"`
 	ConfigFiles []ConfigFile     `json:"configFiles,omitempty"`
+
+	Timings []ProbeTiming `json:"-"`
 }
 
 // CLIDetected holds detected CLI tools
 type CLIDetected struct {
-	Tools  []string `json:"tools,omitempty"`
-	Custom []string `json:"custom,omitempty"`
+	Tools    []string            `json:"tools,omitempty"`
+	Custom   []string            `json:"custom,omitempty"`
+	Packages map[string][]string `json:"packages,omitempty"` // Global npm, pip, cargo, and go packages
 }
 
 // ShellDetected holds shell configuration info
@@ -27,6 +35,7 @@ type ShellDetected struct {
 	Type   string      `json:"type,omitempty"`
 	Prompt *PromptInfo `json:"prompt,omitempty"`
 	Tools  []string    `json:"tools,omitempty"`
+	Direnv *DirenvInfo `json:"direnv,omitempty"`
 }
 
 // PromptInfo holds prompt tool configuration
@@ -36,12 +45,30 @@ type PromptInfo struct {
 	Source string `json:"source,omitempty"`
 }
 
+// DirenvInfo is direnv's whitelist from direnv.toml
+type DirenvInfo struct {
+	Prefix []string `json:"prefix,omitempty"` // Directories whose .envrc files are trusted
+	Exact  []string `json:"exact,omitempty"`  // Individual trusted .envrc files
+}
+
 // GitDetected holds git configuration
 type GitDetected struct {
-	User          string `json:"user,omitempty"`
-	Email         string `json:"email,omitempty"`
-	DefaultBranch string `json:"defaultBranch,omitempty"`
-	LFS           bool   `json:"lfs,omitempty"`
+	User          string            `json:"user,omitempty"`
+	Email         string            `json:"email,omitempty"`
+	DefaultBranch string            `json:"defaultBranch,omitempty"`
+	LFS           bool              `json:"lfs,omitempty"`
+	Pager         string            `json:"pager,omitempty"`       // "delta" or "difftastic"
+	PagerTheme    string            `json:"pagerTheme,omitempty"`  // delta.syntax-theme
+	HookManager   string            `json:"hookManager,omitempty"` // "pre-commit" or "lefthook"
+	Signing       *SigningDetected  `json:"signing,omitempty"`
+	Config        map[string]string `json:"config,omitempty"` // Other global settings, for git.config
+}
+
+// SigningDetected is git's commit signing setup
+type SigningDetected struct {
+	Format string `json:"format"`        // gpg.format: "ssh" or "openpgp"
+	Key    string `json:"key,omitempty"` // user.signingkey
+	Tags   bool   `json:"tags,omitempty"`
 }
 
 // EditorDetected holds editor information
@@ -50,12 +77,24 @@ type EditorDetected struct {
 	Others  []string `json:"others,omitempty"`
 	Theme   string   `json:"theme,omitempty"`
 	Keymap  string   `json:"keymap,omitempty"`
+
+	Extensions map[string][]string `json:"extensions,omitempty"` // Installed extensions, for "vscode" and "cursor"
 }
 
 // TerminalDetected holds terminal configuration
 type TerminalDetected struct {
-	Font     string `json:"font,omitempty"`
-	FontSize int    `json:"fontSize,omitempty"`
+	Font      string                      `json:"font,omitempty"` // The default terminal's
+	FontSize  int                         `json:"fontSize,omitempty"`
+	Default   string                      `json:"default,omitempty"`
+	Emulators map[string]TerminalSettings `json:"emulators,omitempty"` // Installed ones, by config.TerminalEmulators name
+}
+
+// TerminalSettings are the font and theme an emulator is set to, as
+// terminal.<emulator> holds them
+type TerminalSettings struct {
+	Font     string  `json:"font,omitempty"`
+	FontSize float64 `json:"fontSize,omitempty"`
+	Theme    string  `json:"theme,omitempty"`
 }
 
 // LLMDetected holds LLM-related configuration
@@ -84,6 +123,28 @@ type SecretDetected struct {
 	InPactJSON bool   `json:"inPactJson"`
 }
 
+// Secret locations reported by SecretDetected.Location
+const (
+	SecretInBoth       = "env + keychain"
+	SecretEnvOnly      = "env only"
+	SecretKeychainOnly = "keychain only"
+	SecretNotOnMachine = "not set"
+)
+
+// Location classifies where the secret's value lives on this machine
+func (s SecretDetected) Location() string {
+	switch {
+	case s.InEnv && s.InKeychain:
+		return SecretInBoth
+	case s.InEnv:
+		return SecretEnvOnly
+	case s.InKeychain:
+		return SecretKeychainOnly
+	default:
+		return SecretNotOnMachine
+	}
+}
+
 // ConfigFile represents a discovered config file
 type ConfigFile struct {
 	Name       string `json:"name"`
@@ -96,67 +157,83 @@ type ConfigFile struct {
 
 // ScanOptions configures what to scan
 type ScanOptions struct {
-	Modules      []string // Specific modules to scan (empty = all)
-	IncludeFiles bool     // Whether to scan for config files
+	Modules      []string        // Specific modules to scan (empty = all)
+	IncludeFiles bool            // Whether to scan for config files
+	Context      context.Context // Stops the scan when cancelled (nil = never)
+	Secrets      []string        // pact.json's secrets, marked InPactJSON and always listed
+}
+
+// ProbeTiming is how long one of Scan's probes took, or why it didn't run
+type ProbeTiming struct {
+	Probe    string
+	Duration time.Duration
+	Skipped  string
+}
+
+// probe is one thing Scan looks at. It only runs when its module was asked
+// for and the command it shells out to, if any, is installed.
+type probe struct {
+	module  string
+	command string
+	run     func(d *DetectedConfig, opts ScanOptions)
 }
 
-// Scan performs a full environment scan
+var probes = []probe{
+	{"cli", "", func(d *DetectedConfig, _ ScanOptions) { d.CLI = DetectCLITools() }},
+	{"shell", "", func(d *DetectedConfig, _ ScanOptions) { d.Shell = DetectShell() }},
+	{"git", "git", func(d *DetectedConfig, _ ScanOptions) { d.Git = DetectGit() }},
+	{"editor", "", func(d *DetectedConfig, _ ScanOptions) { d.Editor = DetectEditor() }},
+	{"keybindings", "", func(d *DetectedConfig, _ ScanOptions) { d.Keybindings = DetectKeybindings() }},
+	{"snippets", "", func(d *DetectedConfig, _ ScanOptions) { d.Snippets = DetectSnippets() }},
+	{"terminal", "", func(d *DetectedConfig, _ ScanOptions) { d.Terminal = DetectTerminal() }},
+	{"llm", "", func(d *DetectedConfig, _ ScanOptions) { d.LLM = DetectLLM() }},
+	{"apps", "", func(d *DetectedConfig, _ ScanOptions) { d.Apps = DetectApps() }},
+	{"secrets", "", func(d *DetectedConfig, opts ScanOptions) { d.Secrets = DetectSecrets(opts.Secrets) }},
+}
+
+// Scan performs a full environment scan, or just the modules asked for.
+// Timings records each probe's time, in the order they ran.
 func Scan(opts ScanOptions) *DetectedConfig {
 	detected := &DetectedConfig{}
 
-	modules := opts.Modules
-	if len(modules) == 0 {
-		modules = []string{"cli", "shell", "git", "editor", "llm", "secrets"}
-	}
-
 	moduleSet := make(map[string]bool)
-	for _, m := range modules {
+	for _, m := range opts.Modules {
 		moduleSet[m] = true
 	}
+	wanted := func(module string) bool {
+		return len(opts.Modules) == 0 || moduleSet[module]
+	}
 
 	// Always scan config files if no specific modules requested
 	if len(opts.Modules) == 0 {
 		opts.IncludeFiles = true
 	}
 
-	if moduleSet["cli"] {
-		detected.CLI = DetectCLITools()
-	}
-
-	if moduleSet["shell"] {
-		detected.Shell = DetectShell()
-	}
-
-	if moduleSet["git"] {
-		detected.Git = DetectGit()
+	// A cancelled scan returns what was found so far
+	cancelled := func() bool {
+		return opts.Context != nil && opts.Context.Err() != nil
 	}
 
-	if moduleSet["editor"] {
-		detected.Editor = DetectEditor()
-	}
-
-	if moduleSet["llm"] {
-		detected.LLM = DetectLLM()
-	}
-
-	if moduleSet["secrets"] {
-		detected.Secrets = DetectSecrets(nil)
+	for _, p := range probes {
+		if !wanted(p.module) {
+			continue
+		}
+		if cancelled() {
+			return detected
+		}
+		if p.command != "" && !isToolInstalled(p.command) {
+			detected.Timings = append(detected.Timings, ProbeTiming{Probe: p.module, Skipped: p.command + " not installed"})
+			continue
+		}
+		start := time.Now()
+		p.run(detected, opts)
+		detected.Timings = append(detected.Timings, ProbeTiming{Probe: p.module, Duration: time.Since(start)})
 	}
 
-	if opts.IncludeFiles {
-		allConfigs := DiscoverConfigFiles()
-		// Filter config files by requested modules
-		if len(opts.Modules) > 0 {
-			var filtered []ConfigFile
-			for _, cf := range allConfigs {
-				if moduleSet[cf.Module] {
-					filtered = append(filtered, cf)
-				}
-			}
-			detected.ConfigFiles = filtered
-		} else {
-			detected.ConfigFiles = allConfigs
-		}
+	if opts.IncludeFiles && !cancelled() {
+		start := time.Now()
+		detected.ConfigFiles = DiscoverConfigFiles(opts.Modules...)
+		detected.Timings = append(detected.Timings, ProbeTiming{Probe: "config files", Duration: time.Since(start)})
 	}
 
 	return detected
@@ -0,0 +1,27 @@
+package detect
+
+import "testing"
+
+func TestScanOnlyRunsRequestedProbes(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	detected := Scan(ScanOptions{Modules: []string{"git", "shell"}, IncludeFiles: true, Secrets: []string{"API_KEY"}})
+
+	var ran []string
+	for _, timing := range detected.Timings {
+		ran = append(ran, timing.Probe)
+	}
+	if len(ran) != 3 || ran[0] != "shell" || ran[1] != "git" || ran[2] != "config files" {
+		t.Fatalf("probes = %v, want shell, git, config files", ran)
+	}
+	if detected.Timings[1].Skipped == "" {
+		t.Fatalf("git probe should be skipped without git installed")
+	}
+	if len(detected.Secrets) != 0 {
+		t.Fatalf("secrets were scanned: %v", detected.Secrets)
+	}
+	for _, cf := range detected.ConfigFiles {
+		if cf.Module != "git" && cf.Module != "shell" {
+			t.Fatalf("config file from unrequested module %s", cf.Module)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package detect
 
 import (
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/pkg"
 )
 
 // DiffResult shows differences for a module
@@ -43,11 +49,31 @@ func Compare(detected *DetectedConfig, cfg *config.PactConfig) []DiffResult {
 		results = append(results, editorDiff)
 	}
 
+	// Compare keybindings and snippets
+	for _, diff := range []DiffResult{
+		compareEditorFiles("keybindings", detected.Keybindings, cfg),
+		compareEditorFiles("snippets", detected.Snippets, cfg),
+	} {
+		if len(diff.LocalOnly) > 0 || len(diff.PactOnly) > 0 || len(diff.Synced) > 0 {
+			results = append(results, diff)
+		}
+	}
+
+	// Compare terminal emulators
+	if terminalDiff := compareTerminal(detected.Terminal, cfg); len(terminalDiff.LocalOnly) > 0 || len(terminalDiff.PactOnly) > 0 || len(terminalDiff.Synced) > 0 {
+		results = append(results, terminalDiff)
+	}
+
 	// Compare LLM
 	if llmDiff := compareLLM(detected.LLM, cfg); len(llmDiff.LocalOnly) > 0 || len(llmDiff.PactOnly) > 0 || len(llmDiff.Synced) > 0 {
 		results = append(results, llmDiff)
 	}
 
+	// Compare apps
+	if appsDiff := compareApps(detected.Apps, cfg); len(appsDiff.LocalOnly) > 0 || len(appsDiff.PactOnly) > 0 || len(appsDiff.Synced) > 0 {
+		results = append(results, appsDiff)
+	}
+
 	// Compare secrets
 	if secretsDiff := compareSecrets(detected.Secrets, cfg); len(secretsDiff.LocalOnly) > 0 || len(secretsDiff.PactOnly) > 0 || len(secretsDiff.Synced) > 0 {
 		results = append(results, secretsDiff)
@@ -104,6 +130,29 @@ func compareCLI(detected CLIDetected, cfg *config.PactConfig) DiffResult {
 		}
 	}
 
+	// Global language packages, the Value naming the manager
+	for _, manager := range config.PackageManagers {
+		specs := cfg.GetStringSlice("cli." + manager)
+		pactSet := make(map[string]bool, len(specs))
+		for _, spec := range specs {
+			pactSet[strings.ToLower(config.PackageName(manager, spec))] = true
+		}
+		installedSet := lowerSet(detected.Packages[manager])
+		for _, name := range detected.Packages[manager] {
+			item := DiffItem{Name: name, Type: "package", Value: manager}
+			if pactSet[strings.ToLower(name)] {
+				result.Synced = append(result.Synced, item)
+			} else {
+				result.LocalOnly = append(result.LocalOnly, item)
+			}
+		}
+		for _, spec := range specs {
+			if !installedSet[strings.ToLower(config.PackageName(manager, spec))] {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: spec, Type: "package", Value: manager})
+			}
+		}
+	}
+
 	return result
 }
 
@@ -142,6 +191,18 @@ func compareShell(detected ShellDetected, cfg *config.PactConfig) DiffResult {
 		}
 	}
 
+	// Compare direnv whitelist
+	pactWhitelist := cfg.HasKey("shell.direnv.whitelist")
+	if detected.Direnv != nil {
+		if pactWhitelist {
+			result.Synced = append(result.Synced, DiffItem{Name: "direnv whitelist", Type: "direnv"})
+		} else {
+			result.LocalOnly = append(result.LocalOnly, DiffItem{Name: "direnv whitelist", Type: "direnv"})
+		}
+	} else if pactWhitelist {
+		result.PactOnly = append(result.PactOnly, DiffItem{Name: "direnv whitelist", Type: "direnv"})
+	}
+
 	return result
 }
 
@@ -204,9 +265,100 @@ func compareGit(detected GitDetected, cfg *config.PactConfig) DiffResult {
 		result.PactOnly = append(result.PactOnly, DiffItem{Name: "lfs", Type: "setting", Value: true})
 	}
 
+	// Pager
+	pactPager := cfg.GetString("git.pager")
+	if pactPager == "" {
+		pactPager = cfg.GetString("git.pager.tool")
+	}
+	if detected.Pager != "" {
+		if detected.Pager == pactPager {
+			result.Synced = append(result.Synced, DiffItem{Name: "pager", Type: "setting", Value: detected.Pager})
+		} else {
+			result.LocalOnly = append(result.LocalOnly, DiffItem{Name: "pager", Type: "setting", Value: detected.Pager})
+		}
+	} else if pactPager != "" {
+		result.PactOnly = append(result.PactOnly, DiffItem{Name: "pager", Type: "setting", Value: pactPager})
+	}
+
+	// Hook manager
+	pactHooks := cfg.GetString("git.hookManager")
+	if pactHooks == "" {
+		pactHooks = cfg.GetString("git.hookManager.tool")
+	}
+	if detected.HookManager != "" {
+		if detected.HookManager == pactHooks {
+			result.Synced = append(result.Synced, DiffItem{Name: "hookManager", Type: "setting", Value: detected.HookManager})
+		} else {
+			result.LocalOnly = append(result.LocalOnly, DiffItem{Name: "hookManager", Type: "setting", Value: detected.HookManager})
+		}
+	} else if pactHooks != "" {
+		result.PactOnly = append(result.PactOnly, DiffItem{Name: "hookManager", Type: "setting", Value: pactHooks})
+	}
+
+	// Other settings, keyed the way git lists them
+	pactConfig := make(map[string]string)
+	for key, value := range cfg.GitConfig() {
+		pactConfig[canonicalGitKey(key)] = value
+	}
+	for _, key := range sortedKeys(detected.Config) {
+		value := detected.Config[key]
+		item := DiffItem{Name: key, Type: "gitconfig", Value: value}
+		if pactValue, ok := pactConfig[key]; ok && pactValue == value {
+			result.Synced = append(result.Synced, item)
+		} else {
+			result.LocalOnly = append(result.LocalOnly, item)
+		}
+	}
+	for _, key := range sortedKeys(pactConfig) {
+		if _, ok := detected.Config[key]; !ok {
+			result.PactOnly = append(result.PactOnly, DiffItem{Name: key, Type: "gitconfig", Value: pactConfig[key]})
+		}
+	}
+
+	// Commit signing, compared by format
+	pactSigning := ""
+	if _, ok := cfg.Get("git.signing").(map[string]any); ok || cfg.Get("git.signing") == true {
+		pactSigning = cfg.GetString("git.signing.format")
+		switch pactSigning {
+		case "":
+			pactSigning = "ssh"
+		case "gpg":
+			pactSigning = "openpgp"
+		}
+	}
+	if detected.Signing != nil {
+		if detected.Signing.Format == pactSigning {
+			result.Synced = append(result.Synced, DiffItem{Name: "signing", Type: "setting", Value: pactSigning})
+		} else {
+			result.LocalOnly = append(result.LocalOnly, DiffItem{Name: "signing", Type: "setting", Value: detected.Signing.Format})
+		}
+	} else if pactSigning != "" {
+		result.PactOnly = append(result.PactOnly, DiffItem{Name: "signing", Type: "setting", Value: pactSigning})
+	}
+
 	return result
 }
 
+// canonicalGitKey lowercases a git config key's section and name, as git
+// does, leaving a subsection's case alone
+func canonicalGitKey(key string) string {
+	first := strings.Index(key, ".")
+	last := strings.LastIndex(key, ".")
+	if first < 0 {
+		return strings.ToLower(key)
+	}
+	return strings.ToLower(key[:first]) + key[first:last] + strings.ToLower(key[last:])
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func compareEditor(detected EditorDetected, cfg *config.PactConfig) DiffResult {
 	result := DiffResult{Module: "editor"}
 
@@ -232,6 +384,124 @@ func compareEditor(detected EditorDetected, cfg *config.PactConfig) DiffResult {
 		}
 	}
 
+	// Extensions, the Value naming the editor
+	for _, e := range extensionEditors {
+		installed, ok := detected.Extensions[e.name]
+		pact := pactExtensions(cfg, e.name)
+		installedSet := lowerSet(installed)
+		pactSet := lowerSet(pact)
+		for _, ext := range installed {
+			item := DiffItem{Name: ext, Type: "extension", Value: e.name}
+			if pactSet[strings.ToLower(ext)] {
+				result.Synced = append(result.Synced, item)
+			} else {
+				result.LocalOnly = append(result.LocalOnly, item)
+			}
+		}
+		// Without the editor's CLI there's no telling what's missing
+		if !ok && !isToolInstalled(e.command) {
+			continue
+		}
+		for _, ext := range pact {
+			if !installedSet[strings.ToLower(ext)] {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: ext, Type: "extension", Value: e.name})
+			}
+		}
+	}
+
+	return result
+}
+
+// pactExtensions lists the extensions pact installs in editor:
+// editor.<editor>.extensions, plus editor.extensions when it's the default
+func pactExtensions(cfg *config.PactConfig, editor string) []string {
+	extensions := cfg.GetStringSlice("editor." + editor + ".extensions")
+	if normalizeEditorName(cfg.GetString("editor.default")) == editor {
+		extensions = append(extensions, cfg.GetStringSlice("editor.extensions")...)
+	}
+	return extensions
+}
+
+// lowerSet is toSet for names compared without case, such as extension IDs
+func lowerSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[strings.ToLower(item)] = true
+	}
+	return set
+}
+
+// compareTerminal compares the font and theme of each installed terminal
+// emulator against what terminal.<emulator> and the font set for every
+// emulator give it
+func compareTerminal(detected TerminalDetected, cfg *config.PactConfig) DiffResult {
+	result := DiffResult{Module: "terminal"}
+
+	for _, emulator := range config.TerminalEmulators {
+		inPact := cfg.HasKey("terminal." + emulator)
+		s, installed := detected.Emulators[emulator]
+		if !installed || s == (TerminalSettings{}) {
+			if inPact {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: emulator, Type: "emulator"})
+			}
+			continue
+		}
+
+		item := DiffItem{Name: emulator, Type: "emulator", Value: terminalSummary(s)}
+		if inPact && terminalMatches(s, cfg.TerminalFont(emulator), cfg.GetString("terminal."+emulator+".theme")) {
+			result.Synced = append(result.Synced, item)
+		} else {
+			result.LocalOnly = append(result.LocalOnly, item)
+		}
+	}
+
+	return result
+}
+
+// terminalSummary describes settings as "JetBrainsMono Nerd Font 14, nord"
+func terminalSummary(s TerminalSettings) string {
+	var parts []string
+	font := s.Font
+	if s.FontSize > 0 {
+		font = strings.TrimSpace(font + " " + strconv.FormatFloat(s.FontSize, 'f', -1, 64))
+	}
+	for _, part := range []string{font, s.Theme} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// terminalMatches reports whether every setting the emulator has is the
+// one in pact
+func terminalMatches(s TerminalSettings, font config.TerminalFont, theme string) bool {
+	return (s.Font == "" || s.Font == font.Family) &&
+		(s.FontSize == 0 || s.FontSize == font.Size) &&
+		(s.Theme == "" || s.Theme == theme)
+}
+
+// compareEditorFiles compares the editors with keybindings or snippets on
+// this machine against those set in the module
+func compareEditorFiles(module string, detected []EditorFile, cfg *config.PactConfig) DiffResult {
+	result := DiffResult{Module: module}
+
+	found := make(map[string]bool)
+	for _, f := range detected {
+		found[f.Editor] = true
+		item := DiffItem{Name: f.Editor, Type: module, Value: f.Path}
+		if cfg.HasKey(module + "." + f.Editor) {
+			result.Synced = append(result.Synced, item)
+		} else {
+			result.LocalOnly = append(result.LocalOnly, item)
+		}
+	}
+
+	for _, editor := range config.KeybindingEditors {
+		if cfg.HasKey(module+"."+editor) && !found[editor] {
+			result.PactOnly = append(result.PactOnly, DiffItem{Name: editor, Type: module})
+		}
+	}
 	return result
 }
 
@@ -295,25 +565,155 @@ func compareLLM(detected LLMDetected, cfg *config.PactConfig) DiffResult {
 	return result
 }
 
+// compareApps compares App Store apps, and Flatpak and Snap apps on Linux;
+// each kind is skipped where its command isn't installed
+func compareApps(detected AppsDetected, cfg *config.PactConfig) DiffResult {
+	result := DiffResult{Module: "apps"}
+
+	if detected.Mas != nil {
+		pactApps := cfg.GetMasApps()
+		pactIDs := make(map[string]bool)
+		for _, app := range pactApps {
+			pactIDs[app.ID] = true
+		}
+		detectedIDs := make(map[string]bool)
+		for _, app := range detected.Mas {
+			detectedIDs[app.ID] = true
+			item := DiffItem{Name: masName(app), Type: "mas", Value: app.ID}
+			if pactIDs[app.ID] {
+				result.Synced = append(result.Synced, item)
+			} else {
+				result.LocalOnly = append(result.LocalOnly, item)
+			}
+		}
+		for _, app := range pactApps {
+			if !detectedIDs[app.ID] {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: masName(app), Type: "mas", Value: app.ID})
+			}
+		}
+	}
+
+	if detected.Startup != nil {
+		pactStartup := cfg.Apps(runtime.GOOS).Startup
+		for _, app := range detected.Startup {
+			item := DiffItem{Name: app, Type: "startup"}
+			if containsStartupApp(pactStartup, app) {
+				result.Synced = append(result.Synced, item)
+			} else {
+				result.LocalOnly = append(result.LocalOnly, item)
+			}
+		}
+		for _, app := range pactStartup {
+			if !containsStartupApp(detected.Startup, app) {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: app, Type: "startup"})
+			}
+		}
+	}
+
+	// Flatpak and Snap apps, the Value naming the backend. An app is missing
+	// only when its entry says which backend it installs with.
+	pactApps := cfg.Apps(runtime.GOOS).Install
+	for _, backend := range []struct {
+		name      string
+		installed []string
+		key       func(config.Package) (string, bool)
+	}{
+		{"flatpak", detected.Flatpak, flatpakKey},
+		{"snap", detected.Snap, snapKey},
+	} {
+		if backend.installed == nil {
+			continue
+		}
+		installed := lowerSet(backend.installed)
+		pact := make(map[string]bool)
+		for _, app := range pactApps {
+			key, explicit := backend.key(app)
+			pact[key] = true
+			if explicit && !installed[key] {
+				result.PactOnly = append(result.PactOnly, DiffItem{Name: app.Name, Type: "app", Value: backend.name})
+			}
+		}
+		for _, app := range backend.installed {
+			item := DiffItem{Name: app, Type: backend.name}
+			if pact[strings.ToLower(app)] {
+				result.Synced = append(result.Synced, item)
+			} else {
+				result.LocalOnly = append(result.LocalOnly, item)
+			}
+		}
+	}
+
+	return result
+}
+
+// flatpakKey is the lowercased Flatpak ID of an apps.linux.install entry,
+// and whether the entry says it installs with Flatpak
+func flatpakKey(app config.Package) (string, bool) {
+	id, ok := app.For("flatpak")
+	if ok || pkg.IsFlatpakID(id) {
+		return strings.ToLower(id), true
+	}
+	return strings.ToLower(pkg.AppName(app.Name, "flatpak")), false
+}
+
+// snapKey is the lowercased snap name of an apps.linux.install entry, and
+// whether the entry names a snap
+func snapKey(app config.Package) (string, bool) {
+	name, ok := app.For("snap")
+	if fields := strings.Fields(name); len(fields) > 0 {
+		name = fields[0]
+	}
+	return strings.ToLower(name), ok
+}
+
+// containsStartupApp reports whether apps has app, matching names without
+// case, spaces, or dashes, and Linux autostart entries such as
+// com.discordapp.Discord by their last part
+func containsStartupApp(apps []string, app string) bool {
+	key := func(name string) string {
+		return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSuffix(name, ".app")))
+	}
+	last := func(name string) string {
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+	for _, a := range apps {
+		if key(a) == key(app) || key(last(a)) == key(app) || key(a) == key(last(app)) {
+			return true
+		}
+	}
+	return false
+}
+
+// masName is an App Store app's name, or its ID when pact.json lists only IDs
+func masName(app config.MasApp) string {
+	if app.Name != "" {
+		return app.Name
+	}
+	return app.ID
+}
+
 func compareSecrets(detected []SecretDetected, cfg *config.PactConfig) DiffResult {
 	result := DiffResult{Module: "secrets"}
 
-	pactSecrets := cfg.GetSecrets()
-	pactSecretsSet := toSet(pactSecrets)
+	pactSecretsSet := toSet(cfg.GetSecrets())
 
 	detectedSecretsSet := make(map[string]bool)
 	for _, s := range detected {
 		detectedSecretsSet[s.Name] = true
-		if pactSecretsSet[s.Name] {
-			result.Synced = append(result.Synced, DiffItem{Name: s.Name, Type: "secret"})
-		} else {
-			result.LocalOnly = append(result.LocalOnly, DiffItem{Name: s.Name, Type: "secret"})
+		item := DiffItem{Name: s.Name, Type: "secret", Value: s.Location()}
+		switch {
+		case !pactSecretsSet[s.Name]:
+			result.LocalOnly = append(result.LocalOnly, item)
+		case s.InEnv || s.InKeychain:
+			result.Synced = append(result.Synced, item)
+		default:
+			result.PactOnly = append(result.PactOnly, item)
 		}
 	}
 
-	for _, secret := range pactSecrets {
+	for _, secret := range cfg.GetSecrets() {
 		if !detectedSecretsSet[secret] {
-			result.PactOnly = append(result.PactOnly, DiffItem{Name: secret, Type: "secret"})
+			result.PactOnly = append(result.PactOnly, DiffItem{Name: secret, Type: "secret", Value: SecretNotOnMachine})
 		}
 	}
 
@@ -364,3 +764,48 @@ func CountMissingItems(diffs []DiffResult) int {
 	}
 	return count
 }
+
+// Drift narrows diffs to where the machine differs from pact.json. Config
+// files (which pact.json doesn't list) and other installed editors are
+// informational and dropped, as are modules left without differences.
+func Drift(diffs []DiffResult) []DiffResult {
+	var drift []DiffResult
+	for _, d := range diffs {
+		if d.Module == "files" {
+			continue
+		}
+		var local []DiffItem
+		for _, item := range d.LocalOnly {
+			if item.Type != "editor-other" {
+				local = append(local, item)
+			}
+		}
+		if len(local) == 0 && len(d.PactOnly) == 0 {
+			continue
+		}
+		d.LocalOnly = local
+		drift = append(drift, d)
+	}
+	return drift
+}
+
+// WithoutIgnored drops the local-only and pact-only items ignored reports,
+// so they're neither shown nor counted as drift
+func WithoutIgnored(diffs []DiffResult, ignored func(module string, item DiffItem) bool) []DiffResult {
+	keep := func(module string, items []DiffItem) []DiffItem {
+		var kept []DiffItem
+		for _, item := range items {
+			if !ignored(module, item) {
+				kept = append(kept, item)
+			}
+		}
+		return kept
+	}
+	filtered := make([]DiffResult, len(diffs))
+	for i, d := range diffs {
+		d.LocalOnly = keep(d.Module, d.LocalOnly)
+		d.PactOnly = keep(d.Module, d.PactOnly)
+		filtered[i] = d
+	}
+	return filtered
+}
@@ -0,0 +1,131 @@
+package detect
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestDrift(t *testing.T) {
+	diffs := []DiffResult{
+		{Module: "cli", Synced: []DiffItem{{Name: "jq", Type: "tool"}}},
+		{Module: "editor", LocalOnly: []DiffItem{{Name: "vim", Type: "editor-other"}}},
+		{Module: "files", LocalOnly: []DiffItem{{Name: "zshrc", Type: "config"}}},
+		{Module: "git", PactOnly: []DiffItem{{Name: "lfs", Type: "setting"}}},
+	}
+
+	drift := Drift(diffs)
+	if len(drift) != 1 || drift[0].Module != "git" {
+		t.Fatalf("Drift() = %+v, want only git", drift)
+	}
+}
+
+func TestWithoutIgnored(t *testing.T) {
+	diffs := []DiffResult{{
+		Module:    "cli",
+		LocalOnly: []DiffItem{{Name: "python3", Type: "tool"}, {Name: "jq", Type: "tool"}},
+		PactOnly:  []DiffItem{{Name: "bat", Type: "tool"}},
+	}}
+	got := WithoutIgnored(diffs, func(module string, item DiffItem) bool {
+		return module == "cli" && (item.Name == "python3" || item.Name == "bat")
+	})
+	if len(got[0].LocalOnly) != 1 || got[0].LocalOnly[0].Name != "jq" || len(got[0].PactOnly) != 0 {
+		t.Fatalf("WithoutIgnored() = %+v", got)
+	}
+	if len(Drift(got)) != 1 || len(diffs[0].LocalOnly) != 2 {
+		t.Fatalf("drift = %+v, original = %+v", Drift(got), diffs)
+	}
+}
+
+func TestCompareEditorExtensions(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"editor": {
+		"default": "code",
+		"extensions": ["esbenp.prettier-vscode"],
+		"vscode": {"extensions": ["golang.Go", "ms-python.python"]}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detected := EditorDetected{Extensions: map[string][]string{
+		"vscode": {"esbenp.prettier-vscode", "golang.go", "vscodevim.vim"},
+	}}
+
+	diff := compareEditor(detected, cfg)
+	names := func(items []DiffItem) []string {
+		var out []string
+		for _, item := range items {
+			if item.Type == "extension" {
+				out = append(out, item.Name)
+			}
+		}
+		return out
+	}
+	if got := names(diff.Synced); len(got) != 2 || got[0] != "esbenp.prettier-vscode" || got[1] != "golang.go" {
+		t.Fatalf("synced = %v", got)
+	}
+	if got := names(diff.LocalOnly); len(got) != 1 || got[0] != "vscodevim.vim" {
+		t.Fatalf("local only = %v", got)
+	}
+	if got := names(diff.PactOnly); len(got) != 1 || got[0] != "ms-python.python" {
+		t.Fatalf("pact only = %v", got)
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	output := "Installing extensions...\nms-python.python\n\nEsbenp.prettier-vscode\n"
+	got := parseExtensions(output)
+	if len(got) != 2 || got[0] != "Esbenp.prettier-vscode" || got[1] != "ms-python.python" {
+		t.Fatalf("parseExtensions() = %v", got)
+	}
+}
+
+func TestCompareCLIPackages(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"cli": {"npm": ["typescript@5", "@biomejs/biome"], "go": ["golang.org/x/tools/gopls@latest"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detected := CLIDetected{Packages: map[string][]string{
+		"npm": {"typescript", "prettier"},
+		"go":  {"golang.org/x/tools/gopls"},
+	}}
+
+	diff := compareCLI(detected, cfg)
+	if len(diff.Synced) != 2 || diff.Synced[0].Name != "typescript" || diff.Synced[1].Value != "go" {
+		t.Fatalf("synced = %+v", diff.Synced)
+	}
+	if len(diff.LocalOnly) != 1 || diff.LocalOnly[0].Name != "prettier" {
+		t.Fatalf("local only = %+v", diff.LocalOnly)
+	}
+	if len(diff.PactOnly) != 1 || diff.PactOnly[0].Name != "@biomejs/biome" || diff.PactOnly[0].Value != "npm" {
+		t.Fatalf("pact only = %+v", diff.PactOnly)
+	}
+}
+
+func TestCompareLinuxApps(t *testing.T) {
+	cfg, err := config.Parse([]byte(`{"apps": {"` + runtime.GOOS + `": {"install": [
+		"com.spotify.Client", "org.gimp.GIMP", {"name": "code", "snap": "code --classic"}, "firefox"
+	]}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	detected := AppsDetected{Flatpak: []string{"com.spotify.Client", "org.videolan.VLC"}, Snap: []string{"firefox"}}
+
+	diff := compareApps(detected, cfg)
+	if len(diff.Synced) != 2 || diff.Synced[0].Name != "com.spotify.Client" || diff.Synced[1].Name != "firefox" {
+		t.Fatalf("synced = %+v", diff.Synced)
+	}
+	if len(diff.LocalOnly) != 1 || diff.LocalOnly[0].Type != "flatpak" {
+		t.Fatalf("local only = %+v", diff.LocalOnly)
+	}
+	if len(diff.PactOnly) != 2 || diff.PactOnly[0].Name != "org.gimp.GIMP" || diff.PactOnly[1].Value != "snap" {
+		t.Fatalf("pact only = %+v", diff.PactOnly)
+	}
+}
+
+func TestParseGoBuildPath(t *testing.T) {
+	output := "/home/me/go/bin/gopls: go1.22.1\n\tpath\tgolang.org/x/tools/gopls\n\tmod\tgolang.org/x/tools/gopls\tv0.15.2\th1:abc=\n"
+	if got := parseGoBuildPath(output); got != "golang.org/x/tools/gopls" {
+		t.Fatalf("parseGoBuildPath() = %q", got)
+	}
+}
@@ -0,0 +1,55 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+var (
+	tomlHeaderPattern = regexp.MustCompile(`(?m)^\s*\[`)
+	whitelistPattern  = regexp.MustCompile(`(?s)\b(prefix|exact)\s*=\s*\[(.*?)\]`)
+	quotedPattern     = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// detectDirenv reads the whitelist from direnv.toml
+func detectDirenv() *DirenvInfo {
+	data, err := os.ReadFile(filepath.Join(config.DirenvConfigDir(), "direnv.toml"))
+	if err != nil {
+		return nil
+	}
+	return parseDirenvWhitelist(string(data))
+}
+
+// parseDirenvWhitelist extracts prefix and exact from the [whitelist] table
+func parseDirenvWhitelist(data string) *DirenvInfo {
+	start := strings.Index(data, "[whitelist]")
+	if start < 0 {
+		return nil
+	}
+	table := data[start+len("[whitelist]"):]
+	if next := tomlHeaderPattern.FindStringIndex(table); next != nil {
+		table = table[:next[0]]
+	}
+
+	info := &DirenvInfo{}
+	for _, m := range whitelistPattern.FindAllStringSubmatch(table, -1) {
+		var paths []string
+		for _, q := range quotedPattern.FindAllStringSubmatch(m[2], -1) {
+			paths = append(paths, q[1]+q[2])
+		}
+		if m[1] == "prefix" {
+			info.Prefix = append(info.Prefix, paths...)
+		} else {
+			info.Exact = append(info.Exact, paths...)
+		}
+	}
+
+	if len(info.Prefix) == 0 && len(info.Exact) == 0 {
+		return nil
+	}
+	return info
+}
@@ -0,0 +1,34 @@
+package detect
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDirenvWhitelist(t *testing.T) {
+	got := parseDirenvWhitelist(`
+[global]
+load_dotenv = true
+
+[whitelist]
+prefix = [
+  "/home/me/work",
+  '/home/me/oss',
+]
+exact = ["/home/me/code/app/.envrc"]
+
+[other]
+prefix = ["/ignored"]
+`)
+	want := &DirenvInfo{
+		Prefix: []string{"/home/me/work", "/home/me/oss"},
+		Exact:  []string{"/home/me/code/app/.envrc"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("parseDirenvWhitelist() = %+v, want %+v", got, want)
+	}
+
+	if got := parseDirenvWhitelist("[global]\nload_dotenv = true\n"); got != nil {
+		t.Fatalf("parseDirenvWhitelist() without whitelist = %+v, want nil", got)
+	}
+}
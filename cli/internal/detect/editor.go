@@ -2,6 +2,9 @@ package detect
 
 import (
 	"os"
+	"os/exec"
+	"sort"
+	"strings"
 )
 
 // Known editors in preference order
@@ -57,10 +60,60 @@ func DetectEditor() EditorDetected {
 	}
 
 	result.Others = installed
+	result.Extensions = DetectEditorExtensions()
 
 	return result
 }
 
+// extensionEditors are the editors whose extensions pact can list, with the
+// command that lists them
+var extensionEditors = []struct {
+	name    string
+	command string
+}{
+	{"vscode", "code"},
+	{"cursor", "cursor"},
+}
+
+// DetectEditorExtensions lists the extensions installed in VS Code and
+// Cursor, by editor
+func DetectEditorExtensions() map[string][]string {
+	var result map[string][]string
+	for _, e := range extensionEditors {
+		if !isToolInstalled(e.command) {
+			continue
+		}
+		output, err := exec.Command(e.command, "--list-extensions").Output()
+		if err != nil {
+			continue
+		}
+		extensions := parseExtensions(string(output))
+		if len(extensions) == 0 {
+			continue
+		}
+		if result == nil {
+			result = make(map[string][]string)
+		}
+		result[e.name] = extensions
+	}
+	return result
+}
+
+// parseExtensions reads --list-extensions output, one publisher.name per
+// line, skipping anything else the CLI prints
+func parseExtensions(output string) []string {
+	var extensions []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.ContainsAny(line, " \t") || !strings.Contains(line, ".") {
+			continue
+		}
+		extensions = append(extensions, line)
+	}
+	sort.Slice(extensions, func(i, j int) bool { return strings.ToLower(extensions[i]) < strings.ToLower(extensions[j]) })
+	return extensions
+}
+
 // normalizeEditorName converts editor command to name
 func normalizeEditorName(cmd string) string {
 	switch cmd {
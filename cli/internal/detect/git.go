@@ -1,8 +1,13 @@
 package detect
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
 	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
 )
 
 // DetectGit detects git configuration
@@ -17,9 +22,128 @@ func DetectGit() GitDetected {
 	// Check for Git LFS
 	result.LFS = isGitLFSInstalled()
 
+	// Check for a delta or difftastic pager
+	if strings.Contains(getGitConfig("core.pager"), "delta") {
+		result.Pager = "delta"
+		result.PagerTheme = getGitConfig("delta.syntax-theme")
+	} else if strings.Contains(getGitConfig("diff.external"), "difft") {
+		result.Pager = "difftastic"
+	}
+
+	result.HookManager = detectHookManager()
+	result.Signing = detectSigning()
+	result.Config = detectGitConfig()
+
 	return result
 }
 
+// detectSigning reads the signing setup, if commits are signed
+func detectSigning() *SigningDetected {
+	if getGitConfig("commit.gpgsign") != "true" {
+		return nil
+	}
+	signing := &SigningDetected{
+		Format: getGitConfig("gpg.format"),
+		Key:    getGitConfig("user.signingkey"),
+		Tags:   getGitConfig("tag.gpgsign") == "true",
+	}
+	if signing.Format == "" {
+		signing.Format = "openpgp"
+	}
+	return signing
+}
+
+// managedGitKeys are global settings pact reads through its own git keys, or
+// that only make sense on one machine; detectGitConfig leaves them out.
+// Entries ending in "." cover a whole section.
+var managedGitKeys = []string{
+	"user.name", "user.email", "user.signingkey", "init.defaultbranch", "init.templatedir",
+	"core.pager", "interactive.difffilter", "diff.external", "delta.",
+	"commit.gpgsign", "tag.gpgsign", "gpg.format",
+	"credential.", "filter.lfs.", "include.", "includeif.", "safe.directory",
+}
+
+// detectGitConfig reads the rest of the global gitconfig. Keys set more than
+// once, like extra include paths, are skipped since git.config holds one
+// value per key.
+func detectGitConfig() map[string]string {
+	output, err := exec.Command("git", "config", "--global", "--null", "--list").Output()
+	if err != nil {
+		return nil
+	}
+	return parseGitConfigList(string(output))
+}
+
+// parseGitConfigList reads 'git config --null --list' output: "key\nvalue"
+// entries, each ending in a NUL
+func parseGitConfigList(output string) map[string]string {
+	settings := make(map[string]string)
+	seen := make(map[string]int)
+	for _, entry := range strings.Split(output, "\x00") {
+		if entry == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(entry, "\n")
+		if managedGitKey(key) || secretGitKey(key, value) {
+			continue
+		}
+		seen[key]++
+		settings[key] = value
+	}
+	for key, n := range seen {
+		if n > 1 {
+			delete(settings, key)
+		}
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	return settings
+}
+
+func managedGitKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, managed := range managedGitKeys {
+		if key == managed || strings.HasSuffix(managed, ".") && strings.HasPrefix(key, managed) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretGitKeys hold credentials, which must never reach pact.json since
+// it's committed and pushed. Entries ending in "." cover a whole section,
+// and those starting with "." a key in any subsection.
+var secretGitKeys = []string{
+	"credential.", ".extraheader", "github.token", "github.oauth-token", "hub.oauthtoken",
+	"gitlab.token", "sendemail.smtppass", "sendemail.smtpuser", ".password", ".token",
+}
+
+// urlCredentials matches a URL with a user and password or token in it
+var urlCredentials = regexp.MustCompile(`://[^/@\s]+:[^/@\s]+@`)
+
+// secretGitKey reports whether a global setting holds a credential: a key
+// known for it, or a URL with credentials in its key or value, as an
+// insteadOf rewrite can have
+func secretGitKey(key, value string) bool {
+	key = strings.ToLower(key)
+	for _, secret := range secretGitKeys {
+		switch {
+		case strings.HasSuffix(secret, "."):
+			if strings.HasPrefix(key, secret) {
+				return true
+			}
+		case strings.HasPrefix(secret, "."):
+			if strings.HasSuffix(key, secret) {
+				return true
+			}
+		case key == secret:
+			return true
+		}
+	}
+	return urlCredentials.MatchString(key) || urlCredentials.MatchString(value)
+}
+
 // getGitConfig retrieves a git config value
 func getGitConfig(key string) string {
 	cmd := exec.Command("git", "config", "--global", "--get", key)
@@ -42,3 +166,26 @@ func isGitLFSInstalled() bool {
 	err := cmd.Run()
 	return err == nil
 }
+
+// detectHookManager finds a global pre-commit or lefthook setup: a hook in
+// git's init.templateDir, or pre-commit's ~/.config/pre-commit
+func detectHookManager() string {
+	if dir := getGitConfig("init.templateDir"); dir != "" {
+		if dir, err := config.ExpandPath(dir); err == nil {
+			if data, err := os.ReadFile(filepath.Join(dir, "hooks", "pre-commit")); err == nil {
+				switch {
+				case strings.Contains(string(data), "lefthook"):
+					return "lefthook"
+				case strings.Contains(string(data), "pre-commit"):
+					return "pre-commit"
+				}
+			}
+		}
+	}
+
+	home, _ := os.UserHomeDir()
+	if _, err := os.Stat(filepath.Join(home, ".config", "pre-commit")); err == nil && isToolInstalled("pre-commit") {
+		return "pre-commit"
+	}
+	return ""
+}
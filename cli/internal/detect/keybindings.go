@@ -0,0 +1,38 @@
+package detect
+
+import (
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// EditorFile is an editor's keybindings file or snippets directory
+type EditorFile struct {
+	Editor string `json:"editor"`
+	Path   string `json:"path"`
+}
+
+// DetectKeybindings finds user keybindings files. For Neovim that is only
+// the file pact places; other keymaps travel with the nvim config.
+func DetectKeybindings() []EditorFile {
+	var found []EditorFile
+	for _, editor := range config.KeybindingEditors {
+		path := config.KeybindingsPath(editor)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() && info.Size() > 0 {
+			found = append(found, EditorFile{Editor: editor, Path: path})
+		}
+	}
+	return found
+}
+
+// DetectSnippets finds non-empty user snippets directories
+func DetectSnippets() []EditorFile {
+	var found []EditorFile
+	for _, editor := range config.KeybindingEditors {
+		dir := config.SnippetsDir(editor)
+		if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+			found = append(found, EditorFile{Editor: editor, Path: dir})
+		}
+	}
+	return found
+}
@@ -2,26 +2,40 @@ package detect
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/cloudboy-jh/pact/internal/config"
 )
 
 // ImportSelection represents what the user wants to import
 type ImportSelection struct {
-	CLITools     []string     // Tools to add to cli.tools
-	CLICustom    []string     // Tools to add to cli.custom
-	ShellPrompt  *PromptInfo  // Prompt config to set
-	ShellTools   []string     // Tools to add to shell.tools
-	Git          *GitDetected // Git settings to import
-	Editor       string       // Default editor to set
-	LLMProviders []string     // Providers to add
-	LLMRuntime   string       // Local runtime (ollama)
-	LLMModels    []string     // Models to add
-	LLMAgents    []string     // Coding agents to add
-	Secrets      []string     // Secrets to add to secrets array
-	ConfigFiles  []ConfigFile // Config files to copy
+	CLITools     []string                    // Tools to add to cli.tools
+	CLICustom    []string                    // Tools to add to cli.custom
+	CLIPackages  map[string][]string         // Packages to add to cli.<manager>
+	ShellPrompt  *PromptInfo                 // Prompt config to set
+	ShellTools   []string                    // Tools to add to shell.tools
+	ShellDirenv  *DirenvInfo                 // direnv whitelist to set
+	Git          *GitDetected                // Git settings to import
+	Editor       string                      // Default editor to set
+	Extensions   map[string][]string         // Extensions to add to editor.<editor>.extensions
+	Terminal     map[string]TerminalSettings // Emulator settings to set
+	LLMProviders []string                    // Providers to add
+	LLMRuntime   string                      // Local runtime (ollama)
+	LLMModels    []string                    // Models to add
+	LLMAgents    []string                    // Coding agents to add
+	MasApps      []config.MasApp             // App Store apps to add to apps.darwin.mas
+	StartupApps  []string                    // Apps to add to apps.<os>.startup
+	FlatpakApps  []string                    // Flatpak app IDs to add to apps.linux.install
+	Snaps        []string                    // Snaps to add to apps.linux.install
+	Keybindings  []EditorFile                // Keybindings files to copy
+	Snippets     []EditorFile                // Snippets directories to copy
+	Secrets      []string                    // Secrets to add to secrets array
+	ConfigFiles  []ConfigFile                // Config files to copy
 }
 
 // Merge applies the import selection to pact.json
@@ -34,10 +48,11 @@ func Merge(selection ImportSelection, pactDir string) error {
 		return err
 	}
 
-	var raw map[string]any
-	if err := json.Unmarshal(data, &raw); err != nil {
+	cfg, err := config.Parse(data)
+	if err != nil {
 		return err
 	}
+	raw := cfg.Raw
 
 	// Merge CLI tools
 	if len(selection.CLITools) > 0 || len(selection.CLICustom) > 0 {
@@ -53,9 +68,13 @@ func Merge(selection ImportSelection, pactDir string) error {
 			cli["custom"] = mergeStringSlices(existing, selection.CLICustom)
 		}
 	}
+	for manager, packages := range selection.CLIPackages {
+		cli := getOrCreateMap(raw, "cli")
+		cli[manager] = mergeStringSlices(getStringSlice(cli, manager), packages)
+	}
 
 	// Merge shell config
-	if selection.ShellPrompt != nil || len(selection.ShellTools) > 0 {
+	if selection.ShellPrompt != nil || len(selection.ShellTools) > 0 || selection.ShellDirenv != nil {
 		shell := getOrCreateMap(raw, "shell")
 
 		if selection.ShellPrompt != nil {
@@ -74,6 +93,11 @@ func Merge(selection ImportSelection, pactDir string) error {
 			existing := getStringSlice(shell, "tools")
 			shell["tools"] = mergeStringSlices(existing, selection.ShellTools)
 		}
+
+		if selection.ShellDirenv != nil {
+			direnv := getOrCreateMap(shell, "direnv")
+			direnv["whitelist"] = direnvWhitelist(selection.ShellDirenv)
+		}
 	}
 
 	// Merge git config
@@ -92,6 +116,21 @@ func Merge(selection ImportSelection, pactDir string) error {
 		if selection.Git.LFS {
 			git["lfs"] = true
 		}
+		if selection.Git.Pager != "" {
+			git["pager"] = pagerConfig(selection.Git.Pager, selection.Git.PagerTheme)
+		}
+		if selection.Git.HookManager != "" {
+			git["hookManager"] = selection.Git.HookManager
+		}
+		if selection.Git.Signing != nil {
+			git["signing"] = signingConfig(selection.Git.Signing)
+		}
+		if len(selection.Git.Config) > 0 {
+			gitConfig := getOrCreateMap(git, "config")
+			for key, value := range selection.Git.Config {
+				putGitConfig(gitConfig, key, value)
+			}
+		}
 	}
 
 	// Merge editor config
@@ -99,6 +138,18 @@ func Merge(selection ImportSelection, pactDir string) error {
 		editor := getOrCreateMap(raw, "editor")
 		editor["default"] = selection.Editor
 	}
+	for name, extensions := range selection.Extensions {
+		editor := getOrCreateMap(getOrCreateMap(raw, "editor"), name)
+		editor["extensions"] = mergeStringSlices(getStringSlice(editor, "extensions"), extensions)
+	}
+
+	// Merge terminal emulator settings
+	if len(selection.Terminal) > 0 {
+		terminal := getOrCreateMap(raw, "terminal")
+		for emulator, s := range selection.Terminal {
+			terminal[emulator] = terminalConfig(s)
+		}
+	}
 
 	// Merge LLM config
 	if len(selection.LLMProviders) > 0 || selection.LLMRuntime != "" || len(selection.LLMModels) > 0 || len(selection.LLMAgents) > 0 {
@@ -127,10 +178,68 @@ func Merge(selection ImportSelection, pactDir string) error {
 		}
 	}
 
+	// Merge App Store apps, keeping the list or name-to-ID form already there
+	if len(selection.MasApps) > 0 {
+		darwin := getOrCreateMap(getOrCreateMap(raw, "apps"), "darwin")
+		if list, ok := darwin["mas"].([]any); ok {
+			for _, app := range selection.MasApps {
+				list = append(list, masValue(app.ID))
+			}
+			darwin["mas"] = list
+		} else {
+			byName, ok := darwin["mas"].(map[string]any)
+			if !ok {
+				byName = make(map[string]any)
+			}
+			for _, app := range selection.MasApps {
+				byName[masName(app)] = masValue(app.ID)
+			}
+			darwin["mas"] = byName
+		}
+	}
+
+	// Merge startup apps into this OS's apps
+	if len(selection.StartupApps) > 0 {
+		apps := getOrCreateMap(getOrCreateMap(raw, "apps"), runtime.GOOS)
+		apps["startup"] = mergeStringSlices(getStringSlice(apps, "startup"), selection.StartupApps)
+	}
+
+	// Merge Flatpak and Snap apps. A Flatpak ID installs with Flatpak as is;
+	// a snap is an object so it installs with Snap.
+	if len(selection.FlatpakApps) > 0 || len(selection.Snaps) > 0 {
+		linux := getOrCreateMap(getOrCreateMap(raw, "apps"), "linux")
+		linux["install"] = appendApps(linux["install"], selection.FlatpakApps, selection.Snaps)
+	}
+
 	// Merge secrets
 	if len(selection.Secrets) > 0 {
-		existing := getStringSlice(raw, "secrets")
-		raw["secrets"] = mergeStringSlices(existing, selection.Secrets)
+		if scoped, ok := raw["secrets"].(map[string]any); ok {
+			// Scoped secrets: detected env secrets are global
+			existing := getStringSlice(scoped, "global")
+			scoped["global"] = mergeStringSlices(existing, selection.Secrets)
+		} else {
+			existing := getStringSlice(raw, "secrets")
+			raw["secrets"] = mergeStringSlices(existing, selection.Secrets)
+		}
+	}
+
+	// Copy keybindings and snippets into the repo and point the modules at them
+	for _, kb := range selection.Keybindings {
+		dest := filepath.Join("keybindings", kb.Editor+filepath.Ext(kb.Path))
+		if err := os.MkdirAll(filepath.Join(pactDir, "keybindings"), 0755); err != nil {
+			continue
+		}
+		if err := copyFile(kb.Path, filepath.Join(pactDir, dest)); err != nil {
+			continue
+		}
+		getOrCreateMap(raw, "keybindings")[kb.Editor] = filepath.ToSlash(dest)
+	}
+	for _, sn := range selection.Snippets {
+		dest := filepath.Join("snippets", sn.Editor)
+		if err := copyDir(sn.Path, filepath.Join(pactDir, dest)); err != nil {
+			continue
+		}
+		getOrCreateMap(raw, "snippets")[sn.Editor] = filepath.ToSlash(dest)
 	}
 
 	// Copy config files
@@ -142,7 +251,7 @@ func Merge(selection ImportSelection, pactDir string) error {
 	}
 
 	// Write updated config
-	output, err := json.MarshalIndent(raw, "", "  ")
+	output, err := cfg.Marshal()
 	if err != nil {
 		return err
 	}
@@ -162,6 +271,13 @@ func BuildSelectionFromDiffs(selected map[string][]DiffItem, detected *DetectedC
 				selection.CLITools = append(selection.CLITools, item.Name)
 			case "custom":
 				selection.CLICustom = append(selection.CLICustom, item.Name)
+			case "package":
+				if manager, ok := item.Value.(string); ok {
+					if selection.CLIPackages == nil {
+						selection.CLIPackages = make(map[string][]string)
+					}
+					selection.CLIPackages[manager] = append(selection.CLIPackages[manager], item.Name)
+				}
 			}
 		}
 	}
@@ -176,6 +292,8 @@ func BuildSelectionFromDiffs(selected map[string][]DiffItem, detected *DetectedC
 				}
 			case "tool":
 				selection.ShellTools = append(selection.ShellTools, item.Name)
+			case "direnv":
+				selection.ShellDirenv = detected.Shell.Direnv
 			}
 		}
 	}
@@ -199,20 +317,57 @@ func BuildSelectionFromDiffs(selected map[string][]DiffItem, detected *DetectedC
 				}
 			case "lfs":
 				selection.Git.LFS = true
+			case "pager":
+				if v, ok := item.Value.(string); ok {
+					selection.Git.Pager = v
+					if v == detected.Git.Pager {
+						selection.Git.PagerTheme = detected.Git.PagerTheme
+					}
+				}
+			case "hookManager":
+				if v, ok := item.Value.(string); ok {
+					selection.Git.HookManager = v
+				}
+			case "signing":
+				selection.Git.Signing = detected.Git.Signing
+			default:
+				if v, ok := item.Value.(string); ok && item.Type == "gitconfig" {
+					if selection.Git.Config == nil {
+						selection.Git.Config = make(map[string]string)
+					}
+					selection.Git.Config[item.Name] = v
+				}
 			}
 		}
 	}
 
 	// Editor items
-	if items, ok := selected["editor"]; ok {
-		for _, item := range items {
-			if item.Type == "editor" {
+	for _, item := range selected["editor"] {
+		switch item.Type {
+		case "editor":
+			if selection.Editor == "" {
 				selection.Editor = item.Name
-				break
+			}
+		case "extension":
+			if editor, ok := item.Value.(string); ok {
+				if selection.Extensions == nil {
+					selection.Extensions = make(map[string][]string)
+				}
+				selection.Extensions[editor] = append(selection.Extensions[editor], item.Name)
 			}
 		}
 	}
 
+	// Terminal emulators
+	for _, item := range selected["terminal"] {
+		if s, ok := detected.Terminal.Emulators[item.Name]; ok && item.Type == "emulator" {
+			if selection.Terminal == nil {
+				selection.Terminal = make(map[string]TerminalSettings)
+			}
+			selection.Terminal[item.Name] = s
+		}
+	}
+
 	// LLM items
 	if items, ok := selected["llm"]; ok {
 		for _, item := range items {
@@ -229,6 +384,36 @@ func BuildSelectionFromDiffs(selected map[string][]DiffItem, detected *DetectedC
 		}
 	}
 
+	// App Store apps and startup apps
+	for _, item := range selected["apps"] {
+		switch item.Type {
+		case "mas":
+			selection.MasApps = append(selection.MasApps, config.MasApp{ID: fmt.Sprint(item.Value), Name: item.Name})
+		case "startup":
+			selection.StartupApps = append(selection.StartupApps, item.Name)
+		case "flatpak":
+			selection.FlatpakApps = append(selection.FlatpakApps, item.Name)
+		case "snap":
+			selection.Snaps = append(selection.Snaps, item.Name)
+		}
+	}
+
+	// Keybindings and snippets
+	for _, item := range selected["keybindings"] {
+		for _, kb := range detected.Keybindings {
+			if kb.Editor == item.Name {
+				selection.Keybindings = append(selection.Keybindings, kb)
+			}
+		}
+	}
+	for _, item := range selected["snippets"] {
+		for _, sn := range detected.Snippets {
+			if sn.Editor == item.Name {
+				selection.Snippets = append(selection.Snippets, sn)
+			}
+		}
+	}
+
 	// Secrets
 	if items, ok := selected["secrets"]; ok {
 		for _, item := range items {
@@ -260,7 +445,7 @@ func CreateDefaultPactJSON(detected *DetectedConfig, username string, pactDir st
 	}
 
 	// Add CLI tools
-	if len(detected.CLI.Tools) > 0 || len(detected.CLI.Custom) > 0 {
+	if len(detected.CLI.Tools) > 0 || len(detected.CLI.Custom) > 0 || len(detected.CLI.Packages) > 0 {
 		cli := make(map[string]any)
 		if len(detected.CLI.Tools) > 0 {
 			cli["tools"] = detected.CLI.Tools
@@ -268,11 +453,14 @@ func CreateDefaultPactJSON(detected *DetectedConfig, username string, pactDir st
 		if len(detected.CLI.Custom) > 0 {
 			cli["custom"] = detected.CLI.Custom
 		}
+		for manager, packages := range detected.CLI.Packages {
+			cli[manager] = packages
+		}
 		pactJSON["cli"] = cli
 	}
 
 	// Add shell config
-	if detected.Shell.Prompt != nil || len(detected.Shell.Tools) > 0 {
+	if detected.Shell.Prompt != nil || len(detected.Shell.Tools) > 0 || detected.Shell.Direnv != nil {
 		shell := make(map[string]any)
 		if detected.Shell.Prompt != nil {
 			prompt := map[string]any{"tool": detected.Shell.Prompt.Tool}
@@ -287,6 +475,9 @@ func CreateDefaultPactJSON(detected *DetectedConfig, username string, pactDir st
 		if len(detected.Shell.Tools) > 0 {
 			shell["tools"] = detected.Shell.Tools
 		}
+		if detected.Shell.Direnv != nil {
+			shell["direnv"] = map[string]any{"whitelist": direnvWhitelist(detected.Shell.Direnv)}
+		}
 		pactJSON["shell"] = shell
 	}
 
@@ -305,14 +496,46 @@ func CreateDefaultPactJSON(detected *DetectedConfig, username string, pactDir st
 		if detected.Git.LFS {
 			git["lfs"] = true
 		}
+		if detected.Git.Pager != "" {
+			git["pager"] = pagerConfig(detected.Git.Pager, detected.Git.PagerTheme)
+		}
+		if detected.Git.HookManager != "" {
+			git["hookManager"] = detected.Git.HookManager
+		}
+		if detected.Git.Signing != nil {
+			git["signing"] = signingConfig(detected.Git.Signing)
+		}
+		if len(detected.Git.Config) > 0 {
+			gitConfig := make(map[string]any, len(detected.Git.Config))
+			for key, value := range detected.Git.Config {
+				gitConfig[key] = value
+			}
+			git["config"] = gitConfig
+		}
 		pactJSON["git"] = git
 	}
 
 	// Add editor config
-	if detected.Editor.Default != "" {
-		pactJSON["editor"] = map[string]any{
-			"default": detected.Editor.Default,
+	if detected.Editor.Default != "" || len(detected.Editor.Extensions) > 0 {
+		editor := make(map[string]any)
+		if detected.Editor.Default != "" {
+			editor["default"] = detected.Editor.Default
 		}
+		for name, extensions := range detected.Editor.Extensions {
+			editor[name] = map[string]any{"extensions": extensions}
+		}
+		pactJSON["editor"] = editor
+	}
+
+	// Add terminal emulator settings
+	for emulator, s := range detected.Terminal.Emulators {
+		if s == (TerminalSettings{}) {
+			continue
+		}
+		if pactJSON["terminal"] == nil {
+			pactJSON["terminal"] = make(map[string]any)
+		}
+		pactJSON["terminal"].(map[string]any)[emulator] = terminalConfig(s)
 	}
 
 	// Add LLM config
@@ -334,6 +557,22 @@ func CreateDefaultPactJSON(detected *DetectedConfig, username string, pactDir st
 		pactJSON["llm"] = llm
 	}
 
+	// Add App Store apps, and Flatpak and Snap apps
+	apps := make(map[string]any)
+	if len(detected.Apps.Mas) > 0 {
+		mas := make(map[string]any)
+		for _, app := range detected.Apps.Mas {
+			mas[masName(app)] = masValue(app.ID)
+		}
+		apps["darwin"] = map[string]any{"mas": mas}
+	}
+	if len(detected.Apps.Flatpak) > 0 || len(detected.Apps.Snap) > 0 {
+		apps["linux"] = map[string]any{"install": appendApps(nil, detected.Apps.Flatpak, detected.Apps.Snap)}
+	}
+	if len(apps) > 0 {
+		pactJSON["apps"] = apps
+	}
+
 	// Add secrets (just the names, not values)
 	var secretNames []string
 	for _, s := range detected.Secrets {
@@ -377,6 +616,36 @@ func getStringSlice(m map[string]any, key string) []string {
 	return nil
 }
 
+// appendApps adds Flatpak IDs and snaps to an apps.<os>.install list,
+// keeping its objects and skipping apps it already has
+func appendApps(list any, flatpaks, snaps []string) []any {
+	existing, _ := list.([]any)
+	have := make(map[string]bool)
+	for _, item := range existing {
+		switch item := item.(type) {
+		case string:
+			have[strings.ToLower(item)] = true
+		case map[string]any:
+			if name, ok := item["name"].(string); ok {
+				have[strings.ToLower(name)] = true
+			}
+		}
+	}
+	for _, id := range flatpaks {
+		if !have[strings.ToLower(id)] {
+			have[strings.ToLower(id)] = true
+			existing = append(existing, id)
+		}
+	}
+	for _, name := range snaps {
+		if !have[strings.ToLower(name)] {
+			have[strings.ToLower(name)] = true
+			existing = append(existing, map[string]any{"name": name, "snap": name})
+		}
+	}
+	return existing
+}
+
 func mergeStringSlices(existing, new []string) []any {
 	seen := make(map[string]bool)
 	var result []any
@@ -422,3 +691,91 @@ func GetExistingConfig() (*config.PactConfig, error) {
 	}
 	return config.Load()
 }
+
+// direnvWhitelist is the shell.direnv.whitelist value for a detected whitelist
+func direnvWhitelist(info *DirenvInfo) map[string]any {
+	whitelist := make(map[string]any)
+	if len(info.Prefix) > 0 {
+		whitelist["prefix"] = info.Prefix
+	}
+	if len(info.Exact) > 0 {
+		whitelist["exact"] = info.Exact
+	}
+	return whitelist
+}
+
+// pagerConfig returns the git.pager value for a detected pager: the tool
+// name, or an object when it has a theme
+func pagerConfig(tool, theme string) any {
+	if theme == "" {
+		return tool
+	}
+	return map[string]any{"tool": tool, "theme": theme}
+}
+
+// signingConfig returns the git.signing value for a detected setup. An SSH
+// key file is written as its private key's path, under ~ where it can be.
+func signingConfig(s *SigningDetected) map[string]any {
+	signing := map[string]any{"format": s.Format}
+	key := s.Key
+	if s.Format == "ssh" && !strings.HasPrefix(key, "ssh-") && !strings.HasPrefix(key, "key::") {
+		key = strings.TrimSuffix(key, ".pub")
+		if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(key, home+string(os.PathSeparator)) {
+			key = "~/" + filepath.ToSlash(key[len(home)+1:])
+		}
+	}
+	if key != "" {
+		signing["key"] = key
+	}
+	if s.Tags {
+		signing["tags"] = true
+	}
+	return signing
+}
+
+// putGitConfig sets a detected git config key in git.config, where the key
+// already is if it's there under other case or as nested objects, else as
+// a dotted key
+func putGitConfig(node map[string]any, key, value string) {
+	if parent, name, ok := findGitConfig(node, "", key); ok {
+		parent[name] = value
+		return
+	}
+	node[key] = value
+}
+
+func findGitConfig(node map[string]any, prefix, key string) (map[string]any, string, bool) {
+	for name, val := range node {
+		if child, ok := val.(map[string]any); ok {
+			if parent, found, ok := findGitConfig(child, prefix+name+".", key); ok {
+				return parent, found, true
+			}
+		} else if canonicalGitKey(prefix+name) == key {
+			return node, name, true
+		}
+	}
+	return nil, "", false
+}
+
+// masValue writes an App Store ID as a number, the way the mas docs show it
+func masValue(id string) any {
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil {
+		return n
+	}
+	return id
+}
+
+// terminalConfig is the terminal.<emulator> entry for an emulator's settings
+func terminalConfig(s TerminalSettings) map[string]any {
+	entry := make(map[string]any)
+	if s.Font != "" {
+		entry["font"] = s.Font
+	}
+	if s.FontSize > 0 {
+		entry["fontSize"] = s.FontSize
+	}
+	if s.Theme != "" {
+		entry["theme"] = s.Theme
+	}
+	return entry
+}
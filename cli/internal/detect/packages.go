@@ -0,0 +1,68 @@
+package detect
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// DetectPackages lists the global packages installed with each of
+// config.PackageManagers whose command is on PATH
+func DetectPackages() map[string][]string {
+	packages := make(map[string][]string)
+	for _, manager := range config.PackageManagers {
+		var names []string
+		if manager == "go" {
+			names = goPackages()
+		} else if args := config.PackageListCommand(manager); isToolInstalled(args[0]) {
+			// npm ls exits non-zero over problems with any one package
+			output, _ := exec.Command(args[0], args[1:]...).Output()
+			names = config.ParsePackageList(manager, output)
+		}
+		if len(names) > 0 {
+			packages[manager] = names
+		}
+	}
+	return packages
+}
+
+// goPackages returns the package paths of the commands in Go's bin
+// directory, read from the build info 'go install' records in each
+func goPackages() []string {
+	if !isToolInstalled("go") {
+		return nil
+	}
+	dir := config.GoBinDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var packages []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		output, err := exec.Command("go", "version", "-m", filepath.Join(dir, entry.Name())).Output()
+		if err != nil {
+			continue
+		}
+		if pkg := parseGoBuildPath(string(output)); pkg != "" {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// parseGoBuildPath returns the package path in 'go version -m' output
+func parseGoBuildPath(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "path" {
+			return fields[1]
+		}
+	}
+	return ""
+}
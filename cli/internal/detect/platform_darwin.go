@@ -48,17 +48,20 @@ func GetInstalledApps() []string {
 	return nil
 }
 
-// GetDefaultTerminal returns the default terminal app
-// This is a stub for future implementation
+// GetDefaultTerminal returns the terminal emulator pact is running in, else
+// the first installed of those pact configures, or "" for Terminal.app
 func GetDefaultTerminal() string {
-	// Could check defaults read com.apple.LaunchServices/com.apple.launchservices.secure
-	// or look for iTerm2, Alacritty, Kitty, Ghostty, etc.
-	return ""
+	if t := terminalFromEnv(); t != "" {
+		return t
+	}
+	return firstInstalledTerminal("ghostty", "iterm2", "alacritty", "kitty")
 }
 
-// GetTerminalFont returns the font configured in the default terminal
-// This is a stub for future implementation
-func GetTerminalFont() string {
-	// Would need to read Terminal.app or iTerm2 preferences
-	return ""
+// GetStartupApps returns the user's Login Items
+func GetStartupApps() []string {
+	output, err := exec.Command("osascript", "-e", `tell application "System Events" to get the name of every login item`).Output()
+	if err != nil {
+		return nil
+	}
+	return parseLoginItems(string(output))
 }
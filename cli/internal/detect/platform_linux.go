@@ -3,7 +3,9 @@
 package detect
 
 import (
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 )
 
@@ -68,14 +70,49 @@ func GetInstalledApps() []string {
 	return nil
 }
 
-// GetDefaultTerminal returns the default terminal emulator
-// This is a stub for future implementation
+// GetDefaultTerminal returns the terminal emulator pact is running in, else
+// the one $TERMINAL or the x-terminal-emulator alternative names, else the
+// first installed of those pact configures
 func GetDefaultTerminal() string {
-	return ""
+	if t := terminalFromEnv(); t != "" {
+		return t
+	}
+	candidates := []string{os.Getenv("TERMINAL")}
+	if target, err := filepath.EvalSymlinks("/etc/alternatives/x-terminal-emulator"); err == nil {
+		candidates = append(candidates, target)
+	}
+	for _, c := range candidates {
+		switch name := filepath.Base(c); name {
+		case "ghostty", "alacritty", "kitty":
+			return name
+		}
+	}
+	return firstInstalledTerminal("ghostty", "alacritty", "kitty")
 }
 
-// GetTerminalFont returns the configured terminal font
-// This is a stub for future implementation
-func GetTerminalFont() string {
-	return ""
+// GetStartupApps returns the autostart entries in ~/.config/autostart that
+// aren't hidden or disabled
+func GetStartupApps() []string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, _ := os.UserHomeDir()
+		dir = filepath.Join(home, ".config")
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "autostart"))
+	if err != nil {
+		return []string{}
+	}
+	apps := []string{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".desktop")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "autostart", entry.Name()))
+		if err != nil || !autostartEnabled(string(data)) {
+			continue
+		}
+		apps = append(apps, name)
+	}
+	return apps
 }
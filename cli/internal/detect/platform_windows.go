@@ -79,16 +79,28 @@ func GetInstalledApps() []string {
 	return nil
 }
 
-// GetDefaultTerminal returns the default terminal
-// This is a stub for future implementation
+// windowsTerminalDelegation is the DelegationTerminal value that makes
+// Windows Terminal the default terminal
+const windowsTerminalDelegation = "{E12CFF52-A866-4C77-9A90-F570A7AA2C6B}"
+
+// GetDefaultTerminal returns the terminal emulator pact is running in, else
+// Windows Terminal if it's set as the default terminal or installed
 func GetDefaultTerminal() string {
-	// Could be Windows Terminal, PowerShell, cmd, etc.
-	return ""
+	if t := terminalFromEnv(); t != "" {
+		return t
+	}
+	output, err := exec.Command("reg", "query", `HKCU\Console\%%Startup`, "/v", "DelegationTerminal").Output()
+	if err == nil && strings.Contains(strings.ToUpper(string(output)), windowsTerminalDelegation) {
+		return "windows-terminal"
+	}
+	return firstInstalledTerminal("windows-terminal", "alacritty")
 }
 
-// GetTerminalFont returns the configured terminal font
-// This is a stub for future implementation
-func GetTerminalFont() string {
-	// Would need to read Windows Terminal settings.json
-	return ""
+// GetStartupApps returns the values of the current user's Run registry key
+func GetStartupApps() []string {
+	output, err := exec.Command("reg", "query", `HKCU\Software\Microsoft\Windows\CurrentVersion\Run`).Output()
+	if err != nil {
+		return nil
+	}
+	return parseRunKey(string(output))
 }
@@ -95,5 +95,21 @@ func DetectSecrets(existingSecrets []string) []SecretDetected {
 		}
 	}
 
+	// Finally, include the rest of pact.json's secrets (including ones that
+	// aren't in the environment) so callers can classify every one of them
+	for _, name := range existingSecrets {
+		if seen[name] {
+			continue
+		}
+		_, inEnv := os.LookupEnv(name)
+		detected = append(detected, SecretDetected{
+			Name:       name,
+			InEnv:      inEnv,
+			InKeychain: false,
+			InPactJSON: true,
+		})
+		seen[name] = true
+	}
+
 	return detected
 }
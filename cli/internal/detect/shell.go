@@ -6,6 +6,8 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
 )
 
 // DetectShell detects shell configuration
@@ -27,6 +29,8 @@ func DetectShell() ShellDetected {
 		}
 	}
 
+	result.Direnv = detectDirenv()
+
 	return result
 }
 
@@ -48,6 +52,10 @@ func detectShellType() string {
 		return "bash"
 	} else if strings.Contains(shell, "fish") {
 		return "fish"
+	} else if base := filepath.Base(shell); base == "nu" || base == "nushell" {
+		return "nu"
+	} else if strings.Contains(shell, "xonsh") {
+		return "xonsh"
 	}
 
 	return filepath.Base(shell)
@@ -84,13 +92,11 @@ func parseOhMyPoshConfig() (theme, source string) {
 		shellConfigs = []string{
 			filepath.Join(home, ".zshrc"),
 			filepath.Join(home, ".bashrc"),
-			filepath.Join(home, ".config/fish/config.fish"),
+			filepath.Join(config.FishConfigDir(), "config.fish"),
+			filepath.Join(config.NushellConfigDir(), "config.nu"),
 		}
 	case "windows":
-		shellConfigs = []string{
-			filepath.Join(home, "Documents/PowerShell/Microsoft.PowerShell_profile.ps1"),
-			filepath.Join(home, "Documents/WindowsPowerShell/Microsoft.PowerShell_profile.ps1"),
-		}
+		shellConfigs = config.GetAllPowerShellProfiles()
 	}
 
 	// Regex to find oh-my-posh init with config
@@ -0,0 +1,251 @@
+package detect
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// terminalBinaries are the commands that show an emulator is installed, and
+// terminalApps its macOS app bundle
+var (
+	terminalBinaries = map[string]string{"ghostty": "ghostty", "alacritty": "alacritty", "kitty": "kitty", "windows-terminal": "wt"}
+	terminalApps     = map[string]string{"ghostty": "Ghostty.app", "alacritty": "Alacritty.app", "kitty": "kitty.app", "iterm2": "iTerm.app"}
+)
+
+// DetectTerminal finds the installed terminal emulators and the font and
+// theme each is set to
+func DetectTerminal() TerminalDetected {
+	result := TerminalDetected{Default: GetDefaultTerminal()}
+
+	for _, emulator := range config.TerminalEmulators {
+		if !terminalInstalled(emulator) {
+			continue
+		}
+		if result.Emulators == nil {
+			result.Emulators = make(map[string]TerminalSettings)
+		}
+		result.Emulators[emulator] = terminalSettings(emulator)
+	}
+
+	if s, ok := result.Emulators[result.Default]; ok {
+		result.Font = s.Font
+		result.FontSize = int(s.FontSize)
+	}
+	return result
+}
+
+// GetTerminalFont returns the font configured in the default terminal
+func GetTerminalFont() string {
+	return terminalSettings(GetDefaultTerminal()).Font
+}
+
+// terminalFromEnv names the emulator pact is running in, from the variables
+// each one sets, or "" for any other
+func terminalFromEnv() string {
+	switch os.Getenv("TERM_PROGRAM") {
+	case "ghostty":
+		return "ghostty"
+	case "iTerm.app":
+		return "iterm2"
+	}
+	switch {
+	case os.Getenv("GHOSTTY_RESOURCES_DIR") != "":
+		return "ghostty"
+	case os.Getenv("KITTY_WINDOW_ID") != "":
+		return "kitty"
+	case os.Getenv("ALACRITTY_WINDOW_ID") != "", os.Getenv("ALACRITTY_SOCKET") != "":
+		return "alacritty"
+	case os.Getenv("WT_SESSION") != "":
+		return "windows-terminal"
+	}
+	return ""
+}
+
+// firstInstalledTerminal returns the first of the emulators that's installed
+func firstInstalledTerminal(emulators ...string) string {
+	for _, emulator := range emulators {
+		if terminalInstalled(emulator) {
+			return emulator
+		}
+	}
+	return ""
+}
+
+// terminalInstalled reports whether emulator is installed, or at least has
+// a config file here
+func terminalInstalled(emulator string) bool {
+	home, _ := os.UserHomeDir()
+	if path := terminalSettingsPath(emulator, home); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return true
+		}
+	}
+	if binary := terminalBinaries[emulator]; binary != "" && isToolInstalled(binary) {
+		return true
+	}
+	if app := terminalApps[emulator]; app != "" && runtime.GOOS == "darwin" {
+		for _, dir := range []string{"/Applications", filepath.Join(home, "Applications")} {
+			if _, err := os.Stat(filepath.Join(dir, app)); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// terminalSettingsPath is the file an emulator's font and theme are read
+// from
+func terminalSettingsPath(emulator, home string) string {
+	if emulator == "iterm2" {
+		if runtime.GOOS != "darwin" {
+			return ""
+		}
+		return config.ITermProfilePath(home)
+	}
+	return config.TerminalConfigPath(emulator, runtime.GOOS, home)
+}
+
+// terminalSettings reads the font and theme emulator is set to
+func terminalSettings(emulator string) TerminalSettings {
+	if emulator == "iterm2" {
+		return itermSettings()
+	}
+	home, _ := os.UserHomeDir()
+	path := terminalSettingsPath(emulator, home)
+	if path == "" {
+		return TerminalSettings{}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TerminalSettings{}
+	}
+	return parseTerminalSettings(emulator, string(data))
+}
+
+// parseTerminalSettings reads the font and theme from an emulator's config
+func parseTerminalSettings(emulator, data string) TerminalSettings {
+	var s TerminalSettings
+	switch emulator {
+	case "ghostty":
+		for _, line := range strings.Split(data, "\n") {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch strings.TrimSpace(key) {
+			case "font-family":
+				if s.Font == "" {
+					s.Font = value
+				}
+			case "font-size":
+				s.FontSize, _ = strconv.ParseFloat(value, 64)
+			case "theme":
+				s.Theme = value
+			}
+		}
+	case "kitty":
+		lines := strings.Split(data, "\n")
+		for i, line := range lines {
+			key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+			value = strings.TrimSpace(value)
+			switch key {
+			case "font_family":
+				s.Font = value
+			case "font_size":
+				s.FontSize, _ = strconv.ParseFloat(value, 64)
+			case "#":
+				// 'kitten themes' names the theme on the line after its marker
+				if value == "BEGIN_KITTY_THEME" && i+1 < len(lines) {
+					s.Theme = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i+1]), "#"))
+				}
+			}
+		}
+	case "alacritty":
+		table := ""
+		for _, line := range strings.Split(data, "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "[") {
+				table = strings.Trim(line, "[] ")
+				continue
+			}
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+			switch {
+			case table == "font.normal" && key == "family":
+				s.Font = strings.Trim(value, `"'`)
+			case table == "font" && key == "normal":
+				if m := tomlFamily.FindStringSubmatch(value); m != nil {
+					s.Font = m[1]
+				}
+			case table == "font" && key == "size":
+				s.FontSize, _ = strconv.ParseFloat(value, 64)
+			case (table == "general" || table == "") && key == "import":
+				if m := tomlString.FindStringSubmatch(value); m != nil {
+					s.Theme = m[1]
+				}
+			}
+		}
+	case "windows-terminal":
+		var settings struct {
+			Profiles struct {
+				Defaults struct {
+					Font struct {
+						Face string  `json:"face"`
+						Size float64 `json:"size"`
+					} `json:"font"`
+					ColorScheme string `json:"colorScheme"`
+				} `json:"defaults"`
+			} `json:"profiles"`
+		}
+		if json.Unmarshal([]byte(data), &settings) == nil {
+			d := settings.Profiles.Defaults
+			// The face can be followed by fallbacks
+			face, _, _ := strings.Cut(d.Font.Face, ",")
+			s = TerminalSettings{Font: strings.TrimSpace(face), FontSize: d.Font.Size, Theme: d.ColorScheme}
+		}
+	}
+	return s
+}
+
+var (
+	tomlFamily = regexp.MustCompile(`family\s*=\s*["']([^"']+)["']`)
+	tomlString = regexp.MustCompile(`["']([^"']+)["']`)
+	itermFont  = regexp.MustCompile(`"Normal Font" = "([^"]+)"`)
+)
+
+// itermSettings reads the font of iTerm2's first profile. Its color presets
+// aren't stored by name, so there's no theme to read.
+func itermSettings() TerminalSettings {
+	if runtime.GOOS != "darwin" {
+		return TerminalSettings{}
+	}
+	output, err := exec.Command("defaults", "read", "com.googlecode.iterm2", "New Bookmarks").Output()
+	if err != nil {
+		return TerminalSettings{}
+	}
+	m := itermFont.FindStringSubmatch(string(output))
+	if m == nil {
+		return TerminalSettings{}
+	}
+	// "JetBrainsMonoNF-Regular 13": the PostScript name, then the size
+	var s TerminalSettings
+	s.Font = m[1]
+	if i := strings.LastIndex(m[1], " "); i > 0 {
+		if size, err := strconv.ParseFloat(m[1][i+1:], 64); err == nil {
+			s.Font, s.FontSize = m[1][:i], size
+		}
+	}
+	return s
+}
@@ -64,6 +64,8 @@ func DetectCLITools() CLIDetected {
 		}
 	}
 
+	result.Packages = DetectPackages()
+
 	return result
 }
 
@@ -0,0 +1,64 @@
+package detect
+
+import (
+	"context"
+	"os/exec"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// versionArgs are the flags that print a tool's version when it isn't
+// --version
+var versionArgs = map[string][]string{
+	"go":      {"version"},
+	"kubectl": {"version", "--client"},
+	"helm":    {"version", "--short"},
+	"gcloud":  {"version"},
+	"ollama":  {"-v"},
+	"nvim":    {"-v"},
+	"vim":     {"--version"},
+}
+
+var versionPattern = regexp.MustCompile(`\d+\.\d+(\.\d+)?([-+][0-9A-Za-z.\-]+)?`)
+
+// ToolVersion returns the version a tool reports, or "" when it doesn't
+// answer within a few seconds
+func ToolVersion(ctx context.Context, tool string) string {
+	args, ok := versionArgs[tool]
+	if !ok {
+		args = []string{"--version"}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, tool, args...).CombinedOutput()
+	if err != nil && len(output) == 0 {
+		return ""
+	}
+	return parseVersion(string(output))
+}
+
+// parseVersion picks the first version number out of --version output
+func parseVersion(output string) string {
+	return versionPattern.FindString(output)
+}
+
+// DetectVersions collects the versions of tools concurrently
+func DetectVersions(ctx context.Context, tools []string) map[string]string {
+	versions := make(map[string]string, len(tools))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, tool := range tools {
+		wg.Add(1)
+		go func(tool string) {
+			defer wg.Done()
+			v := ToolVersion(ctx, tool)
+			mu.Lock()
+			versions[tool] = v
+			mu.Unlock()
+		}(tool)
+	}
+	wg.Wait()
+	return versions
+}
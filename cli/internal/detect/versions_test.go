@@ -0,0 +1,19 @@
+package detect
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	for output, want := range map[string]string{
+		"go version go1.22.3 darwin/arm64":                   "1.22.3",
+		"git version 2.45.1":                                 "2.45.1",
+		"ripgrep 14.1.0 (rev e50df40a19)\n\nfeatures:+pcre2": "14.1.0",
+		"v20.12.2":                             "20.12.2",
+		"jq-1.7.1":                             "1.7.1",
+		"Docker version 26.1.1, build 4cf5afa": "26.1.1",
+		"no version here":                      "",
+	} {
+		if got := parseVersion(output); got != want {
+			t.Fatalf("parseVersion(%q) = %q, want %q", output, got, want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package git
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Auth is how pact proves who it is to the pact repo's remote, set with
+// git.auth in pact.json. Clone, Pull, Push, and Fetch pass it their token;
+// SSH ignores it.
+type Auth interface {
+	// method is go-git's auth for the remote at url
+	method(url, token string) (transport.AuthMethod, error)
+	// env is the environment the system git needs
+	env(token string) []string
+}
+
+// TokenAuth sends the token over HTTPS as the password for Username, which
+// defaults to GitHub's x-access-token
+type TokenAuth struct {
+	Username string
+}
+
+// SSHAuth signs in with KeyFile, or with the SSH agent when it's empty
+type SSHAuth struct {
+	KeyFile string
+}
+
+var auth Auth = TokenAuth{}
+
+// SetAuth picks how pact authenticates to its remote. Remotes with SSH URLs
+// use SSHAuth whatever's set.
+func SetAuth(a Auth) {
+	if a == nil {
+		a = TokenAuth{}
+	}
+	auth = a
+}
+
+// DefaultRemote is the GitHub repo pact keeps a user's pact in, unless
+// 'pact init --remote' names another
+func DefaultRemote(username string) string {
+	return fmt.Sprintf("https://github.com/%s/my-pact.git", username)
+}
+
+// IsGitHub reports whether a remote URL points at github.com
+func IsGitHub(remote string) bool {
+	return remoteHost(remote) == "github.com"
+}
+
+// RepoPath returns the owner and repo name in a remote URL
+func RepoPath(remote string) (owner, name string) {
+	path := remote
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		path = u.Path
+	} else if _, rest, ok := strings.Cut(remote, ":"); ok {
+		path = rest
+	}
+	owner, name, _ = strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return owner, strings.TrimSuffix(name, ".git")
+}
+
+// IsSSH reports whether a remote is reached over SSH: ssh://host/path or
+// scp-style user@host:path
+func IsSSH(remote string) bool {
+	if u, err := url.Parse(remote); err == nil && u.Scheme != "" {
+		return u.Scheme == "ssh" || u.Scheme == "git+ssh"
+	}
+	// scp-style; a Windows path like C:\repo has no user@ and a one-letter host
+	host, _, ok := strings.Cut(remote, ":")
+	return ok && strings.Contains(host, "@") && !strings.Contains(host, "/")
+}
+
+// remoteHost returns the host a remote URL points at
+func remoteHost(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if !IsSSH(remote) {
+		return ""
+	}
+	host, _, _ := strings.Cut(remote, ":")
+	_, host, _ = strings.Cut(host, "@")
+	return host
+}
+
+// sshUser returns the user in an SSH remote, git if it names none
+func sshUser(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.User != nil {
+		return u.User.Username()
+	}
+	if user, _, ok := strings.Cut(remote, "@"); ok && !strings.Contains(user, ":") {
+		return user
+	}
+	return "git"
+}
+
+// authFor returns how to authenticate to the remote at url
+func authFor(remote string) Auth {
+	if _, ok := auth.(SSHAuth); !ok && IsSSH(remote) {
+		return SSHAuth{}
+	}
+	return auth
+}
+
+// authMethod is go-git's auth for the remote at url
+func authMethod(remote, token string) (transport.AuthMethod, error) {
+	return authFor(remote).method(remote, token)
+}
+
+// originURL returns the URL of the repo's origin remote, or "" if it has none
+func originURL(repo *git.Repository) string {
+	remote, err := repo.Remote("origin")
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+func (a TokenAuth) method(remote, token string) (transport.AuthMethod, error) {
+	return &http.BasicAuth{Username: a.username(), Password: token}, nil
+}
+
+// credentialHelper answers git's credential request with the username and
+// token from the environment, so the token never shows up in a URL or
+// process list
+const credentialHelper = `!f() { echo "username=$PACT_GIT_USER"; echo "password=$PACT_GIT_TOKEN"; }; f`
+
+func (a TokenAuth) env(token string) []string {
+	return []string{
+		"PACT_GIT_USER=" + a.username(),
+		"PACT_GIT_TOKEN=" + token,
+		// An empty helper clears the user's helpers before adding ours
+		"GIT_CONFIG_COUNT=2",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=",
+		"GIT_CONFIG_KEY_1=credential.helper",
+		"GIT_CONFIG_VALUE_1=" + credentialHelper,
+	}
+}
+
+func (a TokenAuth) username() string {
+	if a.Username == "" {
+		return "x-access-token"
+	}
+	return a.Username
+}
+
+func (a SSHAuth) method(remote, token string) (transport.AuthMethod, error) {
+	user := sshUser(remote)
+	if a.KeyFile == "" {
+		method, err := gitssh.NewSSHAgentAuth(user)
+		if err != nil {
+			return nil, fmt.Errorf("no SSH agent to sign in to %s with (set git.auth.key to use a key file): %w", remoteHost(remote), err)
+		}
+		return method, nil
+	}
+	method, err := gitssh.NewPublicKeysFromFile(user, expandHome(a.KeyFile), os.Getenv("PACT_SSH_PASSPHRASE"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH key %s: %w", a.KeyFile, err)
+	}
+	return method, nil
+}
+
+func (a SSHAuth) env(token string) []string {
+	if a.KeyFile == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("GIT_SSH_COMMAND=ssh -i %q -o IdentitiesOnly=yes", expandHome(a.KeyFile))}
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
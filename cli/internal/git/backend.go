@@ -0,0 +1,71 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Backends for talking to the remote, set with git.backend in pact.json
+const (
+	BackendAuto   = "auto"   // The system git when it's on PATH, else go-git
+	BackendSystem = "system" // Always exec the system git
+	BackendGoGit  = "go-git" // Always use the built-in go-git
+)
+
+var backend = BackendAuto
+
+// SetBackend picks how clone, pull, and push reach the remote. Local reads
+// and commits always use go-git.
+func SetBackend(name string) error {
+	switch name {
+	case "", BackendAuto:
+		backend = BackendAuto
+	case BackendSystem, BackendGoGit:
+		backend = name
+	default:
+		return fmt.Errorf("unknown git backend %q (use auto, system, or go-git)", name)
+	}
+	return nil
+}
+
+// useSystem reports whether to exec the system git. It supports LFS and
+// whatever else the user's git config sets up, which go-git doesn't.
+func useSystem() bool {
+	switch backend {
+	case BackendSystem:
+		return true
+	case BackendGoGit:
+		return false
+	}
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// systemGit runs the system git against the remote at url, authenticating
+// the way authFor says, showing progress like go-git does
+func systemGit(ctx context.Context, remote, token, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = append(cmd.Env, authFor(remote).env(token)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if Progress != nil {
+		cmd.Stdout = Progress
+		cmd.Stderr = io.MultiWriter(Progress, &stderr)
+	}
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		lines := strings.Split(strings.TrimSpace(stderr.String()), "\n")
+		return fmt.Errorf("git %s: %s", args[0], lines[len(lines)-1])
+	}
+	return nil
+}
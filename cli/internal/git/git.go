@@ -1,18 +1,28 @@
 package git
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
-// Clone clones the user's my-pact repo to the specified directory
-func Clone(token, username, targetDir string) error {
+// Progress receives clone/pull/push progress output. Set it to nil to
+// silence progress (e.g. when emitting machine-readable output).
+var Progress io.Writer = os.Stdout
+
+// Clone clones the pact repo at repoURL, e.g. DefaultRemote's, to the
+// specified directory
+func Clone(ctx context.Context, token, repoURL, targetDir string) error {
 	// Remove existing directory if it exists
 	if _, err := os.Stat(targetDir); err == nil {
 		if err := os.RemoveAll(targetDir); err != nil {
@@ -20,17 +30,26 @@ func Clone(token, username, targetDir string) error {
 		}
 	}
 
-	repoURL := fmt.Sprintf("https://github.com/%s/my-pact.git", username)
+	if useSystem() {
+		if err := systemGit(ctx, repoURL, token, "", "clone", repoURL, targetDir); err != nil {
+			os.RemoveAll(targetDir)
+			return fmt.Errorf("failed to clone repo: %w", err)
+		}
+		return nil
+	}
 
-	_, err := git.PlainClone(targetDir, false, &git.CloneOptions{
-		URL: repoURL,
-		Auth: &http.BasicAuth{
-			Username: "x-access-token",
-			Password: token,
-		},
-		Progress: os.Stdout,
+	method, err := authMethod(repoURL, token)
+	if err != nil {
+		return err
+	}
+	_, err = git.PlainCloneContext(ctx, targetDir, false, &git.CloneOptions{
+		URL:      repoURL,
+		Auth:     method,
+		Progress: Progress,
 	})
 	if err != nil {
+		// Don't leave a partial clone behind
+		os.RemoveAll(targetDir)
 		return fmt.Errorf("failed to clone repo: %w", err)
 	}
 
@@ -38,23 +57,34 @@ func Clone(token, username, targetDir string) error {
 }
 
 // Pull pulls the latest changes from the remote
-func Pull(token, pactDir string) error {
+func Pull(ctx context.Context, token, pactDir string) error {
 	repo, err := git.PlainOpen(pactDir)
 	if err != nil {
 		return fmt.Errorf("failed to open repo: %w", err)
 	}
+	if err := checkState(repo, pactDir); err != nil {
+		return err
+	}
+
+	if useSystem() {
+		if err := systemGit(ctx, originURL(repo), token, pactDir, "pull", "--ff-only"); err != nil {
+			return fmt.Errorf("failed to pull: %w", err)
+		}
+		return nil
+	}
 
 	worktree, err := repo.Worktree()
 	if err != nil {
 		return fmt.Errorf("failed to get worktree: %w", err)
 	}
 
-	err = worktree.Pull(&git.PullOptions{
-		Auth: &http.BasicAuth{
-			Username: "x-access-token",
-			Password: token,
-		},
-		Progress: os.Stdout,
+	method, err := authMethod(originURL(repo), token)
+	if err != nil {
+		return err
+	}
+	err = worktree.PullContext(ctx, &git.PullOptions{
+		Auth:     method,
+		Progress: Progress,
 	})
 
 	if err == git.NoErrAlreadyUpToDate {
@@ -68,32 +98,230 @@ func Pull(token, pactDir string) error {
 	return nil
 }
 
-// Push commits and pushes local changes to the remote
-func Push(token, pactDir, message string) error {
+// RemoteURL returns the URL of the pact repo's origin remote
+func RemoteURL(pactDir string) (string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repo: %w", err)
+	}
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return "", fmt.Errorf("failed to get origin remote: %w", err)
+	}
+	if urls := remote.Config().URLs; len(urls) > 0 {
+		return urls[0], nil
+	}
+	return "", fmt.Errorf("origin remote has no URL")
+}
+
+// Branch returns the name of the checked-out branch
+func Branch(pactDir string) (string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+	return head.Name().Short(), nil
+}
+
+// AheadBehind counts the commits the checked-out branch has that origin's copy
+// lacks, and the reverse, as of the last pull or fetch
+func AheadBehind(pactDir string) (ahead, behind int, err error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return 0, 0, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return 0, 0, err
+	}
+	remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", head.Name().Short()), true)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no origin/%s to compare with", head.Name().Short())
+	}
+
+	local, err := history(repo, head.Hash())
+	if err != nil {
+		return 0, 0, err
+	}
+	upstream, err := history(repo, remote.Hash())
+	if err != nil {
+		return 0, 0, err
+	}
+	for h := range local {
+		if !upstream[h] {
+			ahead++
+		}
+	}
+	for h := range upstream {
+		if !local[h] {
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
+
+// history returns every commit reachable from from
+func history(repo *git.Repository, from plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&git.LogOptions{From: from})
+	if err != nil {
+		return nil, err
+	}
+	commits := make(map[plumbing.Hash]bool)
+	err = iter.ForEach(func(c *object.Commit) error {
+		commits[c.Hash] = true
+		return nil
+	})
+	return commits, err
+}
+
+// Push commits and pushes local changes to the remote, leaving out changes
+// to the skip paths and committing replace's content for the files in it
+func Push(ctx context.Context, token, pactDir, message string, replace map[string][]byte, skip ...string) error {
 	repo, err := git.PlainOpen(pactDir)
 	if err != nil {
 		return fmt.Errorf("failed to open repo: %w", err)
 	}
+	if err := checkState(repo, pactDir); err != nil {
+		return err
+	}
 
-	worktree, err := repo.Worktree()
+	committed, err := commitAll(repo, message, skip, replace)
 	if err != nil {
-		return fmt.Errorf("failed to get worktree: %w", err)
+		return err
+	}
+	if !committed {
+		return fmt.Errorf("no changes to commit")
+	}
+	return push(ctx, repo, pactDir, token)
+}
+
+// Commit commits local changes without pushing, leaving out changes to the
+// skip paths and committing replace's content for the files in it, and
+// reports whether there was anything to commit
+func Commit(pactDir, message string, replace map[string][]byte, skip ...string) (bool, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return false, fmt.Errorf("failed to open repo: %w", err)
+	}
+	if err := checkState(repo, pactDir); err != nil {
+		return false, err
+	}
+	return commitAll(repo, message, skip, replace)
+}
+
+// PushBranch force-pushes the checked-out branch to branch on the remote,
+// leaving the remote's own copy of the local branch alone
+func PushBranch(ctx context.Context, token, pactDir, branch string) error {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return fmt.Errorf("failed to open repo: %w", err)
+	}
+	if err := checkState(repo, pactDir); err != nil {
+		return err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	err = push(ctx, repo, pactDir, token, config.RefSpec(fmt.Sprintf("+%s:refs/heads/%s", head.Name(), branch)))
+	if errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil
+	}
+	return err
+}
+
+// StartOver replaces the repo's history with one root commit of the files
+// in it, so nothing from before (authors, earlier versions of files) comes
+// along, and pushes that to username's my-pact as origin
+func StartOver(ctx context.Context, token, pactDir, username, message string) error {
+	branch := plumbing.NewBranchReferenceName("main")
+	if old, err := git.PlainOpen(pactDir); err == nil {
+		if head, err := old.Head(); err == nil && head.Name().IsBranch() {
+			branch = head.Name()
+		}
+	}
+	if err := os.RemoveAll(filepath.Join(pactDir, ".git")); err != nil {
+		return fmt.Errorf("failed to remove the old history: %w", err)
+	}
+
+	repo, err := git.PlainInitWithOptions(pactDir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: branch},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create repo: %w", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{
+		Name: "origin",
+		URLs: []string{DefaultRemote(username)},
+	}); err != nil {
+		return fmt.Errorf("failed to set origin: %w", err)
+	}
+
+	if _, err := commitAll(repo, message, nil, nil); err != nil {
+		return err
+	}
+	return push(ctx, repo, pactDir, token)
+}
+
+// commitAll stages and commits every change but those to the skip paths,
+// reporting whether there was anything to commit. Files in replace are
+// committed with that content instead of what's on disk.
+func commitAll(repo *git.Repository, message string, skip []string, replace map[string][]byte) (bool, error) {
+	worktree, err := openWorktree(repo)
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree: %w", err)
 	}
 
 	// Check for changes
 	status, err := worktree.Status()
 	if err != nil {
-		return fmt.Errorf("failed to get status: %w", err)
+		return false, fmt.Errorf("failed to get status: %w", err)
 	}
 
 	if status.IsClean() {
-		return fmt.Errorf("no changes to commit")
+		return false, nil
 	}
 
 	// Stage all changes
-	_, err = worktree.Add(".")
-	if err != nil {
-		return fmt.Errorf("failed to stage changes: %w", err)
+	if len(skip) == 0 {
+		if _, err := worktree.Add("."); err != nil {
+			return false, fmt.Errorf("failed to stage changes: %w", err)
+		}
+	} else {
+		skipped := make(map[string]bool)
+		for _, p := range skip {
+			skipped[filepath.ToSlash(p)] = true
+		}
+		for p, s := range status {
+			if skipped[p] || s.Worktree == git.Unmodified {
+				continue
+			}
+			if _, err := worktree.Add(p); err != nil {
+				return false, fmt.Errorf("failed to stage %s: %w", p, err)
+			}
+		}
+	}
+	if err := stageContent(repo, replace); err != nil {
+		return false, err
+	}
+
+	// Anything to commit is what the index now holds that HEAD doesn't
+	if status, err = worktree.Status(); err != nil {
+		return false, fmt.Errorf("failed to get status: %w", err)
+	}
+	staged := false
+	for _, s := range status {
+		if s.Staging != git.Unmodified && s.Staging != git.Untracked {
+			staged = true
+			break
+		}
+	}
+	if !staged {
+		return false, nil
 	}
 
 	// Get user info from git config
@@ -120,16 +348,77 @@ func Push(token, pactDir, message string) error {
 		},
 	})
 	if err != nil {
-		return fmt.Errorf("failed to commit: %w", err)
+		return false, fmt.Errorf("failed to commit: %w", err)
 	}
+	return true, nil
+}
 
-	// Push
-	err = repo.Push(&git.PushOptions{
-		Auth: &http.BasicAuth{
-			Username: "x-access-token",
-			Password: token,
-		},
-		Progress: os.Stdout,
+// stageContent points the index entries of already-staged paths at the given
+// content, leaving the files on disk as they are
+func stageContent(repo *git.Repository, content map[string][]byte) error {
+	if len(content) == 0 {
+		return nil
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return fmt.Errorf("failed to read the index: %w", err)
+	}
+	for p, data := range content {
+		entry, err := idx.Entry(filepath.ToSlash(p))
+		if err != nil {
+			continue // Not staged, so not being committed
+		}
+		obj := repo.Storer.NewEncodedObject()
+		obj.SetType(plumbing.BlobObject)
+		w, err := obj.Writer()
+		if err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+		if _, err := w.Write(data); err != nil {
+			w.Close()
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+		hash, err := repo.Storer.SetEncodedObject(obj)
+		if err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+		entry.Hash = hash
+		entry.Size = uint32(len(data))
+	}
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		return fmt.Errorf("failed to write the index: %w", err)
+	}
+	return nil
+}
+
+// push pushes refSpecs to origin; by default the system git pushes the
+// checked-out branch and go-git every local branch
+func push(ctx context.Context, repo *git.Repository, pactDir, token string, refSpecs ...config.RefSpec) error {
+	if useSystem() {
+		args := []string{"push", "origin"}
+		for _, rs := range refSpecs {
+			args = append(args, rs.String())
+		}
+		if len(refSpecs) == 0 {
+			args = append(args, "HEAD")
+		}
+		if err := systemGit(ctx, originURL(repo), token, pactDir, args...); err != nil {
+			return fmt.Errorf("failed to push: %w", err)
+		}
+		return nil
+	}
+
+	method, err := authMethod(originURL(repo), token)
+	if err != nil {
+		return err
+	}
+	err = repo.PushContext(ctx, &git.PushOptions{
+		Auth:     method,
+		RefSpecs: refSpecs,
+		Progress: Progress,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to push: %w", err)
@@ -138,6 +427,26 @@ func Push(token, pactDir, message string) error {
 	return nil
 }
 
+// Stage adds paths, relative to the repo, to the index
+func Stage(pactDir string, paths ...string) error {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return fmt.Errorf("failed to open repo: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	for _, p := range paths {
+		if _, err := worktree.Add(filepath.ToSlash(p)); err != nil {
+			return fmt.Errorf("failed to stage %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
 // HasChanges checks if there are uncommitted changes
 func HasChanges(pactDir string) (bool, error) {
 	repo, err := git.PlainOpen(pactDir)
@@ -145,7 +454,7 @@ func HasChanges(pactDir string) (bool, error) {
 		return false, err
 	}
 
-	worktree, err := repo.Worktree()
+	worktree, err := openWorktree(repo)
 	if err != nil {
 		return false, err
 	}
@@ -158,6 +467,100 @@ func HasChanges(pactDir string) (bool, error) {
 	return !status.IsClean(), nil
 }
 
+// ChangedFiles returns the paths, relative to the repo, with uncommitted
+// changes
+func ChangedFiles(pactDir string) ([]string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := openWorktree(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for p := range status {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Kinds of uncommitted change to a file
+const (
+	ChangeAdded    = "added"
+	ChangeModified = "modified"
+	ChangeDeleted  = "deleted"
+)
+
+// FileChange is a file with uncommitted changes, by path relative to the repo
+type FileChange struct {
+	Path string
+	Kind string
+}
+
+// Changes returns the files with uncommitted changes and how each changed
+func Changes(pactDir string) ([]FileChange, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, err
+	}
+	worktree, err := openWorktree(repo)
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FileChange
+	for p, s := range status {
+		kind := ChangeModified
+		switch {
+		case s.Worktree == git.Deleted || (s.Worktree == git.Unmodified && s.Staging == git.Deleted):
+			kind = ChangeDeleted
+		case s.Worktree == git.Untracked || s.Staging == git.Added:
+			kind = ChangeAdded
+		}
+		changes = append(changes, FileChange{Path: p, Kind: kind})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// HeadFile returns a file's committed content, by path relative to the repo
+func HeadFile(pactDir, path string) ([]byte, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	file, err := commit.File(filepath.ToSlash(path))
+	if err != nil {
+		return nil, err
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return []byte(content), nil
+}
+
 // GetStatus returns the git status of the pact repo
 func GetStatus(pactDir string) (string, error) {
 	repo, err := git.PlainOpen(pactDir)
@@ -165,7 +568,7 @@ func GetStatus(pactDir string) (string, error) {
 		return "", err
 	}
 
-	worktree, err := repo.Worktree()
+	worktree, err := openWorktree(repo)
 	if err != nil {
 		return "", err
 	}
@@ -177,3 +580,53 @@ func GetStatus(pactDir string) (string, error) {
 
 	return status.String(), nil
 }
+
+// FileVersion is a file's contents as of a commit
+type FileVersion struct {
+	Hash    string
+	When    time.Time
+	Message string
+	Content []byte
+}
+
+// FileHistory returns every committed version of a file in the pact repo,
+// oldest first
+func FileHistory(pactDir, name string) ([]FileVersion, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, err
+	}
+
+	iter, err := repo.Log(&git.LogOptions{FileName: &name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+	defer iter.Close()
+
+	var versions []FileVersion
+	err = iter.ForEach(func(c *object.Commit) error {
+		version := FileVersion{
+			Hash:    c.Hash.String(),
+			When:    c.Author.When,
+			Message: c.Message,
+		}
+		// The file is missing in the commit that deleted it
+		if file, err := c.File(name); err == nil {
+			contents, err := file.Contents()
+			if err != nil {
+				return err
+			}
+			version.Content = []byte(contents)
+		}
+		versions = append(versions, version)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	for i, j := 0, len(versions)-1; i < j; i, j = i+1, j-1 {
+		versions[i], versions[j] = versions[j], versions[i]
+	}
+	return versions, nil
+}
@@ -0,0 +1,43 @@
+package git
+
+import (
+	"bufio"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreFile lists paths, in .gitignore syntax, that push and autocommit
+// never commit, for machine-local junk in the pact repo
+const IgnoreFile = ".pactignore"
+
+// defaultIgnores are never committed, .pactignore or not
+var defaultIgnores = []string{".DS_Store", "Thumbs.db", "desktop.ini", "*.swp"}
+
+// openWorktree opens the repo's worktree with .pactignore applied, so status
+// doesn't list ignored files and committing everything leaves them out
+func openWorktree(repo *git.Repository) (*git.Worktree, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range defaultIgnores {
+		w.Excludes = append(w.Excludes, gitignore.ParsePattern(p, nil))
+	}
+
+	f, err := w.Filesystem.Open(IgnoreFile)
+	if err != nil {
+		return w, nil
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		w.Excludes = append(w.Excludes, gitignore.ParsePattern(line, nil))
+	}
+	return w, nil
+}
@@ -0,0 +1,217 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Incoming is what the remote changed since this machine last pulled
+type Incoming struct {
+	Ahead  int // Commits here the remote doesn't have
+	Behind int // Commits on the remote to pull
+	Files  []IncomingFile
+
+	remote plumbing.Hash
+}
+
+// IncomingFile is a file the remote changed. Base is the file as of the last
+// common commit, Local the file on disk here, and Remote the remote's; each
+// is nil where the file doesn't exist.
+type IncomingFile struct {
+	Path   string
+	Base   []byte
+	Local  []byte
+	Remote []byte
+
+	mode os.FileMode
+}
+
+// Fetch downloads the remote's commits without changing the checkout
+func Fetch(ctx context.Context, token, pactDir string) error {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return fmt.Errorf("failed to open repo: %w", err)
+	}
+
+	if useSystem() {
+		if err := systemGit(ctx, originURL(repo), token, pactDir, "fetch", "origin"); err != nil {
+			return fmt.Errorf("failed to fetch: %w", err)
+		}
+		return nil
+	}
+
+	method, err := authMethod(originURL(repo), token)
+	if err != nil {
+		return err
+	}
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		Auth:     method,
+		Progress: Progress,
+	})
+	if err != nil && err != git.NoErrAlreadyUpToDate {
+		return fmt.Errorf("failed to fetch: %w", err)
+	}
+	return nil
+}
+
+// GetIncoming compares the checked-out branch with origin's, as of the last
+// fetch, and collects the files the remote changed since they split
+func GetIncoming(pactDir string) (*Incoming, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repo: %w", err)
+	}
+	if err := checkState(repo, pactDir); err != nil {
+		return nil, err
+	}
+	ahead, behind, err := AheadBehind(pactDir)
+	if err != nil {
+		return nil, err
+	}
+	in := &Incoming{Ahead: ahead, Behind: behind}
+	if behind == 0 {
+		return in, nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", head.Name().Short()), true)
+	if err != nil {
+		return nil, err
+	}
+	in.remote = remoteRef.Hash()
+
+	localCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	remoteCommit, err := repo.CommitObject(in.remote)
+	if err != nil {
+		return nil, err
+	}
+	remoteTree, err := remoteCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	baseTree := &object.Tree{}
+	if bases, err := localCommit.MergeBase(remoteCommit); err == nil && len(bases) > 0 {
+		if baseTree, err = bases[0].Tree(); err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := object.DiffTree(baseTree, remoteTree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff with the remote: %w", err)
+	}
+	for _, change := range changes {
+		path := change.To.Name
+		if path == "" {
+			path = change.From.Name
+		}
+		f := IncomingFile{Path: path, mode: 0644}
+		if f.Base, err = treeFile(baseTree, path); err != nil {
+			return nil, err
+		}
+		if f.Remote, err = treeFile(remoteTree, path); err != nil {
+			return nil, err
+		}
+		if file, err := remoteTree.File(path); err == nil {
+			if mode, err := file.Mode.ToOSFileMode(); err == nil {
+				f.mode = mode.Perm()
+			}
+		}
+		if data, err := os.ReadFile(filepath.Join(pactDir, filepath.FromSlash(path))); err == nil {
+			f.Local = data
+		}
+		in.Files = append(in.Files, f)
+	}
+	return in, nil
+}
+
+// treeFile reads path from tree, or returns nil if it isn't there
+func treeFile(tree *object.Tree, path string) ([]byte, error) {
+	file, err := tree.File(path)
+	if err == object.ErrFileNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	r, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// FinishPull writes the merged files (nil removes one) and moves the branch
+// to the remote's commit. Changes here the remote doesn't have stay in the
+// worktree, uncommitted, for the next push; commits only here are first
+// saved to a pact-before-pull-<sha> branch. Returns that branch's name, if
+// one was made.
+func FinishPull(pactDir string, in *Incoming, merged map[string][]byte) (string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repo: %w", err)
+	}
+	if err := checkState(repo, pactDir); err != nil {
+		return "", err
+	}
+	head, err := repo.Head()
+	if err != nil {
+		return "", err
+	}
+
+	var saved string
+	if in.Ahead > 0 {
+		name := plumbing.NewBranchReferenceName("pact-before-pull-" + head.Hash().String()[:7])
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(name, head.Hash())); err != nil {
+			return "", err
+		}
+		saved = name.Short()
+	}
+
+	for _, f := range in.Files {
+		data, ok := merged[f.Path]
+		if !ok {
+			continue
+		}
+		path := filepath.Join(pactDir, filepath.FromSlash(f.Path))
+		if data == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return saved, err
+			}
+			continue
+		}
+		mode := f.mode
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return saved, err
+		}
+		if err := os.WriteFile(path, data, mode); err != nil {
+			return saved, err
+		}
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return saved, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: in.remote, Mode: git.MixedReset}); err != nil {
+		return saved, fmt.Errorf("failed to move to the remote's commit: %w", err)
+	}
+	return saved, nil
+}
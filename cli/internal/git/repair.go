@@ -0,0 +1,241 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Repo states that stop a pull or push
+const (
+	StateRebase   = "rebase"
+	StateMerge    = "merge"
+	StateDetached = "detached"
+)
+
+// State reports whether the repo is mid-rebase, mid-merge, or on a detached
+// HEAD, or "" when it's on a branch and ready to pull and push
+func State(pactDir string) (string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to open repo: %w", err)
+	}
+	return repoState(repo, pactDir), nil
+}
+
+func repoState(repo *git.Repository, pactDir string) string {
+	gitDir := filepath.Join(pactDir, ".git")
+	if rebaseDir(gitDir) != "" {
+		return StateRebase
+	}
+	if _, err := os.Stat(filepath.Join(gitDir, "MERGE_HEAD")); err == nil {
+		return StateMerge
+	}
+	if head, err := repo.Head(); err == nil && !head.Name().IsBranch() {
+		return StateDetached
+	}
+	return ""
+}
+
+// checkState explains a state that go-git can't pull or push from
+func checkState(repo *git.Repository, pactDir string) error {
+	switch repoState(repo, pactDir) {
+	case StateRebase:
+		return fmt.Errorf("the pact repo is in the middle of a rebase; run 'pact repair-repo' to abort it")
+	case StateMerge:
+		return fmt.Errorf("the pact repo is in the middle of a merge; run 'pact repair-repo' to abort it")
+	case StateDetached:
+		head, _ := repo.Head()
+		return fmt.Errorf("the pact repo isn't on a branch (HEAD is detached at %s); run 'pact repair-repo' to return to %s",
+			head.Hash().String()[:7], defaultBranch(repo).Short())
+	}
+	return nil
+}
+
+// rebaseDir returns the directory git keeps an unfinished rebase in
+func rebaseDir(gitDir string) string {
+	for _, name := range []string{"rebase-merge", "rebase-apply"} {
+		dir := filepath.Join(gitDir, name)
+		if _, err := os.Stat(filepath.Join(dir, "head-name")); err == nil {
+			return dir
+		}
+	}
+	return ""
+}
+
+// defaultBranch is the branch origin's HEAD points at, falling back to a
+// local main or master
+func defaultBranch(repo *git.Repository) plumbing.ReferenceName {
+	if ref, err := repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		return plumbing.NewBranchReferenceName(strings.TrimPrefix(ref.Target().String(), "refs/remotes/origin/"))
+	}
+	for _, name := range []string{"main", "master"} {
+		branch := plumbing.NewBranchReferenceName(name)
+		if _, err := repo.Reference(branch, false); err == nil {
+			return branch
+		}
+	}
+	return plumbing.NewBranchReferenceName("main")
+}
+
+// Repair aborts an unfinished rebase or merge and moves a detached HEAD back
+// to the default branch, returning what it did. Commits made while detached
+// are kept on a branch of their own.
+func Repair(pactDir string) ([]string, error) {
+	repo, err := git.PlainOpen(pactDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repo: %w", err)
+	}
+	gitDir := filepath.Join(pactDir, ".git")
+
+	var done []string
+	switch repoState(repo, pactDir) {
+	case StateRebase:
+		if err := abortRebase(repo, rebaseDir(gitDir)); err != nil {
+			return done, err
+		}
+		done = append(done, "aborted the unfinished rebase")
+	case StateMerge:
+		if err := abortMerge(repo, gitDir); err != nil {
+			return done, err
+		}
+		done = append(done, "aborted the unfinished merge")
+	}
+
+	if repoState(repo, pactDir) == StateDetached {
+		steps, err := leaveDetached(repo)
+		done = append(done, steps...)
+		if err != nil {
+			return done, err
+		}
+	}
+	return done, nil
+}
+
+// abortRebase puts the rebased branch back where it was, like
+// 'git rebase --abort'
+func abortRebase(repo *git.Repository, dir string) error {
+	headName, err := os.ReadFile(filepath.Join(dir, "head-name"))
+	if err != nil {
+		return fmt.Errorf("failed to read rebase state: %w", err)
+	}
+	origHead, err := os.ReadFile(filepath.Join(dir, "orig-head"))
+	if err != nil {
+		return fmt.Errorf("failed to read rebase state: %w", err)
+	}
+	orig := plumbing.NewHash(strings.TrimSpace(string(origHead)))
+
+	// head-name is "detached HEAD" when the rebase started off a branch
+	if branch := plumbing.ReferenceName(strings.TrimSpace(string(headName))); branch.IsBranch() {
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(branch, orig)); err != nil {
+			return err
+		}
+		if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branch)); err != nil {
+			return err
+		}
+	} else if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, orig)); err != nil {
+		return err
+	}
+
+	if err := resetHard(repo, orig); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// abortMerge drops an unfinished merge's changes, like 'git merge --abort'
+func abortMerge(repo *git.Repository, gitDir string) error {
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+	if err := resetHard(repo, head.Hash()); err != nil {
+		return err
+	}
+	for _, name := range []string{"MERGE_HEAD", "MERGE_MSG", "MERGE_MODE"} {
+		os.Remove(filepath.Join(gitDir, name))
+	}
+	return nil
+}
+
+func resetHard(repo *git.Repository, commit plumbing.Hash) error {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("failed to get worktree: %w", err)
+	}
+	if err := worktree.Reset(&git.ResetOptions{Commit: commit, Mode: git.HardReset}); err != nil {
+		return fmt.Errorf("failed to reset: %w", err)
+	}
+	return nil
+}
+
+// leaveDetached checks out the default branch, first saving commits that
+// exist only on the detached HEAD to a pact-detached-<sha> branch
+func leaveDetached(repo *git.Repository) ([]string, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get worktree: %w", err)
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status: %w", err)
+	}
+	if !status.IsClean() {
+		return nil, fmt.Errorf("HEAD is detached with uncommitted changes; commit or discard them, then run 'pact repair-repo' again")
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	branch := defaultBranch(repo)
+
+	var done []string
+	if !onBranch(repo, head.Hash(), branch) {
+		saved := plumbing.NewBranchReferenceName("pact-detached-" + head.Hash().String()[:7])
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(saved, head.Hash())); err != nil {
+			return nil, err
+		}
+		done = append(done, fmt.Sprintf("saved commits from the detached HEAD to branch %s", saved.Short()))
+	}
+
+	opts := &git.CheckoutOptions{Branch: branch}
+	if _, err := repo.Reference(branch, false); err != nil {
+		// No local branch yet; start it from origin's
+		remote, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", branch.Short()), true)
+		if err != nil {
+			return done, fmt.Errorf("no %s branch to return to", branch.Short())
+		}
+		opts.Hash = remote.Hash()
+		opts.Create = true
+	}
+	if err := worktree.Checkout(opts); err != nil {
+		return done, fmt.Errorf("failed to check out %s: %w", branch.Short(), err)
+	}
+	return append(done, "checked out "+branch.Short()), nil
+}
+
+// onBranch reports whether commit is already part of branch's history
+func onBranch(repo *git.Repository, commit plumbing.Hash, branch plumbing.ReferenceName) bool {
+	ref, err := repo.Reference(branch, true)
+	if err != nil {
+		return false
+	}
+	if ref.Hash() == commit {
+		return true
+	}
+	detached, err := repo.CommitObject(commit)
+	if err != nil {
+		return false
+	}
+	tip, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		return false
+	}
+	ok, err := detached.IsAncestor(tip)
+	return err == nil && ok
+}
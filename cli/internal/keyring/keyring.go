@@ -1,27 +1,82 @@
 package keyring
 
 import (
+	"runtime"
+	"sort"
+	"strings"
+
 	"github.com/zalando/go-keyring"
 )
 
 const (
 	serviceName = "pact"
-	tokenKey    = "github_token"
+	identityKey = "age_identity"
+	accountsKey = "github_accounts"
+	defaultKey  = "github_default"
+
+	// legacyTokenKey held the one GitHub token before tokens were kept per
+	// account, and legacyTokenKey:<login> an account's token after that
+	legacyTokenKey = "github_token"
 )
 
-// SetToken stores the GitHub token in the OS keychain
-func SetToken(token string) error {
-	return keyring.Set(serviceName, tokenKey, token)
+// account, when set, is the GitHub account whose token GetToken returns
+// instead of the default one
+var account string
+
+// Backend names the OS credential store pact uses
+func Backend() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "macOS Keychain"
+	case "windows":
+		return "Windows Credential Manager"
+	default:
+		return "Secret Service (D-Bus)"
+	}
 }
 
-// GetToken retrieves the GitHub token from the OS keychain
+// GetToken retrieves the GitHub token from the OS keychain: the account
+// picked with UseAccount's, else the default one
 func GetToken() (string, error) {
-	return keyring.Get(serviceName, tokenKey)
+	if account != "" {
+		return GetAccountToken(account)
+	}
+	return DefaultToken()
+}
+
+// DefaultToken retrieves the default account's token, ignoring UseAccount.
+// Until Migrate has run, that may still be the legacy token.
+func DefaultToken() (string, error) {
+	if login := DefaultAccount(); login != "" {
+		return GetAccountToken(login)
+	}
+	return keyring.Get(serviceName, legacyTokenKey)
+}
+
+// SetDefaultAccount makes login's stored token the default one
+func SetDefaultAccount(login string) error {
+	return keyring.Set(serviceName, defaultKey, login)
+}
+
+// DefaultAccount returns the GitHub account whose token is the default, or
+// "" if none is set
+func DefaultAccount() string {
+	login, err := keyring.Get(serviceName, defaultKey)
+	if err != nil {
+		return ""
+	}
+	return login
 }
 
-// DeleteToken removes the GitHub token from the OS keychain
+// DeleteToken unsets the default account and removes the legacy token,
+// leaving the accounts' own tokens in place
 func DeleteToken() error {
-	return keyring.Delete(serviceName, tokenKey)
+	errDefault := keyring.Delete(serviceName, defaultKey)
+	errLegacy := keyring.Delete(serviceName, legacyTokenKey)
+	if errDefault == nil || errLegacy == nil {
+		return nil
+	}
+	return errDefault
 }
 
 // HasToken checks if a token exists in the keychain
@@ -30,6 +85,151 @@ func HasToken() bool {
 	return err == nil
 }
 
+// UseAccount makes GetToken return login's token for the rest of the run;
+// "" goes back to the default token
+func UseAccount(login string) {
+	account = login
+}
+
+// Account returns the account picked with UseAccount, if any
+func Account() string {
+	return account
+}
+
+// AccountTokenKey is the keychain item login's token is kept in, under the
+// "pact" service
+func AccountTokenKey(login string) string {
+	return "pact:github:" + strings.ToLower(login)
+}
+
+// SetAccountToken stores the token for a GitHub account and adds the account
+// to the list Accounts returns
+func SetAccountToken(login, token string) error {
+	if err := keyring.Set(serviceName, AccountTokenKey(login), token); err != nil {
+		return err
+	}
+	accounts := Accounts()
+	for _, a := range accounts {
+		if strings.EqualFold(a, login) {
+			return nil
+		}
+	}
+	return setAccounts(append(accounts, login))
+}
+
+// GetAccountToken retrieves a GitHub account's token
+func GetAccountToken(login string) (string, error) {
+	return keyring.Get(serviceName, AccountTokenKey(login))
+}
+
+// DeleteAccountToken removes a GitHub account's token and forgets the
+// account, unsetting it as the default
+func DeleteAccountToken(login string) error {
+	var kept []string
+	for _, a := range Accounts() {
+		if !strings.EqualFold(a, login) {
+			kept = append(kept, a)
+		}
+	}
+	if err := setAccounts(kept); err != nil {
+		return err
+	}
+	if strings.EqualFold(DefaultAccount(), login) {
+		keyring.Delete(serviceName, defaultKey)
+	}
+	return keyring.Delete(serviceName, AccountTokenKey(login))
+}
+
+// Accounts lists the GitHub accounts with a stored token. The keychain can't
+// be searched, so the list is kept in an entry of its own.
+func Accounts() []string {
+	list, err := keyring.Get(serviceName, accountsKey)
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(list)
+}
+
+func setAccounts(accounts []string) error {
+	if len(accounts) == 0 {
+		err := keyring.Delete(serviceName, accountsKey)
+		if err == keyring.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+	sort.Slice(accounts, func(i, j int) bool { return strings.ToLower(accounts[i]) < strings.ToLower(accounts[j]) })
+	return keyring.Set(serviceName, accountsKey, strings.Join(accounts, " "))
+}
+
+// HasLegacyToken reports whether a token is stored under an item name from
+// before AccountTokenKey
+func HasLegacyToken() bool {
+	if _, err := keyring.Get(serviceName, legacyTokenKey); err == nil {
+		return true
+	}
+	for _, login := range Accounts() {
+		if _, err := keyring.Get(serviceName, legacyTokenKey+":"+strings.ToLower(login)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Migrate moves tokens stored under older item names to AccountTokenKey.
+// The legacy default token is matched against the accounts' tokens, and
+// otherwise login is asked which account it belongs to; it's left in place
+// if that fails, so DefaultToken keeps returning it.
+func Migrate(login func(token string) (string, error)) error {
+	for _, a := range Accounts() {
+		old := legacyTokenKey + ":" + strings.ToLower(a)
+		token, err := keyring.Get(serviceName, old)
+		if err != nil {
+			continue
+		}
+		if err := keyring.Set(serviceName, AccountTokenKey(a), token); err != nil {
+			return err
+		}
+		keyring.Delete(serviceName, old)
+	}
+
+	token, err := keyring.Get(serviceName, legacyTokenKey)
+	if err != nil {
+		return nil
+	}
+	name := ""
+	for _, a := range Accounts() {
+		if stored, err := GetAccountToken(a); err == nil && stored == token {
+			name = a
+			break
+		}
+	}
+	if name == "" {
+		if name, err = login(token); err != nil {
+			return err
+		}
+		if err := SetAccountToken(name, token); err != nil {
+			return err
+		}
+	}
+	if DefaultAccount() == "" {
+		if err := SetDefaultAccount(name); err != nil {
+			return err
+		}
+	}
+	return keyring.Delete(serviceName, legacyTokenKey)
+}
+
+// SetAgeIdentity stores the age secret key that decrypts secrets.enc
+func SetAgeIdentity(identity string) error {
+	return keyring.Set(serviceName, identityKey, identity)
+}
+
+// GetAgeIdentity retrieves the age secret key from the OS keychain
+func GetAgeIdentity() (string, error) {
+	return keyring.Get(serviceName, identityKey)
+}
+
 // SetSecret stores a secret in the OS keychain
 func SetSecret(name, value string) error {
 	return keyring.Set(serviceName, name, value)
@@ -50,3 +250,30 @@ func HasSecret(name string) bool {
 	_, err := GetSecret(name)
 	return err == nil
 }
+
+// projectKey namespaces a project secret so the same name in different
+// projects (or globally) never collides
+func projectKey(project, name string) string {
+	return "project:" + project + ":" + name
+}
+
+// SetProjectSecret stores a project-scoped secret in the OS keychain
+func SetProjectSecret(project, name, value string) error {
+	return keyring.Set(serviceName, projectKey(project, name), value)
+}
+
+// GetProjectSecret retrieves a project-scoped secret from the OS keychain
+func GetProjectSecret(project, name string) (string, error) {
+	return keyring.Get(serviceName, projectKey(project, name))
+}
+
+// DeleteProjectSecret removes a project-scoped secret from the OS keychain
+func DeleteProjectSecret(project, name string) error {
+	return keyring.Delete(serviceName, projectKey(project, name))
+}
+
+// HasProjectSecret checks if a project-scoped secret exists in the keychain
+func HasProjectSecret(project, name string) bool {
+	_, err := GetProjectSecret(project, name)
+	return err == nil
+}
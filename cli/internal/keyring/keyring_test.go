@@ -0,0 +1,37 @@
+package keyring
+
+import (
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestMigrate(t *testing.T) {
+	keyring.MockInit()
+	keyring.Set(serviceName, legacyTokenKey, "gho_default")
+	keyring.Set(serviceName, legacyTokenKey+":work", "gho_work")
+	keyring.Set(serviceName, accountsKey, "Work")
+
+	err := Migrate(func(token string) (string, error) {
+		if token != "gho_default" {
+			t.Fatalf("login asked about %q", token)
+		}
+		return "jh", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := GetAccountToken("work"); got != "gho_work" {
+		t.Fatalf("work token = %q, want gho_work", got)
+	}
+	if got, _ := DefaultToken(); got != "gho_default" || DefaultAccount() != "jh" {
+		t.Fatalf("default = %s %q, want jh gho_default", DefaultAccount(), got)
+	}
+	if HasLegacyToken() {
+		t.Fatal("legacy items left after Migrate")
+	}
+	if err := Migrate(func(string) (string, error) { t.Fatal("nothing left to migrate"); return "", nil }); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Outdated is an installed package with a newer version available
+type Outdated struct {
+	Package   string `json:"package"`
+	Installed string `json:"installed"`
+	Available string `json:"available"`
+}
+
+// Upgrader is a Manager that can list the packages it has updates for and
+// upgrade them one at a time
+type Upgrader interface {
+	Manager
+	// Outdated lists every installed package with an update available
+	Outdated() []string
+	// ParseOutdated reads the output of Outdated
+	ParseOutdated(output string) []Outdated
+	// Upgrade upgrades one package to its newest version
+	Upgrade(name string) []string
+}
+
+func (Brew) Outdated() []string { return []string{"brew", "outdated", "--verbose"} }
+func (Brew) Upgrade(name string) []string {
+	return []string{"brew", "upgrade", name}
+}
+
+// ParseOutdated reads lines like "git (2.43.0) < 2.44.0", where a package
+// with several versions installed lists them all
+func (Brew) ParseOutdated(output string) []Outdated {
+	var outdated []Outdated
+	for _, line := range strings.Split(output, "\n") {
+		name, rest, ok := strings.Cut(strings.TrimSpace(line), " (")
+		if !ok {
+			continue
+		}
+		installed, available, ok := strings.Cut(rest, ") ")
+		if !ok {
+			continue
+		}
+		available = strings.TrimSpace(strings.TrimLeft(available, "<!="))
+		if i := strings.LastIndex(installed, ", "); i >= 0 {
+			installed = installed[i+2:]
+		}
+		outdated = append(outdated, Outdated{Package: name, Installed: installed, Available: available})
+	}
+	return outdated
+}
+
+func (Apt) Outdated() []string { return []string{"apt", "list", "--upgradable"} }
+func (Apt) Upgrade(name string) []string {
+	return []string{"sudo", "apt", "install", "--only-upgrade", "-y", name}
+}
+
+// ParseOutdated reads lines like
+// "ripgrep/jammy-updates 14.1.0-1 amd64 [upgradable from: 13.0.0-2]"
+func (Apt) ParseOutdated(output string) []Outdated {
+	var outdated []Outdated
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		name, _, ok := strings.Cut(fields[0], "/")
+		if !ok {
+			continue
+		}
+		entry := Outdated{Package: name, Available: fields[1]}
+		if _, from, ok := strings.Cut(line, "upgradable from: "); ok {
+			entry.Installed = strings.TrimSuffix(strings.TrimSpace(from), "]")
+		}
+		outdated = append(outdated, entry)
+	}
+	return outdated
+}
+
+func (Winget) Outdated() []string {
+	return []string{"winget", "upgrade", "--accept-source-agreements"}
+}
+func (Winget) Upgrade(name string) []string {
+	return []string{"winget", "upgrade", "--id", name, "-e", "--silent", "--accept-package-agreements", "--accept-source-agreements"}
+}
+
+// ParseOutdated reads winget's upgrade table. Its columns are padded to
+// line up on screen, so they're found and cut by display width: a heading
+// or package name with wide or non-ASCII characters shifts bytes, not
+// columns. Headings are translated, so only their position is used: Name,
+// Id, Version, and Available, then Source.
+func (Winget) ParseOutdated(output string) []Outdated {
+	var outdated []Outdated
+	var columns []int
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i := range lines {
+		// Progress spinners are overwritten with carriage returns
+		if j := strings.LastIndex(lines[i], "\r"); j >= 0 {
+			lines[i] = lines[i][j+1:]
+		}
+	}
+	for i, line := range lines {
+		if isRule(line) {
+			continue
+		}
+		// Each table's headings sit above a rule of dashes
+		if i+1 < len(lines) && isRule(lines[i+1]) {
+			if columns = headingColumns(line); len(columns) < 4 {
+				columns = nil
+			}
+			continue
+		}
+		if columns == nil || ansi.StringWidth(line) <= columns[3] {
+			continue
+		}
+		last := -1
+		if len(columns) > 4 {
+			last = columns[4]
+		}
+		pkgID := cutColumn(line, columns[1], columns[2])
+		if pkgID == "" || strings.Contains(pkgID, " ") || strings.HasPrefix(pkgID, "-") {
+			continue
+		}
+		outdated = append(outdated, Outdated{
+			Package:   pkgID,
+			Installed: cutColumn(line, columns[2], columns[3]),
+			Available: cutColumn(line, columns[3], last),
+		})
+	}
+	return outdated
+}
+
+// isRule reports whether line is the row of dashes under a table's headings
+func isRule(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= 3 && strings.Trim(line, "-") == ""
+}
+
+// headingColumns returns the display column each heading starts at
+func headingColumns(line string) []int {
+	var columns []int
+	width, space := 0, true
+	for _, r := range line {
+		if r != ' ' && space {
+			columns = append(columns, width)
+		}
+		space = r == ' '
+		width += ansi.StringWidth(string(r))
+	}
+	return columns
+}
+
+// cutColumn returns the trimmed text of line from display column from up
+// to to, or to the end when to is negative
+func cutColumn(line string, from, to int) string {
+	if to >= 0 {
+		line = ansi.Truncate(line, to, "")
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, ansi.Truncate(line, from, "")))
+}
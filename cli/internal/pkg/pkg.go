@@ -0,0 +1,260 @@
+// Package pkg describes the system package managers pact installs tools and
+// apps with, as the commands each one runs.
+package pkg
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Manager is a system package manager. Its methods return command lines
+// rather than running them, so callers can show, scope, and time them.
+type Manager interface {
+	// Name is how pact.json and the install journal refer to the manager
+	Name() string
+	// Install installs a package
+	Install(name string) []string
+	// Uninstall removes a package
+	Uninstall(name string) []string
+	// Query succeeds only when a package is installed, or is nil when the
+	// manager can't tell by exit status
+	Query(name string) []string
+}
+
+// AppManager is a Manager that installs GUI apps differently from command
+// line tools, such as Homebrew casks
+type AppManager interface {
+	Manager
+	InstallApp(name string) []string
+	UninstallApp(name string) []string
+	QueryApp(name string) []string
+}
+
+// Brew is Homebrew, whose apps are casks
+type Brew struct{}
+
+func (Brew) Name() string                      { return "brew" }
+func (Brew) Install(name string) []string      { return []string{"brew", "install", name} }
+func (Brew) Uninstall(name string) []string    { return []string{"brew", "uninstall", name} }
+func (Brew) Query(name string) []string        { return []string{"brew", "list", "--formula", name} }
+func (Brew) InstallApp(name string) []string   { return []string{"brew", "install", "--cask", name} }
+func (Brew) UninstallApp(name string) []string { return []string{"brew", "uninstall", "--cask", name} }
+func (Brew) QueryApp(name string) []string     { return []string{"brew", "list", "--cask", name} }
+
+// Apt is Debian and Ubuntu's apt
+type Apt struct{}
+
+func (Apt) Name() string                   { return "apt" }
+func (Apt) Install(name string) []string   { return []string{"sudo", "apt", "install", "-y", name} }
+func (Apt) Uninstall(name string) []string { return []string{"sudo", "apt", "remove", "-y", name} }
+func (Apt) Query(name string) []string     { return []string{"dpkg", "-s", name} }
+
+// Dnf is Fedora's dnf
+type Dnf struct{}
+
+func (Dnf) Name() string                   { return "dnf" }
+func (Dnf) Install(name string) []string   { return []string{"sudo", "dnf", "install", "-y", name} }
+func (Dnf) Uninstall(name string) []string { return []string{"sudo", "dnf", "remove", "-y", name} }
+func (Dnf) Query(name string) []string     { return []string{"rpm", "-q", name} }
+
+// Pacman is Arch's pacman
+type Pacman struct{}
+
+func (Pacman) Name() string { return "pacman" }
+func (Pacman) Install(name string) []string {
+	return []string{"sudo", "pacman", "-S", "--noconfirm", name}
+}
+func (Pacman) Uninstall(name string) []string {
+	return []string{"sudo", "pacman", "-R", "--noconfirm", name}
+}
+func (Pacman) Query(name string) []string { return []string{"pacman", "-Q", name} }
+
+// Zypper is openSUSE's zypper
+type Zypper struct{}
+
+func (Zypper) Name() string { return "zypper" }
+func (Zypper) Install(name string) []string {
+	return []string{"sudo", "zypper", "--non-interactive", "install", name}
+}
+func (Zypper) Uninstall(name string) []string {
+	return []string{"sudo", "zypper", "--non-interactive", "remove", name}
+}
+func (Zypper) Query(name string) []string { return []string{"rpm", "-q", name} }
+
+// Apk is Alpine's apk
+type Apk struct{}
+
+func (Apk) Name() string                   { return "apk" }
+func (Apk) Install(name string) []string   { return []string{"sudo", "apk", "add", name} }
+func (Apk) Uninstall(name string) []string { return []string{"sudo", "apk", "del", name} }
+func (Apk) Query(name string) []string     { return []string{"apk", "info", "-e", name} }
+
+// Winget is the Windows Package Manager, which takes exact package IDs
+type Winget struct{}
+
+func (Winget) Name() string { return "winget" }
+func (Winget) Install(name string) []string {
+	return []string{"winget", "install", "--id", name, "-e", "--silent"}
+}
+func (Winget) Uninstall(name string) []string {
+	return []string{"winget", "uninstall", "--id", name, "-e", "--silent"}
+}
+func (Winget) Query(name string) []string { return []string{"winget", "list", "--id", name, "-e"} }
+func (w Winget) InstallApp(name string) []string {
+	return append(w.Install(name), "--accept-package-agreements", "--accept-source-agreements")
+}
+func (w Winget) UninstallApp(name string) []string { return w.Uninstall(name) }
+func (w Winget) QueryApp(name string) []string     { return w.Query(name) }
+
+// Scoop installs into the user's home without admin rights
+type Scoop struct{}
+
+func (Scoop) Name() string                      { return "scoop" }
+func (Scoop) Install(name string) []string      { return []string{"scoop", "install", name} }
+func (Scoop) Uninstall(name string) []string    { return []string{"scoop", "uninstall", name} }
+func (Scoop) Query(name string) []string        { return []string{"scoop", "prefix", name} }
+func (s Scoop) InstallApp(name string) []string { return s.Install(name) }
+func (s Scoop) UninstallApp(name string) []string {
+	return s.Uninstall(name)
+}
+func (s Scoop) QueryApp(name string) []string { return s.Query(name) }
+
+// Choco is Chocolatey, which exits zero whether or not a package is
+// installed, so it has no Query
+type Choco struct{}
+
+func (Choco) Name() string                      { return "choco" }
+func (Choco) Install(name string) []string      { return []string{"choco", "install", name, "-y"} }
+func (Choco) Uninstall(name string) []string    { return []string{"choco", "uninstall", name, "-y"} }
+func (Choco) Query(string) []string             { return nil }
+func (c Choco) InstallApp(name string) []string { return c.Install(name) }
+func (c Choco) UninstallApp(name string) []string {
+	return c.Uninstall(name)
+}
+func (Choco) QueryApp(string) []string { return nil }
+
+// Flatpak installs sandboxed Linux apps by app ID, such as
+// com.spotify.Client, from the flathub remote
+type Flatpak struct{}
+
+func (Flatpak) Name() string { return "flatpak" }
+func (Flatpak) Install(id string) []string {
+	return []string{"flatpak", "install", "-y", "--noninteractive", FlathubRemote, id}
+}
+func (Flatpak) Uninstall(id string) []string {
+	return []string{"flatpak", "uninstall", "-y", "--noninteractive", id}
+}
+func (Flatpak) Query(id string) []string { return []string{"flatpak", "info", id} }
+
+// FlathubRemote is the remote Flatpak apps install from, at FlathubURL
+const (
+	FlathubRemote = "flathub"
+	FlathubURL    = "https://dl.flathub.org/repo/flathub.flatpakrepo"
+)
+
+// IsFlatpakID reports whether name is a reverse-DNS Flatpak app ID such as
+// org.mozilla.firefox rather than a package name
+func IsFlatpakID(name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) < 3 {
+		return false
+	}
+	for _, part := range parts {
+		if part == "" || strings.ContainsAny(part, " /@") {
+			return false
+		}
+	}
+	return true
+}
+
+// Snap is Canonical's snap. A name can carry install flags, such as
+// "code --classic".
+type Snap struct{}
+
+func (Snap) Name() string { return "snap" }
+func (Snap) Install(name string) []string {
+	return append([]string{"sudo", "snap", "install"}, strings.Fields(name)...)
+}
+func (Snap) Uninstall(name string) []string {
+	return []string{"sudo", "snap", "remove", snapName(name)}
+}
+func (Snap) Query(name string) []string { return []string{"snap", "list", snapName(name)} }
+
+// snapName drops any install flags from a snap name
+func snapName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return name
+}
+
+// preferred lists the managers for each OS in the order pact picks them
+var preferred = map[string][]Manager{
+	"darwin":  {Brew{}},
+	"linux":   {Apt{}, Dnf{}, Pacman{}, Zypper{}, Apk{}, Brew{}},
+	"windows": {Winget{}, Scoop{}, Choco{}},
+}
+
+// all lists every manager once, in the order Available reports them
+var all = []Manager{Brew{}, Apt{}, Dnf{}, Pacman{}, Zypper{}, Apk{}, Flatpak{}, Snap{}, Winget{}, Scoop{}, Choco{}}
+
+// Get returns the manager with the given name, or nil
+func Get(name string) Manager {
+	for _, m := range all {
+		if m.Name() == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// Detect returns the preferred manager on PATH for goos, or nil
+func Detect(goos string) Manager {
+	for _, m := range preferred[goos] {
+		if onPath(m.Name()) {
+			return m
+		}
+	}
+	return nil
+}
+
+// Available returns every manager on PATH
+func Available() []Manager {
+	var found []Manager
+	for _, m := range all {
+		if onPath(m.Name()) {
+			found = append(found, m)
+		}
+	}
+	return found
+}
+
+func onPath(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// appNames are the package names of common apps where they differ from the
+// app's name, by manager
+var appNames = map[string]map[string]string{
+	"brave":    {"brew": "brave-browser", "winget": "Brave.Brave", "flatpak": "com.brave.Browser"},
+	"discord":  {"winget": "Discord.Discord", "flatpak": "com.discordapp.Discord"},
+	"spotify":  {"winget": "Spotify.Spotify", "flatpak": "com.spotify.Client"},
+	"steam":    {"winget": "Valve.Steam", "flatpak": "com.valvesoftware.Steam"},
+	"cursor":   {"winget": "Cursor.Cursor"},
+	"vscode":   {"brew": "visual-studio-code", "winget": "Microsoft.VisualStudioCode", "flatpak": "com.visualstudio.code", "snap": "code --classic"},
+	"slack":    {"winget": "SlackTechnologies.Slack", "flatpak": "com.slack.Slack"},
+	"notion":   {"winget": "Notion.Notion"},
+	"figma":    {"winget": "Figma.Figma"},
+	"docker":   {"winget": "Docker.DockerDesktop", "choco": "docker-desktop"},
+	"obsidian": {"flatpak": "md.obsidian.Obsidian"},
+}
+
+// AppName returns the package an app is known by to manager, which is the
+// app's own name unless it's one pact knows otherwise
+func AppName(app, manager string) string {
+	if name, ok := appNames[strings.ToLower(app)][manager]; ok {
+		return name
+	}
+	return app
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	for _, name := range []string{"brew", "apt", "dnf", "pacman", "zypper", "apk", "flatpak", "snap", "winget", "scoop", "choco"} {
+		if m := Get(name); m == nil || m.Name() != name {
+			t.Fatalf("Get(%s) = %v", name, m)
+		}
+	}
+	if Get("emerge") != nil {
+		t.Fatalf("Get(emerge) should be nil")
+	}
+	if _, ok := Get("apt").(AppManager); ok {
+		t.Fatalf("apt shouldn't install apps")
+	}
+}
+
+func TestAppName(t *testing.T) {
+	if got := AppName("Brave", "brew"); got != "brave-browser" {
+		t.Fatalf("AppName(Brave, brew) = %s", got)
+	}
+	if got := AppName("brave", "choco"); got != "brave" {
+		t.Fatalf("AppName(brave, choco) = %s", got)
+	}
+	want := []string{"brew", "install", "--cask", "brave-browser"}
+	if got := (Brew{}).InstallApp(AppName("brave", "brew")); !reflect.DeepEqual(got, want) {
+		t.Fatalf("InstallApp = %v, want %v", got, want)
+	}
+}
+
+func TestFlatpakAndSnap(t *testing.T) {
+	for name, want := range map[string]bool{"com.spotify.Client": true, "org.mozilla.firefox": true, "spotify": false, "node.js": false, "a..b": false} {
+		if got := IsFlatpakID(name); got != want {
+			t.Fatalf("IsFlatpakID(%s) = %v", name, got)
+		}
+	}
+	if got := (Snap{}).Install("code --classic"); !reflect.DeepEqual(got, []string{"sudo", "snap", "install", "code", "--classic"}) {
+		t.Fatalf("Snap Install = %v", got)
+	}
+	if got := (Snap{}).Query("code --classic"); !reflect.DeepEqual(got, []string{"snap", "list", "code"}) {
+		t.Fatalf("Snap Query = %v", got)
+	}
+}
+
+func TestParseOutdated(t *testing.T) {
+	brew := Brew{}.ParseOutdated("git (2.43.0) < 2.44.0\nnode (20.1.0, 20.2.0) < 21.0.0\nfirefox (120.0) != 121.0\n")
+	if len(brew) != 3 || brew[1] != (Outdated{Package: "node", Installed: "20.2.0", Available: "21.0.0"}) || brew[2].Available != "121.0" {
+		t.Fatalf("Brew.ParseOutdated() = %+v", brew)
+	}
+
+	apt := Apt{}.ParseOutdated("Listing... Done\nripgrep/jammy-updates 14.1.0-1 amd64 [upgradable from: 13.0.0-2]\n")
+	if len(apt) != 1 || apt[0] != (Outdated{Package: "ripgrep", Installed: "13.0.0-2", Available: "14.1.0-1"}) {
+		t.Fatalf("Apt.ParseOutdated() = %+v", apt)
+	}
+
+	table := "\r-\r\\\rName            Id                Version Available Source\n" +
+		"----------------------------------------------------------\n" +
+		"Git             Git.Git           2.43.0  2.44.0    winget\n" +
+		"Microsoft Visu… Microsoft.VSCode  1.85.0  1.86.1    winget\n" +
+		"2 upgrades available.\n"
+	winget := Winget{}.ParseOutdated(table)
+	if len(winget) != 2 || winget[0] != (Outdated{Package: "Git.Git", Installed: "2.43.0", Available: "2.44.0"}) || winget[1].Package != "Microsoft.VSCode" {
+		t.Fatalf("Winget.ParseOutdated() = %+v", winget)
+	}
+
+	// Localized headings, and names wider or longer in bytes than on screen
+	localized := "Name               ID                 Version  Verfügbar Quelle\n" +
+		"----------------------------------------------------------------\n" +
+		"微信                Tencent.WeChat     3.9.8    3.9.10    winget\n" +
+		"Überwachung Plus…   Contoso.Überwacher 1.0      1.1       winget\n"
+	winget = Winget{}.ParseOutdated(localized)
+	if len(winget) != 2 || winget[0] != (Outdated{Package: "Tencent.WeChat", Installed: "3.9.8", Available: "3.9.10"}) ||
+		winget[1] != (Outdated{Package: "Contoso.Überwacher", Installed: "1.0", Available: "1.1"}) {
+		t.Fatalf("Winget.ParseOutdated(localized) = %+v", winget)
+	}
+}
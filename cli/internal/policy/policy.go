@@ -0,0 +1,139 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/git"
+)
+
+// Policy is an admin-provided file that restricts what pact may do on this
+// machine. Users can't override it.
+type Policy struct {
+	DisallowModules []string `json:"disallowModules,omitempty"` // Modules that are never applied
+	RemoteHost      string   `json:"remoteHost,omitempty"`      // The only host the pact repo may live on
+	ForbidScripts   bool     `json:"forbidScripts,omitempty"`   // No downloaded install scripts
+	RequirePrivate  bool     `json:"requirePrivate,omitempty"`  // The pact repo must be private
+
+	Path string `json:"-"` // Where it was loaded from; empty when there is none
+}
+
+// DefaultPath is where admins put the policy file
+func DefaultPath() string {
+	if runtime.GOOS == "windows" {
+		dir := os.Getenv("ProgramData")
+		if dir == "" {
+			dir = `C:\ProgramData`
+		}
+		return filepath.Join(dir, "pact", "policy.json")
+	}
+	return "/etc/pact/policy.json"
+}
+
+// Load reads the policy at DefaultPath
+func Load() (*Policy, error) {
+	return LoadFile(DefaultPath())
+}
+
+// LoadFile reads a policy file. A missing file is an empty policy; a file
+// that can't be read or parsed is an error, never silently ignored.
+func LoadFile(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Policy{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", path, err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", path, err)
+	}
+	p.Path = path
+	return &p, nil
+}
+
+// Active reports whether a policy file is in effect
+func (p *Policy) Active() bool {
+	return p.Path != ""
+}
+
+// AllowsModule reports whether a module may be applied
+func (p *Policy) AllowsModule(module string) bool {
+	for _, m := range p.DisallowModules {
+		if strings.EqualFold(m, module) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter splits modules into those the policy allows and those it doesn't
+func (p *Policy) Filter(modules []string) (allowed, denied []string) {
+	for _, m := range modules {
+		if p.AllowsModule(m) {
+			allowed = append(allowed, m)
+		} else {
+			denied = append(denied, m)
+		}
+	}
+	return allowed, denied
+}
+
+// CheckRemote returns an error when a remote URL is on a host other than
+// the pinned one
+func (p *Policy) CheckRemote(remote string) error {
+	if p.RemoteHost == "" {
+		return nil
+	}
+	if host := remoteHost(remote); !strings.EqualFold(host, p.RemoteHost) {
+		return fmt.Errorf("remote %s is not on %s, the host required by %s", remote, p.RemoteHost, p.Path)
+	}
+	return nil
+}
+
+// RepoLookup asks GitHub whether owner/name is private; found is false when
+// there's no such repo or the token can't see it
+type RepoLookup func(owner, name string) (private, found bool, err error)
+
+// CheckPrivate returns an error, under RequirePrivate, unless lookup says
+// the GitHub repo remote points at is private. A repo elsewhere can't be
+// checked, so it never passes.
+func (p *Policy) CheckPrivate(remote string, lookup RepoLookup) error {
+	if !p.RequirePrivate {
+		return nil
+	}
+	if !git.IsGitHub(remote) {
+		return fmt.Errorf("can't confirm the pact repo at %s is private, which %s requires: pact can only ask GitHub", remote, p.Path)
+	}
+	owner, name := git.RepoPath(remote)
+	private, found, err := lookup(owner, name)
+	if err == nil && !found {
+		err = fmt.Errorf("it wasn't found on GitHub")
+	}
+	if err != nil {
+		return fmt.Errorf("can't confirm %s/%s is private: %w", owner, name, err)
+	}
+	if !private {
+		return fmt.Errorf("%s/%s is public, but %s requires a private repo", owner, name, p.Path)
+	}
+	return nil
+}
+
+// remoteHost returns the host of an https or scp-style (git@host:path) URL
+func remoteHost(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	host, _, _ := strings.Cut(remote, ":")
+	return host
+}
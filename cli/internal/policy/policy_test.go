@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	p, err := LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil || p.Active() {
+		t.Fatalf("LoadFile(missing) = %+v, %v; want an inactive policy", p, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "policy.json")
+	os.WriteFile(path, []byte(`{"disallowModules": ["apps"], "remoteHost": "github.com"}`), 0644)
+	p, err = LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+
+	allowed, denied := p.Filter([]string{"cli", "apps", "shell"})
+	if len(allowed) != 2 || len(denied) != 1 || denied[0] != "apps" {
+		t.Fatalf("Filter() = %v, %v", allowed, denied)
+	}
+
+	for remote, ok := range map[string]bool{
+		"https://github.com/me/my-pact.git":  true,
+		"git@github.com:me/my-pact.git":      true,
+		"https://gitlab.com/me/my-pact.git":  false,
+		"ssh://git@ghe.corp.example/me/pact": false,
+	} {
+		if err := p.CheckRemote(remote); (err == nil) != ok {
+			t.Fatalf("CheckRemote(%s) = %v", remote, err)
+		}
+	}
+
+	os.WriteFile(path, []byte(`{"disallowModules": `), 0644)
+	if _, err := LoadFile(path); err == nil {
+		t.Fatalf("LoadFile() accepted an invalid policy")
+	}
+}
+
+func TestCheckPrivate(t *testing.T) {
+	p := &Policy{RequirePrivate: true, Path: "/etc/pact/policy.json"}
+	// me/my-pact is private, me/dotfiles public
+	lookup := func(owner, name string) (bool, bool, error) {
+		return name == "my-pact", owner == "me", nil
+	}
+
+	if err := p.CheckPrivate("https://github.com/me/my-pact.git", lookup); err != nil {
+		t.Fatalf("CheckPrivate(private my-pact) = %v", err)
+	}
+	if err := p.CheckPrivate("git@github.com:me/dotfiles.git", lookup); err == nil {
+		t.Fatalf("CheckPrivate() passed the public me/dotfiles because me/my-pact is private")
+	}
+	if err := p.CheckPrivate("https://github.com/someone/my-pact.git", lookup); err == nil {
+		t.Fatalf("CheckPrivate() passed a repo GitHub didn't find")
+	}
+	if err := p.CheckPrivate("https://gitlab.com/me/my-pact.git", lookup); err == nil {
+		t.Fatalf("CheckPrivate() passed a repo it can't ask GitHub about")
+	}
+}
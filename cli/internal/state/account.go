@@ -0,0 +1,37 @@
+package state
+
+import (
+	"os"
+	"strings"
+)
+
+const accountFile = "account"
+
+// Account returns the GitHub account this workspace is bound to, or "" to
+// use the default account
+func Account() string {
+	p, err := path(accountFile)
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SetAccount binds this workspace to a GitHub account; "" unbinds it
+func SetAccount(login string) error {
+	if login == "" {
+		p, err := path(accountFile)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return writeFile(accountFile, []byte(login+"\n"))
+}
@@ -0,0 +1,237 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/sync"
+)
+
+// Module sync states, comparing the last applied state with pact.json and
+// the machine
+const (
+	SyncSynced  = "synced"  // Nothing changed since the last sync
+	SyncPending = "pending" // pact.json or the repo changed; the next sync applies it
+	SyncDrifted = "drifted" // A file pact placed was edited or removed here
+)
+
+const appliedFile = "applied.json"
+
+// configChanged is what CheckModule reports as changed when it's the
+// module's pact.json section
+const configChanged = "pact.json"
+
+// AppliedModule is a module as pact last applied it
+type AppliedModule struct {
+	ConfigHash string                 `json:"configHash,omitempty"` // pact.json section, once applied in full
+	AppliedAt  time.Time              `json:"appliedAt"`
+	Files      map[string]AppliedItem `json:"files,omitempty"`
+	Tools      []string               `json:"tools,omitempty"` // Installed tools that were on PATH
+}
+
+// AppliedItem is the hash of a file as pact last applied it
+type AppliedItem struct {
+	Hash       string    `json:"hash"`                 // Repo source
+	Target     string    `json:"target,omitempty"`     // File pact placed
+	TargetHash string    `json:"targetHash,omitempty"` // Target's content once placed
+	AppliedAt  time.Time `json:"appliedAt"`
+}
+
+// Applied maps module to its last applied state
+type Applied map[string]*AppliedModule
+
+// LoadApplied reads the last applied hashes. A missing file is empty.
+func LoadApplied() (Applied, error) {
+	appliedPath, err := path(appliedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := Applied{}
+	data, err := os.ReadFile(appliedPath)
+	if os.IsNotExist(err) {
+		return applied, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &applied); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// RecordApplied stores what a sync applied. complete means the module had no
+// failures, so its pact.json section counts as applied too; files are the
+// module's files that were placed, and tools what it installed or found
+// installed, of which those on PATH are kept to check later.
+func RecordApplied(cfg *config.PactConfig, module string, complete bool, files []config.SyncItem, tools []string) error {
+	applied, err := LoadApplied()
+	if err != nil {
+		applied = Applied{}
+	}
+	m := applied[module]
+	if m == nil {
+		m = &AppliedModule{}
+	}
+	if m.Files == nil {
+		m.Files = make(map[string]AppliedItem)
+	}
+
+	now := time.Now()
+	m.AppliedAt = now
+	if complete {
+		m.ConfigHash = ConfigHash(cfg, module)
+	}
+	for _, f := range files {
+		hash, err := hashItem(f, f.Source)
+		if err != nil {
+			continue
+		}
+		targetHash, _ := hashItem(f, f.Target)
+		m.Files[f.Name] = AppliedItem{Hash: hash, Target: f.Target, TargetHash: targetHash, AppliedAt: now}
+	}
+	for _, tool := range tools {
+		if _, err := exec.LookPath(tool); err == nil && !slices.Contains(m.Tools, tool) {
+			m.Tools = append(m.Tools, tool)
+		}
+	}
+	applied[module] = m
+
+	output, err := json.MarshalIndent(applied, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(appliedFile, output)
+}
+
+// CheckModule compares a module's last applied state with pact.json, the
+// repo, and the files and tools on this machine. It returns "" when the module was
+// never applied, and the items behind a pending or drifted state.
+func CheckModule(cfg *config.PactConfig, module string, applied Applied) (string, []string) {
+	m, ok := applied[module]
+	if !ok || m == nil {
+		return "", nil
+	}
+
+	var pending, drifted []string
+	if m.ConfigHash != ConfigHash(cfg, module) {
+		pending = append(pending, configChanged)
+	}
+
+	files, _ := cfg.GetSyncItemsForModule(module)
+	for _, f := range files {
+		last, ok := m.Files[f.Name]
+		if !ok {
+			pending = append(pending, f.Name)
+			continue
+		}
+		if hash, err := hashItem(f, f.Source); err != nil || hash != last.Hash {
+			pending = append(pending, f.Name)
+			continue
+		}
+		if hash, err := hashItem(f, f.Target); err != nil || hash != last.TargetHash {
+			drifted = append(drifted, f.Name)
+		}
+	}
+
+	// A tool pact.json still lists that's gone from PATH was uninstalled
+	listed := make(map[string]bool)
+	for _, tools := range []string{module + ".tools", module + ".custom"} {
+		for _, p := range cfg.GetPackages(tools) {
+			listed[p.Name] = true
+		}
+	}
+	for _, tool := range m.Tools {
+		if _, err := exec.LookPath(tool); listed[tool] && err != nil {
+			drifted = append(drifted, tool)
+		}
+	}
+
+	switch {
+	case len(pending) > 0:
+		return SyncPending, pending
+	case len(drifted) > 0:
+		return SyncDrifted, drifted
+	}
+	return SyncSynced, nil
+}
+
+// ConfigHash hashes a module's section of pact.json
+func ConfigHash(cfg *config.PactConfig, module string) string {
+	// encoding/json sorts map keys, so equal sections hash the same
+	data, _ := json.Marshal(cfg.Raw[module])
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// hashItem hashes one side of a file entry without its ignored churn
+func hashItem(item config.SyncItem, p string) (string, error) {
+	filter, err := sync.NewFilter(item)
+	if filter == nil || err != nil || item.IsDir {
+		return HashPath(p)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(filter.Apply(data))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// HashPath hashes a file's content, or every file under a directory with
+// its relative path
+func HashPath(p string) (string, error) {
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if !info.IsDir() {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+		h.Write(data)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	// WalkDir doesn't follow a symlinked root, as a symlinked target is
+	if p, err = filepath.EvalSymlinks(p); err != nil {
+		return "", err
+	}
+	var files []string
+	err = filepath.WalkDir(p, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		rel, _ := filepath.Rel(p, file)
+		h.Write([]byte(filepath.ToSlash(rel) + "\x00"))
+		h.Write(data)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestConfigHash(t *testing.T) {
+	a, _ := config.Parse([]byte(`{"git": {"user": "me", "email": "me@example.com"}}`))
+	b, _ := config.Parse([]byte(`{"git": {"email": "me@example.com", "user": "me"}, "cli": {}}`))
+	if ConfigHash(a, "git") != ConfigHash(b, "git") {
+		t.Fatal("ConfigHash() depends on key order or other modules")
+	}
+	b.Raw["git"].(map[string]any)["user"] = "you"
+	if ConfigHash(a, "git") == ConfigHash(b, "git") {
+		t.Fatal("ConfigHash() didn't change with the section")
+	}
+}
+
+func TestHashPath(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "nvim", "lua"), 0755)
+	os.WriteFile(filepath.Join(dir, "nvim", "init.lua"), []byte("require('x')"), 0644)
+	os.WriteFile(filepath.Join(dir, "nvim", "lua", "x.lua"), []byte("return {}"), 0644)
+
+	before, err := HashPath(filepath.Join(dir, "nvim"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A symlinked directory hashes like its target
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(filepath.Join(dir, "nvim"), link); err == nil {
+		if got, err := HashPath(link); err != nil || got != before {
+			t.Fatalf("HashPath(symlink) = %s, %v; want %s", got, err, before)
+		}
+	}
+
+	os.WriteFile(filepath.Join(dir, "nvim", "lua", "x.lua"), []byte("return {1}"), 0644)
+	if after, _ := HashPath(filepath.Join(dir, "nvim")); after == before {
+		t.Fatal("HashPath() didn't change when a nested file did")
+	}
+}
+
+func TestCheckModuleFileNamedConfig(t *testing.T) {
+	dir := t.TempDir()
+	os.MkdirAll(filepath.Join(dir, ".pact", "ssh"), 0755)
+	os.WriteFile(filepath.Join(dir, ".pact", "ssh", "config"), []byte("Host *\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "config"), []byte("Host *\n"), 0644)
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	cfg, _ := config.Parse([]byte(`{"ssh": {"files": {"config": {"source": "ssh/config", "target": "` + filepath.ToSlash(filepath.Join(dir, "config")) + `"}}}}`))
+	files, _ := cfg.GetSyncItemsForModule("ssh")
+	if err := RecordApplied(cfg, "ssh", true, files, nil); err != nil {
+		t.Fatal(err)
+	}
+	applied, _ := LoadApplied()
+	if sync, changed := CheckModule(cfg, "ssh", applied); sync != SyncSynced {
+		t.Fatalf("CheckModule() = %s %v, want synced", sync, changed)
+	}
+}
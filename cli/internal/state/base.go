@@ -0,0 +1,49 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// baseDir holds the content of each text file as pact last placed it, the
+// common base for merging edits made here with changes from pact
+const baseDir = "base"
+
+func basePath(module, name string) (string, error) {
+	p, err := path(baseDir)
+	if err != nil {
+		return "", err
+	}
+	name = strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+	return filepath.Join(p, module, name), nil
+}
+
+// SaveBase records the content a file item was last placed with
+func SaveBase(module, name string, data []byte) error {
+	if _, err := Dir(); err != nil {
+		return err
+	}
+	p, err := basePath(module, name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
+// LoadBase returns the content a file item was last placed with, or nil if
+// pact hasn't recorded it
+func LoadBase(module, name string) []byte {
+	p, err := basePath(module, name)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+)
+
+const deferredFile = "deferred.json"
+
+// DeferredItem is something a low-bandwidth sync postponed
+type DeferredItem struct {
+	Module string    `json:"module"`
+	Name   string    `json:"name"`
+	Since  time.Time `json:"since"`
+}
+
+// LoadDeferred reads the items waiting for an unmetered sync
+func LoadDeferred() ([]DeferredItem, error) {
+	deferredPath, err := path(deferredFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(deferredPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []DeferredItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DeferredModules returns the modules with deferred items
+func DeferredModules() []string {
+	items, _ := LoadDeferred()
+	seen := make(map[string]bool)
+	var modules []string
+	for _, item := range items {
+		if !seen[item.Module] {
+			seen[item.Module] = true
+			modules = append(modules, item.Module)
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// SetDeferred replaces the deferred items of the given modules. Items that
+// were already deferred keep their original time.
+func SetDeferred(modules []string, items []DeferredItem) error {
+	existing, err := LoadDeferred()
+	if err != nil {
+		existing = nil
+	}
+
+	replaced := make(map[string]bool)
+	for _, m := range modules {
+		replaced[m] = true
+	}
+	since := make(map[string]time.Time)
+
+	var kept []DeferredItem
+	for _, item := range existing {
+		if replaced[item.Module] {
+			since[item.Module+":"+item.Name] = item.Since
+			continue
+		}
+		kept = append(kept, item)
+	}
+	for _, item := range items {
+		if t, ok := since[item.Module+":"+item.Name]; ok {
+			item.Since = t
+		}
+		kept = append(kept, item)
+	}
+
+	if len(kept) == 0 && len(existing) == 0 {
+		return nil
+	}
+	output, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(deferredFile, output)
+}
@@ -0,0 +1,91 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const githubFile = "github.json"
+
+// GitHubTTL is how long GitHub's answers are used before asking again, and
+// GitHubOfflineTTL how long they're still used when GitHub can't be reached
+const (
+	GitHubTTL        = 10 * time.Minute
+	GitHubOfflineTTL = 24 * time.Hour
+)
+
+// GitHubCache remembers who a token signs in as and which my-pact repos
+// exist, so repeated commands skip the API calls. Tokens are stored only as
+// a hash.
+type GitHubCache struct {
+	Users map[string]CachedUser `json:"users,omitempty"` // By TokenKey
+	Repos map[string]CachedRepo `json:"repos,omitempty"` // By lowercase owner/name
+}
+
+// CachedUser is the login a token signed in as
+type CachedUser struct {
+	Login     string    `json:"login"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// CachedRepo is a repo GitHub said exists, and its visibility
+type CachedRepo struct {
+	Private   bool      `json:"private"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Age is how long ago GitHub was asked
+func (u CachedUser) Age() time.Duration { return time.Since(u.CheckedAt) }
+
+// Age is how long ago GitHub was asked
+func (r CachedRepo) Age() time.Duration { return time.Since(r.CheckedAt) }
+
+// LoadGitHubCache reads the cache, empty if there's none
+func LoadGitHubCache() *GitHubCache {
+	c := &GitHubCache{}
+	if p, err := path(githubFile); err == nil {
+		if data, err := os.ReadFile(p); err == nil {
+			json.Unmarshal(data, c)
+		}
+	}
+	if c.Users == nil {
+		c.Users = make(map[string]CachedUser)
+	}
+	if c.Repos == nil {
+		c.Repos = make(map[string]CachedRepo)
+	}
+	return c
+}
+
+// Save writes the cache to the state dir. Before 'pact init' has cloned
+// the pact repo there's nowhere to keep it, and nothing is written.
+func (c *GitHubCache) Save() error {
+	p, err := path(githubFile)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Dir(filepath.Dir(p))); err != nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(githubFile, data)
+}
+
+// TokenKey identifies a token in the cache without storing it
+func TokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RepoKey is a repo's key in the cache
+func RepoKey(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
@@ -0,0 +1,37 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGitHubCacheWaitsForPactRepo(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir)
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(dir)
+
+	c := LoadGitHubCache()
+	c.Users[TokenKey("token")] = CachedUser{Login: "me", CheckedAt: time.Now()}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".pact")); !os.IsNotExist(err) {
+		t.Fatalf("Save() created .pact before it was cloned")
+	}
+
+	os.Mkdir(filepath.Join(dir, ".pact"), 0755)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if got := LoadGitHubCache().Users[TokenKey("token")].Login; got != "me" {
+		t.Fatalf("cached login = %q, want me", got)
+	}
+	if _, ok := LoadGitHubCache().Users[TokenKey("other")]; ok {
+		t.Fatalf("another token shares the cached login")
+	}
+}
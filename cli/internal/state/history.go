@@ -0,0 +1,108 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+)
+
+// Sync outcomes
+const (
+	OutcomeSynced  = "synced"
+	OutcomePartial = "partial"
+	OutcomeFailed  = "failed"
+)
+
+const historyFile = "history.json"
+
+// ModuleRun is the result of the last time a module was applied
+type ModuleRun struct {
+	LastApplied time.Time    `json:"lastApplied"`
+	Outcome     string       `json:"outcome"`
+	Applied     int          `json:"applied"`
+	Failed      int          `json:"failed"`
+	FailedItems []FailedItem `json:"failedItems,omitempty"`
+}
+
+// FailedItem is an item that failed the last time its module was applied
+type FailedItem struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Total is the number of items the run applied or failed
+func (r ModuleRun) Total() int {
+	return r.Applied + r.Failed
+}
+
+// History maps module name to its last run
+type History map[string]ModuleRun
+
+// LoadHistory reads per-module sync history. A missing file is an empty
+// history.
+func LoadHistory() (History, error) {
+	historyPath, err := path(historyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	history := History{}
+	data, err := os.ReadFile(historyPath)
+	if os.IsNotExist(err) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// RecordModuleRun stores the outcome of applying a module now, with the
+// items that failed
+func RecordModuleRun(module string, applied int, failed []FailedItem) error {
+	history, err := LoadHistory()
+	if err != nil {
+		history = History{}
+	}
+
+	outcome := OutcomeSynced
+	if len(failed) > 0 {
+		outcome = OutcomeFailed
+		if applied > 0 {
+			outcome = OutcomePartial
+		}
+	}
+
+	history[module] = ModuleRun{
+		LastApplied: time.Now(),
+		Outcome:     outcome,
+		Applied:     applied,
+		Failed:      len(failed),
+		FailedItems: failed,
+	}
+
+	output, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(historyFile, output)
+}
+
+// FailedModules lists the modules with items that failed the last time they
+// were applied
+func FailedModules() []string {
+	history, _ := LoadHistory()
+	var modules []string
+	for module, run := range history {
+		if run.Failed > 0 {
+			modules = append(modules, module)
+		}
+	}
+	sort.Strings(modules)
+	return modules
+}
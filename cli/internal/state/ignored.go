@@ -0,0 +1,130 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+const ignoredFile = "ignored.json"
+
+// IgnoredItem is a difference between this machine and pact.json that pact
+// shouldn't bring up again
+type IgnoredItem struct {
+	Module string `json:"module"`
+	Type   string `json:"type,omitempty"` // Empty for any type
+	Name   string `json:"name"`
+}
+
+// LoadIgnored reads the differences ignored on this machine
+func LoadIgnored() ([]IgnoredItem, error) {
+	ignoredPath, err := path(ignoredFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(ignoredPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var items []IgnoredItem
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Ignore adds items to the ignored differences, skipping ones already there
+func Ignore(items ...IgnoredItem) error {
+	existing, err := LoadIgnored()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[IgnoredItem]bool)
+	for _, item := range existing {
+		seen[item] = true
+	}
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			existing = append(existing, item)
+		}
+	}
+
+	output, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(ignoredFile, output)
+}
+
+// Unignore removes items from the ignored differences. Reports how many
+// were there.
+func Unignore(items ...IgnoredItem) (int, error) {
+	existing, err := LoadIgnored()
+	if err != nil {
+		return 0, err
+	}
+
+	remove := make(map[IgnoredItem]bool)
+	for _, item := range items {
+		remove[item] = true
+	}
+	var kept []IgnoredItem
+	for _, item := range existing {
+		if !remove[item] {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(existing) {
+		return 0, nil
+	}
+
+	output, err := json.MarshalIndent(kept, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	return len(existing) - len(kept), writeFile(ignoredFile, output)
+}
+
+// IgnoreList is the drift read, diff, status, and reconcile leave out: the
+// differences ignored on this machine and pact.json's ignore
+type IgnoreList struct {
+	local  map[IgnoredItem]bool
+	shared map[string][]string
+}
+
+// LoadIgnoreList reads the ignored differences; cfg may be nil
+func LoadIgnoreList(cfg *config.PactConfig) (IgnoreList, error) {
+	list := IgnoreList{local: make(map[IgnoredItem]bool)}
+	if cfg != nil {
+		list.shared = cfg.GetIgnoredDrift()
+	}
+	items, err := LoadIgnored()
+	for _, item := range items {
+		list.local[item] = true
+	}
+	return list, err
+}
+
+// Has reports whether a module's item of the given type is ignored. An
+// ignored item without a type, and pact.json's ignore, match names of any
+// type.
+func (l IgnoreList) Has(module, kind, name string) bool {
+	if l.local[IgnoredItem{Module: module, Type: kind, Name: name}] || l.local[IgnoredItem{Module: module, Name: name}] {
+		return true
+	}
+	for _, pattern := range l.shared[module] {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
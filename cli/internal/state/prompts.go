@@ -0,0 +1,57 @@
+package state
+
+import (
+	"encoding/json"
+	"os"
+)
+
+const promptsFile = "prompts.json"
+
+// maxPromptHistory is how many answers each prompt remembers
+const maxPromptHistory = 100
+
+// PromptHistory returns the earlier answers to a prompt, oldest first
+func PromptHistory(name string) []string {
+	p, err := path(promptsFile)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	var all map[string][]string
+	if json.Unmarshal(data, &all) != nil {
+		return nil
+	}
+	return all[name]
+}
+
+// AddPromptHistory remembers an answer to a prompt, moving a repeated
+// answer to the end
+func AddPromptHistory(name, answer string) error {
+	all := make(map[string][]string)
+	if p, err := path(promptsFile); err == nil {
+		if data, err := os.ReadFile(p); err == nil {
+			json.Unmarshal(data, &all)
+		}
+	}
+
+	var kept []string
+	for _, a := range all[name] {
+		if a != answer {
+			kept = append(kept, a)
+		}
+	}
+	kept = append(kept, answer)
+	if len(kept) > maxPromptHistory {
+		kept = kept[len(kept)-maxPromptHistory:]
+	}
+	all[name] = kept
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(promptsFile, data)
+}
@@ -0,0 +1,213 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Files captured by a snapshot: the environment definition in the pact repo
+// and this machine's install journal
+var (
+	snapshotRepoFiles  = []string{"pact.json", "pact.lock"}
+	snapshotStateFiles = []string{journalFile}
+)
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// Snapshot describes a named environment snapshot
+type Snapshot struct {
+	Name    string    `json:"name"`
+	Created time.Time `json:"created"`
+	Files   []string  `json:"files"`
+}
+
+func snapshotsDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "snapshots"), nil
+}
+
+// snapshotDir returns where the snapshot called name is kept, refusing
+// names that aren't a single plain directory name
+func snapshotDir(name string) (string, error) {
+	if !snapshotNamePattern.MatchString(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid snapshot name %q (use letters, digits, . _ -)", name)
+	}
+	root, err := snapshotsDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(root, name)
+	if filepath.Dir(dir) != root {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return dir, nil
+}
+
+// CreateSnapshot captures pact.json, the lockfile (if any), and the install
+// journal under name, replacing an existing snapshot with that name
+func CreateSnapshot(name string) (*Snapshot, error) {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return nil, err
+	}
+
+	os.RemoveAll(dir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{Name: name, Created: time.Now()}
+	sources := map[string]string{}
+	for _, f := range snapshotRepoFiles {
+		sources[f] = filepath.Join(pactDir, f)
+	}
+	for _, f := range snapshotStateFiles {
+		sources[f] = filepath.Join(pactDir, dirName, f)
+	}
+
+	for _, f := range append(snapshotRepoFiles, snapshotStateFiles...) {
+		data, err := os.ReadFile(sources[f])
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, f), data, 0644); err != nil {
+			return nil, err
+		}
+		snap.Files = append(snap.Files, f)
+	}
+
+	output, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "snapshot.json"), output, 0644); err != nil {
+		return nil, err
+	}
+	return snap, nil
+}
+
+// ListSnapshots returns all snapshots, oldest first
+func ListSnapshots() ([]Snapshot, error) {
+	root, err := snapshotsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, entry.Name(), "snapshot.json"))
+		if err != nil {
+			continue
+		}
+		var snap Snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+	return snapshots, nil
+}
+
+// RestoreSnapshot writes a snapshot's files back into the pact repo and
+// state dir, removing repo files it didn't capture, such as a pact.lock
+// created since
+func RestoreSnapshot(name string) (*Snapshot, error) {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "snapshot.json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot %q not found", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot %q: %w", name, err)
+	}
+
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return nil, err
+	}
+
+	captured := make(map[string]bool)
+	for _, f := range snap.Files {
+		captured[f] = true
+	}
+	targets := map[string]string{}
+	for _, f := range snapshotRepoFiles {
+		targets[f] = filepath.Join(pactDir, f)
+	}
+	for _, f := range snapshotStateFiles {
+		targets[f] = filepath.Join(pactDir, dirName, f)
+	}
+
+	for _, f := range append(snapshotRepoFiles, snapshotStateFiles...) {
+		if !captured[f] {
+			if targets[f] != filepath.Join(pactDir, f) {
+				// The journal keeps tracking installs made since
+				continue
+			}
+			if err := os.Remove(targets[f]); err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, f))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(targets[f], content, 0644); err != nil {
+			return nil, err
+		}
+	}
+	return &snap, nil
+}
+
+// DeleteSnapshot removes a snapshot
+func DeleteSnapshot(name string) error {
+	dir, err := snapshotDir(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("snapshot %q not found", name)
+	}
+	return os.RemoveAll(dir)
+}
@@ -0,0 +1,14 @@
+package state
+
+import "testing"
+
+func TestSnapshotNameStaysInSnapshots(t *testing.T) {
+	for _, name := range []string{"..", ".", "../state", "a/b", ""} {
+		if err := DeleteSnapshot(name); err == nil {
+			t.Fatalf("DeleteSnapshot(%q) was allowed", name)
+		}
+		if _, err := RestoreSnapshot(name); err == nil {
+			t.Fatalf("RestoreSnapshot(%q) was allowed", name)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Machine-local state lives in .pact/state and is never pushed
+const dirName = "state"
+
+// Dir returns the state directory, creating it (and ignoring it in the
+// pact repo) if needed
+func Dir() (string, error) {
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(pactDir, dirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create state dir: %w", err)
+	}
+
+	ensureIgnored(pactDir)
+	return dir, nil
+}
+
+// path returns the path of a file in the state dir without creating anything,
+// so read-only callers like status don't touch the pact repo
+func path(name string) (string, error) {
+	pactDir, err := config.GetPactDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pactDir, dirName, name), nil
+}
+
+// writeFile writes a file into the state dir, creating the dir if needed
+func writeFile(name string, data []byte) error {
+	dir, err := Dir()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name), data, 0644)
+}
+
+// BackupsDir returns the directory holding backups of files pact replaced
+func BackupsDir() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "backups"), nil
+}
+
+// ensureIgnored adds state/ to the pact repo's .gitignore so machine-local
+// state is never committed
+func ensureIgnored(pactDir string) {
+	ignorePath := filepath.Join(pactDir, ".gitignore")
+	data, _ := os.ReadFile(ignorePath)
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == dirName+"/" {
+			return
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += dirName + "/\n"
+	os.WriteFile(ignorePath, []byte(content), 0644)
+}
+
+// Entry records something pact installed and how, so it can be removed later
+type Entry struct {
+	Module      string    `json:"module"`
+	Name        string    `json:"name"`
+	Backend     string    `json:"backend"`           // "brew", "brew-cask", "apt", "winget", "npm", "code", "binary", "files", ...
+	Package     string    `json:"package,omitempty"` // Backend package/extension id when it differs from Name
+	Paths       []string  `json:"paths,omitempty"`   // Files pact placed directly (binaries, fonts)
+	InstalledAt time.Time `json:"installedAt"`
+}
+
+// Key returns the "<module>:<name>" identifier for the entry
+func (e Entry) Key() string {
+	return e.Module + ":" + e.Name
+}
+
+// Journal is the list of installs pact performed on this machine
+type Journal struct {
+	Entries []Entry `json:"entries"`
+}
+
+const journalFile = "installed.json"
+
+// LoadJournal reads the install journal, returning an empty one if none exists
+func LoadJournal() (*Journal, error) {
+	journalPath, err := path(journalFile)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{}
+	data, err := os.ReadFile(journalPath)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install journal: %w", err)
+	}
+	if err := json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("failed to parse install journal: %w", err)
+	}
+	return j, nil
+}
+
+// Find returns the entry for module/name
+func (j *Journal) Find(module, name string) (Entry, bool) {
+	for _, e := range j.Entries {
+		if e.Module == module && e.Name == name {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Record adds or replaces the entry for e.Module/e.Name
+func (j *Journal) Record(e Entry) {
+	if e.InstalledAt.IsZero() {
+		e.InstalledAt = time.Now()
+	}
+	for i, existing := range j.Entries {
+		if existing.Module == e.Module && existing.Name == e.Name {
+			// Keep earlier paths (e.g. a previous theme) so they can still
+			// be cleaned up or uninstalled
+			for _, p := range existing.Paths {
+				if !containsPath(e.Paths, p) {
+					e.Paths = append(e.Paths, p)
+				}
+			}
+			j.Entries[i] = e
+			return
+		}
+	}
+	j.Entries = append(j.Entries, e)
+}
+
+// Remove drops the entry for module/name, reporting whether it existed
+func (j *Journal) Remove(module, name string) bool {
+	for i, e := range j.Entries {
+		if e.Module == module && e.Name == name {
+			j.Entries = append(j.Entries[:i], j.Entries[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Save writes the journal back to the state dir
+func (j *Journal) Save() error {
+	output, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(journalFile, output)
+}
+
+func containsPath(paths []string, p string) bool {
+	for _, existing := range paths {
+		if existing == p {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Filter hides churn a program writes into a synced file on its own, so it
+// doesn't count as a change. Set per file entry:
+//
+//	"ignore": {"lines": ["^SETUVAR __fish_initialized"], "keys": ["window.zoomLevel"]}
+//
+// lines are patterns for whole lines; keys are JSON keys matched by name at
+// any depth, together with their value.
+type Filter struct {
+	lines []*regexp.Regexp
+	keys  []string
+}
+
+// NewFilter compiles a file entry's ignore rules, or returns nil when it has
+// none
+func NewFilter(item config.SyncItem) (*Filter, error) {
+	if len(item.IgnoreLines) == 0 && len(item.IgnoreKeys) == 0 {
+		return nil, nil
+	}
+	f := &Filter{keys: item.IgnoreKeys}
+	for _, pattern := range item.IgnoreLines {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid ignore pattern %q: %w", item.Name, pattern, err)
+		}
+		f.lines = append(f.lines, re)
+	}
+	return f, nil
+}
+
+// Apply returns data without ignored lines and keys. It's for comparing
+// versions of a file, not for writing one: commas at line ends are dropped
+// too, since removing a JSON key changes the comma on the line before it.
+func (f *Filter) Apply(data []byte) []byte {
+	var out bytes.Buffer
+	depth := 0 // Open brackets left in a skipped key's value
+	for _, line := range strings.Split(string(data), "\n") {
+		if depth > 0 {
+			depth += bracketDepth(line)
+			continue
+		}
+		if f.ignoreLine(line) {
+			continue
+		}
+		if value, ok := f.ignoredKey(line); ok {
+			depth = bracketDepth(value)
+			continue
+		}
+		if len(f.keys) > 0 {
+			line = strings.TrimRight(line, " \t,")
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// Strip returns data without ignored lines and keys, for committing a file
+// without its churn. Unlike Apply it keeps the file valid: a comma left
+// dangling before a closing bracket by a removed key is dropped.
+func (f *Filter) Strip(data []byte) []byte {
+	var out []string
+	depth := 0
+	removed := false // A key was removed since the last kept line
+	for _, line := range strings.Split(string(data), "\n") {
+		if depth > 0 {
+			depth += bracketDepth(line)
+			continue
+		}
+		if f.ignoreLine(line) {
+			continue
+		}
+		if value, ok := f.ignoredKey(line); ok {
+			depth = bracketDepth(value)
+			removed = true
+			continue
+		}
+		if trimmed := strings.TrimSpace(line); removed && len(out) > 0 &&
+			(strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]")) {
+			out[len(out)-1] = strings.TrimSuffix(strings.TrimRight(out[len(out)-1], " \t"), ",")
+		}
+		removed = false
+		out = append(out, line)
+	}
+	return []byte(strings.Join(out, "\n"))
+}
+
+// Same reports whether a and b differ only in ignored content
+func (f *Filter) Same(a, b []byte) bool {
+	return bytes.Equal(f.Apply(a), f.Apply(b))
+}
+
+func (f *Filter) ignoreLine(line string) bool {
+	for _, re := range f.lines {
+		if re.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// ignoredKey reports whether line sets an ignored key, returning the rest of
+// the line after the colon
+func (f *Filter) ignoredKey(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	for _, key := range f.keys {
+		prefix := `"` + key + `"`
+		if rest, ok := strings.CutPrefix(trimmed, prefix); ok {
+			if rest = strings.TrimSpace(rest); strings.HasPrefix(rest, ":") {
+				return rest[1:], true
+			}
+		}
+	}
+	return "", false
+}
+
+// bracketDepth counts the brackets a line opens minus those it closes,
+// ignoring any inside strings
+func bracketDepth(line string) int {
+	depth := 0
+	inString := false
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			depth--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,75 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+func TestFilterSame(t *testing.T) {
+	f, err := NewFilter(config.SyncItem{
+		IgnoreLines: []string{"^SETUVAR __fish_"},
+		IgnoreKeys:  []string{"window.zoomLevel", "workbench.colorCustomizations"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fishA := "SETUVAR __fish_initialized:3400\nSETUVAR fish_greeting:\n"
+	fishB := "SETUVAR __fish_initialized:3800\nSETUVAR fish_greeting:\n"
+	if !f.Same([]byte(fishA), []byte(fishB)) {
+		t.Fatalf("fish_variables differing in ignored lines should be the same")
+	}
+
+	old := `{
+  "editor.fontSize": 14
+}`
+	churned := `{
+  "editor.fontSize": 14,
+  "window.zoomLevel": 1,
+  "workbench.colorCustomizations": {
+    "editor.background": "#000"
+  }
+}`
+	if !f.Same([]byte(old), []byte(churned)) {
+		t.Fatalf("settings differing in ignored keys should be the same:\n%s", f.Apply([]byte(churned)))
+	}
+	changed := `{
+  "editor.fontSize": 16,
+  "window.zoomLevel": 1
+}`
+	if f.Same([]byte(old), []byte(changed)) {
+		t.Fatalf("a real change was ignored")
+	}
+}
+
+func TestFilterStrip(t *testing.T) {
+	f, err := NewFilter(config.SyncItem{
+		IgnoreLines: []string{"^SETUVAR __fish_"},
+		IgnoreKeys:  []string{"window.zoomLevel", "workbench.colorCustomizations"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fish := "SETUVAR __fish_initialized:3400\nSETUVAR fish_greeting:\n"
+	if got := string(f.Strip([]byte(fish))); got != "SETUVAR fish_greeting:\n" {
+		t.Fatalf("Strip(fish_variables) = %q", got)
+	}
+
+	churned := `{
+  "editor.fontSize": 16,
+  "window.zoomLevel": 1,
+  "workbench.colorCustomizations": {
+    "editor.background": "#000"
+  }
+}
+`
+	want := `{
+  "editor.fontSize": 16
+}
+`
+	if got := string(f.Strip([]byte(churned))); got != want {
+		t.Fatalf("Strip(settings) =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,188 @@
+package sync
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Conflict markers around the two sides of a change that can't be merged
+const (
+	MarkerLocal = "<<<<<<< this machine"
+	MarkerBase  = "||||||| last sync"
+	MarkerSep   = "======="
+	MarkerPact  = ">>>>>>> pact"
+)
+
+// hunk replaces the base lines from start up to end with lines
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+// lineHunks returns the changes that turn base into other, by base line
+func lineHunks(base, other string) []hunk {
+	var hunks []hunk
+	line := 0
+	var open *hunk
+	for _, d := range diff.Do(base, other) {
+		lines := splitLines(d.Text)
+		if d.Type == diffmatchpatch.DiffEqual {
+			if open != nil {
+				hunks = append(hunks, *open)
+				open = nil
+			}
+			line += len(lines)
+			continue
+		}
+		if open == nil {
+			open = &hunk{start: line, end: line}
+		}
+		if d.Type == diffmatchpatch.DiffDelete {
+			line += len(lines)
+			open.end = line
+		} else {
+			open.lines = append(open.lines, lines...)
+		}
+	}
+	if open != nil {
+		hunks = append(hunks, *open)
+	}
+	return hunks
+}
+
+// splitLines splits text into lines, each keeping its newline
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Merge3 combines the changes this machine and pact each made since base,
+// the content both last agreed on. Changes to different lines are both
+// kept; overlapping changes that differ are written between conflict
+// markers and counted.
+func Merge3(base, local, pact string) (string, int) {
+	baseLines := splitLines(base)
+	localHunks, pactHunks := lineHunks(base, local), lineHunks(base, pact)
+
+	var out strings.Builder
+	conflicts := 0
+	line := 0
+	for len(localHunks) > 0 || len(pactHunks) > 0 {
+		// Take the earliest hunk, then every hunk on either side that
+		// overlaps or touches the region so far
+		start, end := regionStart(localHunks, pactHunks), 0
+		var localRegion, pactRegion []hunk
+		for {
+			grew := false
+			if len(localHunks) > 0 && localHunks[0].start <= max(end, start) {
+				end = max(end, localHunks[0].end)
+				localRegion = append(localRegion, localHunks[0])
+				localHunks = localHunks[1:]
+				grew = true
+			}
+			if len(pactHunks) > 0 && pactHunks[0].start <= max(end, start) {
+				end = max(end, pactHunks[0].end)
+				pactRegion = append(pactRegion, pactHunks[0])
+				pactHunks = pactHunks[1:]
+				grew = true
+			}
+			if !grew {
+				break
+			}
+		}
+
+		writeLines(&out, baseLines[line:start])
+		line = end
+		localText := applyHunks(baseLines, start, end, localRegion)
+		pactText := applyHunks(baseLines, start, end, pactRegion)
+		switch {
+		case pactRegion == nil, localText == pactText:
+			out.WriteString(localText)
+		case localRegion == nil:
+			out.WriteString(pactText)
+		default:
+			conflicts++
+			writeConflict(&out, localText, strings.Join(baseLines[start:end], ""), pactText, true)
+		}
+	}
+	writeLines(&out, baseLines[line:])
+	return out.String(), conflicts
+}
+
+// Merge2 is Merge3 without a common base: lines both sides share are kept
+// and every difference is a conflict
+func Merge2(local, pact string) (string, int) {
+	localLines := splitLines(local)
+	var out strings.Builder
+	conflicts := 0
+	line := 0
+	for _, h := range lineHunks(local, pact) {
+		writeLines(&out, localLines[line:h.start])
+		line = h.end
+		conflicts++
+		writeConflict(&out, strings.Join(localLines[h.start:h.end], ""), "", strings.Join(h.lines, ""), false)
+	}
+	writeLines(&out, localLines[line:])
+	return out.String(), conflicts
+}
+
+// HasConflictMarkers reports whether text still has an unresolved conflict
+func HasConflictMarkers(text string) bool {
+	for _, line := range splitLines(text) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == MarkerLocal || line == MarkerPact {
+			return true
+		}
+	}
+	return false
+}
+
+func regionStart(a, b []hunk) int {
+	switch {
+	case len(a) == 0:
+		return b[0].start
+	case len(b) == 0:
+		return a[0].start
+	}
+	return min(a[0].start, b[0].start)
+}
+
+// applyHunks returns the base lines from start to end with hunks applied
+func applyHunks(base []string, start, end int, hunks []hunk) string {
+	var out strings.Builder
+	line := start
+	for _, h := range hunks {
+		writeLines(&out, base[line:h.start])
+		writeLines(&out, h.lines)
+		line = h.end
+	}
+	writeLines(&out, base[line:end])
+	return out.String()
+}
+
+func writeLines(out *strings.Builder, lines []string) {
+	for _, l := range lines {
+		out.WriteString(l)
+	}
+}
+
+func writeConflict(out *strings.Builder, local, base, pact string, withBase bool) {
+	section := func(marker, text string) {
+		out.WriteString(marker + "\n")
+		out.WriteString(text)
+		if text != "" && !strings.HasSuffix(text, "\n") {
+			out.WriteString("\n")
+		}
+	}
+	section(MarkerLocal, local)
+	if withBase {
+		section(MarkerBase, base)
+	}
+	section(MarkerSep, pact)
+	out.WriteString(MarkerPact + "\n")
+}
@@ -0,0 +1,35 @@
+package sync
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	local := "a\nB\nc\nd\ne\n"
+	pact := "a\nb\nc\nd\nE\nf\n"
+	got, conflicts := Merge3(base, local, pact)
+	if want := "a\nB\nc\nd\nE\nf\n"; got != want || conflicts != 0 {
+		t.Fatalf("Merge3() = %q, %d; want %q, 0", got, conflicts, want)
+	}
+
+	got, conflicts = Merge3(base, "a\nx\nc\nd\ne\n", "a\ny\nc\nd\ne\n")
+	want := "a\n" + MarkerLocal + "\nx\n" + MarkerBase + "\nb\n" + MarkerSep + "\ny\n" + MarkerPact + "\nc\nd\ne\n"
+	if got != want || conflicts != 1 {
+		t.Fatalf("Merge3() = %q, %d; want %q, 1", got, conflicts, want)
+	}
+	if !HasConflictMarkers(got) {
+		t.Fatalf("HasConflictMarkers() = false for a conflicted merge")
+	}
+
+	got, conflicts = Merge3(base, "a\nx\nc\nd\ne\n", "a\nx\nc\nd\ne\n")
+	if got != "a\nx\nc\nd\ne\n" || conflicts != 0 {
+		t.Fatalf("Merge3() of the same change = %q, %d", got, conflicts)
+	}
+}
+
+func TestMerge2(t *testing.T) {
+	got, conflicts := Merge2("a\nb\nc\n", "a\nB\nc\n")
+	want := "a\n" + MarkerLocal + "\nb\n" + MarkerSep + "\nB\n" + MarkerPact + "\nc\n"
+	if got != want || conflicts != 1 {
+		t.Fatalf("Merge2() = %q, %d; want %q, 1", got, conflicts, want)
+	}
+}
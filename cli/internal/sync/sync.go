@@ -78,6 +78,22 @@ func syncItem(item config.SyncItem) Result {
 		strategy = "symlink"
 	}
 
+	if strategy == StrategyMergeTOML {
+		changed, err := MergeTOMLFile(item.Source, item.Target)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		result.Success = true
+		if !changed {
+			result.Skipped = true
+			result.Message = "already merged"
+		} else {
+			result.Message = fmt.Sprintf("merged pact-managed tables into %s", item.Target)
+		}
+		return result
+	}
+
 	// Ensure target parent directory exists
 	targetDir := filepath.Dir(item.Target)
 	if err := os.MkdirAll(targetDir, 0755); err != nil {
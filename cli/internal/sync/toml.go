@@ -0,0 +1,269 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StrategyMergeTOML merges the pact-managed tables of a TOML file into the
+// machine's copy instead of replacing it
+const StrategyMergeTOML = "merge-toml"
+
+// tomlDoc is a TOML file split into top-level keys and tables, keeping each
+// piece's original lines (comments included) so merging preserves formatting
+type tomlDoc struct {
+	top    []tomlEntry
+	tables []tomlTable
+}
+
+// tomlEntry is a top-level key/value with its leading comments; key is empty
+// for trailing comments
+type tomlEntry struct {
+	key   string
+	lines []string
+}
+
+// tomlTable is a [table] or [[array]] entry from its leading comments and
+// header to the next header
+type tomlTable struct {
+	name  string
+	lines []string
+}
+
+// MergeTOML merges managed into local at table granularity: top-level keys
+// and tables present in managed replace local ones, everything else in local
+// is kept in place, and tables only in managed are appended
+func MergeTOML(local, managed []byte) []byte {
+	l, m := parseTOML(local), parseTOML(managed)
+
+	managedKeys := make(map[string]tomlEntry)
+	for _, e := range m.top {
+		if e.key != "" {
+			managedKeys[e.key] = e
+		}
+	}
+	managedTables := make(map[string][]tomlTable)
+	for _, t := range m.tables {
+		managedTables[t.name] = append(managedTables[t.name], t)
+	}
+
+	var out []string
+	usedKeys := make(map[string]bool)
+	for _, e := range l.top {
+		if me, ok := managedKeys[e.key]; ok && e.key != "" {
+			out = append(out, me.lines...)
+			usedKeys[e.key] = true
+			continue
+		}
+		out = append(out, e.lines...)
+	}
+	for _, e := range m.top {
+		if e.key != "" && !usedKeys[e.key] {
+			out = append(out, e.lines...)
+		}
+	}
+
+	usedTables := make(map[string]bool)
+	for _, t := range l.tables {
+		mts, ok := managedTables[t.name]
+		if !ok {
+			out = append(out, t.lines...)
+			continue
+		}
+		// Array tables repeat; the managed entries replace all local ones
+		if !usedTables[t.name] {
+			for _, mt := range mts {
+				out = append(out, mt.lines...)
+			}
+			usedTables[t.name] = true
+		}
+	}
+	for _, t := range m.tables {
+		if !usedTables[t.name] {
+			out = append(out, t.lines...)
+		}
+	}
+
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// MergeTOMLFile merges source into target. A missing target, or one that is
+// a symlink (e.g. from an earlier symlink sync), becomes a copy of source.
+// Reports whether target changed.
+func MergeTOMLFile(source, target string) (bool, error) {
+	managed, err := os.ReadFile(source)
+	if err != nil {
+		return false, fmt.Errorf("%s needs a file source: %w", StrategyMergeTOML, err)
+	}
+
+	var local []byte
+	mode := os.FileMode(0644)
+	info, err := os.Lstat(target)
+	switch {
+	case err == nil && info.Mode()&os.ModeSymlink != 0:
+		if err := os.Remove(target); err != nil {
+			return false, err
+		}
+	case err == nil:
+		if local, err = os.ReadFile(target); err != nil {
+			return false, err
+		}
+		mode = info.Mode().Perm()
+	case !os.IsNotExist(err):
+		return false, err
+	}
+
+	merged := managed
+	if local != nil {
+		merged = MergeTOML(local, managed)
+		if string(merged) == string(local) {
+			return false, nil
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(target, merged, mode)
+}
+
+func parseTOML(data []byte) tomlDoc {
+	var doc tomlDoc
+	content := strings.TrimRight(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	if content == "" {
+		return doc
+	}
+	lines := strings.Split(content, "\n")
+
+	current := -1        // index of the table being read, -1 for top-level
+	var pending []string // comments and blank lines not yet attached
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			pending = append(pending, lines[i])
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			doc.tables = append(doc.tables, tomlTable{
+				name:  tableName(trimmed),
+				lines: append(pending, lines[i]),
+			})
+			current = len(doc.tables) - 1
+			pending = nil
+			continue
+		}
+
+		end := valueEnd(lines, i)
+		entry := append(pending, lines[i:end+1]...)
+		pending = nil
+		if current < 0 {
+			doc.top = append(doc.top, tomlEntry{key: keyName(lines[i]), lines: entry})
+		} else {
+			doc.tables[current].lines = append(doc.tables[current].lines, entry...)
+		}
+		i = end
+	}
+
+	if len(pending) > 0 {
+		if current < 0 {
+			doc.top = append(doc.top, tomlEntry{lines: pending})
+		} else {
+			doc.tables[current].lines = append(doc.tables[current].lines, pending...)
+		}
+	}
+	return doc
+}
+
+// tableName normalizes a header like `[[ a . b ]]  # comment` to "[[a.b]]"
+func tableName(header string) string {
+	array := strings.HasPrefix(header, "[[")
+	name := strings.TrimLeft(header, "[")
+	if end := strings.Index(name, "]"); end >= 0 {
+		name = name[:end]
+	}
+
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	name = strings.Join(parts, ".")
+
+	if array {
+		return "[[" + name + "]]"
+	}
+	return "[" + name + "]"
+}
+
+// keyName returns the key of a key/value line
+func keyName(line string) string {
+	key, _, _ := strings.Cut(line, "=")
+	return strings.TrimSpace(key)
+}
+
+// valueEnd returns the index of the last line of the key/value starting at
+// start, following multi-line strings, arrays, and inline tables
+func valueEnd(lines []string, start int) int {
+	depth := 0
+	multi := "" // delimiter of an open multi-line string
+
+	for i := start; i < len(lines); i++ {
+		s := lines[i]
+		j := 0
+		if i == start {
+			if eq := strings.Index(s, "="); eq >= 0 {
+				j = eq + 1
+			}
+		}
+
+		for j < len(s) {
+			if multi != "" {
+				switch {
+				case strings.HasPrefix(s[j:], multi):
+					j += 3
+					multi = ""
+				case multi == `"""` && s[j] == '\\':
+					j += 2
+				default:
+					j++
+				}
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(s[j:], `"""`), strings.HasPrefix(s[j:], "'''"):
+				multi = s[j : j+3]
+				j += 3
+			case s[j] == '"':
+				for j++; j < len(s) && s[j] != '"'; j++ {
+					if s[j] == '\\' {
+						j++
+					}
+				}
+				j++
+			case s[j] == '\'':
+				for j++; j < len(s) && s[j] != '\''; j++ {
+				}
+				j++
+			case s[j] == '#':
+				j = len(s)
+			case s[j] == '[' || s[j] == '{':
+				depth++
+				j++
+			case s[j] == ']' || s[j] == '}':
+				depth--
+				j++
+			default:
+				j++
+			}
+		}
+
+		if depth <= 0 && multi == "" {
+			return i
+		}
+	}
+	return len(lines) - 1
+}
@@ -0,0 +1,62 @@
+package sync
+
+import "testing"
+
+func TestMergeTOML(t *testing.T) {
+	local := `# machine-local starship config
+command_timeout = 500
+add_newline = true
+
+[aws]
+disabled = true
+
+# my prompt character
+[character]
+success_symbol = "[>](green)"
+
+[kubernetes]
+format = """
+on [$context](blue)
+"""
+`
+	managed := `add_newline = false
+format = "$all"
+
+[character]
+success_symbol = "[➜](bold green)"
+error_symbol = "[➜](bold red)"
+
+[git_branch]
+symbol = "🌱 "
+`
+	want := `# machine-local starship config
+command_timeout = 500
+add_newline = false
+format = "$all"
+
+[aws]
+disabled = true
+
+[character]
+success_symbol = "[➜](bold green)"
+error_symbol = "[➜](bold red)"
+
+[kubernetes]
+format = """
+on [$context](blue)
+"""
+
+[git_branch]
+symbol = "🌱 "
+`
+
+	got := string(MergeTOML([]byte(local), []byte(managed)))
+	if got != want {
+		t.Fatalf("MergeTOML() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Merging again must be a no-op
+	if again := string(MergeTOML([]byte(got), []byte(managed))); again != got {
+		t.Fatalf("MergeTOML() is not idempotent:\n%s", again)
+	}
+}
@@ -1,6 +1,12 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+
+	"github.com/cloudboy-jh/pact/internal/buildinfo"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 // ASCII logo for Pact CLI
 const Logo = `██████   ████   ████  ████████
@@ -12,9 +18,6 @@ const Logo = `██████   ████   ████  █████
 // Tagline displayed under the logo
 const Tagline = "Cross-platform environment manager"
 
-// Version can be set at build time via ldflags
-var Version = "dev"
-
 // Brand color style for the logo
 var logoStyle = lipgloss.NewStyle().
 	Foreground(emerald)
@@ -28,8 +31,18 @@ func RenderLogo() string {
 	return logoStyle.Render(Logo) + "\n\n" + taglineStyle.Render(Tagline) + "\n"
 }
 
-// RenderLogoWithVersion returns the styled logo with version
+// RenderLogoWithVersion returns the styled logo with version and build details
 func RenderLogoWithVersion() string {
-	versionText := taglineStyle.Render("v" + Version)
-	return logoStyle.Render(Logo) + "\n\n" + taglineStyle.Render(Tagline) + "  " + versionText + "\n"
+	info := buildinfo.Get()
+	versionText := taglineStyle.Render("v" + info.Version)
+
+	details := info.Channel
+	if commit := info.ShortCommit(); commit != "" {
+		details = fmt.Sprintf("%s · %s", commit, details)
+	}
+	if info.Date != "" {
+		details = fmt.Sprintf("%s · built %s", details, info.Date)
+	}
+
+	return logoStyle.Render(Logo) + "\n\n" + taglineStyle.Render(Tagline) + "  " + versionText + "\n" + taglineStyle.Render(details) + "\n"
 }
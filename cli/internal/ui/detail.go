@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// RenderModuleDetail renders a single module's description, last sync, and
+// items with their descriptions
+func RenderModuleDetail(cfg *config.PactConfig, module string) string {
+	var sb strings.Builder
+
+	sb.WriteString(titleStyle.Render(module))
+	if desc := cfg.ModuleDescription(module); desc != "" {
+		sb.WriteString(dimStyle.Render(" ── "))
+		sb.WriteString(desc)
+	}
+	sb.WriteString("\n")
+
+	history, _ := state.LoadHistory()
+	if run, ok := history[module]; ok {
+		line := fmt.Sprintf("%s %s (%d ok, %d failed)", run.Outcome, formatSince(run.LastApplied, time.Now()), run.Applied, run.Failed)
+		switch run.Outcome {
+		case state.OutcomeSynced:
+			sb.WriteString(successStyle.Render(line))
+		case state.OutcomePartial:
+			sb.WriteString(warningStyle.Render(line))
+		default:
+			sb.WriteString(errorStyle.Render(line))
+		}
+		for _, item := range run.FailedItems {
+			sb.WriteString("\n")
+			sb.WriteString(errorStyle.Render("  ✗ " + item.Name))
+			if item.Error != "" {
+				sb.WriteString(dimStyle.Render(" " + item.Error))
+			}
+		}
+	} else {
+		sb.WriteString(dimStyle.Render("never synced"))
+	}
+	sb.WriteString("\n")
+
+	applied, _ := state.LoadApplied()
+	switch sync, changed := state.CheckModule(cfg, module, applied); sync {
+	case state.SyncPending:
+		sb.WriteString(warningStyle.Render(fmt.Sprintf("pending: %s changed since the last sync", strings.Join(changed, ", "))))
+		sb.WriteString("\n")
+	case state.SyncDrifted:
+		sb.WriteString(warningStyle.Render(fmt.Sprintf("drifted: %s edited or removed here", strings.Join(changed, ", "))))
+		sb.WriteString("\n")
+	}
+
+	items := cfg.ModuleItems(module)
+	if len(items) == 0 {
+		return boxStyle.Render(sb.String())
+	}
+
+	sb.WriteString("\n")
+	descriptions := cfg.ItemDescriptions(module)
+	for _, item := range items {
+		sb.WriteString(moduleNameStyle.Render(item))
+		if desc := descriptions[item]; desc != "" {
+			sb.WriteString(" ")
+			sb.WriteString(fileCountStyle.Render(desc))
+		}
+		sb.WriteString("\n")
+	}
+
+	return boxStyle.Render(strings.TrimSuffix(sb.String(), "\n"))
+}
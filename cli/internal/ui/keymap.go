@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cloudboy-jh/pact/internal/config"
+)
+
+// Keymap is the keys pact's TUIs share, set with ui.keymap in pact.json,
+// and whether they take mouse input, set with ui.mouse
+type Keymap struct {
+	Up      key.Binding
+	Down    key.Binding
+	Toggle  key.Binding
+	Enter   key.Binding
+	Back    key.Binding
+	All     key.Binding
+	Quit    key.Binding
+	Sync    key.Binding
+	Edit    key.Binding
+	Refresh key.Binding
+	Retry   key.Binding
+	Mouse   bool
+}
+
+// Keymap presets; ui.keymap names one, or is an object with an optional
+// "preset" and a key or list of keys for each action to rebind
+const (
+	PresetDefault = "default" // Arrows and vim keys
+	PresetVim     = "vim"     // j/k only, leaving the arrows unbound
+	PresetArrows  = "arrows"  // Arrows only, leaving j/k free
+)
+
+var keys = DefaultKeymap()
+
+// Keys returns the keymap in use
+func Keys() Keymap {
+	return keys
+}
+
+// DefaultKeymap moves with the arrows or j/k
+func DefaultKeymap() Keymap {
+	km, _ := presetKeymap(PresetDefault)
+	return km
+}
+
+func presetKeymap(name string) (Keymap, error) {
+	up, down := []string{"up", "k"}, []string{"down", "j"}
+	switch name {
+	case "", PresetDefault:
+	case PresetVim:
+		up, down = []string{"k"}, []string{"j"}
+	case PresetArrows:
+		up, down = []string{"up"}, []string{"down"}
+	default:
+		return Keymap{}, fmt.Errorf("unknown keymap preset %q (use default, vim, or arrows)", name)
+	}
+	return Keymap{
+		Up:      key.NewBinding(key.WithKeys(up...)),
+		Down:    key.NewBinding(key.WithKeys(down...)),
+		Toggle:  key.NewBinding(key.WithKeys(" ")),
+		Enter:   key.NewBinding(key.WithKeys("enter")),
+		Back:    key.NewBinding(key.WithKeys("b", "esc")),
+		All:     key.NewBinding(key.WithKeys("a")),
+		Quit:    key.NewBinding(key.WithKeys("q", "ctrl+c")),
+		Sync:    key.NewBinding(key.WithKeys("s")),
+		Edit:    key.NewBinding(key.WithKeys("e")),
+		Refresh: key.NewBinding(key.WithKeys("r")),
+		Retry:   key.NewBinding(key.WithKeys("f")),
+	}, nil
+}
+
+// actions maps the names ui.keymap rebinds to their bindings
+func (k *Keymap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":      &k.Up,
+		"down":    &k.Down,
+		"toggle":  &k.Toggle,
+		"enter":   &k.Enter,
+		"back":    &k.Back,
+		"all":     &k.All,
+		"quit":    &k.Quit,
+		"sync":    &k.Sync,
+		"edit":    &k.Edit,
+		"refresh": &k.Refresh,
+		"retry":   &k.Retry,
+	}
+}
+
+// LoadKeymap reads ui.keymap and ui.mouse from pact.json and makes them the
+// keymap in use. On an error the default keymap stays.
+//
+//	"ui": {"keymap": {"preset": "arrows", "quit": ["q", "x"]}, "mouse": true}
+func LoadKeymap(cfg *config.PactConfig) error {
+	km, err := ParseKeymap(cfg.Get("ui.keymap"))
+	if err != nil {
+		return err
+	}
+	if mouse, ok := cfg.Get("ui.mouse").(bool); ok {
+		km.Mouse = mouse
+	}
+	keys = km
+	return nil
+}
+
+// ParseKeymap builds a keymap from a ui.keymap value: nil, a preset name, or
+// an object of rebound actions
+func ParseKeymap(v any) (Keymap, error) {
+	switch val := v.(type) {
+	case nil:
+		return DefaultKeymap(), nil
+	case string:
+		return presetKeymap(val)
+	case map[string]any:
+		preset, _ := val["preset"].(string)
+		km, err := presetKeymap(preset)
+		if err != nil {
+			return km, err
+		}
+		actions := km.actions()
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if name == "preset" {
+				continue
+			}
+			binding, ok := actions[name]
+			if !ok {
+				return km, fmt.Errorf("unknown action %q in ui.keymap", name)
+			}
+			bound, err := keyList(val[name])
+			if err != nil {
+				return km, fmt.Errorf("ui.keymap.%s: %w", name, err)
+			}
+			*binding = key.NewBinding(key.WithKeys(bound...))
+		}
+		return km, nil
+	}
+	return Keymap{}, fmt.Errorf("ui.keymap should be a preset name or an object")
+}
+
+// keyList reads a key or list of keys, in bubbletea's names ("up",
+// "ctrl+n", "x"); "space" stands for " "
+func keyList(v any) ([]string, error) {
+	var list []string
+	switch val := v.(type) {
+	case string:
+		list = []string{val}
+	case []any:
+		for _, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("keys should be strings")
+			}
+			list = append(list, s)
+		}
+	default:
+		return nil, fmt.Errorf("should be a key or a list of keys")
+	}
+	if len(list) == 0 {
+		return nil, fmt.Errorf("no keys given")
+	}
+	for i, k := range list {
+		if k == "space" {
+			list[i] = " "
+		}
+	}
+	return list, nil
+}
+
+// ProgramOptions adds mouse input to a TUI's options when ui.mouse is on
+func (k Keymap) ProgramOptions(opts ...tea.ProgramOption) []tea.ProgramOption {
+	if k.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return opts
+}
+
+// Scroll turns a mouse wheel event into a move of -1 (up), 1 (down), or 0
+func (k Keymap) Scroll(msg tea.MouseMsg) int {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// Label names the key to press for b in help text: the first key that's a
+// single character, else the first key
+func Label(b key.Binding) string {
+	bound := b.Keys()
+	if len(bound) == 0 {
+		return ""
+	}
+	for _, k := range bound {
+		if len([]rune(k)) == 1 && k != " " {
+			return k
+		}
+	}
+	return keyName(bound[0])
+}
+
+func keyName(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case " ":
+		return "space"
+	}
+	return k
+}
+
+// Matches reports whether a key read outside bubbletea, named like its
+// keys, is bound to b
+func Matches(b key.Binding, name string) bool {
+	for _, k := range b.Keys() {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestParseKeymap(t *testing.T) {
+	km, err := ParseKeymap(map[string]any{"preset": "arrows", "quit": []any{"x", "ctrl+c"}, "toggle": "space"})
+	if err != nil {
+		t.Fatalf("ParseKeymap() error: %v", err)
+	}
+	if Matches(km.Down, "j") || !Matches(km.Down, "down") {
+		t.Fatalf("Down = %q, want only down", km.Down.Keys())
+	}
+	if Matches(km.Quit, "q") || !Matches(km.Quit, "x") {
+		t.Fatalf("Quit = %q, want x and ctrl+c", km.Quit.Keys())
+	}
+	if Label(km.Toggle) != "space" || Label(km.Quit) != "x" {
+		t.Fatalf("labels = %q, %q, want space, x", Label(km.Toggle), Label(km.Quit))
+	}
+
+	if _, err := ParseKeymap("emacs"); err == nil {
+		t.Fatalf("ParseKeymap(emacs) succeeded, want an unknown preset error")
+	}
+	if _, err := ParseKeymap(map[string]any{"jump": "g"}); err == nil {
+		t.Fatalf("ParseKeymap(jump) succeeded, want an unknown action error")
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/cloudboy-jh/pact/internal/config"
 	"github.com/cloudboy-jh/pact/internal/keyring"
+	"github.com/cloudboy-jh/pact/internal/state"
 )
 
 var (
@@ -59,12 +62,113 @@ var (
 			Padding(0, 2)
 )
 
+// narrowWidth is the terminal width below which the status box drops module
+// details, shortens file counts, and narrows its padding, for tmux splits
+const narrowWidth = 60
+
+// layout is how wide the status screen can draw; a width of 0 is unlimited
+type layout struct {
+	width int
+	retry bool // Some module has failed items to retry
+}
+
+func (l layout) narrow() bool {
+	return l.width > 0 && l.width < narrowWidth
+}
+
+func (l layout) box() lipgloss.Style {
+	if l.narrow() {
+		return boxStyle.Padding(1, 1)
+	}
+	return boxStyle
+}
+
+// content is how many columns fit inside the box, 0 if unlimited
+func (l layout) content() int {
+	if l.width <= 0 {
+		return 0
+	}
+	w := l.width - l.box().GetHorizontalFrameSize()
+	if w < 1 {
+		w = 1
+	}
+	return w
+}
+
+// truncate cuts each line of s to w columns, ending cut lines with "…"
+func truncate(s string, w int) string {
+	if w <= 0 {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if ansi.StringWidth(line) > w {
+			lines[i] = ansi.Truncate(line, w, "…")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// helpLines lays the key hints out on as few lines as fit l.width
+func (l layout) helpLines() []string {
+	hints := []string{
+		fmt.Sprintf("[%s] sync", Label(keys.Sync)),
+		fmt.Sprintf("[%s] edit", Label(keys.Edit)),
+		fmt.Sprintf("[%s] refresh", Label(keys.Refresh)),
+	}
+	if l.retry {
+		hints = append(hints, fmt.Sprintf("[%s] retry failed", Label(keys.Retry)))
+	}
+	hints = append(hints,
+		fmt.Sprintf("[%s/%s] scroll", Label(keys.Down), Label(keys.Up)),
+		fmt.Sprintf("[%s] quit", Label(keys.Quit)),
+	)
+	if l.width <= 0 {
+		return []string{strings.Join(hints, "  ")}
+	}
+	w := l.width - helpStyle.GetHorizontalFrameSize()
+	var lines []string
+	var line string
+	for _, hint := range hints {
+		switch {
+		case line == "":
+			line = hint
+		case ansi.StringWidth(line)+2+ansi.StringWidth(hint) <= w:
+			line += "  " + hint
+		default:
+			lines = append(lines, line)
+			line = hint
+		}
+	}
+	lines = append(lines, line)
+	for i := range lines {
+		lines[i] = truncate(lines[i], w)
+	}
+	return lines
+}
+
 // ModuleStatus represents the status of a module
 type ModuleStatus struct {
-	Name      string
-	Status    string // "configured", "has_files", "not_configured"
-	FileCount int
-	Details   string
+	Name        string
+	Status      string // "configured", "has_files", "not_configured"
+	FileCount   int
+	Details     string
+	Outcome     string    // Last sync outcome ("synced", "partial", "failed"), empty if never synced
+	LastApplied time.Time // When the module was last applied
+	Sync        string    // state.SyncSynced, SyncPending, or SyncDrifted; empty if never synced
+	Changed     []string  // Items behind a pending or drifted module
+	Failed      []string  // Items that failed in the last sync
+	Total       int       // Items the last sync applied or failed
+}
+
+// retryable reports whether any module has failed items to retry
+func retryable(statuses []ModuleStatus) bool {
+	for _, s := range statuses {
+		if len(s.Failed) > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 // GetModuleStatuses returns the status of all modules found in config
@@ -73,6 +177,9 @@ func GetModuleStatuses(cfg *config.PactConfig) []ModuleStatus {
 
 	// Get all modules from config (top-level objects)
 	modules := cfg.GetModules()
+	history, _ := state.LoadHistory()
+	applied, _ := state.LoadApplied()
+	ignored, _ := state.LoadIgnoreList(cfg)
 
 	for _, module := range modules {
 		status := ModuleStatus{
@@ -92,6 +199,29 @@ func GetModuleStatuses(cfg *config.PactConfig) []ModuleStatus {
 		// Get some details about the module
 		status.Details = getModuleDetails(cfg, module)
 
+		if run, ok := history[module]; ok {
+			status.Outcome = run.Outcome
+			status.LastApplied = run.LastApplied
+			status.Total = run.Total()
+			for _, item := range run.FailedItems {
+				status.Failed = append(status.Failed, item.Name)
+			}
+		}
+		status.Sync, status.Changed = state.CheckModule(cfg, module, applied)
+		if status.Sync == state.SyncDrifted {
+			// Files edited here on purpose can be ignored
+			var changed []string
+			for _, name := range status.Changed {
+				if !ignored.Has(module, "file", name) {
+					changed = append(changed, name)
+				}
+			}
+			if len(changed) == 0 {
+				status.Sync = state.SyncSynced
+			}
+			status.Changed = changed
+		}
+
 		statuses = append(statuses, status)
 	}
 
@@ -114,6 +244,12 @@ func getModuleDetails(cfg *config.PactConfig, module string) string {
 		if def := cfg.GetString("editor.default"); def != "" {
 			details = append(details, def)
 		}
+	case "keybindings", "snippets":
+		for _, editor := range config.KeybindingEditors {
+			if cfg.HasKey(module + "." + editor) {
+				details = append(details, editor)
+			}
+		}
 	case "terminal":
 		if font := cfg.GetString("terminal.font"); font != "" {
 			details = append(details, font)
@@ -143,17 +279,17 @@ func getModuleDetails(cfg *config.PactConfig, module string) string {
 	return ""
 }
 
-func getReservedLines(hasSecrets bool) int {
-	// Reserve lines for: header(2) + box borders(2) + help(1) + secrets(2 if present)
-	reserved := 2 + 2 + 1
+func getReservedLines(hasSecrets bool, helpLines int) int {
+	// Reserve lines for: header(2) + box borders(2) + help + secrets(2 if present)
+	reserved := 2 + 2 + helpLines
 	if hasSecrets {
 		reserved += 2
 	}
 	return reserved
 }
 
-func getAvailableHeight(termHeight int, hasSecrets bool) int {
-	return termHeight - getReservedLines(hasSecrets)
+func getAvailableHeight(termHeight int, hasSecrets bool, helpLines int) int {
+	return termHeight - getReservedLines(hasSecrets, helpLines)
 }
 
 func getMaxScrollForAvailable(totalLines int, available int) int {
@@ -194,8 +330,8 @@ func getMaxVisible(totalLines int, scrollOffset int, available int) int {
 	return maxVisible
 }
 
-// GetMaxScroll calculates the maximum scroll offset based on content and terminal height
-func GetMaxScroll(cfg *config.PactConfig, termHeight int) int {
+// GetMaxScroll calculates the maximum scroll offset based on content and terminal size
+func GetMaxScroll(cfg *config.PactConfig, termHeight, termWidth int) int {
 	statuses := GetModuleStatuses(cfg)
 	secrets := cfg.GetSecrets()
 
@@ -204,17 +340,22 @@ func GetMaxScroll(cfg *config.PactConfig, termHeight int) int {
 		return 0
 	}
 
-	availableHeight := getAvailableHeight(termHeight, len(secrets) > 0)
+	helpLines := len(layout{width: termWidth, retry: retryable(statuses)}.helpLines())
+	availableHeight := getAvailableHeight(termHeight, len(secrets) > 0, helpLines)
 	return getMaxScrollForAvailable(len(statuses), availableHeight)
 }
 
 // RenderStatus renders the status box with optional scrolling
 // scrollOffset: how many lines to skip from the top of the module list
 // termHeight: terminal height for pagination (0 = no pagination)
-func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) string {
+// termWidth: terminal width to fit the layout to (0 = no limit)
+func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int, termWidth int) string {
 	var sb strings.Builder
 	secrets := cfg.GetSecrets()
 	hasSecrets := len(secrets) > 0
+	statuses := GetModuleStatuses(cfg)
+	l := layout{width: termWidth, retry: retryable(statuses)}
+	help := l.helpLines()
 
 	// Header
 	name := cfg.GetString("name")
@@ -222,25 +363,31 @@ func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) stri
 		name = "pact"
 	}
 	hostname, _ := os.Hostname()
+	gap := 30 - ansi.StringWidth(name)
+	if w := l.content(); w > 0 && gap > w-ansi.StringWidth(name)-ansi.StringWidth(hostname) {
+		gap = w - ansi.StringWidth(name) - ansi.StringWidth(hostname)
+	}
+	if gap < 1 {
+		gap = 1
+	}
 	header := fmt.Sprintf("%s%s%s",
 		titleStyle.Render(name),
-		strings.Repeat(" ", 30-len(name)),
+		strings.Repeat(" ", gap),
 		subtitleStyle.Render(hostname),
 	)
 	sb.WriteString(header)
 	sb.WriteString("\n\n")
 
 	// Modules
-	statuses := GetModuleStatuses(cfg)
 	if len(statuses) == 0 {
 		sb.WriteString(dimStyle.Render("No modules configured"))
 		sb.WriteString("\n")
 	} else {
-		availableHeight := getAvailableHeight(termHeight, hasSecrets)
+		availableHeight := getAvailableHeight(termHeight, hasSecrets, len(help))
 		if termHeight == 0 || availableHeight <= 0 || availableHeight >= len(statuses) {
 			// No pagination needed - show all
 			for _, status := range statuses {
-				line := renderModuleLine(status)
+				line := renderModuleLine(status, l)
 				sb.WriteString(line)
 				sb.WriteString("\n")
 			}
@@ -270,7 +417,7 @@ func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) stri
 
 			// Render visible modules
 			for i := scrollOffset; i < endIndex; i++ {
-				line := renderModuleLine(statuses[i])
+				line := renderModuleLine(statuses[i], l)
 				sb.WriteString(line)
 				sb.WriteString("\n")
 			}
@@ -278,7 +425,7 @@ func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) stri
 			// Show scroll down indicator if not at bottom
 			remaining := len(statuses) - endIndex
 			if remaining > 0 {
-				sb.WriteString(dimStyle.Render(fmt.Sprintf("  ... %d more below (j to scroll)", remaining)))
+				sb.WriteString(dimStyle.Render(fmt.Sprintf("  ... %d more below (%s to scroll)", remaining, Label(keys.Down))))
 				sb.WriteString("\n")
 			}
 		}
@@ -291,36 +438,84 @@ func RenderStatus(cfg *config.PactConfig, scrollOffset int, termHeight int) stri
 		sb.WriteString(secretsLine)
 	}
 
-	content := sb.String()
-	box := boxStyle.Render(content)
+	content := truncate(sb.String(), l.content())
+	box := l.box().Render(content)
 
-	// Help line (updated with scroll hint)
-	help := helpStyle.Render("[s] sync  [e] edit  [r] refresh  [j/k] scroll  [q] quit")
+	// Help, wrapped to the terminal
+	for i, line := range help {
+		help[i] = helpStyle.Render(line)
+	}
+
+	return box + "\n" + strings.Join(help, "\n")
+}
 
-	return box + "\n" + help
+// State sums up a module the way the status screen shows it:
+// "not_configured", a failed or partial last sync, pending or drifted
+// changes, "synced", or "never_synced"
+func (s ModuleStatus) State() string {
+	switch {
+	case s.Status == "not_configured":
+		return "not_configured"
+	case s.Outcome == state.OutcomePartial, s.Outcome == state.OutcomeFailed:
+		return s.Outcome
+	case s.Sync == state.SyncPending, s.Sync == state.SyncDrifted:
+		return s.Sync
+	case s.Outcome == state.OutcomeSynced:
+		return state.OutcomeSynced
+	}
+	return "never_synced"
 }
 
-func renderModuleLine(status ModuleStatus) string {
+func renderModuleLine(status ModuleStatus, l layout) string {
 	name := moduleNameStyle.Render(status.Name)
 	dashes := dimStyle.Render(strings.Repeat("─", 2))
+	if l.narrow() {
+		name = moduleNameStyle.Width(12).Render(status.Name)
+		dashes = dimStyle.Render("─")
+	}
 
 	var statusIcon, statusText string
-	switch status.Status {
-	case "has_files":
-		statusIcon = successStyle.Render("✓")
-		statusText = successStyle.Render("ready")
-	case "configured":
-		statusIcon = successStyle.Render("●")
-		statusText = dimStyle.Render("config only")
+	since := formatSince(status.LastApplied, time.Now())
+	switch status.State() {
 	case "not_configured":
 		statusIcon = dimStyle.Render(" ")
 		statusText = dimStyle.Render("not configured")
+	case state.OutcomePartial:
+		statusIcon = warningStyle.Render("◐")
+		statusText = warningStyle.Render("partially applied")
+	case state.OutcomeFailed:
+		statusIcon = errorStyle.Render("✗")
+		statusText = errorStyle.Render("failed " + since)
+	case state.SyncPending:
+		statusIcon = warningStyle.Render("→")
+		statusText = warningStyle.Render("pending")
+	case state.SyncDrifted:
+		statusIcon = warningStyle.Render("≠")
+		statusText = warningStyle.Render("drifted")
+	case state.OutcomeSynced:
+		statusIcon = successStyle.Render("✓")
+		statusText = successStyle.Render("synced " + since)
+	default:
+		statusIcon = dimStyle.Render("○")
+		statusText = dimStyle.Render("never synced")
 	}
 
 	statusPart := statusTextStyle.Render(fmt.Sprintf("%s %s", statusIcon, statusText))
 
 	var extra string
-	if status.Details != "" {
+	if len(status.Failed) > 0 {
+		// What failed matters more than what the module holds
+		if l.narrow() {
+			extra = fileCountStyle.Render(fmt.Sprintf("(%d/%d)", len(status.Failed), status.Total))
+		} else {
+			extra = fileCountStyle.Render(fmt.Sprintf("%d of %d failed %s: %s", len(status.Failed), status.Total, since, strings.Join(status.Failed, ", ")))
+		}
+	} else if l.narrow() {
+		// Just the count; details are cut at this width anyway
+		if status.FileCount > 0 {
+			extra = fileCountStyle.Render(fmt.Sprintf("(%d)", status.FileCount))
+		}
+	} else if status.Details != "" {
 		extra = fileCountStyle.Render(status.Details)
 	} else if status.FileCount > 0 {
 		unit := "files"
@@ -333,6 +528,24 @@ func renderModuleLine(status ModuleStatus) string {
 	return fmt.Sprintf("%s %s %s  %s", name, dashes, statusPart, extra)
 }
 
+// formatSince renders how long ago t was: "just now", "5m ago", "3h ago",
+// "yesterday", "2d ago"
+func formatSince(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 48*time.Hour:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
 func renderSecretsLine(secrets []string) string {
 	if len(secrets) == 0 {
 		return dimStyle.Render("secrets ──────── none configured")
@@ -3,13 +3,16 @@ package ui
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/x/ansi"
 	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/state"
 )
 
 func TestRenderStatusHelpLine(t *testing.T) {
 	cfg := &config.PactConfig{Raw: map[string]any{"name": "pact"}}
-	output := RenderStatus(cfg, 0, 24)
+	output := RenderStatus(cfg, 0, 24, 0)
 
 	if !strings.Contains(output, "[s] sync") {
 		t.Fatalf("expected help line to include sync hint")
@@ -27,3 +30,59 @@ func TestRenderStatusHelpLine(t *testing.T) {
 		t.Fatalf("expected help line to include quit hint")
 	}
 }
+
+func TestFormatSince(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[time.Duration]string{
+		30 * time.Second: "just now",
+		5 * time.Minute:  "5m ago",
+		3 * time.Hour:    "3h ago",
+		30 * time.Hour:   "yesterday",
+		72 * time.Hour:   "3d ago",
+	}
+	for ago, want := range cases {
+		if got := formatSince(now.Add(-ago), now); got != want {
+			t.Fatalf("formatSince(%s ago) = %q, want %q", ago, got, want)
+		}
+	}
+}
+
+func TestRenderStatusNarrow(t *testing.T) {
+	cfg := &config.PactConfig{Raw: map[string]any{
+		"name":  "a-pact-with-a-rather-long-name",
+		"shell": map[string]any{"tools": []any{"starship", "zoxide", "fzf", "ripgrep"}},
+	}}
+	output := RenderStatus(cfg, 0, 24, 40)
+
+	for _, line := range strings.Split(output, "\n") {
+		if w := ansi.StringWidth(line); w > 40 {
+			t.Fatalf("line is %d columns wide, want at most 40: %q", w, line)
+		}
+	}
+	if strings.Contains(output, "starship") {
+		t.Fatalf("expected module details to be dropped when narrow")
+	}
+	if !strings.Contains(output, "[q] quit") {
+		t.Fatalf("expected help to wrap rather than be cut off")
+	}
+}
+
+func TestRenderModuleLinePartial(t *testing.T) {
+	status := ModuleStatus{
+		Name:        "cli",
+		Status:      "configured",
+		Outcome:     state.OutcomePartial,
+		LastApplied: time.Now(),
+		Failed:      []string{"ripgrep", "fd", "bat"},
+		Total:       10,
+	}
+	line := ansi.Strip(renderModuleLine(status, layout{}))
+	if !strings.Contains(line, "partially applied") || !strings.Contains(line, "3 of 10 failed") || !strings.Contains(line, "ripgrep, fd, bat") {
+		t.Fatalf("expected the failed count and items, got %q", line)
+	}
+
+	help := strings.Join(layout{retry: retryable([]ModuleStatus{status})}.helpLines(), " ")
+	if !strings.Contains(help, "[f] retry failed") {
+		t.Fatalf("expected a retry hint, got %q", help)
+	}
+}
@@ -0,0 +1,390 @@
+package undo
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+// keep is how many sync journals are kept; older ones are pruned on save
+const keep = 10
+
+// Journal records every change one sync makes so it can be reverted
+type Journal struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	Modules []string  `json:"modules"`
+	Changes []Change  `json:"changes"`
+
+	dir  string
+	mu   sync.Mutex // Parallel installs record into one journal
+	seen map[string]bool
+}
+
+// Change is one reversible change, recorded before it is made
+type Change struct {
+	Kind string `json:"kind"` // "file", "install", "gitconfig", "setting"
+
+	// file: the path's state before the sync
+	Path       string `json:"path,omitempty"`
+	Existed    bool   `json:"existed,omitempty"`
+	LinkTarget string `json:"linkTarget,omitempty"` // Set when the path was a symlink
+	Backup     string `json:"backup,omitempty"`     // Copy of the file or directory
+
+	// install: what was installed and how
+	Install *state.Entry `json:"install,omitempty"`
+
+	// gitconfig: the global value before the sync
+	Key   string `json:"key,omitempty"`
+	Value string `json:"value,omitempty"`
+	IsSet bool   `json:"isSet,omitempty"`
+
+	// setting: an OS preference (named by Key) and the command that puts
+	// back its value from before the sync
+	Restore []string `json:"restore,omitempty"`
+}
+
+func journalsDir() (string, error) {
+	dir, err := state.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "undo"), nil
+}
+
+// Begin starts the journal for a sync of the given modules
+func Begin(modules []string) (*Journal, error) {
+	dir, err := journalsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	// IDs go down to the microsecond, and creating the directory claims
+	// one, so two syncs never share a journal
+	for {
+		now := time.Now()
+		j := &Journal{
+			ID:      now.Format("20060102-150405.000000"),
+			Created: now,
+			Modules: modules,
+			seen:    make(map[string]bool),
+		}
+		j.dir = filepath.Join(dir, j.ID)
+		err := os.Mkdir(j.dir, 0755)
+		if err == nil {
+			return j, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+	}
+}
+
+// File backs up a path before it is first modified. A nil journal records
+// nothing.
+func (j *Journal) File(p string) error {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.seen["file:"+p] {
+		return nil
+	}
+	j.seen["file:"+p] = true
+
+	change := Change{Kind: "file", Path: p}
+	info, err := os.Lstat(p)
+	switch {
+	case os.IsNotExist(err):
+	case err != nil:
+		return err
+	case info.Mode()&os.ModeSymlink != 0:
+		change.Existed = true
+		if change.LinkTarget, err = os.Readlink(p); err != nil {
+			return err
+		}
+	default:
+		change.Existed = true
+		change.Backup = filepath.Join(j.dir, fmt.Sprintf("%d-%s", len(j.Changes), filepath.Base(p)))
+		if err := CopyTree(p, change.Backup); err != nil {
+			return err
+		}
+	}
+
+	j.Changes = append(j.Changes, change)
+	return nil
+}
+
+// Install records something that was newly installed
+func (j *Journal) Install(entry state.Entry) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Changes = append(j.Changes, Change{Kind: "install", Install: &entry})
+}
+
+// GitConfig records a global git config value before it is first set
+func (j *Journal) GitConfig(key string) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.seen["git:"+key] {
+		return
+	}
+	j.seen["git:"+key] = true
+
+	change := Change{Kind: "gitconfig", Key: key}
+	if output, err := exec.Command("git", "config", "--global", "--get", key).Output(); err == nil {
+		change.Value = strings.TrimRight(string(output), "\n")
+		change.IsSet = true
+	}
+	j.Changes = append(j.Changes, change)
+}
+
+// Setting records the command that restores an OS preference, before the
+// preference is first changed
+func (j *Journal) Setting(name string, restore []string) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.seen["setting:"+name] {
+		return
+	}
+	j.seen["setting:"+name] = true
+	j.Changes = append(j.Changes, Change{Kind: "setting", Key: name, Restore: restore})
+}
+
+// Save writes the journal, or discards it when the sync changed nothing
+func (j *Journal) Save() error {
+	if j == nil {
+		return nil
+	}
+	if len(j.Changes) == 0 {
+		return j.Discard()
+	}
+
+	output, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(j.dir, "journal.json"), output, 0644); err != nil {
+		return err
+	}
+	prune()
+	return nil
+}
+
+// Discard deletes the journal and its backups
+func (j *Journal) Discard() error {
+	return os.RemoveAll(j.dir)
+}
+
+// List returns the saved journals, newest first
+func List() ([]*Journal, error) {
+	dir, err := journalsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var journals []*Journal
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name(), "journal.json"))
+		if err != nil {
+			continue
+		}
+		var j Journal
+		if err := json.Unmarshal(data, &j); err != nil {
+			continue
+		}
+		j.dir = filepath.Join(dir, e.Name())
+		journals = append(journals, &j)
+	}
+
+	sort.Slice(journals, func(a, b int) bool {
+		return journals[a].Created.After(journals[b].Created)
+	})
+	return journals, nil
+}
+
+// Last returns the most recent journal, or nil when there is nothing to undo
+func Last() (*Journal, error) {
+	journals, err := List()
+	if err != nil || len(journals) == 0 {
+		return nil, err
+	}
+	return journals[0], nil
+}
+
+func prune() {
+	journals, err := List()
+	if err != nil {
+		return
+	}
+	for i := keep; i < len(journals); i++ {
+		journals[i].Discard()
+	}
+}
+
+// Revert undoes the journal's changes newest first. Installs are removed
+// with uninstall and dropped from the install journal. The changes that
+// couldn't be reverted are left in the journal, so saving it lets undo retry
+// just those.
+func (j *Journal) Revert(uninstall func(state.Entry) error) []error {
+	var errs []error
+	var failed []Change
+
+	installs, err := state.LoadJournal()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := len(j.Changes) - 1; i >= 0; i-- {
+		c := j.Changes[i]
+		var err error
+		switch c.Kind {
+		case "file":
+			err = c.restoreFile()
+		case "install":
+			if err = uninstall(*c.Install); err == nil && installs != nil {
+				installs.Remove(c.Install.Module, c.Install.Name)
+			}
+		case "gitconfig":
+			err = c.restoreGitConfig()
+		case "setting":
+			err = c.restoreSetting()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.Describe(), err))
+			failed = append([]Change{c}, failed...)
+		}
+	}
+	j.Changes = failed
+
+	if installs != nil {
+		if err := installs.Save(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Describe says what reverting the change does
+func (c Change) Describe() string {
+	switch c.Kind {
+	case "file":
+		if !c.Existed {
+			return "remove " + c.Path
+		}
+		return "restore " + c.Path
+	case "install":
+		return fmt.Sprintf("uninstall %s (%s)", c.Install.Name, c.Install.Backend)
+	case "gitconfig":
+		if !c.IsSet {
+			return "unset git " + c.Key
+		}
+		return fmt.Sprintf("reset git %s to %s", c.Key, c.Value)
+	case "setting":
+		return "reset " + c.Key
+	}
+	return c.Kind
+}
+
+func (c Change) restoreFile() error {
+	if err := os.RemoveAll(c.Path); err != nil {
+		return err
+	}
+	if !c.Existed {
+		return nil
+	}
+	if c.LinkTarget != "" {
+		return os.Symlink(c.LinkTarget, c.Path)
+	}
+	return CopyTree(c.Backup, c.Path)
+}
+
+func (c Change) restoreGitConfig() error {
+	cmd := exec.Command("git", "config", "--global", "--unset", c.Key)
+	if c.IsSet {
+		cmd = exec.Command("git", "config", "--global", c.Key, c.Value)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		// --unset exits 5 when the key is already gone
+		if !c.IsSet && cmd.ProcessState != nil && cmd.ProcessState.ExitCode() == 5 {
+			return nil
+		}
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func (c Change) restoreSetting() error {
+	if len(c.Restore) == 0 {
+		return nil
+	}
+	if output, err := exec.Command(c.Restore[0], c.Restore[1:]...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// CopyTree copies a file or directory, preserving file modes
+func CopyTree(src, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			link, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+}
@@ -0,0 +1,111 @@
+package undo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/cloudboy-jh/pact/internal/state"
+)
+
+func TestRevertFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".pact"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	rc := filepath.Join(dir, ".zshrc")
+	created := filepath.Join(dir, "starship.toml")
+	os.WriteFile(rc, []byte("export EDITOR=vim\n"), 0644)
+
+	j, err := Begin([]string{"shell"})
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	j.File(rc)
+	j.File(created)
+	os.WriteFile(rc, []byte("export EDITOR=vim\neval \"$(zoxide init zsh)\"\n"), 0644)
+	j.File(rc) // already backed up; must not overwrite the original
+	os.WriteFile(created, []byte("add_newline = false\n"), 0644)
+	if err := j.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	last, err := Last()
+	if err != nil || last == nil {
+		t.Fatalf("Last() = %v, %v", last, err)
+	}
+	if errs := last.Revert(func(state.Entry) error { return nil }); len(errs) > 0 {
+		t.Fatalf("Revert() errors = %v", errs)
+	}
+
+	if data, _ := os.ReadFile(rc); string(data) != "export EDITOR=vim\n" {
+		t.Fatalf(".zshrc = %q, want the original", data)
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Fatalf("starship.toml still exists after revert")
+	}
+}
+
+func TestJournalConcurrent(t *testing.T) {
+	j := &Journal{dir: t.TempDir(), seen: make(map[string]bool)}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			j.Setting("user PATH", []string{"restore"})
+			j.Install(state.Entry{Module: "cli", Name: fmt.Sprint(i)})
+		}(i)
+	}
+	wg.Wait()
+	if len(j.Changes) != 9 {
+		t.Fatalf("recorded %d changes, want 1 setting and 8 installs", len(j.Changes))
+	}
+}
+
+func TestRevertKeepsFailures(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".pact"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	first, err := Begin([]string{"cli"})
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	second, err := Begin([]string{"cli"})
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("two journals share the ID %s", first.ID)
+	}
+
+	second.Install(state.Entry{Module: "cli", Name: "jq"})
+	second.Install(state.Entry{Module: "cli", Name: "fd"})
+	errs := second.Revert(func(e state.Entry) error {
+		if e.Name == "fd" {
+			return fmt.Errorf("still in use")
+		}
+		return nil
+	})
+	if len(errs) != 1 {
+		t.Fatalf("Revert() errors = %v, want 1", errs)
+	}
+	if len(second.Changes) != 1 || second.Changes[0].Install.Name != "fd" {
+		t.Fatalf("journal left with %v, want only the failed fd install", second.Changes)
+	}
+}
@@ -0,0 +1,139 @@
+// Package pact lets other Go programs scan a machine and apply a pact.json
+// the same way the pact CLI does, without shelling out to it.
+//
+//	cfg, err := pact.LoadConfig("/path/to/.pact/pact.json")
+//	if err != nil {
+//		return err
+//	}
+//	results, err := pact.Apply(cfg, pact.Options{DryRun: true})
+//
+// The types here are the ones the CLI uses internally; new fields may be
+// added but existing ones won't change meaning within a major version.
+package pact
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/cloudboy-jh/pact/internal/apply"
+	"github.com/cloudboy-jh/pact/internal/config"
+	"github.com/cloudboy-jh/pact/internal/detect"
+)
+
+// Config is a parsed pact.json. Any structure is valid; read it with
+// Get, GetString, GetStringSlice, and GetMap using dotted keys like
+// "cli.tools" or "git.user".
+type Config = config.PactConfig
+
+// Detected is everything a machine scan found
+type Detected = detect.DetectedConfig
+
+// ScanOptions narrows a scan to some modules and controls whether config
+// files are looked for
+type ScanOptions = detect.ScanOptions
+
+// Diff is how one module on this machine differs from a pact.json
+type Diff = detect.DiffResult
+
+// DiffItem is one tool, setting, secret, or file in a Diff
+type DiffItem = detect.DiffItem
+
+// Result is the outcome of one install or configuration step
+type Result = apply.Result
+
+// Timeouts limit how long each step may run; zero means no limit
+type Timeouts = apply.Timeouts
+
+// ScopeUser keeps every change inside the user's home directory
+const ScopeUser = apply.ScopeUser
+
+// Options control how a config is applied
+type Options struct {
+	// DryRun reports what each step would do without changing anything
+	DryRun bool
+
+	// LowBandwidth defers fonts, GUI apps, and LLM models
+	LowBandwidth bool
+
+	// Scope is ScopeUser to reject anything that needs admin rights
+	Scope string
+
+	// Jobs is how many CLI tools install at once where the package manager
+	// allows it; 0 or 1 installs them one at a time
+	Jobs int
+
+	// ForbidScripts refuses custom tools installed by a downloaded script
+	ForbidScripts bool
+
+	// AcceptLicense is asked whether to re-run an install that stopped on a
+	// license prompt; when nil the step fails with the prompt's notice
+	AcceptLicense func(command, notice string) bool
+
+	// Confirm is asked before changing machine-wide settings like the
+	// hostname; when nil those settings are skipped
+	Confirm func(question string) bool
+
+	// Context stops the running install or download when cancelled and
+	// skips the steps after it; nil never cancels
+	Context context.Context
+
+	// Timeouts limit how long each install, download, and hook may run
+	Timeouts Timeouts
+}
+
+func (o Options) internal() apply.Options {
+	return apply.Options{
+		DryRun:        o.DryRun,
+		LowBandwidth:  o.LowBandwidth,
+		Scope:         o.Scope,
+		Jobs:          o.Jobs,
+		ForbidScripts: o.ForbidScripts,
+		AcceptLicense: o.AcceptLicense,
+		Confirm:       o.Confirm,
+		Context:       o.Context,
+		Timeouts:      o.Timeouts,
+	}
+}
+
+// LoadConfig reads a pact.json file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pact.json: %w", err)
+	}
+	return config.Parse(data)
+}
+
+// ParseConfig parses pact.json contents
+func ParseConfig(data []byte) (*Config, error) {
+	return config.Parse(data)
+}
+
+// Scan inspects the machine for tools, shell setup, git identity, editors,
+// LLM runtimes, secret names, and config files
+func Scan(opts ScanOptions) *Detected {
+	return detect.Scan(opts)
+}
+
+// Compare reports how a scan differs from a pact.json, per module
+func Compare(detected *Detected, cfg *Config) []Diff {
+	return detect.Compare(detected, cfg)
+}
+
+// Modules lists the modules a config defines
+func Modules(cfg *Config) []string {
+	return cfg.GetModules()
+}
+
+// Apply applies every module in the config. Files the config syncs are
+// read from the .pact directory found from the working directory, as with
+// the CLI.
+func Apply(cfg *Config, opts Options) ([]Result, error) {
+	return apply.Apply(cfg, opts.internal())
+}
+
+// ApplyModule applies one module, running its pre and post hooks
+func ApplyModule(cfg *Config, module string, opts Options) ([]Result, error) {
+	return apply.ApplyModule(cfg, module, opts.internal())
+}
@@ -0,0 +1,32 @@
+package pact
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseConfigModules(t *testing.T) {
+	cfg, err := ParseConfig([]byte(`{"name": "me", "cli": {"tools": ["jq"]}, "git": {"user": "me"}, "settings": {}}`))
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	got := Modules(cfg)
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"cli", "git"}) {
+		t.Fatalf("Modules = %v, want [cli git]", got)
+	}
+	if got = cfg.GetStringSlice("cli.tools"); !reflect.DeepEqual(got, []string{"jq"}) {
+		t.Fatalf("cli.tools = %v", got)
+	}
+}
+
+func TestOptionsInternal(t *testing.T) {
+	opts := Options{DryRun: true, Scope: ScopeUser, Jobs: 4, ForbidScripts: true}.internal()
+	if !opts.DryRun || opts.Scope != ScopeUser || opts.Jobs != 4 || !opts.ForbidScripts {
+		t.Fatalf("options not carried over: %+v", opts)
+	}
+	if opts.Undo != nil {
+		t.Fatalf("library applies shouldn't journal for pact undo")
+	}
+}